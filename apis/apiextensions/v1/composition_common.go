@@ -81,9 +81,28 @@ type PipelineStep struct {
 
 // A FunctionReference references a Composition Function that may be used in a
 // Composition pipeline.
+//
+// +kubebuilder:validation:XValidation:rule="!(has(self.revision) && has(self.version))",message="only one of revision or version may be specified"
 type FunctionReference struct {
 	// Name of the referenced Function.
 	Name string `json:"name"`
+
+	// Revision pins this step to the named FunctionRevision of the referenced
+	// Function. The FunctionRevision keeps serving requests even when it's no
+	// longer the Function's active revision, as long as it's retained by the
+	// Function's revisionHistoryLimit.
+	// +optional
+	Revision *string `json:"revision,omitempty"`
+
+	// Version pins this step to the FunctionRevision of the referenced
+	// Function with the highest package version that satisfies the supplied
+	// semantic version constraint, e.g. ">=v1.2.0, <v2.0.0". Only
+	// FunctionRevisions whose package is referenced by a semantic version
+	// tag are considered. The FunctionRevision keeps serving requests even
+	// when it's no longer the Function's active revision, as long as it's
+	// retained by the Function's revisionHistoryLimit.
+	// +optional
+	Version *string `json:"version,omitempty"`
 }
 
 // FunctionCredentials are optional credentials that a Composition Function
@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -38,6 +39,7 @@ const (
 // CompositeResourceDefinitionSpec specifies the desired state of the definition.
 // +kubebuilder:validation:XValidation:rule="self.scope == 'LegacyCluster' || !has(self.claimNames)",message="Only LegacyCluster composite resources can offer claims"
 // +kubebuilder:validation:XValidation:rule="self.scope == 'LegacyCluster' || !has(self.connectionSecretKeys)",message="Only LegacyCluster composite resources support connection secrets"
+// +kubebuilder:validation:XValidation:rule="self.scope == 'Namespaced' || !has(self.connectionDetailsPublishers)",message="Only Namespaced composite resources support connection details publishers"
 type CompositeResourceDefinitionSpec struct {
 	// Group specifies the API group of the defined composite resource.
 	// Composite resources are served under `/apis/<group>/...`. Must match the
@@ -129,6 +131,198 @@ type CompositeResourceDefinitionSpec struct {
 	// Metadata specifies the desired metadata for the defined composite resource and claim CRD's.
 	// +optional
 	Metadata *CompositeResourceDefinitionSpecMetadata `json:"metadata,omitempty"`
+
+	// ComposedResourceLimits limits the composed resources a single composite
+	// resource of this type may have. A composite resource whose Composition
+	// exceeds these limits won't have any of its composed resources applied.
+	// +optional
+	ComposedResourceLimits *ComposedResourceLimits `json:"composedResourceLimits,omitempty"`
+
+	// AllowedComposedResourceKinds lists the kinds of resource a composite
+	// resource of this type may compose. A composite resource may compose any
+	// kind of resource if this list is empty.
+	// +optional
+	AllowedComposedResourceKinds []ComposedResourceKind `json:"allowedComposedResourceKinds,omitempty"`
+
+	// Impersonation configures Crossplane to apply a composite resource's
+	// composed resources as a service account, rather than as itself.
+	// +optional
+	Impersonation *CompositeResourceImpersonation `json:"impersonation,omitempty"`
+
+	// ConnectionDetailsPublishers configure how Crossplane publishes the
+	// connection details of composite resources of this type. Only
+	// Namespaced composite resources support connection details publishers.
+	// +optional
+	ConnectionDetailsPublishers []ConnectionDetailsPublisher `json:"connectionDetailsPublishers,omitempty"`
+}
+
+// ComposedResourceLimits limits the composed resources a single composite
+// resource may have.
+type ComposedResourceLimits struct {
+	// MaxResources is the maximum number of composed resources a single
+	// composite resource may have.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxResources *int64 `json:"maxResources,omitempty"`
+
+	// Kinds limits the number of composed resources of particular kinds a
+	// single composite resource may have.
+	// +optional
+	// +listType=map
+	// +listMapKey=group
+	// +listMapKey=kind
+	Kinds []ComposedResourceKindLimit `json:"kinds,omitempty"`
+}
+
+// ComposedResourceKindLimit limits the number of composed resources of a kind
+// a single composite resource may have.
+type ComposedResourceKindLimit struct {
+	// Group of the composed resource kind. Use the empty string for the core
+	// API group.
+	// +optional
+	// +kubebuilder:default=""
+	Group string `json:"group"`
+
+	// Kind of composed resource.
+	Kind string `json:"kind"`
+
+	// MaxResources is the maximum number of composed resources of this kind
+	// a single composite resource may have.
+	// +kubebuilder:validation:Minimum=0
+	MaxResources int64 `json:"maxResources"`
+}
+
+// A ComposedResourceKind matches a kind of composed resource.
+type ComposedResourceKind struct {
+	// Group of the composed resource kind. Use the empty string for the core
+	// API group.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// Version of the composed resource kind. Matches any version if omitted.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Kind of composed resource. Use '*' to match any kind in the group.
+	Kind string `json:"kind"`
+}
+
+// CompositeResourceImpersonation configures the service account Crossplane
+// impersonates when it applies composed resources.
+type CompositeResourceImpersonation struct {
+	// ServiceAccountName is the name of the service account to impersonate.
+	ServiceAccountName string `json:"serviceAccountName"`
+
+	// ServiceAccountNamespace is the namespace of the service account to
+	// impersonate. Defaults to the composite resource's namespace. Required
+	// for cluster scoped composite resources.
+	// +optional
+	ServiceAccountNamespace *string `json:"serviceAccountNamespace,omitempty"`
+}
+
+// A ConnectionDetailsPublisherType is a type of connection details publisher.
+type ConnectionDetailsPublisherType string
+
+// Types of connection details publisher.
+const (
+	// ConnectionDetailsPublisherTypeSecret publishes connection details to a
+	// Secret in the composite resource's namespace.
+	ConnectionDetailsPublisherTypeSecret ConnectionDetailsPublisherType = "Secret"
+
+	// ConnectionDetailsPublisherTypeConfigMap publishes connection details
+	// to a ConfigMap in the composite resource's namespace. Use it only for
+	// connection details that aren't sensitive.
+	ConnectionDetailsPublisherTypeConfigMap ConnectionDetailsPublisherType = "ConfigMap"
+
+	// ConnectionDetailsPublisherTypeExternal publishes connection details to
+	// an external secret store plugin.
+	ConnectionDetailsPublisherTypeExternal ConnectionDetailsPublisherType = "External"
+)
+
+// A ConnectionDetailsPublisher publishes a composite resource's connection
+// details.
+// +kubebuilder:validation:XValidation:rule="self.type != 'External' || has(self.external)",message="External publishers must specify an external secret store"
+type ConnectionDetailsPublisher struct {
+	// Type of connection details publisher.
+	// +kubebuilder:validation:Enum=Secret;ConfigMap;External
+	Type ConnectionDetailsPublisherType `json:"type"`
+
+	// Keys is the list of connection details keys to publish. All keys are
+	// published if the list is empty.
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+
+	// Secret configures a Secret publisher.
+	// +optional
+	Secret *SecretConnectionDetailsPublisher `json:"secret,omitempty"`
+
+	// ConfigMap configures a ConfigMap publisher.
+	// +optional
+	ConfigMap *ConfigMapConnectionDetailsPublisher `json:"configMap,omitempty"`
+
+	// External configures an external secret store publisher.
+	// +optional
+	External *ExternalConnectionDetailsPublisher `json:"external,omitempty"`
+}
+
+// A SecretConnectionDetailsPublisher publishes connection details to a Secret
+// in the composite resource's namespace.
+type SecretConnectionDetailsPublisher struct {
+	// NameTemplate is a Go template used to render the name of the Secret.
+	// The template is executed against the composite resource, for example
+	// '{{ .metadata.name }}-connection'. Defaults to the composite
+	// resource's name.
+	// +optional
+	NameTemplate *string `json:"nameTemplate,omitempty"`
+
+	// Type of the Secret. Defaults to connection.crossplane.io/v1alpha1.
+	// +optional
+	Type *corev1.SecretType `json:"type,omitempty"`
+}
+
+// A ConfigMapConnectionDetailsPublisher publishes connection details to a
+// ConfigMap in the composite resource's namespace.
+type ConfigMapConnectionDetailsPublisher struct {
+	// NameTemplate is a Go template used to render the name of the
+	// ConfigMap. The template is executed against the composite resource,
+	// for example '{{ .metadata.name }}-connection'. Defaults to the
+	// composite resource's name.
+	// +optional
+	NameTemplate *string `json:"nameTemplate,omitempty"`
+}
+
+// An ExternalConnectionDetailsPublisher publishes connection details to an
+// external secret store plugin.
+type ExternalConnectionDetailsPublisher struct {
+	// Endpoint of the external secret store plugin's gRPC server, for
+	// example 'dns:///ess-plugin-vault.crossplane-system:4040'. Crossplane
+	// connects using its client TLS certificate.
+	Endpoint string `json:"endpoint"`
+
+	// ConfigRef references the plugin-specific configuration the plugin
+	// should use to store connection details.
+	// +optional
+	ConfigRef *ExternalStoreConfigReference `json:"configRef,omitempty"`
+
+	// NameTemplate is a Go template used to render the name connection
+	// details are stored under. The template is executed against the
+	// composite resource. The name is scoped by the composite resource's
+	// namespace. Defaults to the composite resource's name.
+	// +optional
+	NameTemplate *string `json:"nameTemplate,omitempty"`
+}
+
+// An ExternalStoreConfigReference references the configuration of an
+// external secret store plugin.
+type ExternalStoreConfigReference struct {
+	// APIVersion of the referenced config.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the referenced config.
+	Kind string `json:"kind"`
+
+	// Name of the referenced config.
+	Name string `json:"name"`
 }
 
 // A CompositionReference references a Composition.
@@ -22,10 +22,66 @@ package v1
 
 import (
 	commonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposedResourceKind) DeepCopyInto(out *ComposedResourceKind) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedResourceKind.
+func (in *ComposedResourceKind) DeepCopy() *ComposedResourceKind {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposedResourceKind)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposedResourceKindLimit) DeepCopyInto(out *ComposedResourceKindLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedResourceKindLimit.
+func (in *ComposedResourceKindLimit) DeepCopy() *ComposedResourceKindLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposedResourceKindLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposedResourceLimits) DeepCopyInto(out *ComposedResourceLimits) {
+	*out = *in
+	if in.MaxResources != nil {
+		in, out := &in.MaxResources, &out.MaxResources
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]ComposedResourceKindLimit, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedResourceLimits.
+func (in *ComposedResourceLimits) DeepCopy() *ComposedResourceLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposedResourceLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompositeResourceDefinition) DeepCopyInto(out *CompositeResourceDefinition) {
 	*out = *in
@@ -158,6 +214,28 @@ func (in *CompositeResourceDefinitionSpec) DeepCopyInto(out *CompositeResourceDe
 		*out = new(CompositeResourceDefinitionSpecMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ComposedResourceLimits != nil {
+		in, out := &in.ComposedResourceLimits, &out.ComposedResourceLimits
+		*out = new(ComposedResourceLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedComposedResourceKinds != nil {
+		in, out := &in.AllowedComposedResourceKinds, &out.AllowedComposedResourceKinds
+		*out = make([]ComposedResourceKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.Impersonation != nil {
+		in, out := &in.Impersonation, &out.Impersonation
+		*out = new(CompositeResourceImpersonation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConnectionDetailsPublishers != nil {
+		in, out := &in.ConnectionDetailsPublishers, &out.ConnectionDetailsPublishers
+		*out = make([]ConnectionDetailsPublisher, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeResourceDefinitionSpec.
@@ -251,6 +329,26 @@ func (in *CompositeResourceDefinitionVersion) DeepCopy() *CompositeResourceDefin
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositeResourceImpersonation) DeepCopyInto(out *CompositeResourceImpersonation) {
+	*out = *in
+	if in.ServiceAccountNamespace != nil {
+		in, out := &in.ServiceAccountNamespace, &out.ServiceAccountNamespace
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeResourceImpersonation.
+func (in *CompositeResourceImpersonation) DeepCopy() *CompositeResourceImpersonation {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositeResourceImpersonation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompositeResourceValidation) DeepCopyInto(out *CompositeResourceValidation) {
 	*out = *in
@@ -471,6 +569,101 @@ func (in *CompositionSpec) DeepCopy() *CompositionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapConnectionDetailsPublisher) DeepCopyInto(out *ConfigMapConnectionDetailsPublisher) {
+	*out = *in
+	if in.NameTemplate != nil {
+		in, out := &in.NameTemplate, &out.NameTemplate
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapConnectionDetailsPublisher.
+func (in *ConfigMapConnectionDetailsPublisher) DeepCopy() *ConfigMapConnectionDetailsPublisher {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapConnectionDetailsPublisher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionDetailsPublisher) DeepCopyInto(out *ConnectionDetailsPublisher) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SecretConnectionDetailsPublisher)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapConnectionDetailsPublisher)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalConnectionDetailsPublisher)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDetailsPublisher.
+func (in *ConnectionDetailsPublisher) DeepCopy() *ConnectionDetailsPublisher {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionDetailsPublisher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalConnectionDetailsPublisher) DeepCopyInto(out *ExternalConnectionDetailsPublisher) {
+	*out = *in
+	if in.ConfigRef != nil {
+		in, out := &in.ConfigRef, &out.ConfigRef
+		*out = new(ExternalStoreConfigReference)
+		**out = **in
+	}
+	if in.NameTemplate != nil {
+		in, out := &in.NameTemplate, &out.NameTemplate
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalConnectionDetailsPublisher.
+func (in *ExternalConnectionDetailsPublisher) DeepCopy() *ExternalConnectionDetailsPublisher {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalConnectionDetailsPublisher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalStoreConfigReference) DeepCopyInto(out *ExternalStoreConfigReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalStoreConfigReference.
+func (in *ExternalStoreConfigReference) DeepCopy() *ExternalStoreConfigReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalStoreConfigReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FunctionCredentials) DeepCopyInto(out *FunctionCredentials) {
 	*out = *in
@@ -494,6 +687,16 @@ func (in *FunctionCredentials) DeepCopy() *FunctionCredentials {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FunctionReference) DeepCopyInto(out *FunctionReference) {
 	*out = *in
+	if in.Revision != nil {
+		in, out := &in.Revision, &out.Revision
+		*out = new(string)
+		**out = **in
+	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionReference.
@@ -524,7 +727,7 @@ func (in *GeneratedRevisionSpecConverter) DeepCopy() *GeneratedRevisionSpecConve
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineStep) DeepCopyInto(out *PipelineStep) {
 	*out = *in
-	out.FunctionRef = in.FunctionRef
+	in.FunctionRef.DeepCopyInto(&out.FunctionRef)
 	if in.Input != nil {
 		in, out := &in.Input, &out.Input
 		*out = new(runtime.RawExtension)
@@ -549,6 +752,31 @@ func (in *PipelineStep) DeepCopy() *PipelineStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretConnectionDetailsPublisher) DeepCopyInto(out *SecretConnectionDetailsPublisher) {
+	*out = *in
+	if in.NameTemplate != nil {
+		in, out := &in.NameTemplate, &out.NameTemplate
+		*out = new(string)
+		**out = **in
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(corev1.SecretType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretConnectionDetailsPublisher.
+func (in *SecretConnectionDetailsPublisher) DeepCopy() *SecretConnectionDetailsPublisher {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretConnectionDetailsPublisher)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TypeReference) DeepCopyInto(out *TypeReference) {
 	*out = *in
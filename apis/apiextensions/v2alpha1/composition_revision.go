@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// LatestRevision returns the latest revision of the supplied namespaced
+// composition. We use a hash of the labels, the annotations, and the spec to
+// decide to create a new revision. If we revert back to an older state, we
+// increase the existing revision's revision number.
+func LatestRevision(c *NamespacedComposition, revs []NamespacedCompositionRevision) *NamespacedCompositionRevision {
+	// to make sure that we always return a revision controlled by the composition
+	latest := NamespacedCompositionRevision{}
+
+	for i := range revs {
+		if !metav1.IsControlledBy(&revs[i], c) {
+			continue
+		}
+		if latest.Spec.Revision < revs[i].Spec.Revision {
+			latest = revs[i]
+		}
+	}
+
+	// revision numbers start from 1, this means that we have no revision in the list
+	// controlled by the composition
+	if latest.Spec.Revision == 0 {
+		return nil
+	}
+
+	return &latest
+}
+
+// AsCompositionRevision returns a CompositionRevision equivalent to this
+// NamespacedCompositionRevision. The composite resource reconciler only deals
+// in CompositionRevisions, so namespaced revisions are converted to the
+// cluster scoped type before they're used to compose resources. The returned
+// revision retains this revision's namespace.
+func (r *NamespacedCompositionRevision) AsCompositionRevision() *v1.CompositionRevision {
+	rev := &v1.CompositionRevision{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       v1.CompositionRevisionKind,
+		},
+		Spec: v1.CompositionRevisionSpec{
+			CompositeTypeRef: r.Spec.CompositeTypeRef,
+			Mode:             r.Spec.Mode,
+			Revision:         r.Spec.Revision,
+		},
+	}
+	r.ObjectMeta.DeepCopyInto(&rev.ObjectMeta)
+	for i := range r.Spec.Pipeline {
+		rev.Spec.Pipeline = append(rev.Spec.Pipeline, *r.Spec.Pipeline[i].DeepCopy())
+	}
+	r.Status.ConditionedStatus.DeepCopyInto(&rev.Status.ConditionedStatus)
+	return rev
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// NamespacedCompositionRevisionSpec specifies the desired state of the
+// namespaced composition revision.
+type NamespacedCompositionRevisionSpec struct {
+	// CompositeTypeRef specifies the type of composite resource that this
+	// composition is compatible with.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	CompositeTypeRef v1.TypeReference `json:"compositeTypeRef"`
+
+	// Mode controls what type or "mode" of Composition will be used.
+	//
+	// "Pipeline" indicates that a Composition specifies a pipeline of
+	// Composition Functions, each of which is responsible for producing
+	// composed resources that Crossplane should create or update.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=Pipeline
+	// +kubebuilder:default=Pipeline
+	Mode v1.CompositionMode `json:"mode,omitempty"`
+
+	// Pipeline is a list of composition function steps that will be used when a
+	// composite resource referring to this composition is created.
+	// +optional
+	// +listType=map
+	// +listMapKey=step
+	Pipeline []v1.PipelineStep `json:"pipeline,omitempty"`
+
+	// Revision number. Newer revisions have larger numbers.
+	//
+	// This number can change. When a NamespacedComposition transitions from
+	// state A -> B -> A there will be only two NamespacedCompositionRevisions.
+	// Crossplane will edit the original NamespacedCompositionRevision to change
+	// its revision number from 0 to 2.
+	Revision int64 `json:"revision"`
+}
+
+// NamespacedCompositionRevisionStatus shows the observed state of the
+// namespaced composition revision.
+type NamespacedCompositionRevisionStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+// +genclient
+
+// A NamespacedCompositionRevision represents a revision of a
+// NamespacedComposition. Crossplane creates new revisions when there are
+// changes to the NamespacedComposition.
+//
+// Crossplane creates and manages NamespacedCompositionRevisions. Don't directly
+// edit NamespacedCompositionRevisions.
+// +kubebuilder:printcolumn:name="REVISION",type="string",JSONPath=".spec.revision"
+// +kubebuilder:printcolumn:name="XR-KIND",type="string",JSONPath=".spec.compositeTypeRef.kind"
+// +kubebuilder:printcolumn:name="XR-APIVERSION",type="string",JSONPath=".spec.compositeTypeRef.apiVersion"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories=crossplane,shortName=nscomprev
+// +kubebuilder:subresource:status
+type NamespacedCompositionRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NamespacedCompositionRevisionSpec   `json:"spec,omitempty"`
+	Status NamespacedCompositionRevisionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NamespacedCompositionRevisionList contains a list of
+// NamespacedCompositionRevisions.
+type NamespacedCompositionRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespacedCompositionRevision `json:"items"`
+}
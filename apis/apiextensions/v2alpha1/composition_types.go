@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// NamespacedCompositionSpec specifies desired state of a namespaced
+// composition.
+//
+// +kubebuilder:validation:XValidation:rule="self.mode == 'Pipeline' && has(self.pipeline)",message="an array of pipeline steps is required in Pipeline mode"
+type NamespacedCompositionSpec struct {
+	// CompositeTypeRef specifies the type of composite resource that this
+	// composition is compatible with. The type must be a namespaced composite
+	// resource.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	CompositeTypeRef v1.TypeReference `json:"compositeTypeRef"`
+
+	// Mode controls what type or "mode" of Composition will be used.
+	//
+	// "Pipeline" indicates that a Composition specifies a pipeline of
+	// Composition Functions, each of which is responsible for producing
+	// composed resources that Crossplane should create or update.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=Pipeline
+	// +kubebuilder:default=Pipeline
+	Mode v1.CompositionMode `json:"mode,omitempty"`
+
+	// Pipeline is a list of composition function steps that will be used when a
+	// composite resource referring to this composition is created.
+	//
+	// Function credentials may only reference Secrets in the namespace of the
+	// NamespacedComposition.
+	// +optional
+	// +listType=map
+	// +listMapKey=step
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=99
+	Pipeline []v1.PipelineStep `json:"pipeline,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +genclient
+
+// A NamespacedComposition is a Composition that exists within a namespace. Only
+// namespaced composite resources in the same namespace may use it. This allows
+// tenants to author their own Compositions without cluster-wide permissions.
+// Because Crossplane composes resources using its own identity, composite
+// resources may only use a NamespacedComposition when their namespace either
+// configures a service account to apply composed resources as, or restricts
+// the kinds of resource they may compose using the
+// apiextensions.crossplane.io/allowed-composed-resource-kinds annotation.
+// +kubebuilder:printcolumn:name="XR-KIND",type="string",JSONPath=".spec.compositeTypeRef.kind"
+// +kubebuilder:printcolumn:name="XR-APIVERSION",type="string",JSONPath=".spec.compositeTypeRef.apiVersion"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories=crossplane,shortName=nscomp
+type NamespacedComposition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NamespacedCompositionSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NamespacedCompositionList contains a list of NamespacedCompositions.
+type NamespacedCompositionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespacedComposition `json:"items"`
+}
@@ -48,6 +48,24 @@ var (
 	CompositeResourceDefinitionGroupVersionKind = SchemeGroupVersion.WithKind(CompositeResourceDefinitionKind)
 )
 
+// NamespacedComposition type metadata.
+var (
+	NamespacedCompositionKind             = reflect.TypeOf(NamespacedComposition{}).Name()
+	NamespacedCompositionGroupKind        = schema.GroupKind{Group: Group, Kind: NamespacedCompositionKind}.String()
+	NamespacedCompositionKindAPIVersion   = NamespacedCompositionKind + "." + SchemeGroupVersion.String()
+	NamespacedCompositionGroupVersionKind = SchemeGroupVersion.WithKind(NamespacedCompositionKind)
+)
+
+// NamespacedCompositionRevision type metadata.
+var (
+	NamespacedCompositionRevisionKind             = reflect.TypeOf(NamespacedCompositionRevision{}).Name()
+	NamespacedCompositionRevisionGroupKind        = schema.GroupKind{Group: Group, Kind: NamespacedCompositionRevisionKind}.String()
+	NamespacedCompositionRevisionKindAPIVersion   = NamespacedCompositionRevisionKind + "." + SchemeGroupVersion.String()
+	NamespacedCompositionRevisionGroupVersionKind = SchemeGroupVersion.WithKind(NamespacedCompositionRevisionKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&CompositeResourceDefinition{}, &CompositeResourceDefinitionList{})
+	SchemeBuilder.Register(&NamespacedComposition{}, &NamespacedCompositionList{})
+	SchemeBuilder.Register(&NamespacedCompositionRevision{}, &NamespacedCompositionRevisionList{})
 }
@@ -21,11 +21,68 @@ limitations under the License.
 package v2alpha1
 
 import (
-	"github.com/crossplane/crossplane-runtime/apis/common/v1"
+	commonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposedResourceKind) DeepCopyInto(out *ComposedResourceKind) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedResourceKind.
+func (in *ComposedResourceKind) DeepCopy() *ComposedResourceKind {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposedResourceKind)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposedResourceKindLimit) DeepCopyInto(out *ComposedResourceKindLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedResourceKindLimit.
+func (in *ComposedResourceKindLimit) DeepCopy() *ComposedResourceKindLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposedResourceKindLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposedResourceLimits) DeepCopyInto(out *ComposedResourceLimits) {
+	*out = *in
+	if in.MaxResources != nil {
+		in, out := &in.MaxResources, &out.MaxResources
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]ComposedResourceKindLimit, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedResourceLimits.
+func (in *ComposedResourceLimits) DeepCopy() *ComposedResourceLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposedResourceLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompositeResourceDefinition) DeepCopyInto(out *CompositeResourceDefinition) {
 	*out = *in
@@ -118,7 +175,7 @@ func (in *CompositeResourceDefinitionSpec) DeepCopyInto(out *CompositeResourceDe
 	}
 	if in.DefaultCompositionUpdatePolicy != nil {
 		in, out := &in.DefaultCompositionUpdatePolicy, &out.DefaultCompositionUpdatePolicy
-		*out = new(v1.UpdatePolicy)
+		*out = new(commonv1.UpdatePolicy)
 		**out = **in
 	}
 	if in.Versions != nil {
@@ -138,6 +195,28 @@ func (in *CompositeResourceDefinitionSpec) DeepCopyInto(out *CompositeResourceDe
 		*out = new(CompositeResourceDefinitionSpecMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ComposedResourceLimits != nil {
+		in, out := &in.ComposedResourceLimits, &out.ComposedResourceLimits
+		*out = new(ComposedResourceLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedComposedResourceKinds != nil {
+		in, out := &in.AllowedComposedResourceKinds, &out.AllowedComposedResourceKinds
+		*out = make([]ComposedResourceKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.Impersonation != nil {
+		in, out := &in.Impersonation, &out.Impersonation
+		*out = new(CompositeResourceImpersonation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConnectionDetailsPublishers != nil {
+		in, out := &in.ConnectionDetailsPublishers, &out.ConnectionDetailsPublishers
+		*out = make([]ConnectionDetailsPublisher, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ClaimNames != nil {
 		in, out := &in.ClaimNames, &out.ClaimNames
 		*out = new(apiextensionsv1.CustomResourceDefinitionNames)
@@ -145,7 +224,7 @@ func (in *CompositeResourceDefinitionSpec) DeepCopyInto(out *CompositeResourceDe
 	}
 	if in.DefaultCompositeDeletePolicy != nil {
 		in, out := &in.DefaultCompositeDeletePolicy, &out.DefaultCompositeDeletePolicy
-		*out = new(v1.CompositeDeletePolicy)
+		*out = new(commonv1.CompositeDeletePolicy)
 		**out = **in
 	}
 	if in.ConnectionSecretKeys != nil {
@@ -246,6 +325,26 @@ func (in *CompositeResourceDefinitionVersion) DeepCopy() *CompositeResourceDefin
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositeResourceImpersonation) DeepCopyInto(out *CompositeResourceImpersonation) {
+	*out = *in
+	if in.ServiceAccountNamespace != nil {
+		in, out := &in.ServiceAccountNamespace, &out.ServiceAccountNamespace
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeResourceImpersonation.
+func (in *CompositeResourceImpersonation) DeepCopy() *CompositeResourceImpersonation {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositeResourceImpersonation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompositeResourceValidation) DeepCopyInto(out *CompositeResourceValidation) {
 	*out = *in
@@ -277,6 +376,305 @@ func (in *CompositionReference) DeepCopy() *CompositionReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapConnectionDetailsPublisher) DeepCopyInto(out *ConfigMapConnectionDetailsPublisher) {
+	*out = *in
+	if in.NameTemplate != nil {
+		in, out := &in.NameTemplate, &out.NameTemplate
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapConnectionDetailsPublisher.
+func (in *ConfigMapConnectionDetailsPublisher) DeepCopy() *ConfigMapConnectionDetailsPublisher {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapConnectionDetailsPublisher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionDetailsPublisher) DeepCopyInto(out *ConnectionDetailsPublisher) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SecretConnectionDetailsPublisher)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapConnectionDetailsPublisher)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalConnectionDetailsPublisher)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDetailsPublisher.
+func (in *ConnectionDetailsPublisher) DeepCopy() *ConnectionDetailsPublisher {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionDetailsPublisher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalConnectionDetailsPublisher) DeepCopyInto(out *ExternalConnectionDetailsPublisher) {
+	*out = *in
+	if in.ConfigRef != nil {
+		in, out := &in.ConfigRef, &out.ConfigRef
+		*out = new(ExternalStoreConfigReference)
+		**out = **in
+	}
+	if in.NameTemplate != nil {
+		in, out := &in.NameTemplate, &out.NameTemplate
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalConnectionDetailsPublisher.
+func (in *ExternalConnectionDetailsPublisher) DeepCopy() *ExternalConnectionDetailsPublisher {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalConnectionDetailsPublisher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalStoreConfigReference) DeepCopyInto(out *ExternalStoreConfigReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalStoreConfigReference.
+func (in *ExternalStoreConfigReference) DeepCopy() *ExternalStoreConfigReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalStoreConfigReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedComposition) DeepCopyInto(out *NamespacedComposition) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedComposition.
+func (in *NamespacedComposition) DeepCopy() *NamespacedComposition {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedComposition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedComposition) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedCompositionList) DeepCopyInto(out *NamespacedCompositionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespacedComposition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedCompositionList.
+func (in *NamespacedCompositionList) DeepCopy() *NamespacedCompositionList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedCompositionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedCompositionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedCompositionRevision) DeepCopyInto(out *NamespacedCompositionRevision) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedCompositionRevision.
+func (in *NamespacedCompositionRevision) DeepCopy() *NamespacedCompositionRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedCompositionRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedCompositionRevision) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedCompositionRevisionList) DeepCopyInto(out *NamespacedCompositionRevisionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespacedCompositionRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedCompositionRevisionList.
+func (in *NamespacedCompositionRevisionList) DeepCopy() *NamespacedCompositionRevisionList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedCompositionRevisionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedCompositionRevisionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedCompositionRevisionSpec) DeepCopyInto(out *NamespacedCompositionRevisionSpec) {
+	*out = *in
+	out.CompositeTypeRef = in.CompositeTypeRef
+	if in.Pipeline != nil {
+		in, out := &in.Pipeline, &out.Pipeline
+		*out = make([]v1.PipelineStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedCompositionRevisionSpec.
+func (in *NamespacedCompositionRevisionSpec) DeepCopy() *NamespacedCompositionRevisionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedCompositionRevisionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedCompositionRevisionStatus) DeepCopyInto(out *NamespacedCompositionRevisionStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedCompositionRevisionStatus.
+func (in *NamespacedCompositionRevisionStatus) DeepCopy() *NamespacedCompositionRevisionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedCompositionRevisionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedCompositionSpec) DeepCopyInto(out *NamespacedCompositionSpec) {
+	*out = *in
+	out.CompositeTypeRef = in.CompositeTypeRef
+	if in.Pipeline != nil {
+		in, out := &in.Pipeline, &out.Pipeline
+		*out = make([]v1.PipelineStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedCompositionSpec.
+func (in *NamespacedCompositionSpec) DeepCopy() *NamespacedCompositionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedCompositionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretConnectionDetailsPublisher) DeepCopyInto(out *SecretConnectionDetailsPublisher) {
+	*out = *in
+	if in.NameTemplate != nil {
+		in, out := &in.NameTemplate, &out.NameTemplate
+		*out = new(string)
+		**out = **in
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(corev1.SecretType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretConnectionDetailsPublisher.
+func (in *SecretConnectionDetailsPublisher) DeepCopy() *SecretConnectionDetailsPublisher {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretConnectionDetailsPublisher)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TypeReference) DeepCopyInto(out *TypeReference) {
 	*out = *in
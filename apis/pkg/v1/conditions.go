@@ -42,6 +42,10 @@ const (
 	// A TypeVerified indicates whether a package's signature is verified.
 	// It could be either successful or skipped to be marked as complete.
 	TypeVerified xpv1.ConditionType = "Verified"
+
+	// A TypeDigestUnchanged indicates whether the digest a package's tag
+	// resolves to is unchanged since the package was installed.
+	TypeDigestUnchanged xpv1.ConditionType = "DigestUnchanged"
 )
 
 // Reasons a package is or is not installed.
@@ -53,6 +57,8 @@ const (
 	ReasonUnhealthy            xpv1.ConditionReason = "UnhealthyPackageRevision"
 	ReasonHealthy              xpv1.ConditionReason = "HealthyPackageRevision"
 	ReasonUnknownHealth        xpv1.ConditionReason = "UnknownPackageRevisionHealth"
+	ReasonUnsafeCRDUpgrade     xpv1.ConditionReason = "UnsafeCRDUpgrade"
+	ReasonRolledBack           xpv1.ConditionReason = "RolledBackPackageRevision"
 )
 
 // Reasons a package's signature is or is not verified.
@@ -72,6 +78,14 @@ const (
 	ReasonVerificationFailed xpv1.ConditionReason = "SignatureVerificationFailed"
 )
 
+// Reasons a package's digest is or is not unchanged.
+const (
+	ReasonDigestUnchanged        xpv1.ConditionReason = "DigestUnchanged"
+	ReasonDigestChanged          xpv1.ConditionReason = "TagDigestChanged"
+	ReasonAwaitingDigestApproval xpv1.ConditionReason = "AwaitingDigestApproval"
+	ReasonDigestApproved         xpv1.ConditionReason = "DigestApproved"
+)
+
 // Unpacking indicates that the package manager is waiting for a package
 // revision to be unpacked.
 func Unpacking() xpv1.Condition {
@@ -105,6 +119,67 @@ func Active() xpv1.Condition {
 	}
 }
 
+// RolledBack indicates that the package manager re-activated the supplied
+// previous revision because the current revision didn't become healthy.
+func RolledBack(current, previous string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRolledBack,
+		Message:            fmt.Sprintf("Package revision %s did not become healthy in time; re-activated package revision %s", current, previous),
+	}
+}
+
+// DigestUnchanged indicates that the digest a package's tag resolves to hasn't
+// changed since the package's source last changed, or that the package uses
+// the revision for its tag's new digest.
+func DigestUnchanged() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDigestUnchanged,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDigestUnchanged,
+	}
+}
+
+// DigestChanged indicates that a package's tag now resolves to the supplied
+// new digest, and that the package manager produced a revision for it.
+func DigestChanged(previous, current string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDigestUnchanged,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDigestChanged,
+		Message:            fmt.Sprintf("Package tag digest changed from %s to %s", previous, current),
+	}
+}
+
+// AwaitingDigestApproval indicates that a package's tag now resolves to the
+// supplied new digest, but the package manager keeps using the previous digest
+// until the new one is approved.
+func AwaitingDigestApproval(previous, current string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDigestUnchanged,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAwaitingDigestApproval,
+		Message:            fmt.Sprintf("Package tag digest changed from %s to %s; set spec.digestPolicy.approvedDigest to %s to use it", previous, current, current),
+	}
+}
+
+// DigestApproved indicates that the package manager produced a revision for
+// the supplied approved digest of a package's tag.
+func DigestApproved(current string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDigestUnchanged,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDigestApproved,
+		Message:            fmt.Sprintf("Package tag digest %s was approved", current),
+	}
+}
+
 // Unhealthy indicates that the current revision is unhealthy.
 func Unhealthy() xpv1.Condition {
 	return xpv1.Condition{
@@ -156,6 +231,19 @@ func RevisionUnhealthy() xpv1.Condition {
 	}
 }
 
+// RevisionUnsafeCRDUpgrade indicates that the current package revision is
+// unhealthy because updating one of its CRDs could strand existing custom
+// resources.
+func RevisionUnsafeCRDUpgrade(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRevisionHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUnsafeCRDUpgrade,
+		Message:            err.Error(),
+	}
+}
+
 // RevisionHealthy indicates that the current package revision is healthy.
 func RevisionHealthy() xpv1.Condition {
 	return xpv1.Condition{
@@ -20,6 +20,7 @@ import (
 	"slices"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -156,6 +157,33 @@ type Package interface { //nolint:interfacebloat // TODO(negz): Could we break t
 
 	GetResolvedSource() string
 	SetResolvedSource(s string)
+
+	GetUpdatePolicy() *UpdatePolicy
+	SetUpdatePolicy(u *UpdatePolicy)
+
+	GetUpdateStatus() *UpdateStatus
+	SetUpdateStatus(s *UpdateStatus)
+
+	GetRollbackPolicy() *RollbackPolicy
+	SetRollbackPolicy(r *RollbackPolicy)
+
+	GetLastHealthyRevision() string
+	SetLastHealthyRevision(r string)
+
+	GetRolledBackRevision() string
+	SetRolledBackRevision(r string)
+
+	GetRevisionActivationTime() *metav1.Time
+	SetRevisionActivationTime(t *metav1.Time)
+
+	GetDigestPolicy() *DigestPolicy
+	SetDigestPolicy(d *DigestPolicy)
+
+	GetResolvedDigest() string
+	SetResolvedDigest(d string)
+
+	GetPendingDigest() string
+	SetPendingDigest(d string)
 }
 
 // GetCondition of this Provider.
@@ -318,6 +346,96 @@ func (p *Provider) SetResolvedSource(s string) {
 	p.Status.ResolvedPackage = s
 }
 
+// GetUpdatePolicy of this Provider.
+func (p *Provider) GetUpdatePolicy() *UpdatePolicy {
+	return p.Spec.UpdatePolicy
+}
+
+// SetUpdatePolicy of this Provider.
+func (p *Provider) SetUpdatePolicy(u *UpdatePolicy) {
+	p.Spec.UpdatePolicy = u
+}
+
+// GetUpdateStatus of this Provider.
+func (p *Provider) GetUpdateStatus() *UpdateStatus {
+	return p.Status.Update
+}
+
+// SetUpdateStatus of this Provider.
+func (p *Provider) SetUpdateStatus(s *UpdateStatus) {
+	p.Status.Update = s
+}
+
+// GetRollbackPolicy of this Provider.
+func (p *Provider) GetRollbackPolicy() *RollbackPolicy {
+	return p.Spec.RollbackPolicy
+}
+
+// SetRollbackPolicy of this Provider.
+func (p *Provider) SetRollbackPolicy(r *RollbackPolicy) {
+	p.Spec.RollbackPolicy = r
+}
+
+// GetLastHealthyRevision of this Provider.
+func (p *Provider) GetLastHealthyRevision() string {
+	return p.Status.LastHealthyRevision
+}
+
+// SetLastHealthyRevision of this Provider.
+func (p *Provider) SetLastHealthyRevision(r string) {
+	p.Status.LastHealthyRevision = r
+}
+
+// GetRolledBackRevision of this Provider.
+func (p *Provider) GetRolledBackRevision() string {
+	return p.Status.RolledBackRevision
+}
+
+// SetRolledBackRevision of this Provider.
+func (p *Provider) SetRolledBackRevision(r string) {
+	p.Status.RolledBackRevision = r
+}
+
+// GetRevisionActivationTime of this Provider.
+func (p *Provider) GetRevisionActivationTime() *metav1.Time {
+	return p.Status.RevisionActivationTime
+}
+
+// SetRevisionActivationTime of this Provider.
+func (p *Provider) SetRevisionActivationTime(t *metav1.Time) {
+	p.Status.RevisionActivationTime = t
+}
+
+// GetDigestPolicy of this Provider.
+func (p *Provider) GetDigestPolicy() *DigestPolicy {
+	return p.Spec.DigestPolicy
+}
+
+// SetDigestPolicy of this Provider.
+func (p *Provider) SetDigestPolicy(d *DigestPolicy) {
+	p.Spec.DigestPolicy = d
+}
+
+// GetResolvedDigest of this Provider.
+func (p *Provider) GetResolvedDigest() string {
+	return p.Status.ResolvedDigest
+}
+
+// SetResolvedDigest of this Provider.
+func (p *Provider) SetResolvedDigest(d string) {
+	p.Status.ResolvedDigest = d
+}
+
+// GetPendingDigest of this Provider.
+func (p *Provider) GetPendingDigest() string {
+	return p.Status.PendingDigest
+}
+
+// SetPendingDigest of this Provider.
+func (p *Provider) SetPendingDigest(d string) {
+	p.Status.PendingDigest = d
+}
+
 // GetCondition of this Configuration.
 func (p *Configuration) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return p.Status.GetCondition(ct)
@@ -458,6 +576,96 @@ func (p *Configuration) SetResolvedSource(s string) {
 	p.Status.ResolvedPackage = s
 }
 
+// GetUpdatePolicy of this Configuration.
+func (p *Configuration) GetUpdatePolicy() *UpdatePolicy {
+	return p.Spec.UpdatePolicy
+}
+
+// SetUpdatePolicy of this Configuration.
+func (p *Configuration) SetUpdatePolicy(u *UpdatePolicy) {
+	p.Spec.UpdatePolicy = u
+}
+
+// GetUpdateStatus of this Configuration.
+func (p *Configuration) GetUpdateStatus() *UpdateStatus {
+	return p.Status.Update
+}
+
+// SetUpdateStatus of this Configuration.
+func (p *Configuration) SetUpdateStatus(s *UpdateStatus) {
+	p.Status.Update = s
+}
+
+// GetRollbackPolicy of this Configuration.
+func (p *Configuration) GetRollbackPolicy() *RollbackPolicy {
+	return p.Spec.RollbackPolicy
+}
+
+// SetRollbackPolicy of this Configuration.
+func (p *Configuration) SetRollbackPolicy(r *RollbackPolicy) {
+	p.Spec.RollbackPolicy = r
+}
+
+// GetLastHealthyRevision of this Configuration.
+func (p *Configuration) GetLastHealthyRevision() string {
+	return p.Status.LastHealthyRevision
+}
+
+// SetLastHealthyRevision of this Configuration.
+func (p *Configuration) SetLastHealthyRevision(r string) {
+	p.Status.LastHealthyRevision = r
+}
+
+// GetRolledBackRevision of this Configuration.
+func (p *Configuration) GetRolledBackRevision() string {
+	return p.Status.RolledBackRevision
+}
+
+// SetRolledBackRevision of this Configuration.
+func (p *Configuration) SetRolledBackRevision(r string) {
+	p.Status.RolledBackRevision = r
+}
+
+// GetRevisionActivationTime of this Configuration.
+func (p *Configuration) GetRevisionActivationTime() *metav1.Time {
+	return p.Status.RevisionActivationTime
+}
+
+// SetRevisionActivationTime of this Configuration.
+func (p *Configuration) SetRevisionActivationTime(t *metav1.Time) {
+	p.Status.RevisionActivationTime = t
+}
+
+// GetDigestPolicy of this Configuration.
+func (p *Configuration) GetDigestPolicy() *DigestPolicy {
+	return p.Spec.DigestPolicy
+}
+
+// SetDigestPolicy of this Configuration.
+func (p *Configuration) SetDigestPolicy(d *DigestPolicy) {
+	p.Spec.DigestPolicy = d
+}
+
+// GetResolvedDigest of this Configuration.
+func (p *Configuration) GetResolvedDigest() string {
+	return p.Status.ResolvedDigest
+}
+
+// SetResolvedDigest of this Configuration.
+func (p *Configuration) SetResolvedDigest(d string) {
+	p.Status.ResolvedDigest = d
+}
+
+// GetPendingDigest of this Configuration.
+func (p *Configuration) GetPendingDigest() string {
+	return p.Status.PendingDigest
+}
+
+// SetPendingDigest of this Configuration.
+func (p *Configuration) SetPendingDigest(d string) {
+	p.Status.PendingDigest = d
+}
+
 // PackageRevisionWithRuntime is the interface satisfied by revision of packages
 // with runtime types.
 // +k8s:deepcopy-gen=false
@@ -519,6 +727,9 @@ type PackageRevision interface { //nolint:interfacebloat // TODO(negz): Could we
 	GetSource() string
 	SetSource(s string)
 
+	GetDigest() string
+	SetDigest(d string)
+
 	GetPackagePullSecrets() []corev1.LocalObjectReference
 	SetPackagePullSecrets(s []corev1.LocalObjectReference)
 
@@ -549,6 +760,12 @@ type PackageRevision interface { //nolint:interfacebloat // TODO(negz): Could we
 
 	GetResolvedSource() string
 	SetResolvedSource(s string)
+
+	GetAttestationResults() []AttestationResult
+	SetAttestationResults(res []AttestationResult)
+
+	GetImageDigest() string
+	SetImageDigest(d string)
 }
 
 // GetCondition of this ProviderRevision.
@@ -586,6 +803,16 @@ func (p *ProviderRevision) SetSource(s string) {
 	p.Spec.Package = s
 }
 
+// GetDigest of this ProviderRevision.
+func (p *ProviderRevision) GetDigest() string {
+	return p.Spec.Digest
+}
+
+// SetDigest of this ProviderRevision.
+func (p *ProviderRevision) SetDigest(d string) {
+	p.Spec.Digest = d
+}
+
 // GetPackagePullSecrets of this ProviderRevision.
 func (p *ProviderRevision) GetPackagePullSecrets() []corev1.LocalObjectReference {
 	return p.Spec.PackagePullSecrets
@@ -723,6 +950,26 @@ func (p *ProviderRevision) SetResolvedSource(s string) {
 	p.Status.ResolvedPackage = s
 }
 
+// GetAttestationResults of this ProviderRevision.
+func (p *ProviderRevision) GetAttestationResults() []AttestationResult {
+	return p.Status.AttestationResults
+}
+
+// SetAttestationResults of this ProviderRevision.
+func (p *ProviderRevision) SetAttestationResults(res []AttestationResult) {
+	p.Status.AttestationResults = res
+}
+
+// GetImageDigest of this ProviderRevision.
+func (p *ProviderRevision) GetImageDigest() string {
+	return p.Status.ImageDigest
+}
+
+// SetImageDigest of this ProviderRevision.
+func (p *ProviderRevision) SetImageDigest(d string) {
+	p.Status.ImageDigest = d
+}
+
 // GetCondition of this ConfigurationRevision.
 func (p *ConfigurationRevision) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return p.Status.GetCondition(ct)
@@ -758,6 +1005,16 @@ func (p *ConfigurationRevision) SetSource(s string) {
 	p.Spec.Package = s
 }
 
+// GetDigest of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetDigest() string {
+	return p.Spec.Digest
+}
+
+// SetDigest of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetDigest(d string) {
+	p.Spec.Digest = d
+}
+
 // GetPackagePullSecrets of this ConfigurationRevision.
 func (p *ConfigurationRevision) GetPackagePullSecrets() []corev1.LocalObjectReference {
 	return p.Spec.PackagePullSecrets
@@ -865,6 +1122,26 @@ func (p *ConfigurationRevision) SetResolvedSource(s string) {
 	p.Status.ResolvedPackage = s
 }
 
+// GetAttestationResults of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetAttestationResults() []AttestationResult {
+	return p.Status.AttestationResults
+}
+
+// SetAttestationResults of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetAttestationResults(res []AttestationResult) {
+	p.Status.AttestationResults = res
+}
+
+// GetImageDigest of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetImageDigest() string {
+	return p.Status.ImageDigest
+}
+
+// SetImageDigest of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetImageDigest(d string) {
+	p.Status.ImageDigest = d
+}
+
 // PackageRevisionList is the interface satisfied by package revision list
 // types.
 // +k8s:deepcopy-gen=false
@@ -1078,6 +1355,96 @@ func (f *Function) SetResolvedSource(s string) {
 	f.Status.ResolvedPackage = s
 }
 
+// GetUpdatePolicy of this Function.
+func (f *Function) GetUpdatePolicy() *UpdatePolicy {
+	return f.Spec.UpdatePolicy
+}
+
+// SetUpdatePolicy of this Function.
+func (f *Function) SetUpdatePolicy(u *UpdatePolicy) {
+	f.Spec.UpdatePolicy = u
+}
+
+// GetUpdateStatus of this Function.
+func (f *Function) GetUpdateStatus() *UpdateStatus {
+	return f.Status.Update
+}
+
+// SetUpdateStatus of this Function.
+func (f *Function) SetUpdateStatus(s *UpdateStatus) {
+	f.Status.Update = s
+}
+
+// GetRollbackPolicy of this Function.
+func (f *Function) GetRollbackPolicy() *RollbackPolicy {
+	return f.Spec.RollbackPolicy
+}
+
+// SetRollbackPolicy of this Function.
+func (f *Function) SetRollbackPolicy(r *RollbackPolicy) {
+	f.Spec.RollbackPolicy = r
+}
+
+// GetLastHealthyRevision of this Function.
+func (f *Function) GetLastHealthyRevision() string {
+	return f.Status.LastHealthyRevision
+}
+
+// SetLastHealthyRevision of this Function.
+func (f *Function) SetLastHealthyRevision(r string) {
+	f.Status.LastHealthyRevision = r
+}
+
+// GetRolledBackRevision of this Function.
+func (f *Function) GetRolledBackRevision() string {
+	return f.Status.RolledBackRevision
+}
+
+// SetRolledBackRevision of this Function.
+func (f *Function) SetRolledBackRevision(r string) {
+	f.Status.RolledBackRevision = r
+}
+
+// GetRevisionActivationTime of this Function.
+func (f *Function) GetRevisionActivationTime() *metav1.Time {
+	return f.Status.RevisionActivationTime
+}
+
+// SetRevisionActivationTime of this Function.
+func (f *Function) SetRevisionActivationTime(t *metav1.Time) {
+	f.Status.RevisionActivationTime = t
+}
+
+// GetDigestPolicy of this Function.
+func (f *Function) GetDigestPolicy() *DigestPolicy {
+	return f.Spec.DigestPolicy
+}
+
+// SetDigestPolicy of this Function.
+func (f *Function) SetDigestPolicy(d *DigestPolicy) {
+	f.Spec.DigestPolicy = d
+}
+
+// GetResolvedDigest of this Function.
+func (f *Function) GetResolvedDigest() string {
+	return f.Status.ResolvedDigest
+}
+
+// SetResolvedDigest of this Function.
+func (f *Function) SetResolvedDigest(d string) {
+	f.Status.ResolvedDigest = d
+}
+
+// GetPendingDigest of this Function.
+func (f *Function) GetPendingDigest() string {
+	return f.Status.PendingDigest
+}
+
+// SetPendingDigest of this Function.
+func (f *Function) SetPendingDigest(d string) {
+	f.Status.PendingDigest = d
+}
+
 // GetCondition of this FunctionRevision.
 func (r *FunctionRevision) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return r.Status.GetCondition(ct)
@@ -1113,6 +1480,16 @@ func (r *FunctionRevision) SetSource(s string) {
 	r.Spec.Package = s
 }
 
+// GetDigest of this FunctionRevision.
+func (r *FunctionRevision) GetDigest() string {
+	return r.Spec.Digest
+}
+
+// SetDigest of this FunctionRevision.
+func (r *FunctionRevision) SetDigest(d string) {
+	r.Spec.Digest = d
+}
+
 // GetPackagePullSecrets of this FunctionRevision.
 func (r *FunctionRevision) GetPackagePullSecrets() []corev1.LocalObjectReference {
 	return r.Spec.PackagePullSecrets
@@ -1250,6 +1627,26 @@ func (r *FunctionRevision) SetResolvedSource(s string) {
 	r.Status.ResolvedPackage = s
 }
 
+// GetAttestationResults of this FunctionRevision.
+func (r *FunctionRevision) GetAttestationResults() []AttestationResult {
+	return r.Status.AttestationResults
+}
+
+// SetAttestationResults of this FunctionRevision.
+func (r *FunctionRevision) SetAttestationResults(res []AttestationResult) {
+	r.Status.AttestationResults = res
+}
+
+// GetImageDigest of this FunctionRevision.
+func (r *FunctionRevision) GetImageDigest() string {
+	return r.Status.ImageDigest
+}
+
+// SetImageDigest of this FunctionRevision.
+func (r *FunctionRevision) SetImageDigest(d string) {
+	r.Status.ImageDigest = d
+}
+
 // GetRevisions of this ConfigurationRevisionList.
 func (p *FunctionRevisionList) GetRevisions() []PackageRevision {
 	prs := make([]PackageRevision, len(p.Items))
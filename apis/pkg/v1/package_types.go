@@ -16,7 +16,10 @@ limitations under the License.
 
 package v1
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // RevisionActivationPolicy indicates how a package should activate its
 // revisions.
@@ -73,6 +76,128 @@ type PackageSpec struct {
 	// More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/
 	// +optional
 	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+
+	// UpdatePolicy configures the package manager to automatically update the
+	// package to the newest version that satisfies a channel. This is an alpha
+	// feature, and requires the --enable-automatic-package-updates flag. Use a
+	// rollback policy to roll back updates that don't become healthy.
+	// +optional
+	UpdatePolicy *UpdatePolicy `json:"updatePolicy,omitempty"`
+
+	// RollbackPolicy configures the package manager to re-activate the last
+	// healthy revision of the package if the active revision doesn't become
+	// healthy in time. It only applies to packages that use the Automatic
+	// revision activation policy. This is an alpha feature, and requires the
+	// --enable-package-rollbacks flag.
+	// +optional
+	RollbackPolicy *RollbackPolicy `json:"rollbackPolicy,omitempty"`
+
+	// DigestPolicy configures how the package manager handles the digest
+	// spec.package resolves to changing, for example because its tag was
+	// pushed again.
+	// +optional
+	DigestPolicy *DigestPolicy `json:"digestPolicy,omitempty"`
+}
+
+// A DigestPolicyMode determines how the package manager handles the digest of
+// a package's tag changing.
+type DigestPolicyMode string
+
+// Digest policy modes.
+const (
+	// DigestPolicyTrack creates a new package revision when the digest of a
+	// package's tag changes.
+	DigestPolicyTrack DigestPolicyMode = "Track"
+
+	// DigestPolicyPin keeps the package pinned to the digest its tag resolved
+	// to when the package was installed. The package manager only creates a
+	// revision for a new digest once it's approved.
+	DigestPolicyPin DigestPolicyMode = "Pin"
+)
+
+// A DigestPolicy configures how the package manager handles the digest of a
+// package's tag changing.
+type DigestPolicy struct {
+	// Mode is either Track or Pin. Track creates a new package revision when
+	// the digest of the package's tag changes. Pin keeps the package pinned
+	// to its resolved digest until a new digest is approved. The package
+	// manager emits an event and sets the DigestUnchanged condition when the
+	// digest of the package's tag changes in either mode. In Track mode the
+	// condition is cleared once the package uses the new digest's revision.
+	// +optional
+	// +kubebuilder:validation:Enum=Track;Pin
+	// +kubebuilder:default=Track
+	Mode *DigestPolicyMode `json:"mode,omitempty"`
+
+	// ApprovedDigest approves a new digest for a pinned package, for example
+	// sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d974f8ef5d4f6f2.
+	// The package manager creates a revision for the new digest once it
+	// matches status.pendingDigest.
+	// +optional
+	ApprovedDigest *string `json:"approvedDigest,omitempty"`
+}
+
+// A RollbackPolicy configures automatic rollback of package revisions that
+// don't become healthy.
+type RollbackPolicy struct {
+	// Timeout is how long a revision has to become healthy after the package
+	// manager activates it before the package manager re-activates the last
+	// healthy revision.
+	// +optional
+	// +kubebuilder:default="10m"
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// An UpdateSchedule specifies how often the package manager checks for a
+// package update.
+type UpdateSchedule string
+
+// Update schedules.
+const (
+	UpdateScheduleHourly UpdateSchedule = "Hourly"
+	UpdateScheduleDaily  UpdateSchedule = "Daily"
+	UpdateScheduleWeekly UpdateSchedule = "Weekly"
+)
+
+// An UpdatePolicy configures automatic updates of a package.
+type UpdatePolicy struct {
+	// Channel is a semantic version constraint, for example ^1.4. The package
+	// manager updates the package to the newest version tagged in its
+	// repository that satisfies the constraint. It never updates the package
+	// to an older version.
+	Channel string `json:"channel"`
+
+	// Schedule specifies how often the package manager checks for an update.
+	// +optional
+	// +kubebuilder:validation:Enum=Hourly;Daily;Weekly
+	// +kubebuilder:default=Daily
+	Schedule *UpdateSchedule `json:"schedule,omitempty"`
+
+	// Window restricts updates to a recurring maintenance window. The package
+	// manager may update the package at any time if no window is specified.
+	// +optional
+	Window *UpdateWindow `json:"window,omitempty"`
+}
+
+// An UpdateWindow is a recurring maintenance window.
+type UpdateWindow struct {
+	// Days of the week the window opens on. The window opens every day if no
+	// days are specified.
+	// +optional
+	// +kubebuilder:validation:items:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+	Days []string `json:"days,omitempty"`
+
+	// Start is the time of day the window opens, in 24-hour HH:MM format.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// Duration is how long the window stays open, for example 4h.
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the IANA time zone of the start time, for example
+	// Europe/Berlin. Defaults to UTC.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
 }
 
 // PackageStatus represents the observed state of a Package.
@@ -98,6 +223,66 @@ type PackageStatus struct {
 	// resolution. It may be different from spec.package if the package path was
 	// rewritten using an image config.
 	ResolvedPackage string `json:"resolvedPackage,omitempty"`
+
+	// LastHealthyRevision is the name of the most recent package revision
+	// that was healthy while it was active. It's only recorded for packages
+	// with a rollback policy.
+	// +optional
+	LastHealthyRevision string `json:"lastHealthyRevision,omitempty"`
+
+	// RolledBackRevision is the name of a package revision the package
+	// manager rolled back because it didn't become healthy in time. The
+	// package manager keeps it inactive until spec.package changes.
+	// +optional
+	RolledBackRevision string `json:"rolledBackRevision,omitempty"`
+
+	// RevisionActivationTime is when the package manager activated the
+	// current revision. A rollback policy's timeout counts from this time.
+	// It's only recorded for packages with a rollback policy.
+	// +optional
+	RevisionActivationTime *metav1.Time `json:"revisionActivationTime,omitempty"`
+
+	// ResolvedDigest is the digest spec.package resolved to when the package
+	// manager produced the current revision.
+	// +optional
+	ResolvedDigest string `json:"resolvedDigest,omitempty"`
+
+	// PendingDigest is a new digest spec.package resolves to that's awaiting
+	// approval, because the package's digest policy pins it.
+	// +optional
+	PendingDigest string `json:"pendingDigest,omitempty"`
+
+	// Update is the status of automatic updates of the package.
+	// +optional
+	Update *UpdateStatus `json:"update,omitempty"`
+}
+
+// UpdateStatus represents the status of automatic updates of a package.
+type UpdateStatus struct {
+	// LastCheckTime is when the package manager last checked for an update.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// LastUpdateTime is when the package manager last updated the package.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+
+	// PreviousPackage is the package the package manager last updated from.
+	// It's cleared once the updated package becomes healthy. If the package
+	// has a rollback policy and the package manager rolls back the updated
+	// package's revision, the package manager restores spec.package to it.
+	// +optional
+	PreviousPackage string `json:"previousPackage,omitempty"`
+
+	// UpdatedPackage is the package the package manager last updated to. The
+	// package manager won't roll back a package that was changed since.
+	// +optional
+	UpdatedPackage string `json:"updatedPackage,omitempty"`
+
+	// RejectedVersions are versions the package manager rolled back.
+	// It won't update the package to these versions again.
+	// +optional
+	RejectedVersions []string `json:"rejectedVersions,omitempty"`
 }
 
 // ImageConfigRef is a reference to an image config that indicates how the
@@ -41,6 +41,12 @@ type PackageRevisionSpec struct {
 	// Package image used by install Pod to extract package contents.
 	Package string `json:"image"`
 
+	// Digest the package image is pinned to. If set, the package manager
+	// pulls the package image and any images it runs by this digest rather
+	// than by the tag in spec.image.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
 	// PackagePullSecrets are named secrets in the same namespace that can be
 	// used to fetch packages from private registries. They are also applied to
 	// any images pulled for the package, such as a provider's controller image.
@@ -102,6 +108,54 @@ type PackageRevisionStatus struct {
 	// different from spec.image if the package path was rewritten using an
 	// image config.
 	ResolvedPackage string `json:"resolvedImage,omitempty"`
+
+	// ImageDigest is the digest of the package image the revision's package
+	// content was extracted from. The package manager only uses cached
+	// package content that was extracted from this image.
+	// +optional
+	ImageDigest string `json:"imageDigest,omitempty"`
+
+	// AttestationResults records the outcome of verifying each attestation
+	// required by the image config used to verify this revision.
+	// +optional
+	AttestationResults []AttestationResult `json:"attestationResults,omitempty"`
+}
+
+// An AttestationOutcome is the outcome of verifying an attestation.
+type AttestationOutcome string
+
+// Attestation outcomes.
+const (
+	// AttestationPassed indicates the attestation was found and satisfied
+	// its policy, if any.
+	AttestationPassed AttestationOutcome = "Passed"
+
+	// AttestationFailed indicates the attestation was found but didn't
+	// satisfy its policy.
+	AttestationFailed AttestationOutcome = "Failed"
+
+	// AttestationMissing indicates no attestation of the required predicate
+	// type was found.
+	AttestationMissing AttestationOutcome = "Missing"
+)
+
+// An AttestationResult is the outcome of verifying an attestation.
+type AttestationResult struct {
+	// Authority is the name of the authority that required the attestation.
+	Authority string `json:"authority"`
+
+	// Name of the attestation.
+	Name string `json:"name"`
+
+	// PredicateType of the attestation.
+	PredicateType string `json:"predicateType"`
+
+	// Outcome of verifying the attestation.
+	Outcome AttestationOutcome `json:"outcome"`
+
+	// Message explains the outcome.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // A ControllerReference references the controller (e.g. Deployment), if any,
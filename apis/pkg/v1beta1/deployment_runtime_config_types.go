@@ -18,7 +18,11 @@ package v1beta1
 
 import (
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // ObjectMeta is metadata contains the configurable metadata fields for the
@@ -68,6 +72,102 @@ type ServiceAccountTemplate struct {
 	Metadata *ObjectMeta `json:"metadata,omitempty"`
 }
 
+// HorizontalPodAutoscalerTemplate is the template for the
+// HorizontalPodAutoscaler object.
+type HorizontalPodAutoscalerTemplate struct {
+	// Metadata contains the configurable metadata fields for the
+	// HorizontalPodAutoscaler.
+	// +optional
+	Metadata *ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec contains the configurable spec fields for the
+	// HorizontalPodAutoscaler object.
+	Spec HorizontalPodAutoscalerSpec `json:"spec"`
+}
+
+// HorizontalPodAutoscalerSpec contains the configurable spec fields for the
+// HorizontalPodAutoscaler object. Crossplane always scales the package's
+// Deployment.
+type HorizontalPodAutoscalerSpec struct {
+	// MinReplicas is the lower limit for the number of replicas to which the
+	// autoscaler can scale down. It defaults to 1 pod.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit for the number of replicas to which the
+	// autoscaler can scale up.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Metrics contains the specifications for which to use to calculate the
+	// desired replica count. If not set, the default metric will be set to 80%
+	// average CPU utilization.
+	// +optional
+	// +listType=atomic
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+
+	// Behavior configures the scaling behavior of the target in both Up and
+	// Down directions.
+	// +optional
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+
+	// FunctionRequests scales a function on the rate of RunFunctionRequests
+	// Crossplane sends it. It's ignored for providers.
+	// +optional
+	FunctionRequests *FunctionRequestsMetric `json:"functionRequests,omitempty"`
+}
+
+// FunctionRequestsMetric scales a function on the rate of RunFunctionRequests
+// Crossplane sends it. Crossplane exposes its requests to each function as the
+// crossplane_composition_run_function_request_total Prometheus metric. A
+// metrics adapter, such as prometheus-adapter, must expose the rate of this
+// metric as an external metric with a function_name label.
+type FunctionRequestsMetric struct {
+	// MetricName is the name of the external metric.
+	// +optional
+	// +kubebuilder:default="crossplane_composition_run_function_requests_per_second"
+	MetricName string `json:"metricName,omitempty"`
+
+	// TargetAverageValue is the target rate of requests per replica.
+	TargetAverageValue resource.Quantity `json:"targetAverageValue"`
+}
+
+// PodDisruptionBudgetTemplate is the template for the PodDisruptionBudget
+// object.
+type PodDisruptionBudgetTemplate struct {
+	// Metadata contains the configurable metadata fields for the
+	// PodDisruptionBudget.
+	// +optional
+	Metadata *ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec contains the configurable spec fields for the PodDisruptionBudget
+	// object. Crossplane always selects the package's pods. If neither
+	// minAvailable nor maxUnavailable is set maxUnavailable defaults to 1.
+	// +optional
+	Spec *PodDisruptionBudgetSpec `json:"spec,omitempty"`
+}
+
+// PodDisruptionBudgetSpec contains the configurable spec fields for the
+// PodDisruptionBudget object.
+// +kubebuilder:validation:XValidation:rule="!(has(self.minAvailable) && has(self.maxUnavailable))",message="minAvailable and maxUnavailable are mutually exclusive"
+type PodDisruptionBudgetSpec struct {
+	// MinAvailable is the number or percentage of the package's pods that
+	// must be available after an eviction.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number or percentage of the package's pods that
+	// can be unavailable after an eviction.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// UnhealthyPodEvictionPolicy defines the criteria for when unhealthy pods
+	// should be considered for eviction.
+	// +optional
+	UnhealthyPodEvictionPolicy *policyv1.UnhealthyPodEvictionPolicyType `json:"unhealthyPodEvictionPolicy,omitempty"`
+}
+
 // DeploymentRuntimeConfigSpec specifies the configuration for a packaged controller.
 // Values provided will override package manager defaults. Labels and
 // annotations are passed to both the controller Deployment and ServiceAccount.
@@ -81,6 +181,33 @@ type DeploymentRuntimeConfigSpec struct {
 	// ServiceAccountTemplate is the template for the ServiceAccount object.
 	// +optional
 	ServiceAccountTemplate *ServiceAccountTemplate `json:"serviceAccountTemplate,omitempty"`
+	// HorizontalPodAutoscalerTemplate is the template for the
+	// HorizontalPodAutoscaler object. When set Crossplane creates a
+	// HorizontalPodAutoscaler that scales the package's Deployment, and no
+	// longer manages the Deployment's replicas.
+	// +optional
+	HorizontalPodAutoscalerTemplate *HorizontalPodAutoscalerTemplate `json:"horizontalPodAutoscalerTemplate,omitempty"`
+	// PodDisruptionBudgetTemplate is the template for the PodDisruptionBudget
+	// object. When set Crossplane creates a PodDisruptionBudget for the
+	// package's pods.
+	// +optional
+	PodDisruptionBudgetTemplate *PodDisruptionBudgetTemplate `json:"podDisruptionBudgetTemplate,omitempty"`
+	// Endpoint configures the package to use an externally hosted gRPC
+	// endpoint instead of a Deployment. When an endpoint is set Crossplane
+	// doesn't create a Deployment for the package. It still issues the
+	// package's TLS certificates, and reports the package as unhealthy when
+	// the endpoint fails a gRPC health check.
+	// +optional
+	Endpoint *RuntimeEndpoint `json:"endpoint,omitempty"`
+}
+
+// RuntimeEndpoint is an externally hosted gRPC endpoint that serves a package.
+type RuntimeEndpoint struct {
+	// Address of the endpoint, as a gRPC target. For example
+	// dns:///function-example.example.org:9443. Crossplane issues the
+	// package's TLS server certificate for the endpoint's host name.
+	// +kubebuilder:validation:MinLength=1
+	Address string `json:"address"`
 }
 
 // +kubebuilder:object:root=true
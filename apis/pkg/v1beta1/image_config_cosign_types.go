@@ -103,7 +103,8 @@ type Identity struct {
 }
 
 // Modifications over the original policy controller "Attestation" type: https://github.com/sigstore/policy-controller/blob/d73e188a4669780af82d3d168f40a6fff438345a/pkg/apis/policy/v1alpha1/clusterimagepolicy_types.go#L210
-// - Removed the Policy field as it is not supported in the initial implementation.
+// - Replaced the Policy field, which evaluates CUE or Rego, with a Policy field
+//   of type AttestationPolicy that supports a fixed set of content checks.
 
 // Attestation defines the type of attestation to validate and optionally
 // apply a policy decision to it. Authority block is used to verify the
@@ -115,4 +116,40 @@ type Attestation struct {
 	// PredicateType defines which predicate type to verify. Matches cosign
 	// verify-attestation options.
 	PredicateType string `json:"predicateType"`
+
+	// Policy constrains the content of the attestation. It's applied only
+	// after the attestation's signature has been verified.
+	// +optional
+	Policy *AttestationPolicy `json:"policy,omitempty"`
+}
+
+// A VulnerabilitySeverity is the severity of a vulnerability.
+type VulnerabilitySeverity string
+
+// Vulnerability severities, from least to most severe.
+const (
+	VulnerabilitySeverityNone     VulnerabilitySeverity = "None"
+	VulnerabilitySeverityLow      VulnerabilitySeverity = "Low"
+	VulnerabilitySeverityMedium   VulnerabilitySeverity = "Medium"
+	VulnerabilitySeverityHigh     VulnerabilitySeverity = "High"
+	VulnerabilitySeverityCritical VulnerabilitySeverity = "Critical"
+)
+
+// An AttestationPolicy constrains the content of an attestation.
+type AttestationPolicy struct {
+	// MaxVulnerabilitySeverity is the most severe vulnerability a
+	// vulnerability attestation (predicate type vuln) may report. For example
+	// High rejects packages with critical vulnerabilities, and None rejects
+	// packages with any vulnerabilities. Vulnerabilities are read from the
+	// scanner result of the attestation, which must be a SARIF log, a Trivy
+	// JSON report, or a Grype JSON report. Vulnerabilities of unknown
+	// severity are always more severe than allowed unless ignored.
+	// +optional
+	// +kubebuilder:validation:Enum=None;Low;Medium;High;Critical
+	MaxVulnerabilitySeverity *VulnerabilitySeverity `json:"maxVulnerabilitySeverity,omitempty"`
+
+	// IgnoredVulnerabilities are the IDs of vulnerabilities, for example
+	// CVE-2024-3094, that don't count against MaxVulnerabilitySeverity.
+	// +optional
+	IgnoredVulnerabilities []string `json:"ignoredVulnerabilities,omitempty"`
 }
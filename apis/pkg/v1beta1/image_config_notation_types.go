@@ -0,0 +1,83 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// A NotationTrustStoreType is the type of a Notation trust store.
+type NotationTrustStoreType string
+
+// Notation trust store types.
+const (
+	// NotationTrustStoreCA contains the root certificates of certificate
+	// authorities that issue signing certificates.
+	NotationTrustStoreCA NotationTrustStoreType = "ca"
+
+	// NotationTrustStoreSigningAuthority contains the root certificates of
+	// signing authorities.
+	NotationTrustStoreSigningAuthority NotationTrustStoreType = "signingAuthority"
+
+	// NotationTrustStoreTSA contains the root certificates of timestamping
+	// authorities.
+	NotationTrustStoreTSA NotationTrustStoreType = "tsa"
+)
+
+// NotationVerificationConfig contains the configuration for verifying the
+// image using Notation.
+type NotationVerificationConfig struct {
+	// TrustPolicy is a Notation OCI trust policy document, in JSON format.
+	// Its registry scopes are matched against the image being verified.
+	TrustPolicy NotationSource `json:"trustPolicy"`
+
+	// TrustStores are the trust stores the trust policy refers to.
+	TrustStores []NotationTrustStore `json:"trustStores"`
+}
+
+// A NotationTrustStore is a named set of trusted X.509 certificates.
+type NotationTrustStore struct {
+	// Type of the trust store.
+	// +kubebuilder:validation:Enum=ca;signingAuthority;tsa
+	Type NotationTrustStoreType `json:"type"`
+
+	// Name of the trust store. A trust policy refers to the trust store as
+	// <type>:<name>, for example ca:acme-rockets.
+	Name string `json:"name"`
+
+	// Certificates are the PEM encoded certificates in the trust store.
+	Certificates NotationSource `json:"certificates"`
+}
+
+// A NotationSource is a key of a Secret or a ConfigMap in the Crossplane
+// namespace.
+// +kubebuilder:validation:XValidation:rule="has(self.secretRef) != has(self.configMapRef)",message="exactly one of secretRef or configMapRef must be set"
+type NotationSource struct {
+	// SecretRef selects a key of a Secret.
+	// +optional
+	SecretRef *LocalSecretKeySelector `json:"secretRef,omitempty"`
+
+	// ConfigMapRef selects a key of a ConfigMap.
+	// +optional
+	ConfigMapRef *LocalConfigMapKeySelector `json:"configMapRef,omitempty"`
+}
+
+// A LocalConfigMapKeySelector is a reference to a ConfigMap key in a
+// predefined namespace.
+type LocalConfigMapKeySelector struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+
+	// The key to select.
+	Key string `json:"key"`
+}
@@ -39,6 +39,10 @@ const (
 	// ImageVerificationProviderCosign is the cosign provider that should be
 	// used to verify the image.
 	ImageVerificationProviderCosign ImageVerificationProvider = "Cosign"
+
+	// ImageVerificationProviderNotation is the Notation provider that should
+	// be used to verify the image.
+	ImageVerificationProviderNotation ImageVerificationProvider = "Notation"
 )
 
 // +kubebuilder:object:root=true
@@ -83,6 +87,13 @@ type ImageConfigSpec struct {
 	// RewriteImage defines how a matched image's path should be rewritten.
 	// +optional
 	RewriteImage *ImageRewrite `json:"rewriteImage,omitempty"`
+	// Mirrors is an ordered list of registry mirrors for matched images. If
+	// a matched image can't be fetched because its registry is unreachable or
+	// rate limits the request, Crossplane tries each mirror in order. Requires
+	// the --enable-registry-mirrors flag.
+	// +optional
+	// +listType=atomic
+	Mirrors []ImageMirror `json:"mirrors,omitempty"`
 }
 
 // ImageMatch defines a rule for matching image.
@@ -98,10 +109,72 @@ type ImageMatch struct {
 }
 
 // RegistryAuthentication contains the authentication information for a registry.
+//
+// Crossplane uses a token exchange or credential helper to fetch packages and
+// verify their signatures. The kubelet doesn't use them to pull the runtime
+// image of a provider or function. Use a pull secret, or configure a kubelet
+// image credential provider, to let the kubelet pull runtime images from the
+// registry.
+// +kubebuilder:validation:XValidation:rule="(has(self.pullSecretRef) && size(self.pullSecretRef.name) > 0 ? 1 : 0) + (has(self.tokenExchange) ? 1 : 0) + (has(self.credentialHelper) ? 1 : 0) == 1",message="exactly one of pullSecretRef, tokenExchange, or credentialHelper must be set."
 type RegistryAuthentication struct {
 	// PullSecretRef is a reference to a secret that contains the credentials for
 	// the registry.
-	PullSecretRef corev1.LocalObjectReference `json:"pullSecretRef"`
+	// +optional
+	PullSecretRef corev1.LocalObjectReference `json:"pullSecretRef,omitempty"`
+
+	// TokenExchange authenticates to the registry using a short-lived
+	// credential obtained by exchanging a token for Crossplane's service
+	// account. Requires the --enable-registry-credential-providers flag.
+	// +optional
+	TokenExchange *RegistryTokenExchange `json:"tokenExchange,omitempty"`
+
+	// CredentialHelper authenticates to the registry using a Docker
+	// credential helper binary. Requires the
+	// --enable-registry-credential-providers flag.
+	// +optional
+	CredentialHelper *RegistryCredentialHelper `json:"credentialHelper,omitempty"`
+}
+
+// RegistryTokenExchange configures authenticating to a registry by exchanging
+// a service account token for a registry credential.
+//
+// Crossplane requests a token for its service account with the supplied
+// audience, then exchanges it for an access token by sending an OAuth 2.0
+// token exchange request (RFC 8693) to the supplied URL. The access token is
+// used as the registry password, or as a registry bearer token if no username
+// is supplied.
+type RegistryTokenExchange struct {
+	// URL of the token exchange endpoint.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// Audience of the service account token. The token exchange endpoint must
+	// accept tokens with this audience.
+	Audience string `json:"audience"`
+
+	// ExpirationSeconds is the requested lifetime of the service account
+	// token.
+	// +optional
+	// +kubebuilder:validation:Minimum=600
+	// +kubebuilder:default=3600
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+
+	// Username to send with the access token. Some registries expect a fixed
+	// username when an access token is used as a password. If omitted the
+	// access token is sent as a bearer token.
+	// +optional
+	Username *string `json:"username,omitempty"`
+}
+
+// RegistryCredentialHelper configures authenticating to a registry using a
+// Docker credential helper.
+type RegistryCredentialHelper struct {
+	// Name of the credential helper. Crossplane runs the binary
+	// docker-credential-<name>, which must be on Crossplane's PATH when
+	// Crossplane starts. The Crossplane image doesn't include any credential
+	// helpers, so they must be added to a custom image.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9][a-z0-9-]*$`
+	Name string `json:"name"`
 }
 
 // RegistryConfig contains the configuration for the registry.
@@ -112,13 +185,18 @@ type RegistryConfig struct {
 }
 
 // ImageVerification contains the configuration for verifying the image.
+// +kubebuilder:validation:XValidation:rule="self.provider != \"Cosign\" || has(self.cosign)",message="cosign must be set when provider is \"Cosign\"."
+// +kubebuilder:validation:XValidation:rule="self.provider != \"Notation\" || has(self.notation)",message="notation must be set when provider is \"Notation\"."
 type ImageVerification struct {
 	// Provider is the provider that should be used to verify the image.
-	// +kubebuilder:validation:Enum=Cosign
+	// +kubebuilder:validation:Enum=Cosign;Notation
 	Provider ImageVerificationProvider `json:"provider"`
 	// Cosign is the configuration for verifying the image using cosign.
 	// +optional
 	Cosign *CosignVerificationConfig `json:"cosign,omitempty"`
+	// Notation is the configuration for verifying the image using Notation.
+	// +optional
+	Notation *NotationVerificationConfig `json:"notation,omitempty"`
 }
 
 // CosignVerificationConfig contains the configuration for verifying the image
@@ -144,3 +222,28 @@ type ImageRewrite struct {
 	// the longest one will be replaced.
 	Prefix string `json:"prefix"`
 }
+
+// ImageMirror defines a registry mirror for matched images.
+type ImageMirror struct {
+	// Prefix is the prefix that will replace the portion of the image's path
+	// matched by the prefix in the ImageMatch to produce the image's path in
+	// the mirror. If multiple prefixes matched, the longest one will be
+	// replaced.
+	Prefix string `json:"prefix"`
+	// Authentication is the authentication information for the mirror. The
+	// image's pull secrets are not sent to mirrors.
+	// +optional
+	Authentication *RegistryAuthentication `json:"authentication,omitempty"`
+	// TLS is the TLS configuration for the mirror.
+	// +optional
+	TLS *MirrorTLS `json:"tls,omitempty"`
+}
+
+// MirrorTLS defines how to connect to a registry mirror using TLS.
+type MirrorTLS struct {
+	// CABundleConfigMapRef selects a key of a ConfigMap containing PEM encoded
+	// CA certificates used to verify the mirror's certificate, in addition to
+	// the CAs Crossplane trusts for all registries.
+	// +optional
+	CABundleConfigMapRef *LocalConfigMapKeySelector `json:"caBundleConfigMapRef,omitempty"`
+}
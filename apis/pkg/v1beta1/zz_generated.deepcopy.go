@@ -23,13 +23,22 @@ package v1beta1
 import (
 	commonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"k8s.io/api/apps/v1"
+	"k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Attestation) DeepCopyInto(out *Attestation) {
 	*out = *in
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(AttestationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Attestation.
@@ -42,6 +51,46 @@ func (in *Attestation) DeepCopy() *Attestation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AttestationPolicy) DeepCopyInto(out *AttestationPolicy) {
+	*out = *in
+	if in.MaxVulnerabilitySeverity != nil {
+		in, out := &in.MaxVulnerabilitySeverity, &out.MaxVulnerabilitySeverity
+		*out = new(VulnerabilitySeverity)
+		**out = **in
+	}
+	if in.IgnoredVulnerabilities != nil {
+		in, out := &in.IgnoredVulnerabilities, &out.IgnoredVulnerabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AttestationPolicy.
+func (in *AttestationPolicy) DeepCopy() *AttestationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AttestationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AttestationResult) DeepCopyInto(out *AttestationResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AttestationResult.
+func (in *AttestationResult) DeepCopy() *AttestationResult {
+	if in == nil {
+		return nil
+	}
+	out := new(AttestationResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerReference) DeepCopyInto(out *ControllerReference) {
 	*out = *in
@@ -73,7 +122,9 @@ func (in *CosignAuthority) DeepCopyInto(out *CosignAuthority) {
 	if in.Attestations != nil {
 		in, out := &in.Attestations, &out.Attestations
 		*out = make([]Attestation, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -220,6 +271,21 @@ func (in *DeploymentRuntimeConfigSpec) DeepCopyInto(out *DeploymentRuntimeConfig
 		*out = new(ServiceAccountTemplate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HorizontalPodAutoscalerTemplate != nil {
+		in, out := &in.HorizontalPodAutoscalerTemplate, &out.HorizontalPodAutoscalerTemplate
+		*out = new(HorizontalPodAutoscalerTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudgetTemplate != nil {
+		in, out := &in.PodDisruptionBudgetTemplate, &out.PodDisruptionBudgetTemplate
+		*out = new(PodDisruptionBudgetTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(RuntimeEndpoint)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentRuntimeConfigSpec.
@@ -257,6 +323,31 @@ func (in *DeploymentTemplate) DeepCopy() *DeploymentTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DigestPolicy) DeepCopyInto(out *DigestPolicy) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(DigestPolicyMode)
+		**out = **in
+	}
+	if in.ApprovedDigest != nil {
+		in, out := &in.ApprovedDigest, &out.ApprovedDigest
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DigestPolicy.
+func (in *DigestPolicy) DeepCopy() *DigestPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DigestPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Function) DeepCopyInto(out *Function) {
 	*out = *in
@@ -316,6 +407,22 @@ func (in *FunctionList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FunctionRequestsMetric) DeepCopyInto(out *FunctionRequestsMetric) {
+	*out = *in
+	out.TargetAverageValue = in.TargetAverageValue.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionRequestsMetric.
+func (in *FunctionRequestsMetric) DeepCopy() *FunctionRequestsMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(FunctionRequestsMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FunctionRevision) DeepCopyInto(out *FunctionRevision) {
 	*out = *in
@@ -442,6 +549,64 @@ func (in *FunctionStatus) DeepCopy() *FunctionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HorizontalPodAutoscalerSpec) DeepCopyInto(out *HorizontalPodAutoscalerSpec) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]v2.MetricSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Behavior != nil {
+		in, out := &in.Behavior, &out.Behavior
+		*out = new(v2.HorizontalPodAutoscalerBehavior)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FunctionRequests != nil {
+		in, out := &in.FunctionRequests, &out.FunctionRequests
+		*out = new(FunctionRequestsMetric)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HorizontalPodAutoscalerSpec.
+func (in *HorizontalPodAutoscalerSpec) DeepCopy() *HorizontalPodAutoscalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HorizontalPodAutoscalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HorizontalPodAutoscalerTemplate) DeepCopyInto(out *HorizontalPodAutoscalerTemplate) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(ObjectMeta)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HorizontalPodAutoscalerTemplate.
+func (in *HorizontalPodAutoscalerTemplate) DeepCopy() *HorizontalPodAutoscalerTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(HorizontalPodAutoscalerTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Identity) DeepCopyInto(out *Identity) {
 	*out = *in
@@ -553,6 +718,13 @@ func (in *ImageConfigSpec) DeepCopyInto(out *ImageConfigSpec) {
 		*out = new(ImageRewrite)
 		**out = **in
 	}
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]ImageMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageConfigSpec.
@@ -580,6 +752,31 @@ func (in *ImageMatch) DeepCopy() *ImageMatch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageMirror) DeepCopyInto(out *ImageMirror) {
+	*out = *in
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(RegistryAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(MirrorTLS)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMirror.
+func (in *ImageMirror) DeepCopy() *ImageMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageRewrite) DeepCopyInto(out *ImageRewrite) {
 	*out = *in
@@ -603,6 +800,11 @@ func (in *ImageVerification) DeepCopyInto(out *ImageVerification) {
 		*out = new(CosignVerificationConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Notation != nil {
+		in, out := &in.Notation, &out.Notation
+		*out = new(NotationVerificationConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVerification.
@@ -656,6 +858,21 @@ func (in *KeylessRef) DeepCopy() *KeylessRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalConfigMapKeySelector) DeepCopyInto(out *LocalConfigMapKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalConfigMapKeySelector.
+func (in *LocalConfigMapKeySelector) DeepCopy() *LocalConfigMapKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalConfigMapKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalSecretKeySelector) DeepCopyInto(out *LocalSecretKeySelector) {
 	*out = *in
@@ -795,6 +1012,90 @@ func (in *LockStatus) DeepCopy() *LockStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirrorTLS) DeepCopyInto(out *MirrorTLS) {
+	*out = *in
+	if in.CABundleConfigMapRef != nil {
+		in, out := &in.CABundleConfigMapRef, &out.CABundleConfigMapRef
+		*out = new(LocalConfigMapKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorTLS.
+func (in *MirrorTLS) DeepCopy() *MirrorTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(MirrorTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotationSource) DeepCopyInto(out *NotationSource) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(LocalSecretKeySelector)
+		**out = **in
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(LocalConfigMapKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotationSource.
+func (in *NotationSource) DeepCopy() *NotationSource {
+	if in == nil {
+		return nil
+	}
+	out := new(NotationSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotationTrustStore) DeepCopyInto(out *NotationTrustStore) {
+	*out = *in
+	in.Certificates.DeepCopyInto(&out.Certificates)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotationTrustStore.
+func (in *NotationTrustStore) DeepCopy() *NotationTrustStore {
+	if in == nil {
+		return nil
+	}
+	out := new(NotationTrustStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotationVerificationConfig) DeepCopyInto(out *NotationVerificationConfig) {
+	*out = *in
+	in.TrustPolicy.DeepCopyInto(&out.TrustPolicy)
+	if in.TrustStores != nil {
+		in, out := &in.TrustStores, &out.TrustStores
+		*out = make([]NotationTrustStore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotationVerificationConfig.
+func (in *NotationVerificationConfig) DeepCopy() *NotationVerificationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NotationVerificationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectMeta) DeepCopyInto(out *ObjectMeta) {
 	*out = *in
@@ -911,6 +1212,11 @@ func (in *PackageRevisionStatus) DeepCopyInto(out *PackageRevisionStatus) {
 		*out = make([]ImageConfigRef, len(*in))
 		copy(*out, *in)
 	}
+	if in.AttestationResults != nil {
+		in, out := &in.AttestationResults, &out.AttestationResults
+		*out = make([]AttestationResult, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionStatus.
@@ -983,6 +1289,21 @@ func (in *PackageSpec) DeepCopyInto(out *PackageSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.UpdatePolicy != nil {
+		in, out := &in.UpdatePolicy, &out.UpdatePolicy
+		*out = new(UpdatePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RollbackPolicy != nil {
+		in, out := &in.RollbackPolicy, &out.RollbackPolicy
+		*out = new(RollbackPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DigestPolicy != nil {
+		in, out := &in.DigestPolicy, &out.DigestPolicy
+		*out = new(DigestPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageSpec.
@@ -1003,6 +1324,15 @@ func (in *PackageStatus) DeepCopyInto(out *PackageStatus) {
 		*out = make([]ImageConfigRef, len(*in))
 		copy(*out, *in)
 	}
+	if in.RevisionActivationTime != nil {
+		in, out := &in.RevisionActivationTime, &out.RevisionActivationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Update != nil {
+		in, out := &in.Update, &out.Update
+		*out = new(UpdateStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageStatus.
@@ -1015,10 +1345,75 @@ func (in *PackageStatus) DeepCopy() *PackageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetSpec) DeepCopyInto(out *PodDisruptionBudgetSpec) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.UnhealthyPodEvictionPolicy != nil {
+		in, out := &in.UnhealthyPodEvictionPolicy, &out.UnhealthyPodEvictionPolicy
+		*out = new(policyv1.UnhealthyPodEvictionPolicyType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetSpec.
+func (in *PodDisruptionBudgetSpec) DeepCopy() *PodDisruptionBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetTemplate) DeepCopyInto(out *PodDisruptionBudgetTemplate) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(ObjectMeta)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(PodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetTemplate.
+func (in *PodDisruptionBudgetTemplate) DeepCopy() *PodDisruptionBudgetTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgetTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RegistryAuthentication) DeepCopyInto(out *RegistryAuthentication) {
 	*out = *in
 	out.PullSecretRef = in.PullSecretRef
+	if in.TokenExchange != nil {
+		in, out := &in.TokenExchange, &out.TokenExchange
+		*out = new(RegistryTokenExchange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialHelper != nil {
+		in, out := &in.CredentialHelper, &out.CredentialHelper
+		*out = new(RegistryCredentialHelper)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryAuthentication.
@@ -1037,7 +1432,7 @@ func (in *RegistryConfig) DeepCopyInto(out *RegistryConfig) {
 	if in.Authentication != nil {
 		in, out := &in.Authentication, &out.Authentication
 		*out = new(RegistryAuthentication)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -1051,6 +1446,66 @@ func (in *RegistryConfig) DeepCopy() *RegistryConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryCredentialHelper) DeepCopyInto(out *RegistryCredentialHelper) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryCredentialHelper.
+func (in *RegistryCredentialHelper) DeepCopy() *RegistryCredentialHelper {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryCredentialHelper)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryTokenExchange) DeepCopyInto(out *RegistryTokenExchange) {
+	*out = *in
+	if in.ExpirationSeconds != nil {
+		in, out := &in.ExpirationSeconds, &out.ExpirationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Username != nil {
+		in, out := &in.Username, &out.Username
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryTokenExchange.
+func (in *RegistryTokenExchange) DeepCopy() *RegistryTokenExchange {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryTokenExchange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollbackPolicy) DeepCopyInto(out *RollbackPolicy) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackPolicy.
+func (in *RollbackPolicy) DeepCopy() *RollbackPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RollbackPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuntimeConfigReference) DeepCopyInto(out *RuntimeConfigReference) {
 	*out = *in
@@ -1076,6 +1531,21 @@ func (in *RuntimeConfigReference) DeepCopy() *RuntimeConfigReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeEndpoint) DeepCopyInto(out *RuntimeEndpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeEndpoint.
+func (in *RuntimeEndpoint) DeepCopy() *RuntimeEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAccountTemplate) DeepCopyInto(out *ServiceAccountTemplate) {
 	*out = *in
@@ -1115,3 +1585,82 @@ func (in *ServiceTemplate) DeepCopy() *ServiceTemplate {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdatePolicy) DeepCopyInto(out *UpdatePolicy) {
+	*out = *in
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(UpdateSchedule)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(UpdateWindow)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdatePolicy.
+func (in *UpdatePolicy) DeepCopy() *UpdatePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdatePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateStatus) DeepCopyInto(out *UpdateStatus) {
+	*out = *in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RejectedVersions != nil {
+		in, out := &in.RejectedVersions, &out.RejectedVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateStatus.
+func (in *UpdateStatus) DeepCopy() *UpdateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateWindow) DeepCopyInto(out *UpdateWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Duration = in.Duration
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateWindow.
+func (in *UpdateWindow) DeepCopy() *UpdateWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateWindow)
+	in.DeepCopyInto(out)
+	return out
+}
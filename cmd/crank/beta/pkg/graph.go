@@ -0,0 +1,233 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Masterminds/semver"
+	"github.com/alecthomas/kong"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	errKubeConfig     = "failed to get kubeconfig"
+	errInitKubeClient = "cannot init kubeclient"
+	errInitPrinter    = "cannot init new printer"
+	errGetLock        = "cannot get package lock"
+	errCliOutput      = "cannot print output"
+)
+
+// lockName is the name of the singleton Lock maintained by the package manager.
+const lockName = "lock"
+
+// graphCmd prints the dependency graph of installed packages.
+type graphCmd struct {
+	Context string `default:""     help:"Kubernetes context." name:"context"                                 predictor:"context" short:"c"`
+	Output  string `default:"tree" enum:"tree,json,dot"       help:"Output format. One of: tree, json, dot." name:"output"       short:"o"`
+}
+
+// Help returns help for the graph command.
+func (c *graphCmd) Help() string {
+	return `
+This command prints the dependency graph of installed packages, as resolved by
+the package manager. Each dependency shows the version constraint its parent
+imposes on it, and whether the installed version satisfies that constraint.
+Dependencies that are missing or that don't satisfy a constraint are marked as
+unsatisfied.
+
+Examples:
+  # Print the dependency graph as a tree.
+  crossplane beta pkg graph
+
+  # Print the dependency graph as JSON and pipe it to jq.
+  crossplane beta pkg graph -o json | jq
+
+  # Print the dependency graph in dot format and render it as a PNG.
+  crossplane beta pkg graph -o dot | dot -Tpng -o graph.png
+`
+}
+
+// Run runs the graph command.
+func (c *graphCmd) Run(k *kong.Context, logger logging.Logger) error {
+	p, err := newPrinter(c.Output)
+	if err != nil {
+		return errors.Wrap(err, errInitPrinter)
+	}
+
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: c.Context},
+	).ClientConfig()
+	if err != nil {
+		return errors.Wrap(err, errKubeConfig)
+	}
+
+	s := runtime.NewScheme()
+	_ = v1beta1.AddToScheme(s)
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	if err != nil {
+		return errors.Wrap(err, errInitKubeClient)
+	}
+
+	l := &v1beta1.Lock{}
+	if err := kube.Get(context.Background(), types.NamespacedName{Name: lockName}, l); err != nil {
+		return errors.Wrap(err, errGetLock)
+	}
+	logger.Debug("Got package lock", "packages", len(l.Packages))
+
+	return errors.Wrap(p.Print(k.Stdout, NewGraph(l)), errCliOutput)
+}
+
+// A Graph of installed packages and their dependencies.
+type Graph struct {
+	// Packages in the graph, sorted by source. Packages that are depended on
+	// but not installed are included, with Installed set to false.
+	Packages []Package `json:"packages"`
+
+	// Dependencies between packages, sorted by parent then package.
+	Dependencies []Dependency `json:"dependencies"`
+}
+
+// A Package in the dependency graph.
+type Package struct {
+	// Source is the OCI repository of the package.
+	Source string `json:"source"`
+
+	// Kind of the package, e.g. Provider.
+	Kind string `json:"kind,omitempty"`
+
+	// Revision is the name of the package's active revision.
+	Revision string `json:"revision,omitempty"`
+
+	// Version is the installed tag or digest.
+	Version string `json:"version,omitempty"`
+
+	// Installed is false if the package is depended on but not installed.
+	Installed bool `json:"installed"`
+}
+
+// A Dependency of one package on another.
+type Dependency struct {
+	// Parent is the source of the package that imposes the dependency.
+	Parent string `json:"parent"`
+
+	// Package is the source of the package that is depended on.
+	Package string `json:"package"`
+
+	// Constraints the parent imposes on the package's version.
+	Constraints string `json:"constraints"`
+
+	// Satisfied is true if the package is installed at a version that
+	// satisfies the constraints.
+	Satisfied bool `json:"satisfied"`
+}
+
+// NewGraph returns the dependency graph recorded by the supplied Lock.
+func NewGraph(l *v1beta1.Lock) *Graph {
+	g := &Graph{
+		Packages:     make([]Package, 0, len(l.Packages)),
+		Dependencies: make([]Dependency, 0),
+	}
+
+	installed := make(map[string]v1beta1.LockPackage, len(l.Packages))
+	for _, p := range l.Packages {
+		installed[p.Source] = p
+		g.Packages = append(g.Packages, Package{
+			Source:    p.Source,
+			Kind:      lockPackageKind(p),
+			Revision:  p.Name,
+			Version:   p.Version,
+			Installed: true,
+		})
+	}
+
+	missing := map[string]bool{}
+	for _, p := range l.Packages {
+		for _, d := range p.Dependencies {
+			ip, ok := installed[d.Package]
+			g.Dependencies = append(g.Dependencies, Dependency{
+				Parent:      p.Source,
+				Package:     d.Package,
+				Constraints: d.Constraints,
+				Satisfied:   ok && satisfies(ip.Version, d.Constraints),
+			})
+			if ok || missing[d.Package] {
+				continue
+			}
+			missing[d.Package] = true
+			g.Packages = append(g.Packages, Package{Source: d.Package, Kind: dependencyKind(d)})
+		}
+	}
+
+	sort.Slice(g.Packages, func(i, j int) bool { return g.Packages[i].Source < g.Packages[j].Source })
+	sort.SliceStable(g.Dependencies, func(i, j int) bool {
+		if g.Dependencies[i].Parent != g.Dependencies[j].Parent {
+			return g.Dependencies[i].Parent < g.Dependencies[j].Parent
+		}
+		return g.Dependencies[i].Package < g.Dependencies[j].Package
+	})
+
+	return g
+}
+
+// satisfies returns true if the supplied version satisfies the supplied
+// constraints. Constraints are either a semantic version range, or an exact
+// tag or digest.
+func satisfies(version, constraints string) bool {
+	if version == constraints {
+		return true
+	}
+	c, err := semver.NewConstraint(constraints)
+	if err != nil {
+		return false
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	return c.Check(v)
+}
+
+func lockPackageKind(p v1beta1.LockPackage) string {
+	if p.Kind != nil {
+		return *p.Kind
+	}
+	if p.Type != nil {
+		return string(*p.Type)
+	}
+	return ""
+}
+
+func dependencyKind(d v1beta1.Dependency) string {
+	if d.Kind != nil {
+		return *d.Kind
+	}
+	if d.Type != nil {
+		return string(*d.Type)
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+func TestNewGraph(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		lock   *v1beta1.Lock
+		want   *Graph
+	}{
+		"Empty": {
+			reason: "An empty lock should produce an empty graph.",
+			lock:   &v1beta1.Lock{},
+			want: &Graph{
+				Packages:     []Package{},
+				Dependencies: []Dependency{},
+			},
+		},
+		"Dependencies": {
+			reason: "We should record satisfied, unsatisfied, and missing dependencies, and the parent that imposed each constraint.",
+			lock: &v1beta1.Lock{
+				Packages: []v1beta1.LockPackage{
+					{
+						Name:    "config-a-1234",
+						Kind:    ptr.To("Configuration"),
+						Source:  "xpkg.crossplane.io/example/config-a",
+						Version: "v1.0.0",
+						Dependencies: []v1beta1.Dependency{
+							{Package: "xpkg.crossplane.io/example/provider-b", Kind: ptr.To("Provider"), Constraints: ">=v1.0.0"},
+							{Package: "xpkg.crossplane.io/example/function-c", Kind: ptr.To("Function"), Constraints: "v0.2.0"},
+						},
+					},
+					{
+						Name:    "config-d-5678",
+						Type:    ptr.To(v1beta1.ConfigurationPackageType),
+						Source:  "xpkg.crossplane.io/example/config-d",
+						Version: "v2.0.0",
+						Dependencies: []v1beta1.Dependency{
+							{Package: "xpkg.crossplane.io/example/provider-b", Kind: ptr.To("Provider"), Constraints: ">=v2.0.0"},
+						},
+					},
+					{
+						Name:    "provider-b-9012",
+						Kind:    ptr.To("Provider"),
+						Source:  "xpkg.crossplane.io/example/provider-b",
+						Version: "v1.5.0",
+					},
+				},
+			},
+			want: &Graph{
+				Packages: []Package{
+					{Source: "xpkg.crossplane.io/example/config-a", Kind: "Configuration", Revision: "config-a-1234", Version: "v1.0.0", Installed: true},
+					{Source: "xpkg.crossplane.io/example/config-d", Kind: "Configuration", Revision: "config-d-5678", Version: "v2.0.0", Installed: true},
+					{Source: "xpkg.crossplane.io/example/function-c", Kind: "Function"},
+					{Source: "xpkg.crossplane.io/example/provider-b", Kind: "Provider", Revision: "provider-b-9012", Version: "v1.5.0", Installed: true},
+				},
+				Dependencies: []Dependency{
+					{Parent: "xpkg.crossplane.io/example/config-a", Package: "xpkg.crossplane.io/example/function-c", Constraints: "v0.2.0"},
+					{Parent: "xpkg.crossplane.io/example/config-a", Package: "xpkg.crossplane.io/example/provider-b", Constraints: ">=v1.0.0", Satisfied: true},
+					{Parent: "xpkg.crossplane.io/example/config-d", Package: "xpkg.crossplane.io/example/provider-b", Constraints: ">=v2.0.0"},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewGraph(tc.lock)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nNewGraph(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		version     string
+		constraints string
+		want        bool
+	}{
+		"ExactVersion": {
+			reason:      "A version should satisfy an identical constraint.",
+			version:     "v1.0.0",
+			constraints: "v1.0.0",
+			want:        true,
+		},
+		"Digest": {
+			reason:      "A digest should satisfy an identical constraint.",
+			version:     "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d60374e5ec61a8f",
+			constraints: "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d60374e5ec61a8f",
+			want:        true,
+		},
+		"InRange": {
+			reason:      "A version within a semver range should satisfy it.",
+			version:     "v1.2.0",
+			constraints: ">=v1.0.0, <v2.0.0",
+			want:        true,
+		},
+		"OutOfRange": {
+			reason:      "A version outside a semver range shouldn't satisfy it.",
+			version:     "v2.1.0",
+			constraints: ">=v1.0.0, <v2.0.0",
+			want:        false,
+		},
+		"NotSemver": {
+			reason:      "A version that isn't a semantic version shouldn't satisfy a range.",
+			version:     "latest",
+			constraints: ">=v1.0.0",
+			want:        false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := satisfies(tc.version, tc.constraints)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nsatisfies(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pkg contains commands for inspecting installed Crossplane packages.
+package pkg
+
+// Cmd contains commands for inspecting installed packages.
+type Cmd struct {
+	// Keep subcommands sorted alphabetically.
+	Graph graphCmd `cmd:"" help:"Print the resolved dependency graph of installed packages."`
+}
+
+// Help prints out the help for the pkg command.
+func (c *Cmd) Help() string {
+	return `
+Crossplane packages are installed along with their dependencies. These commands
+help inspect how the package manager resolved them.
+`
+}
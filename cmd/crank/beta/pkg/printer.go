@@ -0,0 +1,202 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/emicklei/dot"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errFmtUnknownPrinterType = "unknown printer output type: %s"
+	errWriteHeader           = "cannot write header"
+	errWriteRow              = "cannot write row"
+	errFlushTabWriter        = "cannot flush tab writer"
+	errMarshalJSON           = "cannot marshal dependency graph as JSON"
+)
+
+// Printer output types.
+const (
+	outputTree = "tree"
+	outputJSON = "json"
+	outputDot  = "dot"
+)
+
+// Dependency statuses shown by the tree printer.
+const (
+	statusSatisfied   = "Satisfied"
+	statusUnsatisfied = "Unsatisfied"
+	statusMissing     = "Missing"
+)
+
+// A printer prints a dependency graph.
+type printer interface {
+	Print(w io.Writer, g *Graph) error
+}
+
+func newPrinter(output string) (printer, error) {
+	switch output {
+	case outputTree:
+		return &treePrinter{}, nil
+	case outputJSON:
+		return &jsonPrinter{}, nil
+	case outputDot:
+		return &dotPrinter{}, nil
+	default:
+		return nil, errors.Errorf(errFmtUnknownPrinterType, output)
+	}
+}
+
+// A treePrinter prints the dependency graph as a tree rooted at each package
+// that no other package depends on. Packages that are depended on by several
+// parents appear once under each parent, with that parent's constraints.
+type treePrinter struct{}
+
+// Print prints the supplied dependency graph as a tree.
+func (p *treePrinter) Print(w io.Writer, g *Graph) error {
+	pkgs := make(map[string]Package, len(g.Packages))
+	for _, pkg := range g.Packages {
+		pkgs[pkg.Source] = pkg
+	}
+	deps := make(map[string][]Dependency)
+	depended := make(map[string]bool)
+	for _, d := range g.Dependencies {
+		deps[d.Parent] = append(deps[d.Parent], d)
+		depended[d.Package] = true
+	}
+
+	tw := printers.GetNewTabWriter(w)
+	if _, err := fmt.Fprintln(tw, "NAME\tKIND\tVERSION\tCONSTRAINTS\tSTATUS"); err != nil {
+		return errors.Wrap(err, errWriteHeader)
+	}
+
+	var walk func(d Dependency, prefix, childPrefix string, path map[string]bool) error
+	walk = func(d Dependency, prefix, childPrefix string, path map[string]bool) error {
+		pkg := pkgs[d.Package]
+		status := ""
+		if d.Parent != "" {
+			status = dependencyStatus(pkg, d)
+		}
+		row := []string{prefix + pkg.Source, pkg.Kind, pkg.Version, d.Constraints, status}
+		if _, err := fmt.Fprintln(tw, strings.Join(row, "\t")); err != nil {
+			return errors.Wrap(err, errWriteRow)
+		}
+
+		// The package manager doesn't allow dependency cycles, but we
+		// guard against them rather than recursing forever.
+		if path[pkg.Source] {
+			return nil
+		}
+		path[pkg.Source] = true
+		defer delete(path, pkg.Source)
+
+		children := deps[pkg.Source]
+		for i, c := range children {
+			if i == len(children)-1 {
+				if err := walk(c, childPrefix+"└─ ", childPrefix+"   ", path); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := walk(c, childPrefix+"├─ ", childPrefix+"│  ", path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, pkg := range g.Packages {
+		if depended[pkg.Source] {
+			continue
+		}
+		if err := walk(Dependency{Package: pkg.Source}, "", "", map[string]bool{}); err != nil {
+			return err
+		}
+	}
+
+	return errors.Wrap(tw.Flush(), errFlushTabWriter)
+}
+
+func dependencyStatus(pkg Package, d Dependency) string {
+	switch {
+	case !pkg.Installed:
+		return statusMissing
+	case !d.Satisfied:
+		return statusUnsatisfied
+	default:
+		return statusSatisfied
+	}
+}
+
+// A jsonPrinter prints the dependency graph as JSON.
+type jsonPrinter struct{}
+
+// Print prints the supplied dependency graph as JSON.
+func (p *jsonPrinter) Print(w io.Writer, g *Graph) error {
+	out, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, errMarshalJSON)
+	}
+	_, err = fmt.Fprintln(w, string(out))
+	return err
+}
+
+// A dotPrinter prints the dependency graph in Graphviz dot format. Missing
+// packages and unsatisfied dependencies are highlighted in red.
+type dotPrinter struct{}
+
+// Print prints the supplied dependency graph in dot format.
+func (p *dotPrinter) Print(w io.Writer, g *Graph) error {
+	dg := dot.NewGraph(dot.Directed)
+
+	nodes := make(map[string]dot.Node, len(g.Packages))
+	for _, pkg := range g.Packages {
+		label := []string{"Source: " + pkg.Source}
+		if pkg.Kind != "" {
+			label = append(label, "Kind: "+pkg.Kind)
+		}
+		if pkg.Installed {
+			label = append(label, "Version: "+pkg.Version)
+		} else {
+			label = append(label, "Missing")
+		}
+		n := dg.Node(pkg.Source).Label(strings.Join(label, "\n") + "\n")
+		n.Attr("penwidth", "2")
+		if !pkg.Installed {
+			n.Attr("color", "red")
+		}
+		nodes[pkg.Source] = n
+	}
+
+	for _, d := range g.Dependencies {
+		e := dg.Edge(nodes[d.Parent], nodes[d.Package]).Label(d.Constraints)
+		if !d.Satisfied {
+			e.Attr("color", "red")
+			e.Attr("fontcolor", "red")
+		}
+	}
+
+	dg.Write(w)
+	return nil
+}
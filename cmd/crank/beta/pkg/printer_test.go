@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testGraph() *Graph {
+	return &Graph{
+		Packages: []Package{
+			{Source: "example/config-a", Kind: "Configuration", Version: "v1.0.0", Installed: true},
+			{Source: "example/config-b", Kind: "Configuration", Version: "v1.1.0", Installed: true},
+			{Source: "example/function-d", Kind: "Function"},
+			{Source: "example/provider-c", Kind: "Provider", Version: "v0.3.0", Installed: true},
+		},
+		Dependencies: []Dependency{
+			{Parent: "example/config-a", Package: "example/config-b", Constraints: ">=v1.0.0", Satisfied: true},
+			{Parent: "example/config-a", Package: "example/function-d", Constraints: "v0.1.0"},
+			{Parent: "example/config-b", Package: "example/provider-c", Constraints: ">=v1.0.0"},
+		},
+	}
+}
+
+func TestTreePrinter(t *testing.T) {
+	want := `
+NAME                       KIND            VERSION   CONSTRAINTS   STATUS
+example/config-a           Configuration   v1.0.0                  
+├─ example/config-b        Configuration   v1.1.0    >=v1.0.0      Satisfied
+│  └─ example/provider-c   Provider        v0.3.0    >=v1.0.0      Unsatisfied
+└─ example/function-d      Function                  v0.1.0        Missing
+`
+
+	b := &bytes.Buffer{}
+	if err := (&treePrinter{}).Print(b, testGraph()); err != nil {
+		t.Fatalf("Print(...): %v", err)
+	}
+	if diff := cmp.Diff(strings.TrimPrefix(want, "\n"), b.String()); diff != "" {
+		t.Errorf("Print(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestDotPrinter(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := (&dotPrinter{}).Print(b, testGraph()); err != nil {
+		t.Fatalf("Print(...): %v", err)
+	}
+	got := b.String()
+
+	for _, want := range []string{
+		`label="Source: example/function-d\nKind: Function\nMissing\n"`,
+		`label="v0.1.0"`,
+		`color="red"`,
+		`label=">=v1.0.0"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Print(...): want output to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestNewPrinter(t *testing.T) {
+	for _, o := range []string{outputTree, outputJSON, outputDot} {
+		if _, err := newPrinter(o); err != nil {
+			t.Errorf("newPrinter(%q): %v", o, err)
+		}
+	}
+	if _, err := newPrinter("yaml"); err == nil {
+		t.Errorf("newPrinter(%q): want error, got nil", "yaml")
+	}
+}
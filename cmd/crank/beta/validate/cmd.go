@@ -59,6 +59,9 @@ If providers or configurations are provided as extensions, they will be download
 validation. If the cache directory is not provided, it will default to "~/.crossplane/cache".
 Cache directory can be cleaned before downloading schemas by setting the "clean-cache" flag.
 
+If ImageConfigs with mirrors are provided as extensions, packages are downloaded from their mirrors when their
+registry is unreachable or rate limits the download. Mirrors are accessed using your local registry credentials.
+
 All validation is performed offline locally using the Kubernetes API server's validation library, so it does not require
 any Crossplane instance or control plane to be running or configured.
 
@@ -18,6 +18,7 @@ package validate
 
 import (
 	"archive/tar"
+	"bytes"
 	"fmt"
 	"io"
 	"log"
@@ -27,12 +28,17 @@ import (
 	"strings"
 
 	"github.com/Masterminds/semver"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/crane"
 	"github.com/google/go-containerregistry/pkg/name"
 	conregv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"k8s.io/apimachinery/pkg/util/yaml"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
 const maxDecompressedSize = 200 * 1024 * 1024 // 200 MB
@@ -41,6 +47,7 @@ const maxDecompressedSize = 200 * 1024 * 1024 // 200 MB
 type ImageFetcher interface {
 	FetchBaseLayer(image string) (*conregv1.Layer, error)
 	FetchImage(image string) ([]conregv1.Layer, error)
+	FetchSchemas(image string) ([][]byte, error)
 }
 
 // Fetcher implements the ImageFetcher interface.
@@ -138,6 +145,105 @@ func (f *Fetcher) FetchBaseLayer(image string) (*conregv1.Layer, error) {
 	return &ll, nil
 }
 
+// FetchSchemas fetches the schemas artifact that refers to the image, if any.
+// It returns an xpkg.ArtifactNotFoundError if the image has no schemas
+// artifact.
+func (f *Fetcher) FetchSchemas(image string) ([][]byte, error) {
+	image, err := prepareImageReference(image)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to prepare image reference")
+	}
+
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid image reference: %s", image)
+	}
+
+	d, err := crane.Digest(image)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get digest of image %s", image)
+	}
+
+	idx, err := remote.Referrers(ref.Context().Digest(d), remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get referrers of image %s", image)
+	}
+	im, err := idx.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get referrers of image %s", image)
+	}
+
+	desc, err := xpkg.FindArtifact(im, xpkg.ArtifactTypeSchemas)
+	if err != nil {
+		return nil, err
+	}
+
+	art, err := remote.Image(ref.Context().Digest(desc.Digest.String()), remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot pull schemas artifact %s", desc.Digest)
+	}
+	b, err := xpkg.ArtifactContent(art)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read schemas artifact %s", desc.Digest)
+	}
+
+	return load(bytes.NewReader(b))
+}
+
+// A MirrorFetcher is an ImageFetcher that falls back to the registry mirrors
+// configured by ImageConfigs when an image's registry is unavailable. It uses
+// the local registry credentials for mirrors; the authentication and TLS
+// configuration of mirrors only applies inside a control plane.
+type MirrorFetcher struct {
+	fetcher      ImageFetcher
+	imageConfigs []pkgv1beta1.ImageConfig
+}
+
+// NewMirrorFetcher returns an ImageFetcher that falls back to the mirrors
+// configured by the supplied ImageConfigs.
+func NewMirrorFetcher(f ImageFetcher, ics []pkgv1beta1.ImageConfig) *MirrorFetcher {
+	return &MirrorFetcher{fetcher: f, imageConfigs: ics}
+}
+
+// FetchImage fetches the image, falling back to its mirrors.
+func (f *MirrorFetcher) FetchImage(image string) ([]conregv1.Layer, error) {
+	return fetchWithMirrors(f, image, f.fetcher.FetchImage)
+}
+
+// FetchBaseLayer fetches the base layer of the image, falling back to its
+// mirrors.
+func (f *MirrorFetcher) FetchBaseLayer(image string) (*conregv1.Layer, error) {
+	return fetchWithMirrors(f, image, f.fetcher.FetchBaseLayer)
+}
+
+// FetchSchemas fetches the schemas artifact of the image, falling back to its
+// mirrors.
+func (f *MirrorFetcher) FetchSchemas(image string) ([][]byte, error) {
+	return fetchWithMirrors(f, image, f.fetcher.FetchSchemas)
+}
+
+// fetchWithMirrors calls the supplied function to fetch the supplied image. If
+// the image's registry is unavailable it calls the function for each of the
+// image's mirrors, in order, until one is available.
+func fetchWithMirrors[T any](f *MirrorFetcher, image string, fetch func(image string) (T, error)) (T, error) {
+	out, err := fetch(image)
+	if !xpkg.IsUnavailable(err) {
+		return out, err
+	}
+
+	_, mirrors := xpkg.FindMirrors(f.imageConfigs, image)
+	errs := []error{err}
+	for _, m := range mirrors {
+		mout, mErr := fetch(m.Image)
+		if mErr == nil || !xpkg.IsUnavailable(mErr) {
+			// The mirror was available, so its result is definitive.
+			return mout, errors.Wrapf(mErr, "mirror %q", m.Image)
+		}
+		errs = append(errs, errors.Wrapf(mErr, "mirror %q", m.Image))
+	}
+	return out, errors.Join(errs...)
+}
+
 func findImageTagForVersionConstraint(image string) (string, error) {
 	// Separate the image base and the image tag
 	parts := strings.Split(image, ":")
@@ -22,6 +22,12 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
 )
 
 func TestFindImageTagForVersionConstraint(t *testing.T) {
@@ -144,3 +150,90 @@ func TestIsErrBaseLayerNotFound(t *testing.T) {
 		})
 	}
 }
+
+func TestMirrorFetcher(t *testing.T) {
+	ics := []pkgv1beta1.ImageConfig{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "mirrors"},
+			Spec: pkgv1beta1.ImageConfigSpec{
+				MatchImages: []pkgv1beta1.ImageMatch{{Prefix: "xpkg.crossplane.io/crossplane-contrib"}},
+				Mirrors: []pkgv1beta1.ImageMirror{
+					{Prefix: "mirror-a.example.org/contrib"},
+					{Prefix: "mirror-b.example.org/contrib"},
+				},
+			},
+		},
+	}
+	unavailable := &transport.Error{StatusCode: http.StatusServiceUnavailable}
+	notFound := &transport.Error{StatusCode: http.StatusNotFound}
+
+	cases := map[string]struct {
+		reason  string
+		image   string
+		results map[string]error
+		want    []string
+		wantErr bool
+	}{
+		"Available": {
+			reason:  "We shouldn't try mirrors if the image's registry is available.",
+			image:   "xpkg.crossplane.io/crossplane-contrib/provider-nop:v0.2.0",
+			results: map[string]error{},
+			want:    []string{"xpkg.crossplane.io/crossplane-contrib/provider-nop:v0.2.0"},
+		},
+		"FallBack": {
+			reason: "We should try each mirror in order until one is available.",
+			image:  "xpkg.crossplane.io/crossplane-contrib/provider-nop:v0.2.0",
+			results: map[string]error{
+				"xpkg.crossplane.io/crossplane-contrib/provider-nop:v0.2.0": unavailable,
+				"mirror-a.example.org/contrib/provider-nop:v0.2.0":          unavailable,
+			},
+			want: []string{
+				"xpkg.crossplane.io/crossplane-contrib/provider-nop:v0.2.0",
+				"mirror-a.example.org/contrib/provider-nop:v0.2.0",
+				"mirror-b.example.org/contrib/provider-nop:v0.2.0",
+			},
+		},
+		"MirrorDefinitive": {
+			reason: "We should return the error of the first available mirror.",
+			image:  "xpkg.crossplane.io/crossplane-contrib/provider-nop:v0.2.0",
+			results: map[string]error{
+				"xpkg.crossplane.io/crossplane-contrib/provider-nop:v0.2.0": unavailable,
+				"mirror-a.example.org/contrib/provider-nop:v0.2.0":          notFound,
+			},
+			want: []string{
+				"xpkg.crossplane.io/crossplane-contrib/provider-nop:v0.2.0",
+				"mirror-a.example.org/contrib/provider-nop:v0.2.0",
+			},
+			wantErr: true,
+		},
+		"NoMirrors": {
+			reason: "We should return the registry's error if the image has no mirrors.",
+			image:  "registry.example.org/provider-nop:v0.2.0",
+			results: map[string]error{
+				"registry.example.org/provider-nop:v0.2.0": unavailable,
+			},
+			want:    []string{"registry.example.org/provider-nop:v0.2.0"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			f := NewMirrorFetcher(&MockFetcher{
+				fetchSchemas: func(image string) ([][]byte, error) {
+					got = append(got, image)
+					return nil, tc.results[image]
+				},
+			}, ics)
+
+			_, err := f.FetchSchemas(tc.image)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("\n%s\nFetchSchemas(...): want error %t, got %v", tc.reason, tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nFetchSchemas(...): -want fetched images, +got fetched images:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
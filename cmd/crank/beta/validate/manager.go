@@ -32,7 +32,9 @@ import (
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	metav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/xcrd"
+	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
 const (
@@ -49,9 +51,10 @@ type Manager struct {
 	cache   Cache
 	writer  io.Writer
 
-	crds  []*extv1.CustomResourceDefinition
-	deps  map[string]bool                  // Dependency images
-	confs map[string]*metav1.Configuration // Configuration images
+	crds         []*extv1.CustomResourceDefinition
+	deps         map[string]bool                  // Dependency images
+	confs        map[string]*metav1.Configuration // Configuration images
+	imageConfigs []pkgv1beta1.ImageConfig         // ImageConfigs that may configure mirrors
 }
 
 // Option defines an option for the Manager.
@@ -159,6 +162,19 @@ func (m *Manager) PrepExtensions(extensions []*unstructured.Unstructured) error
 
 			m.confs[image] = nil
 
+		case schema.GroupKind{Group: "pkg.crossplane.io", Kind: "ImageConfig"}:
+			bytes, err := e.MarshalJSON()
+			if err != nil {
+				return errors.Wrap(err, "cannot marshal ImageConfig to JSON")
+			}
+
+			ic := pkgv1beta1.ImageConfig{}
+			if err := yaml.Unmarshal(bytes, &ic); err != nil {
+				return errors.Wrap(err, "cannot unmarshal ImageConfig YAML")
+			}
+
+			m.imageConfigs = append(m.imageConfigs, ic)
+
 		case schema.GroupKind{Group: "meta.pkg.crossplane.io", Kind: "Configuration"}:
 			meta, err := e.MarshalJSON()
 			if err != nil {
@@ -220,7 +236,7 @@ func (m *Manager) addDependencies(confs map[string]*metav1.Configuration) error
 		if cfg == nil {
 			m.deps[image] = true // we need to download the configuration package for the XRDs
 
-			layer, err := m.fetcher.FetchBaseLayer(image)
+			layer, err := m.imageFetcher().FetchBaseLayer(image)
 			if err != nil {
 				return errors.Wrapf(err, "cannot download package %s", image)
 			}
@@ -270,6 +286,15 @@ func (m *Manager) addDependencies(confs map[string]*metav1.Configuration) error
 	return m.addDependencies(deepConfs)
 }
 
+// imageFetcher returns the ImageFetcher used to download packages, which falls
+// back to the mirrors configured by any ImageConfig extensions.
+func (m *Manager) imageFetcher() ImageFetcher {
+	if len(m.imageConfigs) == 0 {
+		return m.fetcher
+	}
+	return NewMirrorFetcher(m.fetcher, m.imageConfigs)
+}
+
 func (m *Manager) cacheDependencies() error {
 	if err := m.cache.Init(); err != nil {
 		return errors.Wrapf(err, "cannot initialize cache directory")
@@ -289,13 +314,27 @@ func (m *Manager) cacheDependencies() error {
 			return errors.Wrapf(err, errWriteOutput)
 		}
 
-		var schemas [][]byte
+		// Prefer the package's schemas artifact, which is much smaller than
+		// the package. Fall back to the package only if there isn't one.
+		// Registries that don't support the referrers API are listed using
+		// the referrers tag schema, so they just have no artifacts.
+		schemas, err := m.imageFetcher().FetchSchemas(image)
+		switch {
+		case err == nil:
+			if err := m.cache.Store(schemas, path); err != nil {
+				return errors.Wrapf(err, "cannot store schemas")
+			}
+			continue
+		case !xpkg.IsArtifactNotFound(err):
+			return errors.Wrapf(err, "cannot fetch schemas for package %s", image)
+		}
+
 		// handling for packages
-		layer, err := m.fetcher.FetchBaseLayer(image)
+		layer, err := m.imageFetcher().FetchBaseLayer(image)
 		switch {
 		case IsErrBaseLayerNotFound(err):
 			// We fall back to fetching the image if the base layer is not found
-			layers, err := m.fetcher.FetchImage(image)
+			layers, err := m.imageFetcher().FetchImage(image)
 			if err != nil {
 				return errors.Wrapf(err, "cannot extract crds")
 			}
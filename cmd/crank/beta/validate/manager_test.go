@@ -19,6 +19,7 @@ package validate
 import (
 	"bytes"
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -28,7 +29,10 @@ import (
 	"github.com/spf13/afero"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
 var (
@@ -333,7 +337,7 @@ func TestConfigurationTypeSupport(t *testing.T) {
 
 		m := NewManager("", fs, w)
 		t.Run(name, func(t *testing.T) {
-			m.fetcher = &MockFetcher{tc.args.fetchMock, nil}
+			m.fetcher = &MockFetcher{fetchBaseLayer: tc.args.fetchMock}
 			err := m.PrepExtensions(tc.args.extensions)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -444,7 +448,7 @@ func TestAddDependencies(t *testing.T) {
 			//   └─►function-dep-1
 			reason: "All dependencies should be successfully fetched and added without specifying a crossplane image",
 			args: args{
-				fetcher: &MockFetcher{fetchMockFunc, nil},
+				fetcher: &MockFetcher{fetchBaseLayer: fetchMockFunc},
 				extensions: []*unstructured.Unstructured{
 					{
 						Object: map[string]interface{}{
@@ -492,9 +496,107 @@ func TestAddDependencies(t *testing.T) {
 	}
 }
 
+func TestCacheDependencies(t *testing.T) {
+	errBoom := errors.New("boom")
+	crd := []byte("apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nmetadata:\n  name: a\n")
+	baseLayer := static.NewLayer([]byte("meta: true\n---\n"+string(crd)+"---\nnot yaml"), types.OCILayer)
+
+	type want struct {
+		cached string
+		err    error
+	}
+	cases := map[string]struct {
+		reason  string
+		fetcher ImageFetcher
+		want    want
+	}{
+		"SchemasArtifact": {
+			reason: "We should cache the schemas artifact without fetching the package if the package has one.",
+			fetcher: &MockFetcher{
+				fetchSchemas: func(_ string) ([][]byte, error) {
+					return [][]byte{crd}, nil
+				},
+				fetchBaseLayer: func(_ string) (*conregv1.Layer, error) {
+					return nil, errBoom
+				},
+			},
+			want: want{
+				cached: string(crd) + "---\n",
+			},
+		},
+		"FallbackToBaseLayer": {
+			reason: "We should cache the schemas in the package's base layer if the package has no schemas artifact.",
+			fetcher: &MockFetcher{
+				fetchBaseLayer: func(_ string) (*conregv1.Layer, error) {
+					return &baseLayer, nil
+				},
+			},
+			want: want{
+				cached: string(crd) + "---\n",
+			},
+		},
+		"FetchSchemasError": {
+			reason: "We should return an error, rather than fall back to the package, if we can't fetch the schemas artifact for a reason other than it not existing.",
+			fetcher: &MockFetcher{
+				fetchSchemas: func(_ string) ([][]byte, error) {
+					return nil, errBoom
+				},
+				fetchBaseLayer: func(_ string) (*conregv1.Layer, error) {
+					return &baseLayer, nil
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, "cannot fetch schemas for package %s", "provider-a:v1.0.0"),
+			},
+		},
+		"FetchBaseLayerError": {
+			reason: "We should return an error if we can't fetch the schemas artifact or the package.",
+			fetcher: &MockFetcher{
+				fetchBaseLayer: func(_ string) (*conregv1.Layer, error) {
+					return nil, errBoom
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, "cannot download package %s", "provider-a:v1.0.0"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			m := NewManager("/cache", fs, &bytes.Buffer{})
+			m.fetcher = tc.fetcher
+			m.deps["provider-a:v1.0.0"] = true
+
+			err := m.cacheDependencies()
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncacheDependencies(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			got, err := afero.ReadFile(fs, filepath.Join("/cache", "provider-a@v1.0.0", packageFileName))
+			if err != nil {
+				t.Fatalf("ReadFile(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want.cached, string(got)); diff != "" {
+				t.Errorf("\n%s\ncacheDependencies(...): -want cached, +got cached:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 type MockFetcher struct {
 	fetchBaseLayer func(image string) (*conregv1.Layer, error)
 	fetchImage     func(image string) ([]conregv1.Layer, error)
+	fetchSchemas   func(image string) ([][]byte, error)
+}
+
+func (m *MockFetcher) FetchSchemas(image string) ([][]byte, error) {
+	if m.fetchSchemas != nil {
+		return m.fetchSchemas(image)
+	}
+	return nil, xpkg.NewArtifactNotFoundError(xpkg.ArtifactTypeSchemas)
 }
 
 func (m *MockFetcher) FetchBaseLayer(image string) (*conregv1.Layer, error) {
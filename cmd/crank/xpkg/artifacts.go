@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errReadPackageStream = "failed to read package.yaml from package"
+	errExtractSchemas    = "failed to extract schemas from package"
+	errFmtNewArtifact    = "failed to build %s artifact"
+	errFmtPushArtifact   = "failed to push %s artifact"
+)
+
+// An artifact to publish alongside a package.
+type artifact struct {
+	artifactType string
+	content      []byte
+}
+
+// packageArtifacts returns the artifacts that describe the supplied package
+// image: its package.yaml stream, its schemas, its examples if it has any, and
+// an SBOM. The supplied digest is the digest the package was pushed as.
+func packageArtifacts(ctx context.Context, img v1.Image, d name.Digest, now time.Time) ([]artifact, error) {
+	stream, err := xpkg.AnnotatedLayerFile(img, xpkg.PackageAnnotation, xpkg.StreamFile)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadPackageStream)
+	}
+	schemas, err := xpkg.Schemas(stream)
+	if err != nil {
+		return nil, errors.Wrap(err, errExtractSchemas)
+	}
+	arts := []artifact{
+		{artifactType: xpkg.ArtifactTypePackage, content: stream},
+		{artifactType: xpkg.ArtifactTypeSchemas, content: schemas},
+	}
+
+	// Examples are optional.
+	if ex, err := xpkg.AnnotatedLayerFile(img, xpkg.ExamplesAnnotation, xpkg.XpkgExamplesFile); err == nil {
+		arts = append(arts, artifact{artifactType: xpkg.ArtifactTypeExamples, content: ex})
+	}
+
+	meta, objs, err := parsePackage(ctx, img)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseSBOMPackage)
+	}
+	doc, err := generateSBOM(d, meta, objs, now)
+	if err != nil {
+		return nil, errors.Wrap(err, errGenerateSBOM)
+	}
+	sbom, err := json.Marshal(doc)
+	if err != nil {
+		return nil, errors.Wrap(err, errGenerateSBOM)
+	}
+	arts = append(arts, artifact{artifactType: xpkg.ArtifactTypeSBOM, content: sbom})
+
+	return arts, nil
+}
+
+// pushArtifacts pushes the supplied artifacts, each referring to the supplied
+// subject and annotated with the supplied creation time.
+func pushArtifacts(repo name.Repository, subject v1.Descriptor, arts []artifact, now time.Time, o ...remote.Option) error {
+	for _, a := range arts {
+		img, err := xpkg.NewArtifact(a.artifactType, a.content, subject, now)
+		if err != nil {
+			return errors.Wrapf(err, errFmtNewArtifact, a.artifactType)
+		}
+		d, err := img.Digest()
+		if err != nil {
+			return errors.Wrapf(err, errFmtNewArtifact, a.artifactType)
+		}
+		if err := remote.Write(repo.Digest(d.String()), img, o...); err != nil {
+			return errors.Wrapf(err, errFmtPushArtifact, a.artifactType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+func TestPublishArtifacts(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0)), registry.WithReferrersSupport(true)))
+	defer srv.Close()
+
+	ref := pushPackage(t, strings.TrimPrefix(srv.URL, "http://")+"/crossplane/configuration-example:v1.0.0")
+	img, err := remote.Image(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := partial.Descriptor(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := ref.Context().Digest(subject.Digest.String())
+
+	arts, err := packageArtifacts(ctx, img, d, time.Now())
+	if err != nil {
+		t.Fatalf("packageArtifacts(...): %v", err)
+	}
+	arts = append(arts, artifact{artifactType: xpkg.ArtifactTypeDocs, content: []byte("# Example\n")})
+	if err := pushArtifacts(ref.Context(), *subject, arts, time.Now()); err != nil {
+		t.Fatalf("pushArtifacts(...): %v", err)
+	}
+
+	idx, err := remote.Referrers(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	im, err := idx.IndexManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, 0, len(im.Manifests))
+	for _, m := range im.Manifests {
+		got = append(got, m.ArtifactType)
+	}
+	sort.Strings(got)
+	want := []string{xpkg.ArtifactTypeSBOM, xpkg.ArtifactTypeDocs, xpkg.ArtifactTypePackage, xpkg.ArtifactTypeSchemas}
+	sort.Strings(want)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Referrers(...): -want artifact types, +got:\n%s", diff)
+	}
+
+	desc, err := xpkg.FindArtifact(im, xpkg.ArtifactTypePackage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	art, err := remote.Image(ref.Context().Digest(desc.Digest.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := xpkg.ArtifactContent(art)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(configurationMeta, string(content)); diff != "" {
+		t.Errorf("ArtifactContent(...): -want package.yaml, +got:\n%s", diff)
+	}
+}
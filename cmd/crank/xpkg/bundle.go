@@ -0,0 +1,376 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/controller/pkg/resolver"
+	"github.com/crossplane/crossplane/internal/xpkg"
+	"github.com/crossplane/crossplane/internal/xpkg/parser/yaml"
+)
+
+const (
+	// annotationKeyRefName is the standard OCI annotation used to record the
+	// reference of each package in a bundle's image index.
+	annotationKeyRefName = "org.opencontainers.image.ref.name"
+
+	// maxBundleRounds bounds how many times we'll solve the dependency graph
+	// while fetching newly selected packages.
+	maxBundleRounds = 100
+
+	errFmtFetchPackage     = "failed to fetch package %s"
+	errFmtParsePackage     = "failed to parse package %s"
+	errFmtListTags         = "failed to list tags of package %s"
+	errFmtInvalidDep       = "package %s has an invalid dependency"
+	errPackageNoMeta       = "package does not contain exactly one package metadata object"
+	errResolveDependencies = "failed to resolve package dependencies"
+	errTooManyRounds       = "gave up resolving package dependencies: too many rounds"
+	errWriteLayout         = "failed to write OCI image layout"
+	errWriteBundle         = "failed to write bundle archive"
+)
+
+// tagsFn lists the tags of a package repository.
+type tagsFn func(context.Context, name.Repository) ([]string, error)
+
+// registryTags lists the tags of a package repository in a registry.
+func registryTags(ctx context.Context, r name.Repository) ([]string, error) {
+	// Use default docker auth, i.e. for private repositories.
+	kc := authn.NewMultiKeychain(authn.DefaultKeychain)
+	return remote.List(r, remote.WithContext(ctx), remote.WithAuthFromKeychain(kc))
+}
+
+// bundleCmd bundles a package and its dependencies.
+type bundleCmd struct {
+	// Arguments.
+	Package string `arg:"" help:"The package to bundle. Must be a valid OCI image reference, including a tag or digest."`
+
+	// Flags. Keep sorted alphabetically.
+	Output  string        `default:"bundle.tar" help:"Path to write the bundle archive to."                                  short:"o"`
+	Timeout time.Duration `default:"5m"         help:"How long to spend fetching packages before giving up."`
+
+	// Internal state. These aren't part of the user-exposed CLI structure.
+	fs    afero.Fs
+	fetch fetchFn
+	tags  tagsFn
+}
+
+func (c *bundleCmd) Help() string {
+	return `
+This command bundles a package and all of its dependencies into a single OCI
+image layout archive, for example to install the package in an air-gapped
+control plane. Dependency versions are resolved the same way the Crossplane
+package manager resolves them. Use crossplane xpkg mirror to push a bundle to a
+private registry.
+
+Credentials for the registry are read from Docker's configuration.
+
+Examples:
+
+  # Bundle a Configuration and all of its dependencies.
+  crossplane xpkg bundle xpkg.crossplane.io/crossplane/configuration-example:v1.0.0 -o example.tar
+`
+}
+
+// AfterApply sets up the bundle command.
+func (c *bundleCmd) AfterApply() error {
+	c.fs = afero.NewOsFs()
+	c.fetch = registryFetch
+	c.tags = registryTags
+	return nil
+}
+
+// Run runs the bundle cmd.
+func (c *bundleCmd) Run(logger logging.Logger) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	ref, err := name.ParseReference(c.Package, name.WithDefaultRegistry(xpkg.DefaultRegistry))
+	if err != nil {
+		return errors.Wrap(err, errInvalidTag)
+	}
+
+	pkgs, err := c.resolve(ctx, logger, ref)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "crossplane-bundle-")
+	if err != nil {
+		return errors.Wrap(err, errWriteLayout)
+	}
+	defer os.RemoveAll(dir) //nolint:errcheck // Not much we can do if this fails.
+
+	p, err := layout.Write(dir, empty.Index)
+	if err != nil {
+		return errors.Wrap(err, errWriteLayout)
+	}
+	for _, bp := range pkgs {
+		if err := p.AppendImage(bp.img, layout.WithAnnotations(map[string]string{annotationKeyRefName: bp.ref.String()})); err != nil {
+			return errors.Wrap(err, errWriteLayout)
+		}
+		logger.Debug("Added package to bundle", "ref", bp.ref.String())
+	}
+
+	f, err := c.fs.Create(filepath.Clean(c.Output))
+	if err != nil {
+		return errors.Wrap(err, errCreateOutputFile)
+	}
+	defer f.Close() //nolint:errcheck // We check the error closing the tar writer.
+
+	return errors.Wrap(tarDir(dir, f), errWriteBundle)
+}
+
+// A fetchedPackage is a package fetched from a registry.
+type fetchedPackage struct {
+	ref  name.Reference
+	img  v1.Image
+	meta pkgmetav1.Pkg
+	objs []runtime.Object
+}
+
+// resolve fetches the supplied package and all of its dependencies.
+func (c *bundleCmd) resolve(ctx context.Context, logger logging.Logger, ref name.Reference) ([]*fetchedPackage, error) {
+	root, fp, err := fetchPackage(ctx, c.fetch, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched := map[string]*fetchedPackage{root.Source: fp}
+	lock, err := solveLock(ctx, logger, c.fetch, c.tags, []v1beta1.LockPackage{root}, fetched, resolver.WithUpgrades(), resolver.WithDowngrades())
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*fetchedPackage, 0, len(lock))
+	for _, lp := range lock {
+		out = append(out, fetched[lp.Source])
+	}
+	return out, nil
+}
+
+// solveLock solves the supplied Lock using the same solver as the package
+// manager's dependency resolver. Each round it fetches any newly selected
+// packages, adds them to the Lock, and solves again until there's nothing left
+// to fetch. It returns the solved Lock, and records the packages it fetched,
+// keyed by Lock source.
+func solveLock(ctx context.Context, logger logging.Logger, fetch fetchFn, tags tagsFn, lock []v1beta1.LockPackage, fetched map[string]*fetchedPackage, o ...resolver.SolverOption) ([]v1beta1.LockPackage, error) {
+	s := resolver.NewSolver(func(ctx context.Context, source string) ([]string, error) {
+		r, err := name.NewRepository(source, name.WithDefaultRegistry(xpkg.DefaultRegistry))
+		if err != nil {
+			return nil, err
+		}
+		t, err := tags(ctx, r)
+		return t, errors.Wrapf(err, errFmtListTags, source)
+	}, o...)
+
+	for range maxBundleRounds {
+		sel, err := s.Solve(ctx, lock)
+		if err != nil {
+			return nil, errors.Wrap(err, errResolveDependencies)
+		}
+		if len(sel) == 0 {
+			return lock, nil
+		}
+
+		for _, s := range sel {
+			format := "%s:%s"
+			if strings.HasPrefix(s.Version, "sha256:") {
+				format = "%s@%s"
+			}
+			dref, err := name.ParseReference(fmt.Sprintf(format, s.Dependency.Package, s.Version), name.WithDefaultRegistry(xpkg.DefaultRegistry))
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtInvalidDep, s.Dependency.Package)
+			}
+			lp, fp, err := fetchPackage(ctx, fetch, dref)
+			if err != nil {
+				return nil, err
+			}
+			logger.Debug("Selected dependency", "ref", dref.String())
+
+			// The solver refers to packages by the source their
+			// dependents use, which might omit the default registry.
+			lp.Source = s.Dependency.Package
+			lp.Type = s.Dependency.Type
+			lp.APIVersion = s.Dependency.APIVersion
+			lp.Kind = s.Dependency.Kind
+			lock = upsertLockPackage(lock, lp)
+			fetched[lp.Source] = fp
+		}
+	}
+
+	return nil, errors.New(errTooManyRounds)
+}
+
+// fetchPackage fetches and parses the supplied package, returning a Lock
+// package that describes it.
+func fetchPackage(ctx context.Context, fetch fetchFn, ref name.Reference) (v1beta1.LockPackage, *fetchedPackage, error) {
+	img, err := fetch(ctx, ref)
+	if err != nil {
+		return v1beta1.LockPackage{}, nil, errors.Wrapf(err, errFmtFetchPackage, ref)
+	}
+	meta, objs, err := parsePackage(ctx, img)
+	if err != nil {
+		return v1beta1.LockPackage{}, nil, errors.Wrapf(err, errFmtParsePackage, ref)
+	}
+	deps, err := xpkg.LockDependencies(meta)
+	if err != nil {
+		return v1beta1.LockPackage{}, nil, errors.Wrapf(err, errFmtInvalidDep, ref)
+	}
+	lp := v1beta1.LockPackage{
+		Name:         xpkg.ToDNSLabel(ref.Context().RepositoryStr()),
+		Source:       xpkg.ParsePackageSourceFromReference(ref),
+		Version:      ref.Identifier(),
+		Dependencies: deps,
+	}
+	return lp, &fetchedPackage{ref: ref, img: img, meta: meta, objs: objs}, nil
+}
+
+// parsePackage returns the package metadata and objects of the supplied
+// package image.
+func parsePackage(ctx context.Context, img v1.Image) (pkgmetav1.Pkg, []runtime.Object, error) {
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errGetManifest)
+	}
+
+	// Use the annotated layer if there is one, otherwise flatten the image.
+	var tarc io.ReadCloser
+	for _, l := range manifest.Layers {
+		if a, ok := l.Annotations[xpkg.AnnotationKey]; !ok || a != xpkg.PackageAnnotation {
+			continue
+		}
+		if tarc != nil {
+			return nil, nil, errors.New(errMultipleAnnotatedLayers)
+		}
+		layer, err := img.LayerByDigest(l.Digest)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, errFetchLayer)
+		}
+		if tarc, err = layer.Uncompressed(); err != nil {
+			return nil, nil, errors.Wrap(err, errGetUncompressed)
+		}
+	}
+	if tarc == nil {
+		tarc = mutate.Extract(img)
+	}
+	defer tarc.Close() //nolint:errcheck // Only reading.
+
+	t := tar.NewReader(tarc)
+	for {
+		h, err := t.Next()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, errOpenPackageStream)
+		}
+		if filepath.Base(h.Name) == xpkg.StreamFile {
+			break
+		}
+	}
+
+	p, err := yaml.New()
+	if err != nil {
+		return nil, nil, err
+	}
+	pkg, err := p.Parse(ctx, io.NopCloser(t))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(pkg.GetMeta()) != 1 {
+		return nil, nil, errors.New(errPackageNoMeta)
+	}
+	meta, ok := xpkg.TryConvertToPkg(pkg.GetMeta()[0], &pkgmetav1.Provider{}, &pkgmetav1.Configuration{}, &pkgmetav1.Function{})
+	if !ok {
+		return nil, nil, errors.New(errPackageNoMeta)
+	}
+	return meta, pkg.GetObjects(), nil
+}
+
+// upsertLockPackage replaces the package with the same source as the supplied
+// package, or appends the supplied package.
+func upsertLockPackage(lock []v1beta1.LockPackage, lp v1beta1.LockPackage) []v1beta1.LockPackage {
+	for i := range lock {
+		if lock[i].Source == lp.Source {
+			lock[i] = lp
+			return lock
+		}
+	}
+	return append(lock, lp)
+}
+
+// tarDir writes the contents of the supplied directory to the supplied writer
+// as a tar archive.
+func tarDir(dir string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		h, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		h.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(h); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		f, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		defer f.Close() //nolint:errcheck // Only reading.
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+// packageImage returns a package image with the supplied package.yaml.
+func packageImage(t *testing.T, meta string) v1.Image {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	_ = tw.WriteHeader(&tar.Header{Name: xpkg.StreamFile, Mode: int64(xpkg.StreamFileMode), Size: int64(len(meta))})
+	_, _ = io.WriteString(tw, meta)
+	_ = tw.Close()
+
+	l, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer:       l,
+		Annotations: map[string]string{xpkg.AnnotationKey: xpkg.PackageAnnotation},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func TestBundleResolve(t *testing.T) {
+	errBoom := errors.New("boom")
+	configuration := `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: configuration-example
+spec:
+  dependsOn:
+  - provider: xpkg.crossplane.io/crossplane/provider-nop
+    version: ">=v0.2.0"
+  - function: xpkg.crossplane.io/crossplane/function-example
+    version: "<v2.0.0"
+`
+	provider := `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: provider-nop
+`
+	function := `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Function
+metadata:
+  name: function-example
+spec:
+  dependsOn:
+  - provider: xpkg.crossplane.io/crossplane/provider-nop
+    version: "<v0.4.0"
+`
+
+	images := map[string]v1.Image{
+		"xpkg.crossplane.io/crossplane/configuration-example:v1.0.0": packageImage(t, configuration),
+		"xpkg.crossplane.io/crossplane/provider-nop:v0.3.0":          packageImage(t, provider),
+		"xpkg.crossplane.io/crossplane/provider-nop:v0.4.0":          packageImage(t, provider),
+		"xpkg.crossplane.io/crossplane/function-example:v1.1.0":      packageImage(t, function),
+	}
+	tags := map[string][]string{
+		"xpkg.crossplane.io/crossplane/provider-nop":     {"v0.1.0", "v0.2.0", "v0.3.0", "v0.4.0"},
+		"xpkg.crossplane.io/crossplane/function-example": {"v1.0.0", "v1.1.0", "v2.0.0"},
+	}
+
+	type want struct {
+		refs []string
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		fetch  fetchFn
+		tags   tagsFn
+		want   want
+	}{
+		"ResolveDependencyTree": {
+			reason: "We should select versions of dependencies that satisfy every constraint in the tree, revisiting versions selected in earlier rounds.",
+			fetch: func(_ context.Context, r name.Reference) (v1.Image, error) {
+				img, ok := images[r.String()]
+				if !ok {
+					return nil, errors.Errorf("unexpected fetch of %s", r)
+				}
+				return img, nil
+			},
+			tags: func(_ context.Context, r name.Repository) ([]string, error) {
+				return tags[r.String()], nil
+			},
+			want: want{
+				refs: []string{
+					"xpkg.crossplane.io/crossplane/configuration-example:v1.0.0",
+					"xpkg.crossplane.io/crossplane/provider-nop:v0.3.0",
+					"xpkg.crossplane.io/crossplane/function-example:v1.1.0",
+				},
+			},
+		},
+		"FetchError": {
+			reason: "We should return an error if we can't fetch a package.",
+			fetch: func(_ context.Context, _ name.Reference) (v1.Image, error) {
+				return nil, errBoom
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtFetchPackage, "xpkg.crossplane.io/crossplane/configuration-example:v1.0.0"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &bundleCmd{fetch: tc.fetch, tags: tc.tags}
+			ref := mustParse(t, "xpkg.crossplane.io/crossplane/configuration-example:v1.0.0")
+			got, err := c.resolve(context.Background(), logging.NewNopLogger(), ref)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nresolve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			var refs []string
+			for _, bp := range got {
+				refs = append(refs, bp.ref.String())
+			}
+			if diff := cmp.Diff(tc.want.refs, refs); diff != "" {
+				t.Errorf("\n%s\nresolve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMirrorIndex(t *testing.T) {
+	errBoom := errors.New("boom")
+	img := packageImage(t, "")
+	idx := mutate.AppendManifests(empty.Index,
+		mutate.IndexAddendum{Add: img, Descriptor: v1.Descriptor{Annotations: map[string]string{annotationKeyRefName: "xpkg.crossplane.io/crossplane/provider-nop:v0.3.0"}}},
+		mutate.IndexAddendum{Add: img, Descriptor: v1.Descriptor{Annotations: map[string]string{annotationKeyRefName: "index.docker.io/crossplane/function-example:v1.1.0"}}},
+	)
+
+	type want struct {
+		pushed []string
+		ics    []*v1beta1.ImageConfig
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		idx    v1.ImageIndex
+		mirror string
+		push   error
+		want   want
+	}{
+		"MirrorBundle": {
+			reason: "We should push each package under the mirror and emit an ImageConfig per source registry.",
+			idx:    idx,
+			mirror: "registry.example.com/mirror/",
+			want: want{
+				pushed: []string{
+					"registry.example.com/mirror/xpkg.crossplane.io/crossplane/provider-nop:v0.3.0",
+					"registry.example.com/mirror/index.docker.io/crossplane/function-example:v1.1.0",
+				},
+				ics: []*v1beta1.ImageConfig{
+					mirrorImageConfig("mirror-index-docker-io", "index.docker.io/", "registry.example.com/mirror/index.docker.io/"),
+					mirrorImageConfig("mirror-xpkg-crossplane-io", "xpkg.crossplane.io/", "registry.example.com/mirror/xpkg.crossplane.io/"),
+				},
+			},
+		},
+		"SameRepositoryInDifferentRegistries": {
+			reason: "Packages with the same repository in different registries shouldn't collide in the mirror.",
+			idx: mutate.AppendManifests(empty.Index,
+				mutate.IndexAddendum{Add: img, Descriptor: v1.Descriptor{Annotations: map[string]string{annotationKeyRefName: "xpkg.crossplane.io/crossplane/provider-nop:v0.3.0"}}},
+				mutate.IndexAddendum{Add: img, Descriptor: v1.Descriptor{Annotations: map[string]string{annotationKeyRefName: "localhost:5000/crossplane/provider-nop:v0.3.0"}}},
+			),
+			mirror: "registry.example.com/mirror",
+			want: want{
+				pushed: []string{
+					"registry.example.com/mirror/xpkg.crossplane.io/crossplane/provider-nop:v0.3.0",
+					"registry.example.com/mirror/localhost-5000/crossplane/provider-nop:v0.3.0",
+				},
+				ics: []*v1beta1.ImageConfig{
+					mirrorImageConfig("mirror-localhost-5000", "localhost:5000/", "registry.example.com/mirror/localhost-5000/"),
+					mirrorImageConfig("mirror-xpkg-crossplane-io", "xpkg.crossplane.io/", "registry.example.com/mirror/xpkg.crossplane.io/"),
+				},
+			},
+		},
+		"PushError": {
+			reason: "We should return an error if we can't push a package.",
+			idx:    idx,
+			mirror: "registry.example.com/mirror",
+			push:   errBoom,
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtPushMirror, "xpkg.crossplane.io/crossplane/provider-nop:v0.3.0"),
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			var pushed []string
+			push := func(_ context.Context, ref name.Reference, _ v1.Image) error {
+				if tc.push != nil {
+					return tc.push
+				}
+				pushed = append(pushed, ref.String())
+				return nil
+			}
+			ics, err := mirrorIndex(context.Background(), logging.NewNopLogger(), tc.idx, tc.mirror, push)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nmirrorIndex(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pushed, pushed); diff != "" {
+				t.Errorf("\n%s\nmirrorIndex(...): -want pushed, +got pushed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ics, ics); diff != "" {
+				t.Errorf("\n%s\nmirrorIndex(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func mustParse(t *testing.T, ref string) name.Reference {
+	t.Helper()
+	r, err := name.ParseReference(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func mirrorImageConfig(name, match, rewrite string) *v1beta1.ImageConfig {
+	ic := &v1beta1.ImageConfig{
+		Spec: v1beta1.ImageConfigSpec{
+			MatchImages:  []v1beta1.ImageMatch{{Type: v1beta1.Prefix, Prefix: match}},
+			RewriteImage: &v1beta1.ImageRewrite{Prefix: rewrite},
+		},
+	}
+	ic.SetGroupVersionKind(v1beta1.ImageConfigGroupVersionKind)
+	ic.SetName(name)
+	return ic
+}
@@ -31,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
@@ -58,6 +59,8 @@ type installCmd struct {
 	Name    string `arg:"" help:"The name of the new package in the Crossplane API. Derived from the package repository and tag by default." optional:""`
 
 	// Flags. Keep sorted alphabetically.
+	DryRun               bool          `help:"Print what installing the package would change, without changing anything."`
+	Namespace            string        `default:"crossplane-system" help:"The namespace Crossplane is installed in. Used to plan a --dry-run."                                        short:"n"`
 	RuntimeConfig        string        `help:"Install the package with a runtime configuration (for example a DeploymentRuntimeConfig)."               placeholder:"NAME"`
 	ManualActivation     bool          `help:"Require the new package's first revision to be manually activated."                                      short:"m"`
 	PackagePullSecrets   []string      `help:"A comma-separated list of secrets the package manager should use to pull the package from the registry." placeholder:"NAME"`
@@ -80,18 +83,30 @@ Examples:
   # customconfig.
   crossplane xpkg install function upbound/function-example:v0.1.4 function-eg \
     --runtime-config=customconfig
+
+  # Print which dependencies installing a Configuration would install, upgrade
+  # or downgrade, and which CRDs, XRDs and Compositions it would change.
+  crossplane xpkg install configuration xpkg.crossplane.io/crossplane/configuration-example:v1.0.0 --dry-run
+
+A dry run fetches the package and its dependencies and resolves dependency
+versions against the control plane's package Lock, assuming automatic
+dependency upgrades and downgrades are enabled. It then dry-runs creating or
+updating each changed package's objects using the control plane's API server,
+and prints the plan as YAML. The plan includes any API versions a CRD or XRD
+would stop serving. It returns an error if the package manager would fail to
+install the package.
 `
 }
 
 // Run the package install cmd.
 func (c *installCmd) Run(k *kong.Context, logger logging.Logger) error {
+	ref, err := name.ParseReference(c.Package, name.WithDefaultRegistry(xpkg.DefaultRegistry))
+	if err != nil {
+		logger.Debug(errPkgIdentifier, "error", err)
+		return errors.Wrap(err, errPkgIdentifier)
+	}
 	pkgName := c.Name
 	if pkgName == "" {
-		ref, err := name.ParseReference(c.Package, name.WithDefaultRegistry(xpkg.DefaultRegistry))
-		if err != nil {
-			logger.Debug(errPkgIdentifier, "error", err)
-			return errors.Wrap(err, errPkgIdentifier)
-		}
 		pkgName = xpkg.ToDNSLabel(ref.Context().RepositoryStr())
 	}
 
@@ -123,16 +138,19 @@ func (c *installCmd) Run(k *kong.Context, logger logging.Logger) error {
 	switch c.Kind {
 	case "provider":
 		pkg = &v1.Provider{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: v1.ProviderKind},
 			ObjectMeta: metav1.ObjectMeta{Name: pkgName},
 			Spec:       v1.ProviderSpec{PackageSpec: spec},
 		}
 	case "configuration":
 		pkg = &v1.Configuration{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: v1.ConfigurationKind},
 			ObjectMeta: metav1.ObjectMeta{Name: pkgName},
 			Spec:       v1.ConfigurationSpec{PackageSpec: spec},
 		}
 	case "function":
 		pkg = &v1.Function{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: v1.FunctionKind},
 			ObjectMeta: metav1.ObjectMeta{Name: pkgName},
 			Spec:       v1.FunctionSpec{PackageSpec: spec},
 		}
@@ -156,6 +174,13 @@ func (c *installCmd) Run(k *kong.Context, logger logging.Logger) error {
 	logger.Debug("Found kubeconfig")
 
 	s := runtime.NewScheme()
+	if c.DryRun {
+		// A dry run gets and dry-runs the objects in packages.
+		if s, err = xpkg.BuildObjectScheme(); err != nil {
+			return errors.Wrap(err, errKubeClient)
+		}
+		_ = corev1.AddToScheme(s)
+	}
 	_ = v1.AddToScheme(s)
 	_ = v1beta1.AddToScheme(s)
 
@@ -165,6 +190,10 @@ func (c *installCmd) Run(k *kong.Context, logger logging.Logger) error {
 	}
 	logger.Debug("Created kubernetes client")
 
+	if c.DryRun {
+		return c.plan(k, logger, kube, pkg, ref)
+	}
+
 	timeout := 10 * time.Second
 	if c.Wait > 0 {
 		timeout = c.Wait
@@ -206,6 +235,34 @@ func (c *installCmd) Run(k *kong.Context, logger logging.Logger) error {
 	return err
 }
 
+// plan prints what installing the supplied package would change.
+func (c *installCmd) plan(k *kong.Context, logger logging.Logger, kube client.Client, pkg v1.Package, ref name.Reference) error {
+	timeout := 5 * time.Minute
+	if c.Wait > 0 {
+		timeout = c.Wait
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	p := &planner{kube: kube, fetch: registryFetch, tags: registryTags, namespace: c.Namespace}
+	plan, err := p.Plan(ctx, logger, pkg, ref)
+	if err != nil {
+		return errors.Wrap(warnIfNotFound(err), errPlanInstall)
+	}
+
+	b, err := yaml.Marshal(plan)
+	if err != nil {
+		return errors.Wrap(err, errMarshalInstallPlan)
+	}
+	if _, err := k.Stdout.Write(b); err != nil {
+		return err
+	}
+	if plan.Conflicts() {
+		return errors.New(errPlanHasConflicts)
+	}
+	return nil
+}
+
 // TODO(negz): What is this trying to do? My guess is its trying to handle the
 // case where the CRD of the package kind isn't installed. Perhaps we could be
 // clearer in the error?
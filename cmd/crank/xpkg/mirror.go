@@ -0,0 +1,286 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+	"github.com/crossplane/crossplane/internal/xpkg/upbound"
+	"github.com/crossplane/crossplane/internal/xpkg/upbound/credhelper"
+)
+
+const (
+	errReadBundle          = "failed to read bundle archive"
+	errReadLayout          = "failed to read OCI image layout from bundle"
+	errInvalidMirror       = "mirror is not a valid OCI repository prefix"
+	errFmtMissingRefName   = "bundled image %s has no reference annotation"
+	errFmtInvalidBundleRef = "bundled image reference %q is invalid"
+	errFmtPushMirror       = "failed to push %s to mirror"
+	errMarshalImageConfigs = "failed to marshal ImageConfigs"
+)
+
+// pushFn pushes an image to a registry.
+type pushFn func(context.Context, name.Reference, v1.Image) error
+
+// mirrorCmd pushes a bundle to a private registry.
+type mirrorCmd struct {
+	// Arguments.
+	Bundle string `arg:"" help:"Path to a bundle archive created by crossplane xpkg bundle." type:"existingfile"`
+	Mirror string `arg:"" help:"The registry and optional path to push packages to, for example registry.example.com/crossplane."`
+
+	// Common Upbound API configuration.
+	upbound.Flags `embed:""`
+
+	// Internal state. These aren't part of the user-exposed CLI structure.
+	push pushFn
+}
+
+func (c *mirrorCmd) Help() string {
+	return `
+This command pushes every package in a bundle created by crossplane xpkg bundle
+to a private registry. Each package keeps its registry, repository path and tag,
+prefixed with the mirror, so packages from different registries never collide.
+For example xpkg.crossplane.io/crossplane/provider-nop:v1.0.0 is pushed to
+registry.example.com/crossplane/xpkg.crossplane.io/crossplane/provider-nop:v1.0.0.
+A registry's port, if any, is separated from its host by a dash rather than a
+colon, which isn't allowed in repository paths.
+
+The command prints ImageConfigs with rewriteImage rules that configure
+Crossplane to pull the packages from the mirror. Apply them to the air-gapped
+control plane before installing packages.
+
+Examples:
+
+  # Mirror a bundle and configure a control plane to pull from the mirror.
+  crossplane xpkg mirror example.tar registry.example.com/crossplane > imageconfigs.yaml
+  kubectl apply -f imageconfigs.yaml
+`
+}
+
+// AfterApply sets up the mirror command.
+func (c *mirrorCmd) AfterApply(logger logging.Logger) error {
+	upCtx, err := upbound.NewFromFlags(c.Flags, upbound.AllowMissingProfile())
+	if err != nil {
+		return err
+	}
+
+	kc := authn.NewMultiKeychain(
+		authn.NewKeychainFromHelper(credhelper.New(
+			credhelper.WithLogger(logger),
+			credhelper.WithProfile(upCtx.ProfileName),
+			credhelper.WithDomain(upCtx.Domain.Hostname()),
+		)),
+		authn.DefaultKeychain,
+	)
+	t := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: upCtx.InsecureSkipTLSVerify, //nolint:gosec // we need to support insecure connections if requested
+		},
+	}
+	c.push = func(ctx context.Context, ref name.Reference, img v1.Image) error {
+		return remote.Write(ref, img, remote.WithContext(ctx), remote.WithAuthFromKeychain(kc), remote.WithTransport(t))
+	}
+	return nil
+}
+
+// Run runs the mirror cmd.
+func (c *mirrorCmd) Run(k *kong.Context, logger logging.Logger) error {
+	dir, err := os.MkdirTemp("", "crossplane-mirror-")
+	if err != nil {
+		return errors.Wrap(err, errReadBundle)
+	}
+	defer os.RemoveAll(dir) //nolint:errcheck // Not much we can do if this fails.
+
+	f, err := os.Open(filepath.Clean(c.Bundle))
+	if err != nil {
+		return errors.Wrap(err, errReadBundle)
+	}
+	defer f.Close() //nolint:errcheck // Only reading.
+	if err := untar(f, dir); err != nil {
+		return errors.Wrap(err, errReadBundle)
+	}
+
+	p, err := layout.FromPath(dir)
+	if err != nil {
+		return errors.Wrap(err, errReadLayout)
+	}
+	idx, err := p.ImageIndex()
+	if err != nil {
+		return errors.Wrap(err, errReadLayout)
+	}
+
+	ics, err := mirrorIndex(context.Background(), logger, idx, c.Mirror, c.push)
+	if err != nil {
+		return err
+	}
+
+	for _, ic := range ics {
+		b, err := yaml.Marshal(ic)
+		if err != nil {
+			return errors.Wrap(err, errMarshalImageConfigs)
+		}
+		if _, err := fmt.Fprintf(k.Stdout, "---\n%s", b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mirrorIndex pushes every image in the supplied bundle index to the supplied
+// mirror, and returns ImageConfigs that rewrite each mirrored registry to its
+// path in the mirror.
+func mirrorIndex(ctx context.Context, logger logging.Logger, idx v1.ImageIndex, mirror string, push pushFn) ([]*v1beta1.ImageConfig, error) {
+	mirror = strings.TrimSuffix(mirror, "/")
+	if _, err := name.NewRepository(mirror + "/x"); err != nil {
+		return nil, errors.Wrap(err, errInvalidMirror)
+	}
+
+	im, err := idx.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrap(err, errReadLayout)
+	}
+
+	registries := map[string]bool{}
+	for _, d := range im.Manifests {
+		rn, ok := d.Annotations[annotationKeyRefName]
+		if !ok {
+			return nil, errors.Errorf(errFmtMissingRefName, d.Digest)
+		}
+		ref, err := name.ParseReference(rn, name.WithDefaultRegistry(xpkg.DefaultRegistry))
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtInvalidBundleRef, rn)
+		}
+		img, err := idx.Image(d.Digest)
+		if err != nil {
+			return nil, errors.Wrap(err, errReadLayout)
+		}
+
+		target, err := mirrorReference(ref, mirror)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtInvalidBundleRef, rn)
+		}
+		if err := push(ctx, target, img); err != nil {
+			return nil, errors.Wrapf(err, errFmtPushMirror, ref)
+		}
+		logger.Debug("Pushed package to mirror", "ref", ref.String(), "mirror", target.String())
+
+		registries[ref.Context().RegistryStr()] = true
+	}
+
+	regs := make([]string, 0, len(registries))
+	for r := range registries {
+		regs = append(regs, r)
+	}
+	sort.Strings(regs)
+
+	ics := make([]*v1beta1.ImageConfig, len(regs))
+	for i, r := range regs {
+		ics[i] = &v1beta1.ImageConfig{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1beta1.ImageConfigGroupVersionKind.GroupVersion().String(),
+				Kind:       v1beta1.ImageConfigKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: "mirror-" + xpkg.ToDNSLabel(r)},
+			Spec: v1beta1.ImageConfigSpec{
+				MatchImages:  []v1beta1.ImageMatch{{Type: v1beta1.Prefix, Prefix: r + "/"}},
+				RewriteImage: &v1beta1.ImageRewrite{Prefix: mirrorRepository(mirror, r) + "/"},
+			},
+		}
+	}
+	return ics, nil
+}
+
+// mirrorReference returns the reference the supplied package should be pushed
+// to in the supplied mirror.
+func mirrorReference(ref name.Reference, mirror string) (name.Reference, error) {
+	repo := mirrorRepository(mirror, ref.Context().RegistryStr()) + "/" + ref.Context().RepositoryStr()
+	if d, ok := ref.(name.Digest); ok {
+		return name.NewDigest(repo + "@" + d.DigestStr())
+	}
+	return name.NewTag(repo + ":" + ref.Identifier())
+}
+
+// mirrorRepository returns the path under which packages from the supplied
+// registry are pushed to the supplied mirror. Keeping the registry in the path
+// stops packages with the same repository in different registries colliding.
+func mirrorRepository(mirror, registry string) string {
+	return mirror + "/" + strings.ToLower(strings.ReplaceAll(registry, ":", "-"))
+}
+
+// untar extracts the supplied tar archive to the supplied directory.
+func untar(r io.Reader, dir string) error {
+	t := tar.NewReader(r)
+	for {
+		h, err := t.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, filepath.Clean(h.Name))
+		if !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return errors.Errorf("invalid path %q in archive", h.Name)
+		}
+
+		switch h.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o750); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, t); err != nil { //nolint:gosec // The bundle is supplied by the user.
+				_ = f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,369 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Masterminds/semver"
+	"github.com/google/go-containerregistry/pkg/name"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	xv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v2alpha1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/controller/pkg/resolver"
+	"github.com/crossplane/crossplane/internal/controller/pkg/revision"
+)
+
+const (
+	// lockName is the name of the package manager's Lock.
+	lockName = "lock"
+
+	errGetLock            = "failed to get package Lock"
+	errFmtGetRevision     = "failed to get active revision of package %s"
+	errFmtUnknownPkgKind  = "package %s has unknown kind %q"
+	errConvertPlanObj     = "failed to convert object to unstructured"
+	errPlanHasConflicts   = "installing the package would fail; see the plan for details"
+	errMarshalInstallPlan = "failed to marshal install plan"
+	errPlanInstall        = "failed to plan package installation"
+)
+
+// Actions an install plan may take.
+const (
+	planActionInstall   = "Install"
+	planActionUpgrade   = "Upgrade"
+	planActionDowngrade = "Downgrade"
+	planActionUpdate    = "Update"
+	planActionCreate    = "Create"
+	planActionUnchanged = "Unchanged"
+)
+
+// An installPlan describes what installing a package would change.
+type installPlan struct {
+	// Packages that would be installed or changed, starting with the package
+	// being installed and followed by its dependencies.
+	Packages []planPackage `json:"packages"`
+
+	// Objects the changed packages would create or update.
+	Objects []planObject `json:"objects,omitempty"`
+}
+
+// Conflicts returns true if any package in the plan can't be installed.
+func (p *installPlan) Conflicts() bool {
+	for _, pkg := range p.Packages {
+		if pkg.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// A planPackage describes what installing a package would do to a package.
+type planPackage struct {
+	Source         string `json:"source"`
+	Kind           string `json:"kind,omitempty"`
+	Action         string `json:"action"`
+	CurrentVersion string `json:"currentVersion,omitempty"`
+	Version        string `json:"version"`
+
+	// Error is why the package's objects can't be created or updated.
+	Error string `json:"error,omitempty"`
+}
+
+// A planObject describes what installing a package would do to an object.
+type planObject struct {
+	Package    string `json:"package"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Action     string `json:"action"`
+
+	// DroppedVersions are API versions the object currently serves that it
+	// would no longer serve. Only set for CRDs and XRDs.
+	DroppedVersions []string `json:"droppedVersions,omitempty"`
+}
+
+// A planner plans the installation of a package without changing anything.
+type planner struct {
+	kube      client.Client
+	fetch     fetchFn
+	tags      tagsFn
+	namespace string
+}
+
+// Plan returns a plan to install the supplied package. It resolves the
+// package's dependencies against the package manager's Lock, then dry-runs the
+// creation or update of the objects of every package that would change.
+func (p *planner) Plan(ctx context.Context, logger logging.Logger, pkg v1.Package, ref name.Reference) (*installPlan, error) {
+	l := &v1beta1.Lock{}
+	if err := p.kube.Get(ctx, types.NamespacedName{Name: lockName}, l); resource.IgnoreNotFound(err) != nil {
+		return nil, errors.Wrap(err, errGetLock)
+	}
+
+	root, fp, err := fetchPackage(ctx, p.fetch, ref)
+	if err != nil {
+		return nil, err
+	}
+	kind := pkg.GetObjectKind().GroupVersionKind().Kind
+	root.Name = pkg.GetName()
+	root.APIVersion = ptr.To(v1.SchemeGroupVersion.String())
+	root.Kind = ptr.To(kind)
+	root.Type = ptr.To(v1beta1.PackageType(kind))
+
+	current := make(map[string]v1beta1.LockPackage, len(l.Packages))
+	for _, lp := range l.Packages {
+		current[lp.Source] = lp
+	}
+
+	// Installing the package would update the Lock, not replace the package
+	// that's already in it. Solve a copy so we can compare the two.
+	lock := upsertLockPackage(append([]v1beta1.LockPackage{}, l.Packages...), root)
+	fetched := map[string]*fetchedPackage{root.Source: fp}
+	solved, err := solveLock(ctx, logger, p.fetch, p.tags, lock, fetched, resolver.WithUpgrades(), resolver.WithDowngrades())
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &installPlan{}
+	for _, lp := range planOrder(solved, root.Source) {
+		cur, installed := current[lp.Source]
+		pp := planPackage{
+			Source:  lp.Source,
+			Kind:    packageKind(lp),
+			Action:  packageAction(cur.Version, lp.Version, installed),
+			Version: lp.Version,
+		}
+		if installed {
+			pp.CurrentVersion = cur.Version
+		}
+
+		fp, ok := fetched[lp.Source]
+		if !ok || pp.Action == planActionUnchanged {
+			plan.Packages = append(plan.Packages, pp)
+			continue
+		}
+
+		parent, err := p.parentRevision(ctx, lp, cur, installed)
+		if err != nil {
+			return nil, err
+		}
+
+		// The package manager would refuse to install the package if
+		// it couldn't establish control of its objects. That's part of
+		// the plan, not a reason to stop planning.
+		objs, err := planObjects(ctx, revision.NewAPIEstablisher(p.kube, p.namespace, 1), lp.Source, parent, fp.objs)
+		if err != nil {
+			pp.Error = err.Error()
+		}
+		plan.Packages = append(plan.Packages, pp)
+		plan.Objects = append(plan.Objects, objs...)
+	}
+
+	return plan, nil
+}
+
+// planObjects dry-runs the creation or update of the supplied package's
+// objects, as the supplied revision of the package would.
+func planObjects(ctx context.Context, e *revision.APIEstablisher, source string, parent v1.PackageRevision, in []runtime.Object) ([]planObject, error) {
+	cds, err := e.Validate(ctx, in, parent, true)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := make([]planObject, 0, len(cds))
+	for _, cd := range cds {
+		gvk := cd.Desired.GetObjectKind().GroupVersionKind()
+		o := planObject{
+			Package:    source,
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Name:       cd.Desired.GetName(),
+			Action:     planActionCreate,
+		}
+		if cd.Exists {
+			changed, err := specChanged(cd.Current, cd.Desired)
+			if err != nil {
+				return nil, err
+			}
+			o.Action = planActionUnchanged
+			if changed {
+				o.Action = planActionUpdate
+			}
+			o.DroppedVersions = droppedVersions(cd.Current, cd.Desired)
+		}
+		objs = append(objs, o)
+	}
+
+	sort.Slice(objs, func(i, j int) bool {
+		if objs[i].Kind != objs[j].Kind {
+			return objs[i].Kind < objs[j].Kind
+		}
+		return objs[i].Name < objs[j].Name
+	})
+	return objs, nil
+}
+
+// parentRevision returns the package revision that would establish control of
+// the supplied package's objects. We use the active revision of an installed
+// package; it's the revision that currently controls its objects. Otherwise we
+// use a new revision that doesn't exist in the API server.
+func (p *planner) parentRevision(ctx context.Context, lp, cur v1beta1.LockPackage, installed bool) (v1.PackageRevision, error) {
+	kind := packageKind(lp)
+	rev, gvk, err := newRevision(kind)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtUnknownPkgKind, lp.Source, kind)
+	}
+
+	if installed {
+		// The Lock names each package after its active revision.
+		if err := p.kube.Get(ctx, types.NamespacedName{Name: cur.Name}, rev); err != nil {
+			return nil, errors.Wrapf(err, errFmtGetRevision, lp.Source)
+		}
+		rev.GetObjectKind().SetGroupVersionKind(gvk)
+		return rev, nil
+	}
+
+	rev.GetObjectKind().SetGroupVersionKind(gvk)
+	rev.SetName(lp.Name)
+	rev.SetUID(uuid.NewUUID())
+	rev.SetLabels(map[string]string{v1.LabelParentPackage: lp.Name})
+	return rev, nil
+}
+
+// newRevision returns a new revision of the supplied package kind.
+func newRevision(kind string) (v1.PackageRevision, schema.GroupVersionKind, error) {
+	switch kind {
+	case v1.ProviderKind:
+		return &v1.ProviderRevision{}, v1.ProviderRevisionGroupVersionKind, nil
+	case v1.ConfigurationKind:
+		return &v1.ConfigurationRevision{}, v1.ConfigurationRevisionGroupVersionKind, nil
+	case v1.FunctionKind:
+		return &v1.FunctionRevision{}, v1.FunctionRevisionGroupVersionKind, nil
+	}
+	return nil, schema.GroupVersionKind{}, errors.New("unknown package kind")
+}
+
+// planOrder returns the supplied Lock packages, starting with the root package
+// and followed by the rest ordered by source.
+func planOrder(lock []v1beta1.LockPackage, root string) []v1beta1.LockPackage {
+	out := append([]v1beta1.LockPackage{}, lock...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if (out[i].Source == root) != (out[j].Source == root) {
+			return out[i].Source == root
+		}
+		return out[i].Source < out[j].Source
+	})
+	return out
+}
+
+// packageKind returns the kind of the supplied Lock package.
+func packageKind(lp v1beta1.LockPackage) string {
+	if lp.Kind != nil {
+		return *lp.Kind
+	}
+	if lp.Type != nil {
+		return string(*lp.Type)
+	}
+	return ""
+}
+
+// packageAction returns what installing a package would do to a package at the
+// supplied current version.
+func packageAction(current, desired string, installed bool) string {
+	switch {
+	case !installed:
+		return planActionInstall
+	case current == desired:
+		return planActionUnchanged
+	}
+	cv, cerr := semver.NewVersion(current)
+	dv, derr := semver.NewVersion(desired)
+	switch {
+	case cerr != nil || derr != nil:
+		// At least one version is a digest. We can't tell which is newer.
+		return planActionUpdate
+	case dv.LessThan(cv):
+		return planActionDowngrade
+	}
+	return planActionUpgrade
+}
+
+// specChanged returns true if the supplied objects differ, ignoring their
+// metadata and status.
+func specChanged(current, desired runtime.Object) (bool, error) {
+	c, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+	if err != nil {
+		return false, errors.Wrap(err, errConvertPlanObj)
+	}
+	d, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return false, errors.Wrap(err, errConvertPlanObj)
+	}
+	for _, f := range []string{"apiVersion", "kind", "metadata", "status"} {
+		delete(c, f)
+		delete(d, f)
+	}
+	return !equality.Semantic.DeepEqual(c, d), nil
+}
+
+// droppedVersions returns the versions the current CRD or XRD serves that the
+// desired CRD or XRD doesn't.
+func droppedVersions(current, desired runtime.Object) []string {
+	served := servedVersions(desired)
+	var dropped []string
+	for v, s := range servedVersions(current) {
+		if s && !served[v] {
+			dropped = append(dropped, v)
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
+
+// servedVersions returns the versions served by the supplied CRD or XRD.
+func servedVersions(o runtime.Object) map[string]bool {
+	served := map[string]bool{}
+	switch d := o.(type) {
+	case *extv1.CustomResourceDefinition:
+		for _, v := range d.Spec.Versions {
+			served[v.Name] = v.Served
+		}
+	case *xv1.CompositeResourceDefinition:
+		for _, v := range d.Spec.Versions {
+			served[v.Name] = v.Served
+		}
+	case *v2alpha1.CompositeResourceDefinition:
+		for _, v := range d.Spec.Versions {
+			served[v.Name] = v.Served
+		}
+	}
+	return served
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+func TestPlan(t *testing.T) {
+	errBoom := errors.New("boom")
+	configuration := `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: configuration-example
+spec:
+  dependsOn:
+  - provider: xpkg.crossplane.io/crossplane/provider-nop
+    version: ">=v0.4.0"
+`
+	provider := `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: provider-nop
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: nopresources.nop.crossplane.io
+spec:
+  group: nop.crossplane.io
+  names:
+    kind: NopResource
+    plural: nopresources
+  scope: Cluster
+  versions:
+  - name: v1
+    served: true
+    storage: true
+`
+	images := map[string]v1.Image{
+		"xpkg.crossplane.io/crossplane/configuration-example:v1.0.0": packageImage(t, configuration),
+		"xpkg.crossplane.io/crossplane/provider-nop:v0.4.0":          packageImage(t, provider),
+	}
+	fetch := func(_ context.Context, r name.Reference) (v1.Image, error) {
+		img, ok := images[r.String()]
+		if !ok {
+			return nil, errors.Errorf("unexpected fetch of %s", r)
+		}
+		return img, nil
+	}
+	tags := func(_ context.Context, _ name.Repository) ([]string, error) {
+		return []string{"v0.3.0", "v0.4.0"}, nil
+	}
+
+	lock := &v1beta1.Lock{
+		Packages: []v1beta1.LockPackage{{
+			Name:    "crossplane-provider-nop-0123456789ab",
+			Kind:    ptr.To(pkgv1.ProviderKind),
+			Source:  "xpkg.crossplane.io/crossplane/provider-nop",
+			Version: "v0.3.0",
+		}},
+	}
+
+	type want struct {
+		plan *installPlan
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		want   want
+	}{
+		"UpgradeDependency": {
+			reason: "We should plan to install the package and upgrade its dependency, noting CRD versions that would no longer be served.",
+			kube: &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					switch o := obj.(type) {
+					case *v1beta1.Lock:
+						lock.DeepCopyInto(o)
+					case *pkgv1.ProviderRevision:
+						o.SetName("crossplane-provider-nop-0123456789ab")
+						o.SetUID("rev-uid")
+					case *extv1.CustomResourceDefinition:
+						nopCRD("rev-uid", "v1alpha1", "v1").DeepCopyInto(o)
+					default:
+						return errors.Errorf("unexpected get of %T", obj)
+					}
+					return nil
+				},
+				MockUpdate: test.NewMockUpdateFn(nil),
+			},
+			want: want{
+				plan: &installPlan{
+					Packages: []planPackage{
+						{
+							Source:  "xpkg.crossplane.io/crossplane/configuration-example",
+							Kind:    pkgv1.ConfigurationKind,
+							Action:  planActionInstall,
+							Version: "v1.0.0",
+						},
+						{
+							Source:         "xpkg.crossplane.io/crossplane/provider-nop",
+							Kind:           pkgv1.ProviderKind,
+							Action:         planActionUpgrade,
+							CurrentVersion: "v0.3.0",
+							Version:        "v0.4.0",
+						},
+					},
+					Objects: []planObject{{
+						Package:         "xpkg.crossplane.io/crossplane/provider-nop",
+						APIVersion:      "apiextensions.k8s.io/v1",
+						Kind:            "CustomResourceDefinition",
+						Name:            "nopresources.nop.crossplane.io",
+						Action:          planActionUpdate,
+						DroppedVersions: []string{"v1alpha1"},
+					}},
+				},
+			},
+		},
+		"ControlConflict": {
+			reason: "We should record that a package can't be installed if another package controls its objects.",
+			kube: &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					switch o := obj.(type) {
+					case *v1beta1.Lock:
+						return kerrors.NewNotFound(schema.GroupResource{}, lockName)
+					case *extv1.CustomResourceDefinition:
+						nopCRD("other-uid", "v1").DeepCopyInto(o)
+					default:
+						return errors.Errorf("unexpected get of %T", obj)
+					}
+					return nil
+				},
+			},
+			want: want{
+				plan: &installPlan{
+					Packages: []planPackage{
+						{
+							Source:  "xpkg.crossplane.io/crossplane/configuration-example",
+							Kind:    pkgv1.ConfigurationKind,
+							Action:  planActionInstall,
+							Version: "v1.0.0",
+						},
+						{
+							Source:  "xpkg.crossplane.io/crossplane/provider-nop",
+							Kind:    pkgv1.ProviderKind,
+							Action:  planActionInstall,
+							Version: "v0.4.0",
+							Error:   "nopresources.nop.crossplane.io is already controlled by ProviderRevision crossplane-provider-nop-0123456789ab (UID other-uid)",
+						},
+					},
+				},
+			},
+		},
+		"GetLockError": {
+			reason: "We should return an error if we can't get the Lock.",
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(errBoom),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetLock),
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			p := &planner{kube: tc.kube, fetch: fetch, tags: tags, namespace: "crossplane-system"}
+			pkg := &pkgv1.Configuration{
+				TypeMeta:   metav1.TypeMeta{APIVersion: pkgv1.SchemeGroupVersion.String(), Kind: pkgv1.ConfigurationKind},
+				ObjectMeta: metav1.ObjectMeta{Name: "crossplane-configuration-example"},
+			}
+			ref := mustParse(t, "xpkg.crossplane.io/crossplane/configuration-example:v1.0.0")
+			got, err := p.Plan(context.Background(), logging.NewNopLogger(), pkg, ref)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPlan(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.plan, got); diff != "" {
+				t.Errorf("\n%s\nPlan(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPackageAction(t *testing.T) {
+	cases := map[string]struct {
+		reason    string
+		current   string
+		desired   string
+		installed bool
+		want      string
+	}{
+		"NotInstalled": {
+			reason:  "A package that isn't installed would be installed.",
+			desired: "v1.0.0",
+			want:    planActionInstall,
+		},
+		"SameVersion": {
+			reason:    "A package at the desired version would be unchanged.",
+			current:   "v1.0.0",
+			desired:   "v1.0.0",
+			installed: true,
+			want:      planActionUnchanged,
+		},
+		"Upgrade": {
+			reason:    "A package at an older version would be upgraded.",
+			current:   "v1.0.0",
+			desired:   "v1.1.0",
+			installed: true,
+			want:      planActionUpgrade,
+		},
+		"Downgrade": {
+			reason:    "A package at a newer version would be downgraded.",
+			current:   "v1.1.0",
+			desired:   "v1.0.0",
+			installed: true,
+			want:      planActionDowngrade,
+		},
+		"Digest": {
+			reason:    "A package pinned to a digest would be updated.",
+			current:   "v1.0.0",
+			desired:   "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d6e4a6c4a3b2bc4",
+			installed: true,
+			want:      planActionUpdate,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := packageAction(tc.current, tc.desired, tc.installed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\npackageAction(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// nopCRD returns a CRD controlled by the supplied UID that serves the supplied
+// versions.
+func nopCRD(controller string, versions ...string) *extv1.CustomResourceDefinition {
+	crd := &extv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "nopresources.nop.crossplane.io",
+			ResourceVersion: "1",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: pkgv1.SchemeGroupVersion.String(),
+				Kind:       pkgv1.ProviderRevisionKind,
+				Name:       "crossplane-provider-nop-0123456789ab",
+				UID:        types.UID(controller),
+				Controller: ptr.To(true),
+			}},
+		},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "nop.crossplane.io",
+			Names: extv1.CustomResourceDefinitionNames{Kind: "NopResource", Plural: "nopresources"},
+			Scope: extv1.ClusterScoped,
+		},
+	}
+	for _, v := range versions {
+		crd.Spec.Versions = append(crd.Spec.Versions, extv1.CustomResourceDefinitionVersion{Name: v, Served: true, Storage: v == "v1"})
+	}
+	return crd
+}
@@ -23,12 +23,14 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/empty"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/spf13/afero"
@@ -55,6 +57,8 @@ const (
 	errFmtGetMediaType  = "failed to get media type of package file %s"
 	errFmtGetConfigFile = "failed to get OCI config file of package file %s"
 	errFmtWriteIndex    = "failed to push an OCI image index of %d packages"
+	errGetIndexDigest   = "failed to get digest of OCI image index"
+	errFmtReadDocs      = "failed to read documentation file %s"
 )
 
 // pushCmd pushes a package.
@@ -63,7 +67,13 @@ type pushCmd struct {
 	Package string `arg:"" help:"Where to push the package."`
 
 	// Flags. Keep sorted alphabetically.
-	PackageFiles []string `help:"A comma-separated list of xpkg files to push." placeholder:"PATH" predictor:"xpkg_file" short:"f" type:"existingfile"`
+	Artifacts    bool     `help:"Publish the package's metadata, schemas, examples, and SBOM as OCI artifacts that refer to it."`
+	Docs         string   `help:"A Markdown file to publish as the package's documentation artifact."                            placeholder:"PATH" type:"existingfile"`
+	PackageFiles []string `help:"A comma-separated list of xpkg files to push."                                                  placeholder:"PATH" predictor:"xpkg_file" short:"f" type:"existingfile"`
+	Sign         bool     `help:"Sign the pushed package digest using the supplied --key."`
+
+	// Signing configuration, used if --sign is set.
+	signFlags `embed:""`
 
 	// Common Upbound API configuration.
 	upbound.Flags `embed:""`
@@ -79,6 +89,13 @@ xpkg.upbound.io registry by default. A package's OCI tag must be a semantic
 version. Credentials for the registry are automatically retrieved from xpkg login 
 and dockers configuration as fallback.
 
+With --artifacts the package's package.yaml, schemas, examples, and an SBOM are
+also published as OCI artifacts that refer to the package using the OCI
+referrers API. crossplane beta validate fetches the schemas artifact, if there
+is one, rather than pulling the package. The package manager doesn't use these
+artifacts. They aren't covered by the package's digest or signature, and the
+package manager only pulls the package's annotated base layer anyway.
+
 Examples:
 
   # Push a multi-platform package.
@@ -86,6 +103,15 @@ Examples:
 
   # Push the xpkg file in the current directory to a different registry.
   crossplane xpkg push index.docker.io/crossplane/function-example:v1.0.0
+
+  # Push a package, then sign it and attach an SBOM attestation.
+  crossplane xpkg push --sign --key cosign.key --sbom crossplane/function-example:v1.0.0
+
+  # Push a package and publish its documentation as an OCI artifact.
+  crossplane xpkg push --docs README.md crossplane/function-example:v1.0.0
+
+  # Push a package and publish its metadata as OCI artifacts.
+  crossplane xpkg push --artifacts crossplane/function-example:v1.0.0
 `
 }
 
@@ -122,24 +148,15 @@ func (c *pushCmd) Run(logger logging.Logger) error { //nolint:gocognit // This f
 		logger.Debug("Found package in directory", "path", path)
 	}
 
-	kc := authn.NewMultiKeychain(
-		authn.NewKeychainFromHelper(credhelper.New(
-			credhelper.WithLogger(logger),
-			credhelper.WithProfile(upCtx.ProfileName),
-			credhelper.WithDomain(upCtx.Domain.Hostname()),
-		)),
-		authn.DefaultKeychain,
-	)
-
-	t := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: upCtx.InsecureSkipTLSVerify, //nolint:gosec // we need to support insecure connections if requested
-		},
-	}
+	options := remoteOptions(upCtx, logger)
 
-	options := []remote.Option{
-		remote.WithAuthFromKeychain(kc),
-		remote.WithTransport(t),
+	// Load the signing key before we push, so we don't push a package we
+	// can't sign.
+	var signer *packageSigner
+	if c.Sign {
+		if signer, err = newPackageSigner(c.signFlags, options...); err != nil {
+			return err
+		}
 	}
 
 	// If there's only one package file, handle the simple path.
@@ -156,7 +173,18 @@ func (c *pushCmd) Run(logger logging.Logger) error { //nolint:gocognit // This f
 			return errors.Wrapf(err, errFmtPushPackage, c.PackageFiles[0])
 		}
 		logger.Debug("Pushed package", "path", c.PackageFiles[0], "ref", tag.String())
-		return nil
+		d, err := img.Digest()
+		if err != nil {
+			return errors.Wrapf(err, errFmtGetDigest, c.PackageFiles[0])
+		}
+		subject, err := partial.Descriptor(img)
+		if err != nil {
+			return errors.Wrapf(err, errFmtGetDigest, c.PackageFiles[0])
+		}
+		if err := c.publish(context.Background(), logger, img, *subject, tag.Digest(d.String()), options...); err != nil {
+			return err
+		}
+		return c.sign(context.Background(), logger, signer, tag.Digest(d.String()))
 	}
 
 	// If there's more than one package file we'll write (push) them all by
@@ -219,9 +247,88 @@ func (c *pushCmd) Run(logger logging.Logger) error { //nolint:gocognit // This f
 		return err
 	}
 
-	if err := remote.WriteIndex(tag, mutate.AppendManifests(empty.Index, adds...), options...); err != nil {
+	idx := mutate.AppendManifests(empty.Index, adds...)
+	if err := remote.WriteIndex(tag, idx, options...); err != nil {
 		return errors.Wrapf(err, errFmtWriteIndex, len(adds))
 	}
 	logger.Debug("Wrote OCI index", "ref", tag.String(), "manifests", len(adds))
+	d, err := idx.Digest()
+	if err != nil {
+		return errors.Wrap(err, errGetIndexDigest)
+	}
+	subject, err := partial.Descriptor(idx)
+	if err != nil {
+		return errors.Wrap(err, errGetIndexDigest)
+	}
+	// Every package in the index has the same metadata, so we publish
+	// artifacts derived from the first one, referring to the index.
+	img := adds[0].Add.(v1.Image) //nolint:forcetypeassert // We only add images.
+	if err := c.publish(context.Background(), logger, img, *subject, tag.Digest(d.String()), options...); err != nil {
+		return err
+	}
+	return c.sign(context.Background(), logger, signer, tag.Digest(d.String()))
+}
+
+// publish publishes OCI artifacts describing the supplied pushed package, if
+// artifact publishing is enabled.
+func (c *pushCmd) publish(ctx context.Context, logger logging.Logger, img v1.Image, subject v1.Descriptor, d name.Digest, o ...remote.Option) error {
+	if !c.Artifacts && c.Docs == "" {
+		return nil
+	}
+	now := time.Now()
+	var arts []artifact
+	if c.Artifacts {
+		a, err := packageArtifacts(ctx, img, d, now)
+		if err != nil {
+			return err
+		}
+		arts = append(arts, a...)
+	}
+	if c.Docs != "" {
+		b, err := afero.ReadFile(c.fs, c.Docs)
+		if err != nil {
+			return errors.Wrapf(err, errFmtReadDocs, c.Docs)
+		}
+		arts = append(arts, artifact{artifactType: xpkg.ArtifactTypeDocs, content: b})
+	}
+	if err := pushArtifacts(d.Context(), subject, arts, now, append(o, remote.WithContext(ctx))...); err != nil {
+		return err
+	}
+	logger.Debug("Published package artifacts", "ref", d.String(), "artifacts", len(arts))
 	return nil
 }
+
+// sign signs the supplied pushed package digest, if signing is enabled.
+func (c *pushCmd) sign(ctx context.Context, logger logging.Logger, s *packageSigner, d name.Digest) error {
+	if s == nil {
+		return nil
+	}
+	if err := s.Sign(ctx, d); err != nil {
+		return err
+	}
+	logger.Debug("Signed package", "ref", d.String())
+	return nil
+}
+
+// remoteOptions returns the options used to interact with the registry.
+func remoteOptions(upCtx *upbound.Context, logger logging.Logger) []remote.Option {
+	kc := authn.NewMultiKeychain(
+		authn.NewKeychainFromHelper(credhelper.New(
+			credhelper.WithLogger(logger),
+			credhelper.WithProfile(upCtx.ProfileName),
+			credhelper.WithDomain(upCtx.Domain.Hostname()),
+		)),
+		authn.DefaultKeychain,
+	)
+
+	t := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: upCtx.InsecureSkipTLSVerify, //nolint:gosec // we need to support insecure connections if requested
+		},
+	}
+
+	return []remote.Option{
+		remote.WithAuthFromKeychain(kc),
+		remote.WithTransport(t),
+	}
+}
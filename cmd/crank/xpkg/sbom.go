@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	spdxVersion    = "SPDX-2.3"
+	spdxLicense    = "CC0-1.0"
+	spdxNoAssert   = "NOASSERTION"
+	spdxDocumentID = "SPDXRef-DOCUMENT"
+	spdxPackageID  = "SPDXRef-Package"
+	spdxCreator    = "Tool: crossplane-cli"
+)
+
+// An spdxDocument is the subset of an SPDX 2.3 JSON document we generate.
+// See https://spdx.github.io/spdx-spec/v2.3/.
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Summary          string            `json:"summary,omitempty"`
+	Comment          string            `json:"comment,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// generateSBOM returns an SPDX document describing the supplied package, its
+// contents, and its dependencies.
+func generateSBOM(ref name.Digest, meta pkgmetav1.Pkg, objs []runtime.Object, now time.Time) (*spdxDocument, error) {
+	deps, err := xpkg.LockDependencies(meta)
+	if err != nil {
+		return nil, errors.Wrap(err, errInvalidDependencies)
+	}
+
+	kinds := map[string]int{}
+	for _, o := range objs {
+		kinds[o.GetObjectKind().GroupVersionKind().Kind]++
+	}
+	contents := make([]string, 0, len(kinds))
+	for k, n := range kinds {
+		contents = append(contents, fmt.Sprintf("%d %s", n, k))
+	}
+	sort.Strings(contents)
+
+	repo := ref.Context().Name()
+	doc := &spdxDocument{
+		SPDXVersion:       spdxVersion,
+		DataLicense:       spdxLicense,
+		SPDXID:            spdxDocumentID,
+		Name:              repo,
+		DocumentNamespace: fmt.Sprintf("https://crossplane.io/spdx/%s@%s", repo, ref.DigestStr()),
+		CreationInfo: spdxCreationInfo{
+			Created:  now.UTC().Format(time.RFC3339),
+			Creators: []string{spdxCreator},
+		},
+		Packages: []spdxPackage{{
+			Name:             repo,
+			SPDXID:           spdxPackageID,
+			VersionInfo:      ref.DigestStr(),
+			DownloadLocation: ref.String(),
+			Summary:          fmt.Sprintf("Crossplane %s package %s", meta.GetObjectKind().GroupVersionKind().Kind, meta.GetName()),
+			ExternalRefs:     []spdxExternalRef{purl(ref.Context(), ref.DigestStr())},
+		}},
+		Relationships: []spdxRelationship{{
+			SPDXElementID:      spdxDocumentID,
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: spdxPackageID,
+		}},
+	}
+
+	if len(contents) > 0 {
+		doc.Packages[0].Comment = "Contents: " + strings.Join(contents, ", ")
+	}
+
+	for i, d := range deps {
+		id := fmt.Sprintf("SPDXRef-Dependency-%d", i)
+		doc.Packages = append(doc.Packages, spdxPackage{
+			Name:             d.Package,
+			SPDXID:           id,
+			DownloadLocation: spdxNoAssert,
+			Comment:          fmt.Sprintf("Version constraints: %s", d.Constraints),
+		})
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      spdxPackageID,
+			RelationshipType:   "DEPENDS_ON",
+			RelatedSPDXElement: id,
+		})
+	}
+
+	return doc, nil
+}
+
+// purl returns a package URL external reference for the supplied OCI
+// repository and digest. See https://github.com/package-url/purl-spec.
+func purl(repo name.Repository, digest string) spdxExternalRef {
+	return spdxExternalRef{
+		ReferenceCategory: "PACKAGE-MANAGER",
+		ReferenceType:     "purl",
+		ReferenceLocator:  fmt.Sprintf("pkg:oci/%s@%s?repository_url=%s", path.Base(repo.RepositoryStr()), strings.ReplaceAll(digest, ":", "%3A"), repo.Name()),
+	}
+}
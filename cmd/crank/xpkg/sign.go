@@ -0,0 +1,351 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/attestation"
+	cbundle "github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	rekor "github.com/sigstore/rekor/pkg/client"
+	rekorclient "github.com/sigstore/rekor/pkg/generated/client"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/internal/xpkg"
+	"github.com/crossplane/crossplane/internal/xpkg/upbound"
+)
+
+const (
+	// envKeyPrefix is the prefix of a --key that refers to an environment
+	// variable rather than a file.
+	envKeyPrefix = "env://"
+
+	// envKeyPassword is the environment variable cosign reads the password
+	// of an encrypted private key from.
+	envKeyPassword = "COSIGN_PASSWORD" //nolint:gosec // Not a credential.
+
+	// predicateSPDXJSON is the cosign predicate type of an SPDX JSON SBOM.
+	predicateSPDXJSON = "spdxjson"
+)
+
+const (
+	errSignRequiresKey     = "--key is required to sign a package"
+	errFmtReadKeyFile      = "failed to read cosign private key file %s"
+	errFmtReadKeyEnv       = "environment variable %s is not set"
+	errLoadKey             = "failed to load cosign private key; set " + envKeyPassword + " if it is encrypted"
+	errNewRekorClient      = "failed to create Rekor client"
+	errMarshalPublicKey    = "failed to marshal public key"
+	errFmtResolveDigest    = "failed to resolve digest of package %s"
+	errFetchSignedEntity   = "failed to fetch package to sign"
+	errSignPayload         = "failed to sign package"
+	errUploadTlog          = "failed to upload signature to the transparency log"
+	errNewSignature        = "failed to create signature"
+	errAttachSignature     = "failed to attach signature to package"
+	errWriteSignature      = "failed to push signature"
+	errInvalidDependencies = "package has invalid dependencies"
+	errGenerateSBOM        = "failed to generate SBOM"
+	errGenerateStatement   = "failed to generate SBOM attestation statement"
+	errWriteAttestation    = "failed to push SBOM attestation"
+	errFetchSBOMImage      = "failed to fetch package image to generate SBOM from"
+	errParseSBOMPackage    = "failed to parse package to generate SBOM from"
+)
+
+// signFlags configure how packages are signed.
+type signFlags struct {
+	Key        string `help:"Cosign private key to sign the package with. Either a path to a key file, or env://NAME to read the key from the NAME environment variable. Set COSIGN_PASSWORD if the key is encrypted." placeholder:"PATH"`
+	SBOM       bool   `help:"Attach an SPDX SBOM attestation, generated from the package contents, to the package."                                                                                                  name:"sbom"`
+	TlogUpload bool   `default:"true"                                                                                                                                                                                help:"Upload the signature to the Rekor transparency log. Crossplane requires a transparency log entry to verify a signature." negatable:""`
+	RekorURL   string `default:"https://rekor.sigstore.dev"                                                                                                                                                          help:"Address of the Rekor transparency log."`
+}
+
+// signCmd signs a package.
+type signCmd struct {
+	// Arguments.
+	Package string `arg:"" help:"The package to sign. Tags are resolved to the digest they refer to, which is what gets signed."`
+
+	// Flags. Keep sorted alphabetically.
+	signFlags `embed:""`
+
+	// Common Upbound API configuration.
+	upbound.Flags `embed:""`
+}
+
+func (c *signCmd) Help() string {
+	return `
+Sign a package that has been pushed to a registry, using a cosign private key.
+The signature is pushed to the package's repository, where Crossplane can
+verify it using an ImageConfig. Credentials for the registry are automatically
+retrieved from xpkg login and dockers configuration as fallback.
+
+Examples:
+
+  # Sign a package using a cosign private key file.
+  crossplane xpkg sign --key cosign.key xpkg.crossplane.io/crossplane/function-example:v1.0.0
+
+  # Sign a package and attach an SBOM attestation, reading the key from the
+  # COSIGN_KEY environment variable.
+  crossplane xpkg sign --key env://COSIGN_KEY --sbom xpkg.crossplane.io/crossplane/function-example:v1.0.0
+`
+}
+
+// Run runs the sign cmd.
+func (c *signCmd) Run(logger logging.Logger) error {
+	upCtx, err := upbound.NewFromFlags(c.Flags, upbound.AllowMissingProfile())
+	if err != nil {
+		return err
+	}
+
+	ref, err := name.ParseReference(c.Package, name.WithDefaultRegistry(xpkg.DefaultRegistry))
+	if err != nil {
+		return errors.Wrapf(err, errFmtNewTag, c.Package)
+	}
+
+	s, err := newPackageSigner(c.signFlags, remoteOptions(upCtx, logger)...)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	d, err := s.Resolve(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if err := s.Sign(ctx, d); err != nil {
+		return err
+	}
+	logger.Debug("Signed package", "ref", d.String())
+	return nil
+}
+
+// A packageSigner signs packages, and attaches SBOM attestations to them.
+type packageSigner struct {
+	sv    signature.SignerVerifier
+	pub   []byte
+	rekor *rekorclient.Rekor
+	sbom  bool
+	opts  []remote.Option
+	now   func() time.Time
+}
+
+// newPackageSigner returns a packageSigner configured by the supplied flags.
+func newPackageSigner(f signFlags, o ...remote.Option) (*packageSigner, error) {
+	if f.Key == "" {
+		return nil, errors.New(errSignRequiresKey)
+	}
+
+	var key []byte
+	if env, ok := strings.CutPrefix(f.Key, envKeyPrefix); ok {
+		v, ok := os.LookupEnv(env)
+		if !ok {
+			return nil, errors.Errorf(errFmtReadKeyEnv, env)
+		}
+		key = []byte(v)
+	} else {
+		b, err := os.ReadFile(filepath.Clean(f.Key))
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtReadKeyFile, f.Key)
+		}
+		key = b
+	}
+
+	sv, err := cosign.LoadPrivateKey(key, []byte(os.Getenv(envKeyPassword)))
+	if err != nil {
+		return nil, errors.Wrap(err, errLoadKey)
+	}
+	pk, err := sv.PublicKey()
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalPublicKey)
+	}
+	pub, err := cryptoutils.MarshalPublicKeyToPEM(pk)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalPublicKey)
+	}
+
+	s := &packageSigner{sv: sv, pub: pub, sbom: f.SBOM, opts: o, now: time.Now}
+	if f.TlogUpload {
+		s.rekor, err = rekor.GetRekorClient(f.RekorURL)
+		if err != nil {
+			return nil, errors.Wrap(err, errNewRekorClient)
+		}
+	}
+	return s, nil
+}
+
+// Resolve returns the digest the supplied reference refers to.
+func (s *packageSigner) Resolve(ctx context.Context, ref name.Reference) (name.Digest, error) {
+	if d, ok := ref.(name.Digest); ok {
+		return d, nil
+	}
+	desc, err := remote.Head(ref, append(s.opts, remote.WithContext(ctx))...)
+	if err != nil {
+		return name.Digest{}, errors.Wrapf(err, errFmtResolveDigest, ref)
+	}
+	return ref.Context().Digest(desc.Digest.String()), nil
+}
+
+// Sign signs the supplied package digest and, if configured to, attaches an
+// SBOM attestation generated from the package contents.
+func (s *packageSigner) Sign(ctx context.Context, d name.Digest) error {
+	ro := ociremote.WithRemoteOptions(append(s.opts, remote.WithContext(ctx))...)
+
+	se, err := ociremote.SignedEntity(d, ro)
+	if err != nil {
+		return errors.Wrap(err, errFetchSignedEntity)
+	}
+
+	p, err := (&payload.Cosign{Image: d}).MarshalJSON()
+	if err != nil {
+		return errors.Wrap(err, errSignPayload)
+	}
+	raw, err := s.sv.SignMessage(bytes.NewReader(p))
+	if err != nil {
+		return errors.Wrap(err, errSignPayload)
+	}
+	var so []static.Option
+	if s.rekor != nil {
+		h := sha256.New()
+		_, _ = h.Write(p)
+		e, err := cosign.TLogUpload(ctx, s.rekor, raw, h, s.pub)
+		if err != nil {
+			return errors.Wrap(err, errUploadTlog)
+		}
+		so = append(so, static.WithBundle(cbundle.EntryToBundle(e)))
+	}
+	sig, err := static.NewSignature(p, base64.StdEncoding.EncodeToString(raw), so...)
+	if err != nil {
+		return errors.Wrap(err, errNewSignature)
+	}
+	se, err = mutate.AttachSignatureToEntity(se, sig)
+	if err != nil {
+		return errors.Wrap(err, errAttachSignature)
+	}
+	if err := ociremote.WriteSignatures(d.Repository, se, ro); err != nil {
+		return errors.Wrap(err, errWriteSignature)
+	}
+
+	if !s.sbom {
+		return nil
+	}
+
+	att, err := s.attestSBOM(ctx, d, se)
+	if err != nil {
+		return err
+	}
+	se, err = mutate.AttachAttestationToEntity(se, att)
+	if err != nil {
+		return errors.Wrap(err, errAttachSignature)
+	}
+	return errors.Wrap(ociremote.WriteAttestations(d.Repository, se, ro), errWriteAttestation)
+}
+
+// attestSBOM returns a signed SBOM attestation of the supplied package.
+func (s *packageSigner) attestSBOM(ctx context.Context, d name.Digest, se oci.SignedEntity) (oci.Signature, error) {
+	img, err := signedPackageImage(se)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchSBOMImage)
+	}
+	meta, objs, err := parsePackage(ctx, img)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseSBOMPackage)
+	}
+	doc, err := generateSBOM(d, meta, objs, s.now())
+	if err != nil {
+		return nil, errors.Wrap(err, errGenerateSBOM)
+	}
+	predicate, err := json.Marshal(doc)
+	if err != nil {
+		return nil, errors.Wrap(err, errGenerateSBOM)
+	}
+
+	h, err := v1.NewHash(d.DigestStr())
+	if err != nil {
+		return nil, errors.Wrap(err, errGenerateStatement)
+	}
+	st, err := attestation.GenerateStatement(attestation.GenerateOpts{
+		Predicate: bytes.NewReader(predicate),
+		Type:      predicateSPDXJSON,
+		Digest:    h.Hex,
+		Repo:      d.Repository.String(),
+		Time:      s.now,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errGenerateStatement)
+	}
+	statement, err := json.Marshal(st)
+	if err != nil {
+		return nil, errors.Wrap(err, errGenerateStatement)
+	}
+
+	envelope, err := dsse.WrapSigner(s.sv, in_toto.PayloadType).SignMessage(bytes.NewReader(statement))
+	if err != nil {
+		return nil, errors.Wrap(err, errSignPayload)
+	}
+	so := []static.Option{static.WithLayerMediaType("application/vnd.dsse.envelope.v1+json")}
+	if s.rekor != nil {
+		e, err := cosign.TLogUploadInTotoAttestation(ctx, s.rekor, envelope, s.pub)
+		if err != nil {
+			return nil, errors.Wrap(err, errUploadTlog)
+		}
+		so = append(so, static.WithBundle(cbundle.EntryToBundle(e)))
+	}
+	att, err := static.NewAttestation(envelope, so...)
+	return att, errors.Wrap(err, errNewSignature)
+}
+
+// signedPackageImage returns the image of the supplied signed package. If the
+// package is a multi-platform index we return its first image; the package
+// contents are the same for all platforms.
+func signedPackageImage(se oci.SignedEntity) (v1.Image, error) {
+	switch e := se.(type) {
+	case oci.SignedImage:
+		return e, nil
+	case oci.SignedImageIndex:
+		m, err := e.IndexManifest()
+		if err != nil {
+			return nil, err
+		}
+		if len(m.Manifests) == 0 {
+			return nil, errors.New("package index has no images")
+		}
+		return e.Image(m.Manifests[0].Digest)
+	default:
+		return nil, errors.Errorf("unsupported package type %T", se)
+	}
+}
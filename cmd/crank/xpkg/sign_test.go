@@ -0,0 +1,237 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"crypto"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+const configurationMeta = `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: configuration-example
+spec:
+  dependsOn:
+  - provider: xpkg.crossplane.io/crossplane/provider-nop
+    version: ">=v0.2.0"
+`
+
+func TestGenerateSBOM(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	d := name.MustParseReference("xpkg.crossplane.io/crossplane/configuration-example@sha256:0000000000000000000000000000000000000000000000000000000000000000").(name.Digest)
+
+	meta, objs, err := parsePackage(context.Background(), packageImage(t, configurationMeta))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              "xpkg.crossplane.io/crossplane/configuration-example",
+		DocumentNamespace: "https://crossplane.io/spdx/xpkg.crossplane.io/crossplane/configuration-example@" + d.DigestStr(),
+		CreationInfo: spdxCreationInfo{
+			Created:  "2025-01-02T03:04:05Z",
+			Creators: []string{"Tool: crossplane-cli"},
+		},
+		Packages: []spdxPackage{
+			{
+				Name:             "xpkg.crossplane.io/crossplane/configuration-example",
+				SPDXID:           "SPDXRef-Package",
+				VersionInfo:      d.DigestStr(),
+				DownloadLocation: d.String(),
+				Summary:          "Crossplane Configuration package configuration-example",
+				ExternalRefs: []spdxExternalRef{{
+					ReferenceCategory: "PACKAGE-MANAGER",
+					ReferenceType:     "purl",
+					ReferenceLocator:  "pkg:oci/configuration-example@sha256%3A0000000000000000000000000000000000000000000000000000000000000000?repository_url=xpkg.crossplane.io/crossplane/configuration-example",
+				}},
+			},
+			{
+				Name:             "xpkg.crossplane.io/crossplane/provider-nop",
+				SPDXID:           "SPDXRef-Dependency-0",
+				DownloadLocation: "NOASSERTION",
+				Comment:          "Version constraints: >=v0.2.0",
+			},
+		},
+		Relationships: []spdxRelationship{
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: "SPDXRef-Package"},
+			{SPDXElementID: "SPDXRef-Package", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: "SPDXRef-Dependency-0"},
+		},
+	}
+
+	got, err := generateSBOM(d, meta, objs, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("generateSBOM(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestPackageSignerSign(t *testing.T) {
+	t.Setenv(envKeyPassword, "crossplane")
+	keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return []byte("crossplane"), nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cosign.key")
+	if err := os.WriteFile(path, keys.PrivateBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CROSSPLANE_TEST_COSIGN_KEY", string(keys.PrivateBytes))
+
+	pk, err := cryptoutils.UnmarshalPEMToPublicKey(keys.PublicBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := signature.LoadVerifier(pk, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type want struct {
+		err          error
+		signatures   int
+		attestations int
+	}
+
+	cases := map[string]struct {
+		reason string
+		flags  signFlags
+		want   want
+	}{
+		"NoKey": {
+			reason: "We should return an error if no key is supplied.",
+			flags:  signFlags{},
+			want:   want{err: cmpopts.AnyError},
+		},
+		"MissingKeyEnv": {
+			reason: "We should return an error if the key environment variable isn't set.",
+			flags:  signFlags{Key: "env://CROSSPLANE_TEST_NO_SUCH_KEY"},
+			want:   want{err: cmpopts.AnyError},
+		},
+		"KeyFile": {
+			reason: "We should sign the package using a key read from a file.",
+			flags:  signFlags{Key: path},
+			want:   want{signatures: 1},
+		},
+		"KeyEnvWithSBOM": {
+			reason: "We should sign the package using a key read from an environment variable, and attach an SBOM attestation.",
+			flags:  signFlags{Key: "env://CROSSPLANE_TEST_COSIGN_KEY", SBOM: true},
+			want:   want{signatures: 1, attestations: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+			defer srv.Close()
+
+			ref := pushPackage(t, strings.TrimPrefix(srv.URL, "http://")+"/crossplane/configuration-example:v1.0.0")
+
+			s, err := newPackageSigner(tc.flags)
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nnewPackageSigner(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			d, err := s.Resolve(ctx, ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Sign(ctx, d); err != nil {
+				t.Fatalf("\n%s\nSign(...): %v", tc.reason, err)
+			}
+
+			co := &cosign.CheckOpts{
+				SigVerifier:   pub,
+				IgnoreTlog:    true,
+				ClaimVerifier: cosign.SimpleClaimVerifier,
+			}
+			sigs, _, err := cosign.VerifyImageSignatures(ctx, d, co)
+			if err != nil {
+				t.Errorf("\n%s\nVerifyImageSignatures(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.signatures, len(sigs)); diff != "" {
+				t.Errorf("\n%s\nVerifyImageSignatures(...): -want count, +got count:\n%s", tc.reason, diff)
+			}
+
+			se, err := ociremote.SignedEntity(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			atts, err := se.Attestations()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := atts.Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want.attestations, len(got)); diff != "" {
+				t.Errorf("\n%s\nAttestations(): -want count, +got count:\n%s", tc.reason, diff)
+			}
+			if tc.want.attestations == 0 {
+				return
+			}
+
+			co.ClaimVerifier = cosign.IntotoSubjectClaimVerifier
+			if _, _, err := cosign.VerifyImageAttestations(ctx, d, co); err != nil {
+				t.Errorf("\n%s\nVerifyImageAttestations(...): %v", tc.reason, err)
+			}
+		})
+	}
+}
+
+// pushPackage pushes a package image to the supplied tag.
+func pushPackage(t *testing.T, tag string) name.Reference {
+	t.Helper()
+
+	ref, err := name.ParseReference(tag, name.Insecure)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, packageImage(t, configurationMeta)); err != nil {
+		t.Fatal(err)
+	}
+	return ref
+}
@@ -0,0 +1,482 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/alecthomas/kong"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+
+	_ "k8s.io/client-go/plugin/pkg/client/auth" // Load all the auth plugins for the cloud providers.
+)
+
+const (
+	errGetPackage           = "cannot get package"
+	errFmtGetCRD            = "cannot get CustomResourceDefinition %s"
+	errFmtListResources     = "cannot list %s"
+	errFmtDeletePackage     = "cannot delete %s %s"
+	errMarshalUninstallPlan = "failed to marshal uninstall plan"
+	errPlanUninstall        = "failed to plan package uninstallation"
+	errUninstallBlocked     = "uninstalling the package would orphan managed resources or break other packages; see the plan for details"
+	errWaitForDependents    = "timed out waiting for dependent packages to be uninstalled"
+)
+
+// categoryManaged is the category of the CRDs of managed resources. Providers
+// also install CRDs that aren't managed resources, such as ProviderConfigs.
+const categoryManaged = "managed"
+
+// Actions an uninstall plan may take.
+const (
+	planActionDelete = "Delete"
+	planActionKeep   = "Keep"
+)
+
+// uninstallCmd uninstalls a package.
+type uninstallCmd struct {
+	// Arguments.
+	Kind string `arg:"" enum:"provider,configuration,function"                             help:"The kind of package to uninstall. One of \"provider\", \"configuration\", or \"function\"."`
+	Name string `arg:"" help:"The name of the package to uninstall in the Crossplane API."`
+
+	// Flags. Keep sorted alphabetically.
+	Cascade bool          `help:"Also uninstall the package's dependencies, unless another package depends on them."`
+	DryRun  bool          `help:"Print which packages uninstalling the package would delete, without deleting anything."`
+	Timeout time.Duration `default:"2m"                                                                                  help:"How long to wait for a dependency's dependents to be uninstalled before uninstalling it."`
+}
+
+func (c *uninstallCmd) Help() string {
+	return `
+This command uninstalls a package from a Crossplane control plane. It uses
+~/.kube/config to connect to the control plane. You can override this using the
+KUBECONFIG environment variable.
+
+By default only the named package is uninstalled. With --cascade, the package's
+dependencies are uninstalled too, unless a package that isn't being uninstalled
+depends on them. Dependencies are found using the package manager's Lock, so a
+dependency you installed yourself is uninstalled if only the named package
+depends on it.
+
+The command refuses to uninstall anything if a provider it would uninstall
+still has managed resources, or if another package depends on the named
+package.
+
+Examples:
+
+  # Uninstall the Provider named provider-nop.
+  crossplane xpkg uninstall provider provider-nop
+
+  # Print which packages uninstalling a Configuration and its dependencies
+  # would delete, without deleting anything.
+  crossplane xpkg uninstall configuration configuration-example --cascade --dry-run
+
+  # Uninstall a Configuration and the dependencies no other package needs.
+  crossplane xpkg uninstall configuration configuration-example --cascade
+`
+}
+
+// Run the package uninstall cmd.
+func (c *uninstallCmd) Run(k *kong.Context, logger logging.Logger) error {
+	pkg, err := newPackage(c.Kind)
+	if err != nil {
+		return err
+	}
+	pkg.SetName(c.Name)
+
+	logger = logger.WithValues(
+		"kind", c.Kind,
+		"name", c.Name,
+	)
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, errKubeConfig)
+	}
+	logger.Debug("Found kubeconfig")
+
+	s := runtime.NewScheme()
+	_ = v1.AddToScheme(s)
+	_ = v1beta1.AddToScheme(s)
+	_ = extv1.AddToScheme(s)
+
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	if err != nil {
+		return errors.Wrap(err, errKubeClient)
+	}
+	logger.Debug("Created kubernetes client")
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	u := &uninstaller{kube: kube, poll: 2 * time.Second}
+	plan, err := u.Plan(ctx, pkg, c.Cascade)
+	if err != nil {
+		return errors.Wrap(warnIfNotFound(err), errPlanUninstall)
+	}
+
+	if c.DryRun || plan.Blocked() {
+		b, err := yaml.Marshal(plan)
+		if err != nil {
+			return errors.Wrap(err, errMarshalUninstallPlan)
+		}
+		if _, err := k.Stdout.Write(b); err != nil {
+			return err
+		}
+		if plan.Blocked() {
+			return errors.New(errUninstallBlocked)
+		}
+		return nil
+	}
+
+	return u.Uninstall(ctx, logger, k.Stdout, plan)
+}
+
+// An uninstallPlan describes what uninstalling a package would delete.
+type uninstallPlan struct {
+	// Packages that would be deleted or kept, starting with the package being
+	// uninstalled and followed by its dependencies.
+	Packages []uninstallPackage `json:"packages"`
+}
+
+// Blocked returns true if any package the plan would delete can't safely be
+// deleted.
+func (p *uninstallPlan) Blocked() bool {
+	for _, pkg := range p.Packages {
+		if pkg.Action != planActionDelete {
+			continue
+		}
+		if len(pkg.RequiredBy) > 0 || len(pkg.ManagedResources) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// An uninstallPackage describes what uninstalling a package would do to a
+// package.
+type uninstallPackage struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	Source  string `json:"source,omitempty"`
+	Version string `json:"version,omitempty"`
+	Action  string `json:"action"`
+
+	// RequiredBy are the sources of packages that would still depend on the
+	// package. A package that's kept is kept because of these packages.
+	RequiredBy []string `json:"requiredBy,omitempty"`
+
+	// ManagedResources are the CRDs of a provider that still have managed
+	// resources.
+	ManagedResources []string `json:"managedResources,omitempty"`
+}
+
+// An uninstaller plans and performs the uninstallation of a package.
+type uninstaller struct {
+	kube client.Client
+	poll time.Duration
+}
+
+// Plan returns a plan to uninstall the supplied package. If cascade is true
+// the plan also deletes the package's dependencies, unless a package that
+// won't be deleted depends on them.
+func (u *uninstaller) Plan(ctx context.Context, pkg v1.Package, cascade bool) (*uninstallPlan, error) {
+	kind := pkg.GetObjectKind().GroupVersionKind().Kind
+	if err := u.kube.Get(ctx, types.NamespacedName{Name: pkg.GetName()}, pkg); err != nil {
+		return nil, errors.Wrap(err, errGetPackage)
+	}
+
+	l := &v1beta1.Lock{}
+	if err := u.kube.Get(ctx, types.NamespacedName{Name: lockName}, l); resource.IgnoreNotFound(err) != nil {
+		return nil, errors.Wrap(err, errGetLock)
+	}
+
+	lock := make(map[string]v1beta1.LockPackage, len(l.Packages))
+	dependents := make(map[string][]string)
+	var root *v1beta1.LockPackage
+	for i, lp := range l.Packages {
+		lock[lp.Source] = lp
+		for _, d := range lp.Dependencies {
+			dependents[d.Package] = append(dependents[d.Package], lp.Source)
+		}
+		// The Lock names each package after its active revision.
+		if lp.Name == pkg.GetCurrentRevision() {
+			root = &l.Packages[i]
+		}
+	}
+
+	rp := uninstallPackage{Name: pkg.GetName(), Kind: kind, Action: planActionDelete}
+	if root == nil {
+		// The package isn't in the Lock, for example because it never
+		// became active. Nothing can depend on it.
+		return &uninstallPlan{Packages: []uninstallPackage{rp}}, nil
+	}
+	rp.Source = root.Source
+	rp.Version = root.Version
+	rp.RequiredBy = sortedStrings(dependents[root.Source])
+
+	deleted := map[string]bool{root.Source: true}
+	deps := transitiveDependencies(lock, root.Source)
+	if cascade {
+		deleted = removable(deps, dependents, root.Source)
+	}
+
+	plan := &uninstallPlan{}
+	for _, source := range append([]string{root.Source}, deps...) {
+		lp, ok := lock[source]
+		if !ok {
+			// The dependency isn't installed.
+			continue
+		}
+
+		p := rp
+		if source != root.Source {
+			name, err := u.packageName(ctx, lp)
+			if err != nil {
+				return nil, err
+			}
+			p = uninstallPackage{Name: name, Kind: packageKind(lp), Source: lp.Source, Version: lp.Version, Action: planActionKeep}
+			if deleted[source] {
+				p.Action = planActionDelete
+			}
+			for _, d := range dependents[source] {
+				if !deleted[d] {
+					p.RequiredBy = append(p.RequiredBy, d)
+				}
+			}
+			sort.Strings(p.RequiredBy)
+		}
+
+		if p.Action == planActionDelete && p.Kind == v1.ProviderKind {
+			mrs, err := u.managedResources(ctx, lp)
+			if err != nil {
+				return nil, err
+			}
+			p.ManagedResources = mrs
+		}
+		plan.Packages = append(plan.Packages, p)
+	}
+
+	return plan, nil
+}
+
+// Uninstall deletes the packages the supplied plan would delete. It only
+// deletes a dependency once no package in the Lock depends on it, so that the
+// package manager doesn't reinstall it.
+func (u *uninstaller) Uninstall(ctx context.Context, logger logging.Logger, w io.Writer, plan *uninstallPlan) error {
+	remaining := make([]uninstallPackage, 0, len(plan.Packages))
+	for _, p := range plan.Packages {
+		if p.Action == planActionDelete {
+			remaining = append(remaining, p)
+		}
+	}
+
+	for {
+		l := &v1beta1.Lock{}
+		if err := u.kube.Get(ctx, types.NamespacedName{Name: lockName}, l); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errGetLock)
+		}
+		required := map[string]bool{}
+		for _, lp := range l.Packages {
+			for _, d := range lp.Dependencies {
+				required[d.Package] = true
+			}
+		}
+
+		waiting := remaining[:0]
+		for _, p := range remaining {
+			if p.Source != "" && required[p.Source] {
+				logger.Debug("Waiting for dependents to be uninstalled", "package", p.Name)
+				waiting = append(waiting, p)
+				continue
+			}
+			pkg, err := newPackage(p.Kind)
+			if err != nil {
+				return err
+			}
+			pkg.SetName(p.Name)
+			if err := u.kube.Delete(ctx, pkg); resource.IgnoreNotFound(err) != nil {
+				return errors.Wrapf(err, errFmtDeletePackage, p.Kind, p.Name)
+			}
+			if _, err := fmt.Fprintf(w, "%s/%s deleted\n", p.Kind, p.Name); err != nil {
+				return err
+			}
+		}
+		remaining = waiting
+
+		if len(remaining) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), errWaitForDependents)
+		case <-time.After(u.poll):
+		}
+	}
+}
+
+// packageName returns the name of the package object of the supplied Lock
+// package.
+func (u *uninstaller) packageName(ctx context.Context, lp v1beta1.LockPackage) (string, error) {
+	kind := packageKind(lp)
+	rev, _, err := newRevision(kind)
+	if err != nil {
+		return "", errors.Wrapf(err, errFmtUnknownPkgKind, lp.Source, kind)
+	}
+	if err := u.kube.Get(ctx, types.NamespacedName{Name: lp.Name}, rev); err != nil {
+		return "", errors.Wrapf(err, errFmtGetRevision, lp.Source)
+	}
+	return rev.GetLabels()[v1.LabelParentPackage], nil
+}
+
+// managedResources returns the names of the managed resource CRDs of the
+// supplied provider that still have managed resources.
+func (u *uninstaller) managedResources(ctx context.Context, lp v1beta1.LockPackage) ([]string, error) {
+	rev := &v1.ProviderRevision{}
+	if err := u.kube.Get(ctx, types.NamespacedName{Name: lp.Name}, rev); err != nil {
+		return nil, errors.Wrapf(err, errFmtGetRevision, lp.Source)
+	}
+
+	var out []string
+	for _, ref := range rev.GetObjects() {
+		if ref.Kind != "CustomResourceDefinition" {
+			continue
+		}
+		crd := &extv1.CustomResourceDefinition{}
+		if err := u.kube.Get(ctx, types.NamespacedName{Name: ref.Name}, crd); err != nil {
+			if resource.IgnoreNotFound(err) == nil {
+				continue
+			}
+			return nil, errors.Wrapf(err, errFmtGetCRD, ref.Name)
+		}
+		if !slices.Contains(crd.Spec.Names.Categories, categoryManaged) {
+			continue
+		}
+
+		version := ""
+		for _, v := range crd.Spec.Versions {
+			if v.Storage {
+				version = v.Name
+			}
+		}
+		l := &unstructured.UnstructuredList{}
+		l.SetAPIVersion(crd.Spec.Group + "/" + version)
+		l.SetKind(crd.Spec.Names.ListKind)
+		if err := u.kube.List(ctx, l, client.Limit(1)); err != nil {
+			return nil, errors.Wrapf(err, errFmtListResources, crd.GetName())
+		}
+		if len(l.Items) > 0 {
+			out = append(out, crd.GetName())
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// transitiveDependencies returns the sources of all the installed packages the
+// supplied package depends on, directly or indirectly, sorted by source.
+func transitiveDependencies(lock map[string]v1beta1.LockPackage, source string) []string {
+	seen := map[string]bool{source: true}
+	queue := []string{source}
+	var out []string
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for _, d := range lock[s].Dependencies {
+			if seen[d.Package] {
+				continue
+			}
+			seen[d.Package] = true
+			out = append(out, d.Package)
+			queue = append(queue, d.Package)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// removable returns the sources of the root package and those of the supplied
+// dependencies that only packages being removed depend on.
+func removable(deps []string, dependents map[string][]string, root string) map[string]bool {
+	removed := map[string]bool{root: true}
+	for changed := true; changed; {
+		changed = false
+		for _, d := range deps {
+			if removed[d] {
+				continue
+			}
+			ok := true
+			for _, p := range dependents[d] {
+				if !removed[p] {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				removed[d] = true
+				changed = true
+			}
+		}
+	}
+	return removed
+}
+
+// newPackage returns a new package of the supplied kind. The kind may be a
+// package kind (e.g. Provider) or a CLI argument (e.g. provider).
+func newPackage(kind string) (v1.Package, error) {
+	var pkg v1.Package
+	switch kind {
+	case "provider", v1.ProviderKind:
+		pkg = &v1.Provider{}
+		pkg.GetObjectKind().SetGroupVersionKind(v1.ProviderGroupVersionKind)
+	case "configuration", v1.ConfigurationKind:
+		pkg = &v1.Configuration{}
+		pkg.GetObjectKind().SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+	case "function", v1.FunctionKind:
+		pkg = &v1.Function{}
+		pkg.GetObjectKind().SetGroupVersionKind(v1.FunctionGroupVersionKind)
+	default:
+		return nil, errors.Errorf("unsupported package kind %q", kind)
+	}
+	return pkg, nil
+}
+
+func sortedStrings(in []string) []string {
+	if len(in) == 0 {
+		return nil
+	}
+	out := append([]string{}, in...)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+// uninstallLock returns a Lock in which configuration-a depends on provider-b
+// and function-c, and configuration-d depends on provider-b.
+func uninstallLock() *v1beta1.Lock {
+	return &v1beta1.Lock{
+		Packages: []v1beta1.LockPackage{
+			{
+				Name:    "configuration-a-0123",
+				Kind:    ptr.To(pkgv1.ConfigurationKind),
+				Source:  "xpkg.crossplane.io/example/configuration-a",
+				Version: "v1.0.0",
+				Dependencies: []v1beta1.Dependency{
+					{Package: "xpkg.crossplane.io/example/provider-b", Constraints: ">=v1.0.0"},
+					{Package: "xpkg.crossplane.io/example/function-c", Constraints: ">=v0.1.0"},
+				},
+			},
+			{
+				Name:    "configuration-d-4567",
+				Kind:    ptr.To(pkgv1.ConfigurationKind),
+				Source:  "xpkg.crossplane.io/example/configuration-d",
+				Version: "v1.0.0",
+				Dependencies: []v1beta1.Dependency{
+					{Package: "xpkg.crossplane.io/example/provider-b", Constraints: ">=v1.0.0"},
+				},
+			},
+			{
+				Name:    "provider-b-89ab",
+				Kind:    ptr.To(pkgv1.ProviderKind),
+				Source:  "xpkg.crossplane.io/example/provider-b",
+				Version: "v1.1.0",
+			},
+			{
+				Name:    "function-c-cdef",
+				Kind:    ptr.To(pkgv1.FunctionKind),
+				Source:  "xpkg.crossplane.io/example/function-c",
+				Version: "v0.2.0",
+			},
+		},
+	}
+}
+
+// uninstallGet returns a MockGetFn that gets the supplied Lock, packages and
+// revisions named as in uninstallLock, and a managed resource CRD and a
+// ProviderConfig CRD owned by provider-b.
+func uninstallGet(l *v1beta1.Lock) test.MockGetFn {
+	return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		switch o := obj.(type) {
+		case *v1beta1.Lock:
+			l.DeepCopyInto(o)
+		case *pkgv1.Configuration:
+			o.SetName(key.Name)
+			o.Status.CurrentRevision = map[string]string{
+				"configuration-a": "configuration-a-0123",
+				"configuration-d": "configuration-d-4567",
+			}[key.Name]
+		case *pkgv1.ProviderRevision:
+			o.SetName(key.Name)
+			o.SetLabels(map[string]string{pkgv1.LabelParentPackage: "provider-b"})
+			o.Status.ObjectRefs = []xpv1.TypedReference{
+				{
+					APIVersion: "apiextensions.k8s.io/v1",
+					Kind:       "CustomResourceDefinition",
+					Name:       "nopresources.nop.crossplane.io",
+				},
+				{
+					APIVersion: "apiextensions.k8s.io/v1",
+					Kind:       "CustomResourceDefinition",
+					Name:       "providerconfigs.nop.crossplane.io",
+				},
+			}
+		case *pkgv1.FunctionRevision:
+			o.SetName(key.Name)
+			o.SetLabels(map[string]string{pkgv1.LabelParentPackage: "function-c"})
+		case *extv1.CustomResourceDefinition:
+			o.SetName(key.Name)
+			o.Spec.Group = "nop.crossplane.io"
+			o.Spec.Versions = []extv1.CustomResourceDefinitionVersion{{Name: "v1alpha1", Storage: true}}
+			switch key.Name {
+			case "nopresources.nop.crossplane.io":
+				o.Spec.Names.ListKind = "NopResourceList"
+				o.Spec.Names.Categories = []string{"crossplane", "managed", "nop"}
+			case "providerconfigs.nop.crossplane.io":
+				o.Spec.Names.ListKind = "ProviderConfigList"
+				o.Spec.Names.Categories = []string{"crossplane", "provider", "nop"}
+			}
+		default:
+			return errors.Errorf("unexpected get of %T", obj)
+		}
+		return nil
+	}
+}
+
+func TestUninstallPlan(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		pkg     string
+		cascade bool
+	}
+	type want struct {
+		plan    *uninstallPlan
+		blocked bool
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		args   args
+		want   want
+	}{
+		"NoCascade": {
+			reason: "Without cascade we should only delete the package, and keep its dependencies.",
+			kube: &test.MockClient{
+				MockGet: uninstallGet(uninstallLock()),
+			},
+			args: args{pkg: "configuration-a"},
+			want: want{
+				plan: &uninstallPlan{Packages: []uninstallPackage{
+					{Name: "configuration-a", Kind: pkgv1.ConfigurationKind, Source: "xpkg.crossplane.io/example/configuration-a", Version: "v1.0.0", Action: planActionDelete},
+					{Name: "function-c", Kind: pkgv1.FunctionKind, Source: "xpkg.crossplane.io/example/function-c", Version: "v0.2.0", Action: planActionKeep},
+					{Name: "provider-b", Kind: pkgv1.ProviderKind, Source: "xpkg.crossplane.io/example/provider-b", Version: "v1.1.0", Action: planActionKeep, RequiredBy: []string{"xpkg.crossplane.io/example/configuration-d"}},
+				}},
+			},
+		},
+		"Cascade": {
+			reason: "With cascade we should delete dependencies no other package needs, and keep those another package needs.",
+			kube: &test.MockClient{
+				MockGet: uninstallGet(uninstallLock()),
+			},
+			args: args{pkg: "configuration-a", cascade: true},
+			want: want{
+				plan: &uninstallPlan{Packages: []uninstallPackage{
+					{Name: "configuration-a", Kind: pkgv1.ConfigurationKind, Source: "xpkg.crossplane.io/example/configuration-a", Version: "v1.0.0", Action: planActionDelete},
+					{Name: "function-c", Kind: pkgv1.FunctionKind, Source: "xpkg.crossplane.io/example/function-c", Version: "v0.2.0", Action: planActionDelete},
+					{Name: "provider-b", Kind: pkgv1.ProviderKind, Source: "xpkg.crossplane.io/example/provider-b", Version: "v1.1.0", Action: planActionKeep, RequiredBy: []string{"xpkg.crossplane.io/example/configuration-d"}},
+				}},
+			},
+		},
+		"ManagedResourcesExist": {
+			reason: "We should block deleting a provider that still has managed resources.",
+			kube: &test.MockClient{
+				MockGet: func() test.MockGetFn {
+					l := uninstallLock()
+					// Only configuration-a depends on provider-b.
+					l.Packages[1].Dependencies = nil
+					return uninstallGet(l)
+				}(),
+				MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+					l := obj.(*unstructured.UnstructuredList) //nolint:forcetypeassert // We only list unstructured.
+					l.Items = []unstructured.Unstructured{{}}
+					return nil
+				},
+			},
+			args: args{pkg: "configuration-a", cascade: true},
+			want: want{
+				plan: &uninstallPlan{Packages: []uninstallPackage{
+					{Name: "configuration-a", Kind: pkgv1.ConfigurationKind, Source: "xpkg.crossplane.io/example/configuration-a", Version: "v1.0.0", Action: planActionDelete},
+					{Name: "function-c", Kind: pkgv1.FunctionKind, Source: "xpkg.crossplane.io/example/function-c", Version: "v0.2.0", Action: planActionDelete},
+					{Name: "provider-b", Kind: pkgv1.ProviderKind, Source: "xpkg.crossplane.io/example/provider-b", Version: "v1.1.0", Action: planActionDelete, ManagedResources: []string{"nopresources.nop.crossplane.io"}},
+				}},
+				blocked: true,
+			},
+		},
+		"OnlyProviderConfigsExist": {
+			reason: "We shouldn't block deleting a provider that has ProviderConfigs but no managed resources.",
+			kube: &test.MockClient{
+				MockGet: func() test.MockGetFn {
+					l := uninstallLock()
+					// Only configuration-a depends on provider-b.
+					l.Packages[1].Dependencies = nil
+					return uninstallGet(l)
+				}(),
+				MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+					l := obj.(*unstructured.UnstructuredList) //nolint:forcetypeassert // We only list unstructured.
+					if l.GetKind() == "ProviderConfigList" {
+						l.Items = []unstructured.Unstructured{{}}
+					}
+					return nil
+				},
+			},
+			args: args{pkg: "configuration-a", cascade: true},
+			want: want{
+				plan: &uninstallPlan{Packages: []uninstallPackage{
+					{Name: "configuration-a", Kind: pkgv1.ConfigurationKind, Source: "xpkg.crossplane.io/example/configuration-a", Version: "v1.0.0", Action: planActionDelete},
+					{Name: "function-c", Kind: pkgv1.FunctionKind, Source: "xpkg.crossplane.io/example/function-c", Version: "v0.2.0", Action: planActionDelete},
+					{Name: "provider-b", Kind: pkgv1.ProviderKind, Source: "xpkg.crossplane.io/example/provider-b", Version: "v1.1.0", Action: planActionDelete},
+				}},
+			},
+		},
+		"RequiredByOtherPackage": {
+			reason: "We should block deleting a package another package depends on.",
+			kube: &test.MockClient{
+				MockGet: func() test.MockGetFn {
+					l := uninstallLock()
+					// configuration-d depends on configuration-a.
+					l.Packages[1].Dependencies = []v1beta1.Dependency{{Package: "xpkg.crossplane.io/example/configuration-a", Constraints: ">=v1.0.0"}}
+					l.Packages[0].Dependencies = nil
+					return uninstallGet(l)
+				}(),
+			},
+			args: args{pkg: "configuration-a", cascade: true},
+			want: want{
+				plan: &uninstallPlan{Packages: []uninstallPackage{
+					{Name: "configuration-a", Kind: pkgv1.ConfigurationKind, Source: "xpkg.crossplane.io/example/configuration-a", Version: "v1.0.0", Action: planActionDelete, RequiredBy: []string{"xpkg.crossplane.io/example/configuration-d"}},
+				}},
+				blocked: true,
+			},
+		},
+		"GetPackageError": {
+			reason: "We should return an error if we can't get the package.",
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(errBoom),
+			},
+			args: args{pkg: "configuration-a"},
+			want: want{
+				err: errors.Wrap(errBoom, errGetPackage),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pkg, _ := newPackage(pkgv1.ConfigurationKind)
+			pkg.SetName(tc.args.pkg)
+
+			u := &uninstaller{kube: tc.kube}
+			got, err := u.Plan(context.Background(), pkg, tc.args.cascade)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPlan(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.plan, got); diff != "" {
+				t.Errorf("\n%s\nPlan(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if got == nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.blocked, got.Blocked()); diff != "" {
+				t.Errorf("\n%s\nBlocked(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUninstall(t *testing.T) {
+	plan := &uninstallPlan{Packages: []uninstallPackage{
+		{Name: "configuration-a", Kind: pkgv1.ConfigurationKind, Source: "xpkg.crossplane.io/example/configuration-a", Action: planActionDelete},
+		{Name: "function-c", Kind: pkgv1.FunctionKind, Source: "xpkg.crossplane.io/example/function-c", Action: planActionDelete},
+		{Name: "provider-b", Kind: pkgv1.ProviderKind, Source: "xpkg.crossplane.io/example/provider-b", Action: planActionKeep},
+	}}
+
+	// The Lock still contains configuration-a the first time we get it, so we
+	// should wait before deleting function-c.
+	gets := 0
+	var deleted []string
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			gets++
+			if gets == 1 {
+				uninstallLock().DeepCopyInto(obj.(*v1beta1.Lock)) //nolint:forcetypeassert // We only get the Lock.
+			}
+			return nil
+		},
+		MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+			deleted = append(deleted, obj.GetName())
+			return nil
+		},
+	}
+
+	u := &uninstaller{kube: kube, poll: time.Millisecond}
+	out := &bytes.Buffer{}
+	if err := u.Uninstall(context.Background(), logging.NewNopLogger(), out, plan); err != nil {
+		t.Fatalf("Uninstall(...): %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"configuration-a", "function-c"}, deleted); diff != "" {
+		t.Errorf("Uninstall(...): -want deleted, +got deleted:\n%s", diff)
+	}
+	if diff := cmp.Diff(2, gets); diff != "" {
+		t.Errorf("Uninstall(...): -want Lock gets, +got Lock gets:\n%s", diff)
+	}
+	want := "Configuration/configuration-a deleted\nFunction/function-c deleted\n"
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("Uninstall(...): -want output, +got output:\n%s", diff)
+	}
+}
@@ -22,14 +22,18 @@ package xpkg
 // Cmd contains commands for interacting with xpkgs.
 type Cmd struct {
 	// Keep subcommands sorted alphabetically.
-	Build   buildCmd   `cmd:"" help:"Build a new package."`
-	Init    initCmd    `cmd:"" help:"Initialize a new package from a template."`
-	Install installCmd `cmd:"" help:"Install a package in a control plane."`
-	Login   loginCmd   `cmd:"" help:"Login to the default package registry."`
-	Logout  logoutCmd  `cmd:"" help:"Logout of the default package registry."`
-	Push    pushCmd    `cmd:"" help:"Push a package to a registry."`
-	Update  updateCmd  `cmd:"" help:"Update a package in a control plane."`
-	Extract extractCmd `cmd:"" help:"Extract package contents into a Crossplane cache compatible format. Fetches from a remote registry by default."`
+	Build     buildCmd     `cmd:"" help:"Build a new package."`
+	Bundle    bundleCmd    `cmd:"" help:"Bundle a package and its dependencies into an OCI image layout archive."`
+	Init      initCmd      `cmd:"" help:"Initialize a new package from a template."`
+	Install   installCmd   `cmd:"" help:"Install a package in a control plane."`
+	Login     loginCmd     `cmd:"" help:"Login to the default package registry."`
+	Logout    logoutCmd    `cmd:"" help:"Logout of the default package registry."`
+	Mirror    mirrorCmd    `cmd:"" help:"Push a package bundle to a private registry."`
+	Push      pushCmd      `cmd:"" help:"Push a package to a registry."`
+	Sign      signCmd      `cmd:"" help:"Sign a package in a registry."`
+	Uninstall uninstallCmd `cmd:"" help:"Uninstall a package from a control plane, optionally with its dependencies."`
+	Update    updateCmd    `cmd:"" help:"Update a package in a control plane."`
+	Extract   extractCmd   `cmd:"" help:"Extract package contents into a Crossplane cache compatible format. Fetches from a remote registry by default."`
 }
 
 // Help prints out the help for the xpkg command.
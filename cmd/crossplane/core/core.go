@@ -29,6 +29,7 @@ import (
 	"github.com/alecthomas/kong"
 	"github.com/spf13/afero"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	kcache "k8s.io/client-go/tools/cache"
@@ -98,6 +99,8 @@ type startCommand struct {
 
 	XpkgCacheDir string `aliases:"cache-dir" default:"/cache/xpkg" env:"XPKG_CACHE_DIR,CACHE_DIR" help:"Directory used for caching package images." short:"c"`
 
+	XpkgCacheMaxSize string `env:"XPKG_CACHE_MAX_SIZE" help:"Maximum size of the package cache, for example 512Mi. The least recently used packages are evicted when the cache exceeds it. Pre-loaded packages are never evicted and don't count toward it. The cache is unbounded if unset."`
+
 	PackageRuntime string `default:"Deployment" env:"PACKAGE_RUNTIME" help:"The package runtime to use for packages with a runtime (e.g. Providers and Functions)"`
 
 	SyncInterval                     time.Duration `default:"1h"  help:"How often all resources will be double-checked for drift from the desired state."                      short:"s"`
@@ -120,6 +123,13 @@ type startCommand struct {
 	EnableDependencyVersionDowngrades bool `group:"Alpha Features:" help:"Enable support for upgrading and downgrading dependency versions when a dependent package is updated."`
 	EnableSignatureVerification       bool `group:"Alpha Features:" help:"Enable support for package signature verification via ImageConfig API."`
 	EnableFunctionResponseCache       bool `group:"Alpha Features:" help:"Enable support for caching composition function responses."`
+	EnableNamespacedCompositions      bool `group:"Alpha Features:" help:"Enable support for NamespacedCompositions, which namespaced composite resources may select."`
+	EnableSafeCRDUpgrades             bool `group:"Alpha Features:" help:"Enable support for refusing package CRD updates that could strand existing custom resources."`
+	EnableCRDStorageVersionMigration  bool `group:"Alpha Features:" help:"Enable support for migrating custom resources off CRD versions a package removes. Implies --enable-safe-crd-upgrades."`
+	EnableAutomaticPackageUpdates     bool `group:"Alpha Features:" help:"Enable support for automatically updating packages to the newest version in a channel."`
+	EnableRegistryCredentialProviders bool `group:"Alpha Features:" help:"Enable support for authenticating to package registries using credential providers configured via ImageConfig API."`
+	EnableRegistryMirrors             bool `group:"Alpha Features:" help:"Enable support for falling back to package registry mirrors configured via ImageConfig API."`
+	EnablePackageRollbacks            bool `group:"Alpha Features:" help:"Enable support for re-activating a package's last healthy revision when a new revision doesn't become healthy."`
 
 	XfnCacheDir    string        `default:"/cache/xfn" env:"XFN_CACHE_DIR"     group:"Alpha Features:" help:"Directory used for caching function responses. Requires --enable-function-response-cache."`
 	XfnCacheMaxTTL time.Duration `default:"24h"        env:"XFN_CACHE_MAX_TTL" group:"Alpha Features:" help:"Maximum TTL for cached function responses. Set to 0 to disable. Requires --enable-function-response-cache."`
@@ -260,6 +270,7 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		cfr := cached.NewFileBackedRunner(pfr, c.XfnCacheDir,
 			cached.WithLogger(log),
 			cached.WithMetrics(cfrm),
+			cached.WithRevisionResolver(pfr),
 		)
 
 		// Periodically delete expired cache entries.
@@ -297,6 +308,35 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		o.Features.Enable(features.EnableAlphaSignatureVerification)
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaSignatureVerification)
 	}
+	if c.EnableNamespacedCompositions {
+		o.Features.Enable(features.EnableAlphaNamespacedCompositions)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaNamespacedCompositions)
+	}
+	// Enabling storage version migration implicitly enables safe CRD upgrades.
+	if c.EnableSafeCRDUpgrades || c.EnableCRDStorageVersionMigration {
+		o.Features.Enable(features.EnableAlphaSafeCRDUpgrades)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaSafeCRDUpgrades)
+	}
+	if c.EnableCRDStorageVersionMigration {
+		o.Features.Enable(features.EnableAlphaCRDStorageVersionMigration)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaCRDStorageVersionMigration)
+	}
+	if c.EnableAutomaticPackageUpdates {
+		o.Features.Enable(features.EnableAlphaAutomaticPackageUpdates)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaAutomaticPackageUpdates)
+	}
+	if c.EnableRegistryCredentialProviders {
+		o.Features.Enable(features.EnableAlphaRegistryCredentialProviders)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaRegistryCredentialProviders)
+	}
+	if c.EnableRegistryMirrors {
+		o.Features.Enable(features.EnableAlphaRegistryMirrors)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaRegistryMirrors)
+	}
+	if c.EnablePackageRollbacks {
+		o.Features.Enable(features.EnableAlphaPackageRollbacks)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaPackageRollbacks)
+	}
 
 	// Claim and XR controllers are started and stopped dynamically by the
 	// ControllerEngine below. When realtime compositions are enabled, they also
@@ -397,6 +437,7 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		Options:          o,
 		ControllerEngine: ce,
 		FunctionRunner:   runner,
+		ClientTLSConfig:  clienttls,
 	}
 
 	if err := apiextensions.Setup(mgr, ao); err != nil {
@@ -414,15 +455,28 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 			c.PackageRuntime, pkgcontroller.PackageRuntimeDeployment, pkgcontroller.PackageRuntimeExternal)
 	}
 
+	pcm := xpkg.NewPrometheusCacheMetrics()
+	metrics.Registry.MustRegister(pcm)
+
+	co := []xpkg.FsPackageCacheOption{xpkg.WithCacheMetrics(pcm)}
+	if c.XpkgCacheMaxSize != "" {
+		q, err := resource.ParseQuantity(c.XpkgCacheMaxSize)
+		if err != nil {
+			return errors.Wrap(err, "cannot parse package cache max size")
+		}
+		co = append(co, xpkg.WithMaxBytes(q.Value()))
+	}
+
 	po := pkgcontroller.Options{
 		Options:                          o,
-		Cache:                            xpkg.NewFsPackageCache(c.XpkgCacheDir, afero.NewOsFs()),
+		Cache:                            xpkg.NewFsPackageCache(c.XpkgCacheDir, afero.NewOsFs(), co...),
 		Namespace:                        c.Namespace,
 		ServiceAccount:                   c.ServiceAccount,
 		DefaultRegistry:                  c.Registry,
 		FetcherOptions:                   []xpkg.FetcherOpt{xpkg.WithUserAgent(c.UserAgent)},
 		PackageRuntime:                   pr,
 		MaxConcurrentPackageEstablishers: c.MaxConcurrentPackageEstablishers,
+		ClientTLSConfig:                  clienttls,
 	}
 
 	// We need to set the TUF_ROOT environment variable so that the TUF client
@@ -445,6 +499,16 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		po.FetcherOptions = append(po.FetcherOptions, xpkg.WithCustomCA(rootCAs))
 	}
 
+	if o.Features.Enabled(features.EnableAlphaRegistryCredentialProviders) {
+		// ImageConfigs that use any other credential helper are rejected.
+		log.Info("Found registry credential helpers", "helpers", xpkg.InstalledCredentialHelpers())
+		po.FetcherOptions = append(po.FetcherOptions, xpkg.WithCredentialProviders(xpkg.NewImageConfigStore(mgr.GetClient(), c.Namespace)))
+	}
+
+	if o.Features.Enabled(features.EnableAlphaRegistryMirrors) {
+		po.FetcherOptions = append(po.FetcherOptions, xpkg.WithMirrors(xpkg.NewImageConfigStore(mgr.GetClient(), c.Namespace)))
+	}
+
 	if err := pkg.Setup(mgr, po); err != nil {
 		return errors.Wrap(err, "cannot add package manager controllers to manager")
 	}
@@ -24,6 +24,7 @@ import (
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/definition"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/offered"
+	"github.com/crossplane/crossplane/internal/features"
 )
 
 // Setup API extensions controllers.
@@ -32,6 +33,12 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		return err
 	}
 
+	if o.Features.Enabled(features.EnableAlphaNamespacedCompositions) {
+		if err := composition.SetupNamespaced(mgr, o); err != nil {
+			return err
+		}
+	}
+
 	if err := definition.Setup(mgr, o); err != nil {
 		return err
 	}
@@ -38,6 +38,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v2alpha1"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -55,6 +56,14 @@ const (
 	errCompositionNotCompatible        = "referenced composition is not compatible with this composite resource"
 	errGetXRD                          = "cannot get composite resource definition"
 	errFetchCompositionRevision        = "cannot fetch composition revision"
+
+	errGetNamespacedComposition           = "cannot get NamespacedComposition"
+	errGetNamespacedCompositionRevision   = "cannot get NamespacedCompositionRevision"
+	errListNamespacedCompositions         = "cannot list NamespacedCompositions"
+	errListNamespacedCompositionRevisions = "cannot list NamespacedCompositionRevisions"
+	errNamespacedCompositionForClusterXR  = "only namespaced composite resources may use a NamespacedComposition"
+	errFmtUnsupportedCompositionKind      = "unsupported composition kind %q"
+	errFmtCredentialsSecretNotInNamespace = "credentials %q of pipeline step %q must reference a Secret in namespace %q"
 )
 
 // Event reasons.
@@ -166,8 +175,13 @@ func (f *APIRevisionFetcher) Fetch(ctx context.Context, cr resource.Composite) (
 	// We either haven't yet selected a revision, or our update policy is
 	// automatic. Either way we need to determine the latest revision.
 
+	ref := cr.GetCompositionReference()
+	if ref != nil && !IsCompositionReference(ref) {
+		return nil, errors.Errorf(errFmtUnsupportedCompositionKind, ref.Kind)
+	}
+
 	comp := &v1.Composition{}
-	if err := f.client.Get(ctx, meta.NamespacedNameOf(cr.GetCompositionReference()), comp); err != nil {
+	if err := f.client.Get(ctx, meta.NamespacedNameOf(ref), comp); err != nil {
 		return nil, errors.Wrap(err, errGetComposition)
 	}
 
@@ -207,6 +221,123 @@ func (f *APIRevisionFetcher) getCompositionRevisionList(ctx context.Context, cr
 	return rl, nil
 }
 
+// IsCompositionReference returns true if the supplied reference refers to a
+// (cluster scoped) Composition. References that don't specify a kind refer to
+// a Composition.
+func IsCompositionReference(ref *corev1.ObjectReference) bool {
+	return ref != nil && (ref.Kind == "" || ref.Kind == v1.CompositionKind)
+}
+
+// IsNamespacedCompositionReference returns true if the supplied reference
+// refers to a NamespacedComposition.
+func IsNamespacedCompositionReference(ref *corev1.ObjectReference) bool {
+	return ref != nil && ref.Kind == v2alpha1.NamespacedCompositionKind
+}
+
+// An APINamespacedRevisionFetcher fetches the appropriate revision of the
+// NamespacedComposition referenced by a namespaced composite resource. It
+// delegates to another CompositionRevisionFetcher when the composite resource
+// references a Composition.
+type APINamespacedRevisionFetcher struct {
+	client  client.Client
+	wrapped CompositionRevisionFetcher
+}
+
+// NewAPINamespacedRevisionFetcher returns a CompositionRevisionFetcher that
+// fetches revisions of NamespacedCompositions, and uses the supplied fetcher to
+// fetch revisions of Compositions.
+func NewAPINamespacedRevisionFetcher(c client.Client, wrapped CompositionRevisionFetcher) *APINamespacedRevisionFetcher {
+	return &APINamespacedRevisionFetcher{client: c, wrapped: wrapped}
+}
+
+// Fetch the appropriate revision of the NamespacedComposition referenced by the
+// supplied XR, converted to a CompositionRevision. Only NamespacedCompositions
+// in the same namespace as the XR may be used.
+func (f *APINamespacedRevisionFetcher) Fetch(ctx context.Context, cr resource.Composite) (*v1.CompositionRevision, error) {
+	ref := cr.GetCompositionReference()
+	if !IsNamespacedCompositionReference(ref) {
+		return f.wrapped.Fetch(ctx, cr)
+	}
+
+	if cr.GetNamespace() == "" {
+		return nil, errors.New(errNamespacedCompositionForClusterXR)
+	}
+
+	current := cr.GetCompositionRevisionReference()
+	pol := cr.GetCompositionUpdatePolicy()
+
+	// We've already selected a revision, and our update policy is manual.
+	// Just fetch and return the selected revision.
+	if current != nil && pol != nil && *pol == xpv1.UpdateManual {
+		rev := &v2alpha1.NamespacedCompositionRevision{}
+		if err := f.client.Get(ctx, types.NamespacedName{Namespace: cr.GetNamespace(), Name: current.Name}, rev); err != nil {
+			return nil, errors.Wrap(err, errGetNamespacedCompositionRevision)
+		}
+		if err := ValidateNamespacedCompositionRevision(cr, rev); err != nil {
+			return nil, err
+		}
+		return rev.AsCompositionRevision(), nil
+	}
+
+	comp := &v2alpha1.NamespacedComposition{}
+	if err := f.client.Get(ctx, types.NamespacedName{Namespace: cr.GetNamespace(), Name: ref.Name}, comp); err != nil {
+		return nil, errors.Wrap(err, errGetNamespacedComposition)
+	}
+
+	ml := client.MatchingLabels{}
+	if pol != nil && *pol == xpv1.UpdateAutomatic && cr.GetCompositionRevisionSelector() != nil {
+		ml = cr.GetCompositionRevisionSelector().MatchLabels
+	}
+	ml[v1.LabelCompositionName] = comp.GetName()
+
+	rl := &v2alpha1.NamespacedCompositionRevisionList{}
+	if err := f.client.List(ctx, rl, client.InNamespace(cr.GetNamespace()), ml); err != nil {
+		return nil, errors.Wrap(errors.Wrap(err, errListNamespacedCompositionRevisions), errFetchCompositionRevision)
+	}
+
+	latest := v2alpha1.LatestRevision(comp, rl.Items)
+	if latest == nil {
+		return nil, errors.New(errNoCompatibleCompositionRevision)
+	}
+
+	if err := ValidateNamespacedCompositionRevision(cr, latest); err != nil {
+		return nil, err
+	}
+
+	if current == nil || current.Name != latest.GetName() {
+		cr.SetCompositionRevisionReference(&corev1.LocalObjectReference{Name: latest.GetName()})
+		if err := f.client.Update(ctx, cr); err != nil {
+			return nil, errors.Wrap(err, errUpdate)
+		}
+	}
+
+	return latest.AsCompositionRevision(), nil
+}
+
+// ValidateNamespacedCompositionRevision returns an error if the supplied
+// NamespacedCompositionRevision may not be used to compose the supplied XR. The
+// revision must be compatible with the XR's type, and its pipeline steps may
+// only load credentials from Secrets in the revision's namespace.
+func ValidateNamespacedCompositionRevision(cr resource.Composite, rev *v2alpha1.NamespacedCompositionRevision) error {
+	apiVersion, kind := cr.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
+	if rev.Spec.CompositeTypeRef.APIVersion != apiVersion || rev.Spec.CompositeTypeRef.Kind != kind {
+		return errors.New(errCompositionNotCompatible)
+	}
+
+	for _, s := range rev.Spec.Pipeline {
+		for _, c := range s.Credentials {
+			if c.SecretRef == nil {
+				continue
+			}
+			if c.SecretRef.Namespace != rev.GetNamespace() {
+				return errors.Errorf(errFmtCredentialsSecretNotInNamespace, c.Name, s.Step, rev.GetNamespace())
+			}
+		}
+	}
+
+	return nil
+}
+
 // NewCompositionSelectorChain returns a new CompositionSelectorChain.
 func NewCompositionSelectorChain(list ...CompositionSelector) *CompositionSelectorChain {
 	return &CompositionSelectorChain{list: list}
@@ -279,6 +410,66 @@ func (r *APILabelSelectorResolver) SelectComposition(ctx context.Context, cp res
 	return errors.Wrap(r.client.Update(ctx, cp), errUpdateComposite)
 }
 
+// NewAPINamespacedLabelSelectorResolver returns a SelectorResolver for
+// namespaced composite resources.
+func NewAPINamespacedLabelSelectorResolver(c client.Client) *APINamespacedLabelSelectorResolver {
+	return &APINamespacedLabelSelectorResolver{client: c}
+}
+
+// APINamespacedLabelSelectorResolver is used to resolve the composition
+// selector on a namespaced composite resource to a composition reference. It
+// considers both Compositions and the NamespacedCompositions in the composite
+// resource's namespace.
+type APINamespacedLabelSelectorResolver struct {
+	client client.Client
+}
+
+// SelectComposition resolves selector to a reference if it doesn't exist.
+func (r *APINamespacedLabelSelectorResolver) SelectComposition(ctx context.Context, cp resource.Composite) error {
+	if cp.GetCompositionReference() != nil {
+		return nil
+	}
+	labels := map[string]string{}
+	sel := cp.GetCompositionSelector()
+	if sel != nil {
+		labels = sel.MatchLabels
+	}
+
+	v, k := cp.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
+
+	list := &v1.CompositionList{}
+	if err := r.client.List(ctx, list, client.MatchingLabels(labels)); err != nil {
+		return errors.Wrap(err, errListCompositions)
+	}
+
+	candidates := make([]corev1.ObjectReference, 0, len(list.Items))
+	for _, comp := range list.Items {
+		if comp.Spec.CompositeTypeRef.APIVersion == v && comp.Spec.CompositeTypeRef.Kind == k {
+			candidates = append(candidates, corev1.ObjectReference{Name: comp.Name})
+		}
+	}
+
+	nlist := &v2alpha1.NamespacedCompositionList{}
+	if err := r.client.List(ctx, nlist, client.InNamespace(cp.GetNamespace()), client.MatchingLabels(labels)); err != nil {
+		return errors.Wrap(err, errListNamespacedCompositions)
+	}
+
+	for _, comp := range nlist.Items {
+		if comp.Spec.CompositeTypeRef.APIVersion == v && comp.Spec.CompositeTypeRef.Kind == k {
+			candidates = append(candidates, corev1.ObjectReference{Kind: v2alpha1.NamespacedCompositionKind, Name: comp.Name})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return errors.New(errNoCompatibleComposition)
+	}
+
+	random := rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec // We don't need this to be cryptographically random.
+	selected := candidates[random.Intn(len(candidates))]
+	cp.SetCompositionReference(&selected)
+	return errors.Wrap(r.client.Update(ctx, cp), errUpdateComposite)
+}
+
 // NewAPIDefaultCompositionSelector returns a APIDefaultCompositionSelector.
 func NewAPIDefaultCompositionSelector(c client.Client, ref corev1.ObjectReference, r event.Recorder) *APIDefaultCompositionSelector {
 	return &APIDefaultCompositionSelector{client: c, defRef: ref, recorder: r}
@@ -333,7 +524,7 @@ func (s *EnforcedCompositionSelector) SelectComposition(_ context.Context, cp re
 	}
 	// If the composition is already chosen, we don't need to check for compatibility
 	// as its target type reference is immutable.
-	if cp.GetCompositionReference() != nil && cp.GetCompositionReference().Name == s.def.Spec.EnforcedCompositionRef.Name {
+	if ref := cp.GetCompositionReference(); IsCompositionReference(ref) && ref.Name == s.def.Spec.EnforcedCompositionRef.Name {
 		return nil
 	}
 	cp.SetCompositionReference(&corev1.ObjectReference{Name: s.def.Spec.EnforcedCompositionRef.Name})
@@ -36,6 +36,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v2alpha1"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -490,6 +491,203 @@ func TestFetchRevision(t *testing.T) {
 	}
 }
 
+func TestFetchNamespacedRevision(t *testing.T) {
+	errBoom := errors.New("boom")
+	manual := xpv1.UpdateManual
+	ctrl := true
+
+	a, k := schema.EmptyObjectKind.GroupVersionKind().ToAPIVersionAndKind()
+	tref := v1.TypeReference{APIVersion: a, Kind: k}
+
+	comp := &v2alpha1.NamespacedComposition{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "tenant",
+			Name:      "cool-composition",
+			UID:       types.UID("no-you-id"),
+		},
+		Spec: v2alpha1.NamespacedCompositionSpec{
+			CompositeTypeRef: tref,
+		},
+	}
+	ref := &corev1.ObjectReference{Kind: v2alpha1.NamespacedCompositionKind, Name: comp.GetName()}
+
+	rev := &v2alpha1.NamespacedCompositionRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "tenant",
+			Name:      comp.GetName() + "-dl2nd",
+			OwnerReferences: []metav1.OwnerReference{{
+				UID:                comp.GetUID(),
+				Controller:         &ctrl,
+				BlockOwnerDeletion: &ctrl,
+			}},
+		},
+		Spec: v2alpha1.NamespacedCompositionRevisionSpec{
+			CompositeTypeRef: tref,
+			Revision:         1,
+		},
+	}
+
+	// This revision loads credentials from another namespace.
+	escapee := rev.DeepCopy()
+	escapee.Spec.Pipeline = []v1.PipelineStep{{
+		Step: "escape",
+		Credentials: []v1.FunctionCredentials{{
+			Name:      "creds",
+			Source:    v1.FunctionCredentialsSourceSecret,
+			SecretRef: &xpv1.SecretReference{Namespace: "crossplane-system", Name: "secret"},
+		}},
+	}}
+
+	type args struct {
+		cr resource.Composite
+	}
+	type want struct {
+		rev *v1.CompositionRevision
+		err error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		client  client.Client
+		wrapped CompositionRevisionFetcher
+		args    args
+		want    want
+	}{
+		"Composition": {
+			reason: "We should delegate to the wrapped fetcher if the XR references a Composition.",
+			wrapped: CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+				return &v1.CompositionRevision{}, nil
+			}),
+			args: args{
+				cr: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: &corev1.ObjectReference{Name: "cluster-composition"}},
+				},
+			},
+			want: want{
+				rev: &v1.CompositionRevision{},
+			},
+		},
+		"ClusterScopedXR": {
+			reason: "We should return an error if a cluster scoped XR references a NamespacedComposition.",
+			args: args{
+				cr: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: ref},
+				},
+			},
+			want: want{
+				err: errors.New(errNamespacedCompositionForClusterXR),
+			},
+		},
+		"GetNamespacedCompositionError": {
+			reason: "We should wrap and return errors encountered getting the NamespacedComposition.",
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(errBoom),
+			},
+			args: args{
+				cr: &fake.Composite{
+					ObjectMeta:            metav1.ObjectMeta{Namespace: "tenant"},
+					CompositionReferencer: fake.CompositionReferencer{Ref: ref},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetNamespacedComposition),
+			},
+		},
+		"UpdateManual": {
+			reason: "When we're using the manual update policy we should return the referenced revision from the XR's namespace.",
+			client: &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					if key.Namespace != "tenant" {
+						t.Errorf("Get(...): want namespace %q, got %q", "tenant", key.Namespace)
+					}
+					*obj.(*v2alpha1.NamespacedCompositionRevision) = *rev
+					return nil
+				},
+			},
+			args: args{
+				cr: &fake.Composite{
+					ObjectMeta:                    metav1.ObjectMeta{Namespace: "tenant"},
+					CompositionReferencer:         fake.CompositionReferencer{Ref: ref},
+					CompositionRevisionReferencer: fake.CompositionRevisionReferencer{Ref: &corev1.LocalObjectReference{Name: rev.GetName()}},
+					CompositionUpdater:            fake.CompositionUpdater{Policy: &manual},
+				},
+			},
+			want: want{
+				rev: rev.AsCompositionRevision(),
+			},
+		},
+		"NoRevisionSet": {
+			reason: "We should return the latest revision and update our reference if none is set.",
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					*obj.(*v2alpha1.NamespacedComposition) = *comp
+					return nil
+				}),
+				MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+					lo := &client.ListOptions{}
+					lo.ApplyOptions(opts)
+					if lo.Namespace != "tenant" {
+						t.Errorf("List(...): want namespace %q, got %q", "tenant", lo.Namespace)
+					}
+					*obj.(*v2alpha1.NamespacedCompositionRevisionList) = v2alpha1.NamespacedCompositionRevisionList{
+						Items: []v2alpha1.NamespacedCompositionRevision{*rev},
+					}
+					return nil
+				},
+				MockUpdate: test.NewMockUpdateFn(nil),
+			},
+			args: args{
+				cr: &fake.Composite{
+					ObjectMeta:            metav1.ObjectMeta{Namespace: "tenant"},
+					CompositionReferencer: fake.CompositionReferencer{Ref: ref},
+				},
+			},
+			want: want{
+				rev: rev.AsCompositionRevision(),
+			},
+		},
+		"CredentialsInOtherNamespace": {
+			reason: "We should return an error if the latest revision loads credentials from another namespace.",
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					*obj.(*v2alpha1.NamespacedComposition) = *comp
+					return nil
+				}),
+				MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+					*obj.(*v2alpha1.NamespacedCompositionRevisionList) = v2alpha1.NamespacedCompositionRevisionList{
+						Items: []v2alpha1.NamespacedCompositionRevision{*escapee},
+					}
+					return nil
+				}),
+			},
+			args: args{
+				cr: &fake.Composite{
+					ObjectMeta:            metav1.ObjectMeta{Namespace: "tenant"},
+					CompositionReferencer: fake.CompositionReferencer{Ref: ref},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtCredentialsSecretNotInNamespace, "creds", "escape", "tenant"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := NewAPINamespacedRevisionFetcher(tc.client, tc.wrapped)
+			got, err := f.Fetch(context.Background(), tc.args.cr)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("%s\nf.Fetch(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.rev, got); diff != "" {
+				t.Errorf("%s\nf.Fetch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestConfigure(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -716,6 +914,119 @@ func TestSelectorResolver(t *testing.T) {
 	}
 }
 
+func TestNamespacedSelectorResolver(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	a, k := schema.EmptyObjectKind.GroupVersionKind().ToAPIVersionAndKind()
+	tref := v1.TypeReference{APIVersion: a, Kind: k}
+	comp := v2alpha1.NamespacedComposition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "tenant",
+		},
+		Spec: v2alpha1.NamespacedCompositionSpec{
+			CompositeTypeRef: tref,
+		},
+	}
+	sel := &metav1.LabelSelector{MatchLabels: map[string]string{"select": "me"}}
+
+	type args struct {
+		kube client.Client
+		cp   resource.Composite
+	}
+	type want struct {
+		cp  resource.Composite
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"AlreadyResolved": {
+			reason: "Should be no-op if the composition selector is already resolved",
+			args: args{
+				cp: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: &corev1.ObjectReference{Name: comp.Name}},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: &corev1.ObjectReference{Name: comp.Name}},
+				},
+			},
+		},
+		"ListNamespacedFailed": {
+			reason: "Should fail if listing NamespacedCompositions fails",
+			args: args{
+				kube: &test.MockClient{MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+					if _, ok := obj.(*v2alpha1.NamespacedCompositionList); ok {
+						return errBoom
+					}
+					return nil
+				}},
+				cp: &fake.Composite{},
+			},
+			want: want{
+				cp:  &fake.Composite{},
+				err: errors.Wrap(errBoom, errListNamespacedCompositions),
+			},
+		},
+		"SelectedTheCompatibleOne": {
+			reason: "Should select the compatible NamespacedComposition in the XR's namespace",
+			args: args{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+						list, ok := obj.(*v2alpha1.NamespacedCompositionList)
+						if !ok {
+							return nil
+						}
+						lo := &client.ListOptions{}
+						lo.ApplyOptions(opts)
+						if lo.Namespace != "tenant" {
+							t.Errorf("List(...): want namespace %q, got %q", "tenant", lo.Namespace)
+						}
+						list.Items = []v2alpha1.NamespacedComposition{
+							{
+								Spec: v2alpha1.NamespacedCompositionSpec{
+									CompositeTypeRef: v1.TypeReference{APIVersion: "foreign", Kind: "tome"},
+								},
+							},
+							comp,
+						}
+						return nil
+					},
+				},
+				cp: &fake.Composite{
+					ObjectMeta:          metav1.ObjectMeta{Namespace: "tenant"},
+					CompositionSelector: fake.CompositionSelector{Sel: sel},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta:            metav1.ObjectMeta{Namespace: "tenant"},
+					CompositionReferencer: fake.CompositionReferencer{Ref: &corev1.ObjectReference{Kind: v2alpha1.NamespacedCompositionKind, Name: comp.Name}},
+					CompositionSelector:   fake.CompositionSelector{Sel: sel},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewAPINamespacedLabelSelectorResolver(tc.args.kube)
+			err := c.SelectComposition(context.Background(), tc.args.cp)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSelectComposition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cp, tc.args.cp); diff != "" {
+				t.Errorf("\n%s\nSelectComposition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestAPIDefaultCompositionSelector(t *testing.T) {
 	errBoom := errors.New("boom")
 	a, k := schema.EmptyObjectKind.GroupVersionKind().ToAPIVersionAndKind()
@@ -901,6 +1212,22 @@ func TestAPIEnforcedCompositionSelector(t *testing.T) {
 				},
 			},
 		},
+		"SuccessOverrideNamespaced": {
+			reason: "Successfully set the enforced composition reference even if a NamespacedComposition with the same name was set",
+			args: args{
+				def: v1.CompositeResourceDefinition{
+					Spec: v1.CompositeResourceDefinitionSpec{EnforcedCompositionRef: &v1.CompositionReference{Name: comp.Name}},
+				},
+				cp: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: &corev1.ObjectReference{Kind: v2alpha1.NamespacedCompositionKind, Name: comp.Name}},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: &corev1.ObjectReference{Name: comp.Name}},
+				},
+			},
+		},
 		"SuccessOverride": {
 			reason: "Successfully set the default composition reference even if another one was set",
 			args: args{
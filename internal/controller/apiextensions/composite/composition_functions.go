@@ -52,6 +52,7 @@ import (
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/names"
 	"github.com/crossplane/crossplane/internal/xcrd"
+	"github.com/crossplane/crossplane/internal/xfn"
 )
 
 // Error strings.
@@ -73,8 +74,12 @@ const (
 	errListExtraResources       = "cannot list extra resources"
 	errGetComposed              = "cannot get composed resource"
 	errMarshalJSON              = "cannot marshal to JSON"
+	errComposedResourceLimits   = "composed resource limits exceeded"
+	errImpersonate              = "cannot determine the identity to apply composed resources as"
+	errFetchCompositionPolicy   = "cannot fetch composition policy"
 
 	errFmtApplyCD                    = "cannot apply composed resource %q"
+	errFmtImpersonateApplyCD         = "cannot apply composed resource %q as %q"
 	errFmtFetchCDConnectionDetails   = "cannot fetch connection details for composed resource %q (a %s named %s)"
 	errFmtUnmarshalPipelineStepInput = "cannot unmarshal input for Composition pipeline step %q"
 	errFmtGetCredentialsFromSecret   = "cannot get Composition pipeline step %q credential %q from Secret"
@@ -87,7 +92,9 @@ const (
 	errFmtGenerateName               = "cannot generate a name for composed resource %q"
 	errFmtCDAsStruct                 = "cannot encode composed resource %q to protocol buffer Struct well-known type"
 	errFmtFatalResult                = "pipeline step %q returned a fatal result: %s"
+	errFmtValidateComposedKinds      = "cannot validate the kinds of composed resource desired by pipeline step %q"
 	errFmtInvalidName                = "cannot apply composed resource %q because it has an invalid name %q. Must be a valid RFC 1123 subdomain name."
+	errFmtNamespacedCompositionKinds = "refusing to compose resources using a NamespacedComposition: namespace %q must either configure a service account to apply composed resources as, or annotate the kinds of resource its composite resources may compose"
 )
 
 // Server-side-apply field owners. We need two of these because it's possible
@@ -122,6 +129,10 @@ type xr struct {
 	ComposedResourceGarbageCollector
 	ExtraResourcesFetcher
 	ManagedFieldsUpgrader
+	CompositionPolicyFetcher
+	ComposedResourceLimiter
+	ComposedResourceKindValidator
+	Impersonator
 }
 
 // A FunctionRunner runs a single Composition Function.
@@ -236,6 +247,40 @@ func WithManagedFieldsUpgrader(u ManagedFieldsUpgrader) FunctionComposerOption {
 	}
 }
 
+// WithCompositionPolicyFetcher configures how the FunctionComposer should fetch
+// the policy that limits what a composite resource may compose, and the
+// identity its composed resources are applied as. The policy is fetched once
+// per composition.
+func WithCompositionPolicyFetcher(f CompositionPolicyFetcher) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.composite.CompositionPolicyFetcher = f
+	}
+}
+
+// WithComposedResourceLimiter configures how the FunctionComposer should
+// limit the composed resources a composite resource may have.
+func WithComposedResourceLimiter(l ComposedResourceLimiter) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.composite.ComposedResourceLimiter = l
+	}
+}
+
+// WithComposedResourceKindValidator configures how the FunctionComposer should
+// validate the kinds of composed resource a composite resource may have.
+func WithComposedResourceKindValidator(v ComposedResourceKindValidator) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.composite.ComposedResourceKindValidator = v
+	}
+}
+
+// WithImpersonator configures how the FunctionComposer should determine the
+// identity to apply composed resources as.
+func WithImpersonator(i Impersonator) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.composite.Impersonator = i
+	}
+}
+
 // NewFunctionComposer returns a new Composer that supports composing resources using
 // both Patch and Transform (P&T) logic and a pipeline of Composition Functions.
 func NewFunctionComposer(cached, uncached client.Client, r FunctionRunner, o ...FunctionComposerOption) *FunctionComposer {
@@ -250,6 +295,10 @@ func NewFunctionComposer(cached, uncached client.Client, r FunctionRunner, o ...
 			ComposedResourceGarbageCollector: NewDeletingComposedResourceGarbageCollector(cached),
 			NameGenerator:                    names.NewNameGenerator(cached),
 			ManagedFieldsUpgrader:            NewPatchingManagedFieldsUpgrader(cached),
+			CompositionPolicyFetcher:         NopCompositionPolicyFetcher{},
+			ComposedResourceLimiter:          NopComposedResourceLimiter{},
+			ComposedResourceKindValidator:    NopComposedResourceKindValidator{},
+			Impersonator:                     NopImpersonator{},
 		},
 
 		pipeline: r,
@@ -290,6 +339,13 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		return CompositionResult{}, errors.Wrap(err, errBuildObserved)
 	}
 
+	// Fetch the policy that limits what the XR may compose, and who its
+	// composed resources are applied as.
+	policy, err := c.composite.FetchCompositionPolicy(ctx, xr)
+	if err != nil {
+		return CompositionResult{}, errors.Wrap(err, errFetchCompositionPolicy)
+	}
+
 	// Time-to-live for this composition pipeline run. Each function returns
 	// a TTL. The pipeline's TTL will be the shortest non-zero TTL returned
 	// by any function. A TTL of zero means unlimited TTL.
@@ -340,7 +396,7 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 
 		req.Meta = &fnv1.RequestMeta{Tag: Tag(req)}
 
-		rsp, err := c.pipeline.RunFunction(ctx, fn.FunctionRef.Name, req)
+		rsp, err := c.pipeline.RunFunction(functionContext(ctx, fn.FunctionRef), fn.FunctionRef.Name, req)
 		if err != nil {
 			return CompositionResult{}, errors.Wrapf(err, errFmtRunPipelineStep, fn.Step)
 		}
@@ -412,6 +468,47 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 			}
 			events = append(events, e)
 		}
+
+		// Make sure this step didn't desire any kinds of composed resource the
+		// XR isn't allowed to compose. We check after each step so that we can
+		// tell which step desired the disallowed resource.
+		err = c.composite.ValidateComposedResourceKinds(ctx, xr, policy, d.GetResources())
+		if IsKindNotAllowed(err) {
+			return CompositionResult{Events: events, Conditions: conditions}, errors.Errorf(errFmtFatalResult, fn.Step, err.Error())
+		}
+		if err != nil {
+			return CompositionResult{Events: events, Conditions: conditions}, errors.Wrapf(err, errFmtValidateComposedKinds, fn.Step)
+		}
+	}
+
+	// Make sure the pipeline didn't produce more composed resources than the
+	// XR is allowed to have. We check this before we garbage collect or apply
+	// anything to avoid leaving the XR partially composed.
+	if err := c.composite.CheckComposedResourceLimits(ctx, xr, policy, d.GetResources()); err != nil {
+		return CompositionResult{Events: events, Conditions: conditions}, errors.Wrap(err, errComposedResourceLimits)
+	}
+
+	// Determine who we should apply composed resources as. By default we
+	// apply them as ourselves.
+	applier := c.client
+	ic, username, err := c.composite.Impersonate(ctx, xr, policy)
+	if err != nil {
+		return CompositionResult{}, errors.Wrap(err, errImpersonate)
+	}
+	if ic != nil {
+		applier = ic
+	}
+
+	// Anyone who can edit a namespace's XRs can author the
+	// NamespacedCompositions they use. Unless we apply composed resources as
+	// an impersonated service account, they could use Crossplane's own
+	// identity to compose resources they aren't allowed to create. So the
+	// namespace must explicitly allow the kinds of resource they may compose.
+	// The ComposedResourceKindValidator enforces the allowed kinds.
+	if ic == nil && IsNamespacedCompositionReference(xr.GetCompositionReference()) {
+		if _, ok := NamespaceAllowedKinds(policy.Namespace); !ok {
+			return CompositionResult{Events: events, Conditions: conditions}, errors.Errorf(errFmtNamespacedCompositionKinds, xr.GetNamespace())
+		}
 	}
 
 	// Load our desired composed resources from the Function pipeline.
@@ -525,7 +622,19 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		// NOTE(phisco): We need to set a field owner unique for each XR here,
 		// this prevents multiple XRs composing the same resource to be
 		// continuously alternated as controllers.
-		if err := c.client.Patch(ctx, cd.Resource, client.Apply, client.ForceOwnership, client.FieldOwner(ComposedFieldOwnerName(xr))); err != nil {
+		if err := applier.Patch(ctx, cd.Resource, client.Apply, client.ForceOwnership, client.FieldOwner(ComposedFieldOwnerName(xr))); err != nil {
+			if ic != nil && kerrors.IsForbidden(err) {
+				// The identity we're impersonating isn't allowed to apply
+				// this composed resource. This is a policy decision, not
+				// something retrying will fix, so we emit a warning event
+				// and move on to the next resource.
+				events = append(events, TargetedEvent{
+					Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtImpersonateApplyCD, name, username)),
+					Target: CompositionTargetComposite,
+				})
+				resources = append(resources, ComposedResource{ResourceName: name, Ready: cd.Ready, Synced: false})
+				continue
+			}
 			if kerrors.IsInvalid(err) {
 				// We tried applying an invalid resource, we can't tell whether
 				// this means the resource will never be valid or it will if we
@@ -603,6 +712,15 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 	return result, nil
 }
 
+// functionContext returns a context that pins the referenced Function to a
+// specific FunctionRevision, if the reference requires one.
+func functionContext(ctx context.Context, ref v1.FunctionReference) context.Context {
+	if s, ok := xfn.RevisionSelectorFor(ref); ok {
+		return xfn.WithRevisionSelector(ctx, s)
+	}
+	return ctx
+}
+
 // Tag uniquely identifies a request. Two identical requests created by the
 // same Crossplane binary will produce identical tags. Different builds of
 // Crossplane may produce different tags for the same inputs. See the docs for
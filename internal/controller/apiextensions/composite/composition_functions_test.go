@@ -49,6 +49,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v2alpha1"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -56,6 +57,7 @@ func TestFunctionCompose(t *testing.T) {
 	errBoom := errors.New("boom")
 
 	errProtoSyntax := protojson.Unmarshal([]byte("hi"), &structpb.Struct{})
+	errForbidden := kerrors.NewForbidden(schema.GroupResource{Group: "test.crossplane.io", Resource: "uncoolcomposeds"}, "", errBoom)
 
 	type params struct {
 		c  client.Client
@@ -360,6 +362,169 @@ func TestFunctionCompose(t *testing.T) {
 				},
 			},
 		},
+		"FetchCompositionPolicyError": {
+			reason: "We should return any error encountered fetching the XR's composition policy",
+			params: params{
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithCompositionPolicyFetcher(CompositionPolicyFetcherFn(func(_ context.Context, _ resource.Composite) (*CompositionPolicy, error) {
+						return nil, errBoom
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFetchCompositionPolicy),
+			},
+		},
+		"ComposedResourceKindNotAllowedError": {
+			reason: "We should return a fatal result naming the step that desired a composed resource of a kind the XR may not compose",
+			params: params{
+				r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					d := &fnv1.State{
+						Resources: map[string]*fnv1.Resource{
+							"cool-resource": {
+								Resource: MustStruct(map[string]any{
+									"apiVersion": "rbac.authorization.k8s.io/v1",
+									"kind":       "ClusterRole",
+								}),
+							},
+						},
+					}
+					return &fnv1.RunFunctionResponse{Desired: d}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithComposedResourceKindValidator(ComposedResourceKindValidatorFn(func(_ context.Context, _ resource.Composite, _ *CompositionPolicy, _ map[string]*fnv1.Resource) error {
+						return &KindNotAllowedError{msg: "not allowed"}
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtFatalResult, "run-cool-function", "not allowed"),
+			},
+		},
+		"ValidateComposedResourceKindsError": {
+			reason: "We should return an error, not a fatal result, if we can't tell whether the XR may compose the desired kinds",
+			params: params{
+				r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					d := &fnv1.State{
+						Resources: map[string]*fnv1.Resource{
+							"cool-resource": {
+								Resource: MustStruct(map[string]any{
+									"apiVersion": "rbac.authorization.k8s.io/v1",
+									"kind":       "ClusterRole",
+								}),
+							},
+						},
+					}
+					return &fnv1.RunFunctionResponse{Desired: d}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithComposedResourceKindValidator(ComposedResourceKindValidatorFn(func(_ context.Context, _ resource.Composite, _ *CompositionPolicy, _ map[string]*fnv1.Resource) error {
+						return errBoom
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtValidateComposedKinds, "run-cool-function"),
+			},
+		},
+		"ComposedResourceLimitsError": {
+			reason: "We should return an error without applying anything if the desired composed resources exceed the XR's limits",
+			params: params{
+				r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					d := &fnv1.State{
+						Resources: map[string]*fnv1.Resource{
+							"cool-resource": {
+								Resource: MustStruct(map[string]any{
+									"apiVersion": "test.crossplane.io/v1",
+									"kind":       "CoolComposed",
+								}),
+							},
+						},
+					}
+					return &fnv1.RunFunctionResponse{Desired: d}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithComposedResourceLimiter(ComposedResourceLimiterFn(func(_ context.Context, _ resource.Composite, _ *CompositionPolicy, _ map[string]*fnv1.Resource) error {
+						return errBoom
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errComposedResourceLimits),
+			},
+		},
 		"RenderComposedResourceMetadataError": {
 			reason: "We should return any error we encounter when rendering composed resource metadata",
 			params: params{
@@ -727,6 +892,174 @@ func TestFunctionCompose(t *testing.T) {
 				err: errors.Wrapf(errBoom, errFmtApplyCD, "uncool-resource"),
 			},
 		},
+		"ImpersonateError": {
+			reason: "We should return any error we encounter determining who to apply composed resources as",
+			params: params{
+				c: &test.MockClient{
+					MockGet:   test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "UncoolComposed"}, "")), // all names are available
+					MockPatch: test.NewMockPatchFn(nil),
+				},
+				r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					d := &fnv1.State{
+						Resources: map[string]*fnv1.Resource{
+							"uncool-resource": {
+								Resource: MustStruct(map[string]any{
+									"apiVersion": "test.crossplane.io/v1",
+									"kind":       "UncoolComposed",
+								}),
+							},
+						},
+					}
+					return &fnv1.RunFunctionResponse{Desired: d}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+						return nil
+					})),
+					WithImpersonator(ImpersonatorFn(func(_ context.Context, _ resource.Composite, _ *CompositionPolicy) (client.Client, string, error) {
+						return nil, "", errBoom
+					})),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errImpersonate),
+			},
+		},
+		"NamespacedCompositionKindsNotAllowedError": {
+			reason: "We should refuse to compose resources as ourselves using a NamespacedComposition when the namespace doesn't allow any composed resource kinds",
+			params: params{
+				r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					return &fnv1.RunFunctionResponse{}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithCompositionPolicyFetcher(CompositionPolicyFetcherFn(func(_ context.Context, _ resource.Composite) (*CompositionPolicy, error) {
+						return &CompositionPolicy{
+							Definition: &v1.CompositeResourceDefinition{},
+							Namespace:  &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant"}},
+						}, nil
+					})),
+				},
+			},
+			args: args{
+				xr: func() *composite.Unstructured {
+					xr := composite.New()
+					xr.SetNamespace("tenant")
+					xr.SetCompositionReference(&corev1.ObjectReference{Kind: v2alpha1.NamespacedCompositionKind, Name: "cool-composition"})
+					return xr
+				}(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtNamespacedCompositionKinds, "tenant"),
+			},
+		},
+		"ImpersonatedApplyForbidden": {
+			reason: "We should emit an event and continue if the impersonated identity isn't allowed to apply a composed resource",
+			params: params{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "UncoolComposed"}, "")), // all names are available
+					MockPatch: test.NewMockPatchFn(nil, func(obj client.Object) error {
+						// We shouldn't apply composed resources as ourselves.
+						if _, ok := obj.(*composed.Unstructured); ok {
+							t.Errorf("Patch(...): unexpectedly applied composed resource without impersonation")
+						}
+						return nil
+					}),
+					MockStatusPatch: test.NewMockSubResourcePatchFn(nil),
+				},
+				r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					d := &fnv1.State{
+						Resources: map[string]*fnv1.Resource{
+							"uncool-resource": {
+								Resource: MustStruct(map[string]any{
+									"apiVersion": "test.crossplane.io/v1",
+									"kind":       "UncoolComposed",
+								}),
+							},
+						},
+					}
+					return &fnv1.RunFunctionResponse{Desired: d}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+						return nil
+					})),
+					WithImpersonator(ImpersonatorFn(func(_ context.Context, _ resource.Composite, _ *CompositionPolicy) (client.Client, string, error) {
+						return &test.MockClient{MockPatch: test.NewMockPatchFn(errForbidden)}, "system:serviceaccount:ns:tenant", nil
+					})),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{ResourceName: "uncool-resource", Synced: false}},
+					Events: []TargetedEvent{
+						{
+							Event:  event.Warning(reasonCompose, errors.Wrapf(errForbidden, errFmtImpersonateApplyCD, "uncool-resource", "system:serviceaccount:ns:tenant")),
+							Target: CompositionTargetComposite,
+						},
+					},
+				},
+			},
+		},
 		"Successful": {
 			reason: "We should return a valid CompositionResult when a 'pure Function' (i.e. patch-and-transform-less) reconcile succeeds",
 			params: params{
@@ -0,0 +1,348 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"crypto/tls"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	essproto "github.com/crossplane/crossplane-runtime/apis/proto/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// Error strings.
+const (
+	errApplyConfigMap         = "cannot apply connection details ConfigMap"
+	errApplyExternal          = "cannot apply connection details to external secret store"
+	errPublisherNotNamespaced = "connection details publishers only support namespaced composite resources"
+	errParseNameTemplate      = "cannot parse name template"
+	errExecuteNameTemplate    = "cannot execute name template"
+	errEmptyName              = "name template rendered an empty name"
+
+	errFmtUnknownPublisherType = "unknown connection details publisher type %q"
+	errFmtDialExternal         = "cannot dial external secret store %q"
+)
+
+// A NameRenderer renders the name connection details should be published
+// under for the supplied composite resource.
+type NameRenderer interface {
+	RenderName(o ConnectionSecretOwner) (string, error)
+}
+
+// A NameRendererFn renders the name connection details should be published
+// under for the supplied composite resource.
+type NameRendererFn func(o ConnectionSecretOwner) (string, error)
+
+// RenderName renders the name connection details should be published under.
+func (fn NameRendererFn) RenderName(o ConnectionSecretOwner) (string, error) {
+	return fn(o)
+}
+
+// A TemplatedNameRenderer renders names using a Go template. The template is
+// executed against the composite resource.
+type TemplatedNameRenderer struct {
+	tmpl *template.Template
+}
+
+// NewTemplatedNameRenderer returns a NameRenderer that renders names using the
+// supplied Go template. It renders the composite resource's name if the
+// template is nil.
+func NewTemplatedNameRenderer(tmpl *string) (*TemplatedNameRenderer, error) {
+	t, err := template.New("name").Option("missingkey=error").Parse(ptr.Deref(tmpl, "{{ .metadata.name }}"))
+	if err != nil {
+		return nil, errors.Wrap(err, errParseNameTemplate)
+	}
+	return &TemplatedNameRenderer{tmpl: t}, nil
+}
+
+// RenderName renders the name connection details should be published under.
+func (r *TemplatedNameRenderer) RenderName(o ConnectionSecretOwner) (string, error) {
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return "", errors.Wrap(err, errExecuteNameTemplate)
+	}
+	b := &strings.Builder{}
+	if err := r.tmpl.Execute(b, data); err != nil {
+		return "", errors.Wrap(err, errExecuteNameTemplate)
+	}
+	name := strings.TrimSpace(b.String())
+	if name == "" {
+		return "", errors.New(errEmptyName)
+	}
+	return name, nil
+}
+
+// FilterConnectionDetails returns the supplied connection details that are
+// included in the supplied filter. All details are returned if the filter is
+// empty.
+func FilterConnectionDetails(c managed.ConnectionDetails, filter []string) managed.ConnectionDetails {
+	if len(filter) == 0 {
+		return c
+	}
+	m := map[string]bool{}
+	for _, key := range filter {
+		m[key] = true
+	}
+	out := managed.ConnectionDetails{}
+	for key, val := range c {
+		if m[key] {
+			out[key] = val
+		}
+	}
+	return out
+}
+
+// APITemplatedSecretPublisher publishes connection details to a Secret in the
+// composite resource's namespace, with a templated name.
+type APITemplatedSecretPublisher struct {
+	client resource.Applicator
+	name   NameRenderer
+	typ    corev1.SecretType
+	filter []string
+}
+
+// NewAPITemplatedSecretPublisher returns a ConnectionPublisher that publishes
+// connection details to a Secret of the supplied type in the composite
+// resource's namespace.
+func NewAPITemplatedSecretPublisher(c client.Client, n NameRenderer, t corev1.SecretType, filter []string) *APITemplatedSecretPublisher {
+	return &APITemplatedSecretPublisher{client: resource.NewAPIPatchingApplicator(c), name: n, typ: t, filter: filter}
+}
+
+// PublishConnection publishes the supplied ConnectionDetails to a Secret.
+func (a *APITemplatedSecretPublisher) PublishConnection(ctx context.Context, o ConnectionSecretOwner, c managed.ConnectionDetails) (bool, error) {
+	if o.GetNamespace() == "" {
+		return false, errors.New(errPublisherNotNamespaced)
+	}
+	name, err := a.name.RenderName(o)
+	if err != nil {
+		return false, err
+	}
+
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       o.GetNamespace(),
+			Name:            name,
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(o, o.GetObjectKind().GroupVersionKind()))},
+		},
+		Type: a.typ,
+		Data: FilterConnectionDetails(c, a.filter),
+	}
+
+	err = a.client.Apply(ctx, s,
+		resource.ConnectionSecretMustBeControllableBy(o.GetUID()),
+		resource.AllowUpdateIf(func(current, desired runtime.Object) bool {
+			//nolint:forcetypeassert // These will always be secrets.
+			return !cmp.Equal(current.(*corev1.Secret).Data, desired.(*corev1.Secret).Data, cmpopts.EquateEmpty())
+		}),
+	)
+	if resource.IsNotAllowed(err) {
+		// The update was not allowed because it was a no-op.
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, errApplySecret)
+	}
+
+	return true, nil
+}
+
+// APIConfigMapPublisher publishes connection details to a ConfigMap in the
+// composite resource's namespace, with a templated name. Connection details
+// published to a ConfigMap aren't treated as sensitive.
+type APIConfigMapPublisher struct {
+	client resource.Applicator
+	name   NameRenderer
+	filter []string
+}
+
+// NewAPIConfigMapPublisher returns a ConnectionPublisher that publishes
+// connection details to a ConfigMap in the composite resource's namespace.
+func NewAPIConfigMapPublisher(c client.Client, n NameRenderer, filter []string) *APIConfigMapPublisher {
+	return &APIConfigMapPublisher{client: resource.NewAPIPatchingApplicator(c), name: n, filter: filter}
+}
+
+// PublishConnection publishes the supplied ConnectionDetails to a ConfigMap.
+func (a *APIConfigMapPublisher) PublishConnection(ctx context.Context, o ConnectionSecretOwner, c managed.ConnectionDetails) (bool, error) {
+	if o.GetNamespace() == "" {
+		return false, errors.New(errPublisherNotNamespaced)
+	}
+	name, err := a.name.RenderName(o)
+	if err != nil {
+		return false, err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       o.GetNamespace(),
+			Name:            name,
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(o, o.GetObjectKind().GroupVersionKind()))},
+		},
+		Data: map[string]string{},
+	}
+	for k, v := range FilterConnectionDetails(c, a.filter) {
+		cm.Data[k] = string(v)
+	}
+
+	err = a.client.Apply(ctx, cm,
+		resource.MustBeControllableBy(o.GetUID()),
+		resource.AllowUpdateIf(func(current, desired runtime.Object) bool {
+			//nolint:forcetypeassert // These will always be ConfigMaps.
+			return !cmp.Equal(current.(*corev1.ConfigMap).Data, desired.(*corev1.ConfigMap).Data, cmpopts.EquateEmpty())
+		}),
+	)
+	if resource.IsNotAllowed(err) {
+		// The update was not allowed because it was a no-op.
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, errApplyConfigMap)
+	}
+
+	return true, nil
+}
+
+// An ExternalStorePublisher publishes connection details to an external secret
+// store plugin.
+type ExternalStorePublisher struct {
+	client essproto.ExternalSecretStorePluginServiceClient
+	config *essproto.ConfigReference
+	name   NameRenderer
+	filter []string
+}
+
+// NewExternalStorePublisher returns a ConnectionPublisher that publishes
+// connection details to the supplied external secret store plugin.
+func NewExternalStorePublisher(c essproto.ExternalSecretStorePluginServiceClient, cfg *essproto.ConfigReference, n NameRenderer, filter []string) *ExternalStorePublisher {
+	return &ExternalStorePublisher{client: c, config: cfg, name: n, filter: filter}
+}
+
+// PublishConnection publishes the supplied ConnectionDetails to the external
+// secret store. Connection details are stored under a name scoped by the
+// composite resource's namespace.
+func (p *ExternalStorePublisher) PublishConnection(ctx context.Context, o ConnectionSecretOwner, c managed.ConnectionDetails) (bool, error) {
+	if o.GetNamespace() == "" {
+		return false, errors.New(errPublisherNotNamespaced)
+	}
+	name, err := p.name.RenderName(o)
+	if err != nil {
+		return false, err
+	}
+
+	rsp, err := p.client.ApplySecret(ctx, &essproto.ApplySecretRequest{
+		Config: p.config,
+		Secret: &essproto.Secret{
+			ScopedName: path.Join(o.GetNamespace(), name),
+			Data:       FilterConnectionDetails(c, p.filter),
+		},
+	})
+	if err != nil {
+		return false, errors.Wrap(err, errApplyExternal)
+	}
+
+	return rsp.GetChanged(), nil
+}
+
+// PublisherConns are the gRPC client connections used by connection details
+// publishers to reach external secret store plugins.
+type PublisherConns []*grpc.ClientConn
+
+// Close all of the connections. The publishers that use them can't be used
+// once they're closed.
+func (c PublisherConns) Close() error {
+	errs := make([]error, 0, len(c))
+	for _, conn := range c {
+		errs = append(errs, conn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// NewConnectionPublishers returns the ConnectionPublishers configured by the
+// supplied publisher specs. External secret store plugins are dialed using
+// the supplied TLS config. The caller must close the returned connections
+// once it's done with the publishers.
+func NewConnectionPublishers(c client.Client, tcfg *tls.Config, specs []v1.ConnectionDetailsPublisher) ([]ConnectionPublisher, PublisherConns, error) {
+	ps := make([]ConnectionPublisher, 0, len(specs))
+	conns := PublisherConns{}
+	for _, s := range specs {
+		p, conn, err := newConnectionPublisher(c, tcfg, s)
+		if err != nil {
+			_ = conns.Close()
+			return nil, nil, err
+		}
+		if conn != nil {
+			conns = append(conns, conn)
+		}
+		ps = append(ps, p)
+	}
+	return ps, conns, nil
+}
+
+// newConnectionPublisher returns the ConnectionPublisher configured by the
+// supplied spec, and the gRPC client connection it uses, if any.
+func newConnectionPublisher(c client.Client, tcfg *tls.Config, s v1.ConnectionDetailsPublisher) (ConnectionPublisher, *grpc.ClientConn, error) {
+	switch s.Type {
+	case v1.ConnectionDetailsPublisherTypeSecret:
+		cfg := ptr.Deref(s.Secret, v1.SecretConnectionDetailsPublisher{})
+		n, err := NewTemplatedNameRenderer(cfg.NameTemplate)
+		if err != nil {
+			return nil, nil, err
+		}
+		return NewAPITemplatedSecretPublisher(c, n, ptr.Deref(cfg.Type, resource.SecretTypeConnection), s.Keys), nil, nil
+	case v1.ConnectionDetailsPublisherTypeConfigMap:
+		cfg := ptr.Deref(s.ConfigMap, v1.ConfigMapConnectionDetailsPublisher{})
+		n, err := NewTemplatedNameRenderer(cfg.NameTemplate)
+		if err != nil {
+			return nil, nil, err
+		}
+		return NewAPIConfigMapPublisher(c, n, s.Keys), nil, nil
+	case v1.ConnectionDetailsPublisherTypeExternal:
+		cfg := ptr.Deref(s.External, v1.ExternalConnectionDetailsPublisher{})
+		n, err := NewTemplatedNameRenderer(cfg.NameTemplate)
+		if err != nil {
+			return nil, nil, err
+		}
+		conn, err := grpc.NewClient(cfg.Endpoint, grpc.WithTransportCredentials(credentials.NewTLS(tcfg)))
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, errFmtDialExternal, cfg.Endpoint)
+		}
+		var ref *essproto.ConfigReference
+		if cfg.ConfigRef != nil {
+			ref = &essproto.ConfigReference{ApiVersion: cfg.ConfigRef.APIVersion, Kind: cfg.ConfigRef.Kind, Name: cfg.ConfigRef.Name}
+		}
+		return NewExternalStorePublisher(essproto.NewExternalSecretStorePluginServiceClient(conn), ref, n, s.Keys), conn, nil
+	default:
+		return nil, nil, errors.Errorf(errFmtUnknownPublisherType, s.Type)
+	}
+}
@@ -0,0 +1,452 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/testing/protocmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	essproto "github.com/crossplane/crossplane-runtime/apis/proto/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func newPublisherXR(namespace string) *composite.Unstructured {
+	xr := composite.New(composite.WithGroupVersionKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XDatabase"}))
+	xr.SetNamespace(namespace)
+	xr.SetName("cool-db")
+	xr.SetUID(types.UID("no-you-id"))
+	return xr
+}
+
+func TestTemplatedNameRenderer(t *testing.T) {
+	type args struct {
+		tmpl *string
+		o    ConnectionSecretOwner
+	}
+	type want struct {
+		name string
+		err  bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Default": {
+			reason: "We should render the XR's name if no template is supplied.",
+			args: args{
+				o: newPublisherXR("tenant"),
+			},
+			want: want{
+				name: "cool-db",
+			},
+		},
+		"Template": {
+			reason: "We should render the supplied template against the XR.",
+			args: args{
+				tmpl: ptr.To("{{ .metadata.name }}-{{ .kind }}-connection"),
+				o:    newPublisherXR("tenant"),
+			},
+			want: want{
+				name: "cool-db-XDatabase-connection",
+			},
+		},
+		"MissingKey": {
+			reason: "We should return an error if the template references a field the XR doesn't have.",
+			args: args{
+				tmpl: ptr.To("{{ .spec.nope }}"),
+				o:    newPublisherXR("tenant"),
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"Empty": {
+			reason: "We should return an error if the template renders an empty name.",
+			args: args{
+				tmpl: ptr.To(" "),
+				o:    newPublisherXR("tenant"),
+			},
+			want: want{
+				err: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := NewTemplatedNameRenderer(tc.args.tmpl)
+			if err != nil {
+				t.Fatalf("NewTemplatedNameRenderer(...): %v", err)
+			}
+			got, err := r.RenderName(tc.args.o)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nRenderName(...): -want error, +got error:\n%s\n%v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.want.name, got); diff != "" {
+				t.Errorf("\n%s\nRenderName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAPITemplatedSecretPublisher(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := newPublisherXR("tenant")
+	owner := []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(xr, xr.GetObjectKind().GroupVersionKind()))}
+
+	type args struct {
+		o ConnectionSecretOwner
+		c managed.ConnectionDetails
+	}
+	type want struct {
+		published bool
+		err       error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		name   NameRenderer
+		filter []string
+		args   args
+		want   want
+	}{
+		"NotNamespaced": {
+			reason: "We should return an error if the XR isn't namespaced.",
+			args: args{
+				o: newPublisherXR(""),
+			},
+			want: want{
+				err: errors.New(errPublisherNotNamespaced),
+			},
+		},
+		"RenderNameError": {
+			reason: "We should return any error encountered rendering the Secret's name.",
+			name:   NameRendererFn(func(_ ConnectionSecretOwner) (string, error) { return "", errBoom }),
+			args: args{
+				o: xr,
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
+		"ApplyError": {
+			reason: "We should return any error encountered applying the Secret.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			name:   NameRendererFn(func(_ ConnectionSecretOwner) (string, error) { return "cool-secret", nil }),
+			args: args{
+				o: xr,
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, "cannot get object"), errApplySecret),
+			},
+		},
+		"Published": {
+			reason: "We should create a filtered Secret of the configured type.",
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "cool-secret")),
+				MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+					want := &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace:       "tenant",
+							Name:            "cool-secret",
+							OwnerReferences: owner,
+						},
+						Type: corev1.SecretTypeOpaque,
+						Data: map[string][]byte{"password": []byte("hunter2")},
+					}
+					if diff := cmp.Diff(want, obj); diff != "" {
+						t.Errorf("Create(...): -want, +got:\n%s", diff)
+					}
+					return nil
+				},
+			},
+			name:   NameRendererFn(func(_ ConnectionSecretOwner) (string, error) { return "cool-secret", nil }),
+			filter: []string{"password"},
+			args: args{
+				o: xr,
+				c: managed.ConnectionDetails{"password": []byte("hunter2"), "username": []byte("admin")},
+			},
+			want: want{
+				published: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewAPITemplatedSecretPublisher(tc.client, tc.name, corev1.SecretTypeOpaque, tc.filter)
+			got, err := p.PublishConnection(context.Background(), tc.args.o, tc.args.c)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.published, got); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAPIConfigMapPublisher(t *testing.T) {
+	xr := newPublisherXR("tenant")
+	owner := []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(xr, xr.GetObjectKind().GroupVersionKind()))}
+
+	type args struct {
+		o ConnectionSecretOwner
+		c managed.ConnectionDetails
+	}
+	type want struct {
+		published bool
+		err       error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		filter []string
+		args   args
+		want   want
+	}{
+		"NotNamespaced": {
+			reason: "We should return an error if the XR isn't namespaced.",
+			args: args{
+				o: newPublisherXR(""),
+			},
+			want: want{
+				err: errors.New(errPublisherNotNamespaced),
+			},
+		},
+		"NoOp": {
+			reason: "We shouldn't report that we published if the ConfigMap is unchanged.",
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					cm := obj.(*corev1.ConfigMap)
+					cm.SetOwnerReferences(owner)
+					cm.Data = map[string]string{"endpoint": "db.example.org"}
+					return nil
+				}),
+			},
+			args: args{
+				o: xr,
+				c: managed.ConnectionDetails{"endpoint": []byte("db.example.org")},
+			},
+			want: want{
+				published: false,
+			},
+		},
+		"Published": {
+			reason: "We should create a filtered ConfigMap.",
+			client: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "cool-db")),
+				MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+					want := &corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace:       "tenant",
+							Name:            "cool-db",
+							OwnerReferences: owner,
+						},
+						Data: map[string]string{"endpoint": "db.example.org"},
+					}
+					if diff := cmp.Diff(want, obj); diff != "" {
+						t.Errorf("Create(...): -want, +got:\n%s", diff)
+					}
+					return nil
+				},
+			},
+			filter: []string{"endpoint"},
+			args: args{
+				o: xr,
+				c: managed.ConnectionDetails{"endpoint": []byte("db.example.org"), "password": []byte("hunter2")},
+			},
+			want: want{
+				published: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			n, _ := NewTemplatedNameRenderer(nil)
+			p := NewAPIConfigMapPublisher(tc.client, n, tc.filter)
+			got, err := p.PublishConnection(context.Background(), tc.args.o, tc.args.c)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.published, got); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type MockExternalSecretStoreClient struct {
+	essproto.ExternalSecretStorePluginServiceClient
+
+	MockApplySecret func(ctx context.Context, in *essproto.ApplySecretRequest, opts ...grpc.CallOption) (*essproto.ApplySecretResponse, error)
+}
+
+func (m *MockExternalSecretStoreClient) ApplySecret(ctx context.Context, in *essproto.ApplySecretRequest, opts ...grpc.CallOption) (*essproto.ApplySecretResponse, error) {
+	return m.MockApplySecret(ctx, in, opts...)
+}
+
+func TestExternalStorePublisher(t *testing.T) {
+	errBoom := errors.New("boom")
+	cfg := &essproto.ConfigReference{ApiVersion: "secrets.example.org/v1", Kind: "VaultConfig", Name: "default"}
+
+	type args struct {
+		o ConnectionSecretOwner
+		c managed.ConnectionDetails
+	}
+	type want struct {
+		published bool
+		err       error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client essproto.ExternalSecretStorePluginServiceClient
+		filter []string
+		args   args
+		want   want
+	}{
+		"NotNamespaced": {
+			reason: "We should return an error if the XR isn't namespaced.",
+			args: args{
+				o: newPublisherXR(""),
+			},
+			want: want{
+				err: errors.New(errPublisherNotNamespaced),
+			},
+		},
+		"ApplyError": {
+			reason: "We should return any error encountered applying the secret.",
+			client: &MockExternalSecretStoreClient{
+				MockApplySecret: func(_ context.Context, _ *essproto.ApplySecretRequest, _ ...grpc.CallOption) (*essproto.ApplySecretResponse, error) {
+					return nil, errBoom
+				},
+			},
+			args: args{
+				o: newPublisherXR("tenant"),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errApplyExternal),
+			},
+		},
+		"Published": {
+			reason: "We should apply filtered connection details scoped by the XR's namespace.",
+			client: &MockExternalSecretStoreClient{
+				MockApplySecret: func(_ context.Context, in *essproto.ApplySecretRequest, _ ...grpc.CallOption) (*essproto.ApplySecretResponse, error) {
+					want := &essproto.ApplySecretRequest{
+						Config: cfg,
+						Secret: &essproto.Secret{
+							ScopedName: "tenant/cool-db",
+							Data:       map[string][]byte{"password": []byte("hunter2")},
+						},
+					}
+					if diff := cmp.Diff(want, in, protocmp.Transform()); diff != "" {
+						t.Errorf("ApplySecret(...): -want, +got:\n%s", diff)
+					}
+					return &essproto.ApplySecretResponse{Changed: true}, nil
+				},
+			},
+			filter: []string{"password"},
+			args: args{
+				o: newPublisherXR("tenant"),
+				c: managed.ConnectionDetails{"password": []byte("hunter2"), "username": []byte("admin")},
+			},
+			want: want{
+				published: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			n, _ := NewTemplatedNameRenderer(nil)
+			p := NewExternalStorePublisher(tc.client, cfg, n, tc.filter)
+			got, err := p.PublishConnection(context.Background(), tc.args.o, tc.args.c)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.published, got); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewConnectionPublishers(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		specs  []v1.ConnectionDetailsPublisher
+		want   int
+		conns  int
+		err    error
+	}{
+		"UnknownType": {
+			reason: "We should return an error for unknown publisher types.",
+			specs:  []v1.ConnectionDetailsPublisher{{Type: "Carrier Pigeon"}},
+			err:    errors.Errorf(errFmtUnknownPublisherType, "Carrier Pigeon"),
+		},
+		"AllTypes": {
+			reason: "We should return a publisher for each spec.",
+			specs: []v1.ConnectionDetailsPublisher{
+				{Type: v1.ConnectionDetailsPublisherTypeSecret, Secret: &v1.SecretConnectionDetailsPublisher{NameTemplate: ptr.To("{{ .metadata.name }}-conn")}},
+				{Type: v1.ConnectionDetailsPublisherTypeConfigMap},
+				{Type: v1.ConnectionDetailsPublisherTypeExternal, External: &v1.ExternalConnectionDetailsPublisher{Endpoint: "dns:///ess.crossplane-system:4040"}},
+			},
+			want:  3,
+			conns: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ps, conns, err := NewConnectionPublishers(&test.MockClient{}, nil, tc.specs)
+			defer conns.Close() //nolint:errcheck // Only fails if the connections are already closed.
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nNewConnectionPublishers(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, len(ps)); diff != "" {
+				t.Errorf("\n%s\nNewConnectionPublishers(...): -want publishers, +got publishers:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.conns, len(conns)); diff != "" {
+				t.Errorf("\n%s\nNewConnectionPublishers(...): -want connections, +got connections:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// AnnotationKeyComposedResourceServiceAccount may be set on a Namespace to
+// configure Crossplane to apply the composed resources of composite resources
+// in that namespace as the named service account, in that namespace. A
+// CompositeResourceDefinition's impersonation configuration takes precedence.
+const AnnotationKeyComposedResourceServiceAccount = "apiextensions.crossplane.io/composed-resource-service-account"
+
+// Defaults for the impersonated client cache. Every service account
+// impersonated within the TTL counts against the maximum size, so it bounds
+// the clients cached for a CompositeResourceDefinition whose composite
+// resources span many namespaces.
+const (
+	DefaultMaxImpersonatedClients = 100
+	DefaultImpersonatedClientTTL  = 1 * time.Hour
+)
+
+// Error strings.
+const (
+	errImpersonationNamespace = "cannot impersonate a service account for a cluster scoped composite resource without a service account namespace"
+	errFmtNewImpersonating    = "cannot create client impersonating %q"
+)
+
+// An Impersonator determines the identity Crossplane should apply composed
+// resources as.
+type Impersonator interface {
+	// Impersonate returns a client that impersonates the identity the
+	// supplied composite resource's composed resources should be applied as,
+	// and the username of that identity. It returns a nil client and an empty
+	// username if composed resources should be applied as Crossplane.
+	Impersonate(ctx context.Context, xr resource.Composite, p *CompositionPolicy) (client.Client, string, error)
+}
+
+// An ImpersonatorFn determines the identity Crossplane should apply composed
+// resources as.
+type ImpersonatorFn func(ctx context.Context, xr resource.Composite, p *CompositionPolicy) (client.Client, string, error)
+
+// Impersonate returns a client that impersonates the identity the supplied
+// composite resource's composed resources should be applied as.
+func (fn ImpersonatorFn) Impersonate(ctx context.Context, xr resource.Composite, p *CompositionPolicy) (client.Client, string, error) {
+	return fn(ctx, xr, p)
+}
+
+// A NopImpersonator never impersonates.
+type NopImpersonator struct{}
+
+// Impersonate always returns a nil client.
+func (n NopImpersonator) Impersonate(_ context.Context, _ resource.Composite, _ *CompositionPolicy) (client.Client, string, error) {
+	return nil, "", nil
+}
+
+// A NewClientFn returns a client that impersonates the supplied username.
+type NewClientFn func(username string) (client.Client, error)
+
+// NewImpersonatingClientFn returns a NewClientFn that creates clients from the
+// supplied REST config and options.
+func NewImpersonatingClientFn(cfg *rest.Config, o client.Options) NewClientFn {
+	return func(username string) (client.Client, error) {
+		ic := rest.CopyConfig(cfg)
+		ic.Impersonate = rest.ImpersonationConfig{UserName: username}
+		c, err := client.New(ic, o)
+		return c, errors.Wrapf(err, errFmtNewImpersonating, username)
+	}
+}
+
+// A PolicyImpersonator impersonates the service account configured by a
+// composite resource's CompositeResourceDefinition or namespace.
+type PolicyImpersonator struct {
+	newClient NewClientFn
+
+	maxClients int
+	clientTTL  time.Duration
+	clients    *cache.LRUExpireCache
+}
+
+// A PolicyImpersonatorOption configures a PolicyImpersonator.
+type PolicyImpersonatorOption func(i *PolicyImpersonator)
+
+// WithMaxImpersonatedClients configures the maximum number of impersonated
+// clients a PolicyImpersonator caches. The least recently used client is
+// evicted when the cache is full.
+func WithMaxImpersonatedClients(n int) PolicyImpersonatorOption {
+	return func(i *PolicyImpersonator) {
+		i.maxClients = n
+	}
+}
+
+// WithImpersonatedClientTTL configures how long a PolicyImpersonator caches an
+// impersonated client for.
+func WithImpersonatedClientTTL(ttl time.Duration) PolicyImpersonatorOption {
+	return func(i *PolicyImpersonator) {
+		i.clientTTL = ttl
+	}
+}
+
+// NewPolicyImpersonator returns an Impersonator that impersonates the service
+// account configured by the composite resource's CompositeResourceDefinition,
+// or its namespace.
+func NewPolicyImpersonator(fn NewClientFn, o ...PolicyImpersonatorOption) *PolicyImpersonator {
+	i := &PolicyImpersonator{
+		newClient:  fn,
+		maxClients: DefaultMaxImpersonatedClients,
+		clientTTL:  DefaultImpersonatedClientTTL,
+	}
+	for _, fn := range o {
+		fn(i)
+	}
+	i.clients = cache.NewLRUExpireCache(i.maxClients)
+	return i
+}
+
+// Impersonate returns a client that impersonates the service account the
+// supplied composite resource's composed resources should be applied as.
+func (i *PolicyImpersonator) Impersonate(_ context.Context, xr resource.Composite, p *CompositionPolicy) (client.Client, string, error) {
+	var username string
+	switch imp := p.Definition.Spec.Impersonation; {
+	case imp != nil:
+		ns := ptr.Deref(imp.ServiceAccountNamespace, xr.GetNamespace())
+		if ns == "" {
+			return nil, "", errors.New(errImpersonationNamespace)
+		}
+		username = serviceaccount.MakeUsername(ns, imp.ServiceAccountName)
+	case p.Namespace != nil:
+		ns := p.Namespace
+		sa := ns.GetAnnotations()[AnnotationKeyComposedResourceServiceAccount]
+		if sa == "" {
+			return nil, "", nil
+		}
+		username = serviceaccount.MakeUsername(ns.GetName(), sa)
+	default:
+		return nil, "", nil
+	}
+
+	if c, ok := i.clients.Get(username); ok {
+		return c.(client.Client), username, nil //nolint:forcetypeassert // We only add clients to the cache.
+	}
+
+	c, err := i.newClient(username)
+	if err != nil {
+		return nil, "", err
+	}
+	i.clients.Add(username, c, i.clientTTL)
+	return c, username, nil
+}
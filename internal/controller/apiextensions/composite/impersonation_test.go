@@ -0,0 +1,185 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestImpersonate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	withXRD := func(imp *v1.CompositeResourceImpersonation, ns *corev1.Namespace) *CompositionPolicy {
+		d := &v1.CompositeResourceDefinition{}
+		d.Spec.Impersonation = imp
+		return &CompositionPolicy{Definition: d, Namespace: ns}
+	}
+
+	newClient := func(_ string) (client.Client, error) {
+		return &test.MockClient{}, nil
+	}
+
+	type want struct {
+		impersonating bool
+		username      string
+		err           error
+	}
+
+	cases := map[string]struct {
+		reason    string
+		policy    *CompositionPolicy
+		newClient NewClientFn
+		xr        resource.Composite
+		want      want
+	}{
+		"NotConfigured": {
+			reason: "We shouldn't impersonate a cluster scoped XR if its XRD doesn't configure impersonation.",
+			policy: withXRD(nil, nil),
+			xr:     &fake.Composite{},
+			want:   want{},
+		},
+		"ClusterScopedWithoutNamespace": {
+			reason: "We should return an error if the XRD doesn't specify a service account namespace for a cluster scoped XR.",
+			policy: withXRD(&v1.CompositeResourceImpersonation{ServiceAccountName: "composer"}, nil),
+			xr:     &fake.Composite{},
+			want: want{
+				err: errors.New(errImpersonationNamespace),
+			},
+		},
+		"XRDServiceAccount": {
+			reason:    "We should impersonate the service account configured by the XRD.",
+			policy:    withXRD(&v1.CompositeResourceImpersonation{ServiceAccountName: "composer", ServiceAccountNamespace: ptr.To("crossplane-system")}, nil),
+			newClient: newClient,
+			xr:        &fake.Composite{},
+			want: want{
+				impersonating: true,
+				username:      "system:serviceaccount:crossplane-system:composer",
+			},
+		},
+		"XRDServiceAccountInXRNamespace": {
+			reason:    "We should impersonate the service account configured by the XRD, in the XR's namespace.",
+			policy:    withXRD(&v1.CompositeResourceImpersonation{ServiceAccountName: "composer"}, nil),
+			newClient: newClient,
+			xr:        &fake.Composite{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant"}},
+			want: want{
+				impersonating: true,
+				username:      "system:serviceaccount:tenant:composer",
+			},
+		},
+		"NamespaceNotConfigured": {
+			reason: "We shouldn't impersonate if neither the XRD nor the XR's namespace configure impersonation.",
+			policy: withXRD(nil, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant"}}),
+			xr:     &fake.Composite{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant"}},
+			want:   want{},
+		},
+		"NamespaceServiceAccount": {
+			reason: "We should impersonate the service account configured by the XR's namespace.",
+			policy: withXRD(nil, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "tenant",
+				Annotations: map[string]string{AnnotationKeyComposedResourceServiceAccount: "composer"},
+			}}),
+			newClient: newClient,
+			xr:        &fake.Composite{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant"}},
+			want: want{
+				impersonating: true,
+				username:      "system:serviceaccount:tenant:composer",
+			},
+		},
+		"NewClientError": {
+			reason: "We should return any error encountered creating an impersonating client.",
+			policy: withXRD(&v1.CompositeResourceImpersonation{ServiceAccountName: "composer"}, nil),
+			newClient: func(_ string) (client.Client, error) {
+				return nil, errBoom
+			},
+			xr: &fake.Composite{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant"}},
+			want: want{
+				err: errBoom,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			i := NewPolicyImpersonator(tc.newClient)
+			c, username, err := i.Impersonate(context.Background(), tc.xr, tc.policy)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nImpersonate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.username, username); diff != "" {
+				t.Errorf("\n%s\nImpersonate(...): -want username, +got username:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.impersonating, c != nil); diff != "" {
+				t.Errorf("\n%s\nImpersonate(...): -want impersonating client, +got impersonating client:\n%s", tc.reason, diff)
+			}
+
+			// We should reuse clients for the same identity.
+			if c == nil {
+				return
+			}
+			again, _, _ := i.Impersonate(context.Background(), tc.xr, tc.policy)
+			if again != c {
+				t.Errorf("\n%s\nImpersonate(...): want the same client for the same identity", tc.reason)
+			}
+		})
+	}
+}
+
+func TestImpersonateEviction(t *testing.T) {
+	p := &CompositionPolicy{Definition: &v1.CompositeResourceDefinition{}}
+	p.Definition.Spec.Impersonation = &v1.CompositeResourceImpersonation{ServiceAccountName: "cool-sa"}
+
+	created := 0
+	newClient := func(_ string) (client.Client, error) {
+		created++
+		return &test.MockClient{}, nil
+	}
+
+	i := NewPolicyImpersonator(newClient, WithMaxImpersonatedClients(1))
+
+	xr := func(ns string) resource.Composite {
+		xr := &fake.Composite{}
+		xr.SetNamespace(ns)
+		return xr
+	}
+
+	for _, ns := range []string{"a", "a", "b", "a"} {
+		if _, _, err := i.Impersonate(context.Background(), xr(ns), p); err != nil {
+			t.Fatalf("Impersonate(...): unexpected error: %v", err)
+		}
+	}
+
+	// We should reuse the cached client for namespace a once, then create a
+	// new one after it's evicted to make room for namespace b.
+	if diff := cmp.Diff(3, created); diff != "" {
+		t.Errorf("Impersonate(...): -want clients created, +got clients created:\n%s", diff)
+	}
+}
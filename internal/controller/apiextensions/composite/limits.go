@@ -0,0 +1,286 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// AnnotationKeyComposedResourceLimit may be set on a Namespace to limit the
+// number of composed resources a single composite resource in that namespace
+// may have.
+const AnnotationKeyComposedResourceLimit = "apiextensions.crossplane.io/composed-resource-limit"
+
+// AnnotationKeyAllowedComposedResourceKinds may be set on a Namespace to limit
+// the kinds of resource composite resources in that namespace may compose. Its
+// value is a comma separated list of kinds in Kind.group form, e.g.
+// "Bucket.s3.aws.upbound.io,ConfigMap". Use "*.group" to allow any kind in a
+// group. Composite resources that use a NamespacedComposition may only compose
+// resources in a namespace with this annotation, unless their composed
+// resources are applied as an impersonated service account.
+const AnnotationKeyAllowedComposedResourceKinds = "apiextensions.crossplane.io/allowed-composed-resource-kinds"
+
+// Error strings.
+const (
+	errGetNamespace = "cannot get composite resource's namespace"
+
+	errFmtParseNamespaceLimit   = "cannot parse annotation %q of namespace %q"
+	errFmtExceedsLimit          = "composite resource would have %d composed resources, but CompositeResourceDefinition %q allows at most %d"
+	errFmtExceedsKindLimit      = "composite resource would have %d composed resources of kind %q, but CompositeResourceDefinition %q allows at most %d"
+	errFmtExceedsNamespaceLimit = "composite resource would have %d composed resources, but namespace %q allows at most %d"
+
+	errFmtKindNotAllowed          = "composed resource %q is a %s, which CompositeResourceDefinition %q doesn't allow"
+	errFmtKindNotAllowedNamespace = "composed resource %q is a %s, which namespace %q doesn't allow"
+)
+
+// A KindNotAllowedError indicates that a desired composed resource is of a
+// kind the composite resource may not compose.
+type KindNotAllowedError struct {
+	msg string
+}
+
+// Error implements the error interface.
+func (e *KindNotAllowedError) Error() string {
+	return e.msg
+}
+
+// IsKindNotAllowed returns true if the supplied error indicates that a desired
+// composed resource is of a kind the composite resource may not compose.
+func IsKindNotAllowed(err error) bool {
+	var e *KindNotAllowedError
+	return errors.As(err, &e)
+}
+
+// A ComposedResourceLimiter checks whether the composed resources desired by
+// a Composition pipeline are within the composite resource's limits.
+type ComposedResourceLimiter interface {
+	// CheckComposedResourceLimits returns an error if the desired composed
+	// resources exceed the composite resource's limits.
+	CheckComposedResourceLimits(ctx context.Context, xr resource.Composite, p *CompositionPolicy, desired map[string]*fnv1.Resource) error
+}
+
+// A ComposedResourceLimiterFn checks whether the composed resources desired by
+// a Composition pipeline are within the composite resource's limits.
+type ComposedResourceLimiterFn func(ctx context.Context, xr resource.Composite, p *CompositionPolicy, desired map[string]*fnv1.Resource) error
+
+// CheckComposedResourceLimits returns an error if the desired composed
+// resources exceed the composite resource's limits.
+func (fn ComposedResourceLimiterFn) CheckComposedResourceLimits(ctx context.Context, xr resource.Composite, p *CompositionPolicy, desired map[string]*fnv1.Resource) error {
+	return fn(ctx, xr, p, desired)
+}
+
+// A NopComposedResourceLimiter doesn't limit composed resources.
+type NopComposedResourceLimiter struct{}
+
+// CheckComposedResourceLimits always returns nil.
+func (n NopComposedResourceLimiter) CheckComposedResourceLimits(_ context.Context, _ resource.Composite, _ *CompositionPolicy, _ map[string]*fnv1.Resource) error {
+	return nil
+}
+
+// A PolicyComposedResourceLimiter limits composed resources according to the
+// composite resource's CompositeResourceDefinition, and for namespaced
+// composite resources according to their namespace.
+type PolicyComposedResourceLimiter struct{}
+
+// CheckComposedResourceLimits returns an error if the desired composed
+// resources exceed the limits set by the composite resource's
+// CompositeResourceDefinition or namespace.
+func (l PolicyComposedResourceLimiter) CheckComposedResourceLimits(_ context.Context, _ resource.Composite, p *CompositionPolicy, desired map[string]*fnv1.Resource) error {
+	def := p.Definition
+	if lim := def.Spec.ComposedResourceLimits; lim != nil {
+		if lim.MaxResources != nil && int64(len(desired)) > *lim.MaxResources {
+			return errors.Errorf(errFmtExceedsLimit, len(desired), def.GetName(), *lim.MaxResources)
+		}
+
+		count := DesiredGroupKinds(desired)
+		for _, k := range lim.Kinds {
+			gk := schema.GroupKind{Group: k.Group, Kind: k.Kind}
+			if n := count[gk]; n > k.MaxResources {
+				return errors.Errorf(errFmtExceedsKindLimit, n, gk.String(), def.GetName(), k.MaxResources)
+			}
+		}
+	}
+
+	ns := p.Namespace
+	if ns == nil {
+		return nil
+	}
+
+	v, ok := ns.GetAnnotations()[AnnotationKeyComposedResourceLimit]
+	if !ok {
+		return nil
+	}
+	limit, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, errFmtParseNamespaceLimit, AnnotationKeyComposedResourceLimit, ns.GetName())
+	}
+	if int64(len(desired)) > limit {
+		return errors.Errorf(errFmtExceedsNamespaceLimit, len(desired), ns.GetName(), limit)
+	}
+
+	return nil
+}
+
+// DesiredGroupKinds counts the supplied desired composed resources by their
+// group and kind.
+func DesiredGroupKinds(desired map[string]*fnv1.Resource) map[schema.GroupKind]int64 {
+	count := map[schema.GroupKind]int64{}
+	for _, r := range desired {
+		count[desiredGroupVersionKind(r).GroupKind()]++
+	}
+	return count
+}
+
+// A ComposedResourceKindValidator validates that the composed resources desired
+// by a Composition pipeline are of kinds the composite resource may compose.
+type ComposedResourceKindValidator interface {
+	// ValidateComposedResourceKinds returns a KindNotAllowedError if any of
+	// the desired composed resources are of a kind the composite resource may
+	// not compose. It returns any other error if it can't tell.
+	ValidateComposedResourceKinds(ctx context.Context, xr resource.Composite, p *CompositionPolicy, desired map[string]*fnv1.Resource) error
+}
+
+// A ComposedResourceKindValidatorFn validates that the composed resources
+// desired by a Composition pipeline are of kinds the composite resource may
+// compose.
+type ComposedResourceKindValidatorFn func(ctx context.Context, xr resource.Composite, p *CompositionPolicy, desired map[string]*fnv1.Resource) error
+
+// ValidateComposedResourceKinds returns an error if any of the desired
+// composed resources are of a kind the composite resource may not compose.
+func (fn ComposedResourceKindValidatorFn) ValidateComposedResourceKinds(ctx context.Context, xr resource.Composite, p *CompositionPolicy, desired map[string]*fnv1.Resource) error {
+	return fn(ctx, xr, p, desired)
+}
+
+// A NopComposedResourceKindValidator allows any kind of composed resource.
+type NopComposedResourceKindValidator struct{}
+
+// ValidateComposedResourceKinds always returns nil.
+func (n NopComposedResourceKindValidator) ValidateComposedResourceKinds(_ context.Context, _ resource.Composite, _ *CompositionPolicy, _ map[string]*fnv1.Resource) error {
+	return nil
+}
+
+// A PolicyComposedResourceKindValidator allows the composed resource kinds
+// listed by the composite resource's CompositeResourceDefinition, and for
+// namespaced composite resources by their namespace.
+type PolicyComposedResourceKindValidator struct{}
+
+// ValidateComposedResourceKinds returns an error if any of the desired
+// composed resources are of a kind not allowed by the composite resource's
+// CompositeResourceDefinition or namespace. Both must allow a kind for it to
+// be composed.
+func (v PolicyComposedResourceKindValidator) ValidateComposedResourceKinds(_ context.Context, xr resource.Composite, p *CompositionPolicy, desired map[string]*fnv1.Resource) error {
+	def := p.Definition
+	allowed, _ := NamespaceAllowedKinds(p.Namespace)
+
+	// Sort names so we return a stable error.
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		gvk := desiredGroupVersionKind(desired[name])
+
+		if len(def.Spec.AllowedComposedResourceKinds) > 0 && !ComposedResourceKindAllowed(def.Spec.AllowedComposedResourceKinds, gvk) {
+			return &KindNotAllowedError{msg: fmt.Sprintf(errFmtKindNotAllowed, name, gvk.String(), def.GetName())}
+		}
+
+		if allowed != nil && !GroupKindAllowed(allowed, gvk.GroupKind()) {
+			return &KindNotAllowedError{msg: fmt.Sprintf(errFmtKindNotAllowedNamespace, name, gvk.String(), xr.GetNamespace())}
+		}
+	}
+
+	return nil
+}
+
+// NamespaceAllowedKinds returns the kinds of resource composite resources in
+// the supplied namespace may compose, and whether the namespace limits them.
+func NamespaceAllowedKinds(ns *corev1.Namespace) ([]schema.GroupKind, bool) {
+	if ns == nil {
+		return nil, false
+	}
+	a, ok := ns.GetAnnotations()[AnnotationKeyAllowedComposedResourceKinds]
+	if !ok {
+		return nil, false
+	}
+	return ParseGroupKinds(a), true
+}
+
+// ComposedResourceKindAllowed returns true if the supplied GVK matches any of
+// the supplied composed resource kinds.
+func ComposedResourceKindAllowed(allowed []v1.ComposedResourceKind, gvk schema.GroupVersionKind) bool {
+	for _, k := range allowed {
+		if k.Group != gvk.Group {
+			continue
+		}
+		if k.Version != "" && k.Version != gvk.Version {
+			continue
+		}
+		if k.Kind == "*" || k.Kind == gvk.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupKindAllowed returns true if the supplied GroupKind matches any of the
+// supplied allowed GroupKinds. An allowed GroupKind of kind '*' matches any
+// kind in its group.
+func GroupKindAllowed(allowed []schema.GroupKind, gk schema.GroupKind) bool {
+	for _, a := range allowed {
+		if a.Group != gk.Group {
+			continue
+		}
+		if a.Kind == "*" || a.Kind == gk.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseGroupKinds parses a comma separated list of kinds in Kind.group form.
+func ParseGroupKinds(s string) []schema.GroupKind {
+	gks := []schema.GroupKind{}
+	for _, k := range strings.Split(s, ",") {
+		k = strings.TrimSpace(k)
+		if k == "" {
+			continue
+		}
+		gks = append(gks, schema.ParseGroupKind(k))
+	}
+	return gks
+}
+
+func desiredGroupVersionKind(r *fnv1.Resource) schema.GroupVersionKind {
+	f := r.GetResource().GetFields()
+	gv, _ := schema.ParseGroupVersion(f["apiVersion"].GetStringValue())
+	return gv.WithKind(f["kind"].GetStringValue())
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestCheckComposedResourceLimits(t *testing.T) {
+	desired := map[string]*fnv1.Resource{
+		"a": {Resource: MustStruct(map[string]any{"apiVersion": "example.org/v1", "kind": "Bucket"})},
+		"b": {Resource: MustStruct(map[string]any{"apiVersion": "example.org/v1", "kind": "Bucket"})},
+		"c": {Resource: MustStruct(map[string]any{"apiVersion": "v1", "kind": "ConfigMap"})},
+	}
+
+	withXRD := func(l *v1.ComposedResourceLimits, ns *corev1.Namespace) *CompositionPolicy {
+		d := &v1.CompositeResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "buckets.example.org"}}
+		d.Spec.ComposedResourceLimits = l
+		return &CompositionPolicy{Definition: d, Namespace: ns}
+	}
+
+	type args struct {
+		xr      resource.Composite
+		desired map[string]*fnv1.Resource
+	}
+
+	cases := map[string]struct {
+		reason string
+		policy *CompositionPolicy
+		args   args
+		want   error
+	}{
+		"NoLimits": {
+			reason: "A cluster scoped XR with no limits shouldn't be limited.",
+			policy: withXRD(nil, nil),
+			args: args{
+				xr:      &fake.Composite{},
+				desired: desired,
+			},
+			want: nil,
+		},
+		"ExceedsMaxResources": {
+			reason: "We should return an error if the XR would exceed the XRD's maximum number of composed resources.",
+			policy: withXRD(&v1.ComposedResourceLimits{MaxResources: ptr.To[int64](2)}, nil),
+			args: args{
+				xr:      &fake.Composite{},
+				desired: desired,
+			},
+			want: errors.Errorf(errFmtExceedsLimit, 3, "buckets.example.org", 2),
+		},
+		"ExceedsKindLimit": {
+			reason: "We should return an error if the XR would exceed the XRD's maximum number of composed resources of a kind.",
+			policy: withXRD(&v1.ComposedResourceLimits{
+				MaxResources: ptr.To[int64](10),
+				Kinds: []v1.ComposedResourceKindLimit{
+					{Kind: "ConfigMap", MaxResources: 1},
+					{Group: "example.org", Kind: "Bucket", MaxResources: 1},
+				},
+			}, nil),
+			args: args{
+				xr:      &fake.Composite{},
+				desired: desired,
+			},
+			want: errors.Errorf(errFmtExceedsKindLimit, 2, "Bucket.example.org", "buckets.example.org", 1),
+		},
+		"InvalidNamespaceLimit": {
+			reason: "We should return an error if the namespace's limit annotation isn't an integer.",
+			policy: withXRD(nil, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "tenant",
+				Annotations: map[string]string{AnnotationKeyComposedResourceLimit: "lots"},
+			}}),
+			args: args{
+				xr:      &fake.Composite{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant"}},
+				desired: desired,
+			},
+			want: errors.Wrapf(func() error { _, err := strconv.ParseInt("lots", 10, 64); return err }(), errFmtParseNamespaceLimit, AnnotationKeyComposedResourceLimit, "tenant"),
+		},
+		"ExceedsNamespaceLimit": {
+			reason: "We should return an error if the XR would exceed its namespace's maximum number of composed resources.",
+			policy: withXRD(nil, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "tenant",
+				Annotations: map[string]string{AnnotationKeyComposedResourceLimit: "1"},
+			}}),
+			args: args{
+				xr:      &fake.Composite{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant"}},
+				desired: desired,
+			},
+			want: errors.Errorf(errFmtExceedsNamespaceLimit, 3, "tenant", 1),
+		},
+		"WithinLimits": {
+			reason: "We shouldn't return an error if the XR is within all of its limits.",
+			policy: withXRD(&v1.ComposedResourceLimits{
+				MaxResources: ptr.To[int64](3),
+				Kinds: []v1.ComposedResourceKindLimit{
+					{Group: "example.org", Kind: "Bucket", MaxResources: 2},
+				},
+			}, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "tenant",
+				Annotations: map[string]string{AnnotationKeyComposedResourceLimit: "3"},
+			}}),
+			args: args{
+				xr:      &fake.Composite{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant"}},
+				desired: desired,
+			},
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := PolicyComposedResourceLimiter{}.CheckComposedResourceLimits(context.Background(), tc.args.xr, tc.policy, tc.args.desired)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCheckComposedResourceLimits(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateComposedResourceKinds(t *testing.T) {
+	desired := map[string]*fnv1.Resource{
+		"a": {Resource: MustStruct(map[string]any{"apiVersion": "example.org/v1", "kind": "Bucket"})},
+		"b": {Resource: MustStruct(map[string]any{"apiVersion": "rbac.authorization.k8s.io/v1", "kind": "ClusterRole"})},
+	}
+
+	withXRD := func(k []v1.ComposedResourceKind, ns *corev1.Namespace) *CompositionPolicy {
+		d := &v1.CompositeResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "buckets.example.org"}}
+		d.Spec.AllowedComposedResourceKinds = k
+		return &CompositionPolicy{Definition: d, Namespace: ns}
+	}
+
+	type args struct {
+		xr      resource.Composite
+		desired map[string]*fnv1.Resource
+	}
+
+	cases := map[string]struct {
+		reason string
+		policy *CompositionPolicy
+		args   args
+		want   error
+	}{
+		"NoAllowList": {
+			reason: "A cluster scoped XR whose XRD has no allow-list may compose any kind.",
+			policy: withXRD(nil, nil),
+			args: args{
+				xr:      &fake.Composite{},
+				desired: desired,
+			},
+			want: nil,
+		},
+		"NotAllowedByXRD": {
+			reason: "We should return an error if the XRD doesn't allow a desired kind.",
+			policy: withXRD([]v1.ComposedResourceKind{{Group: "example.org", Kind: "*"}}, nil),
+			args: args{
+				xr:      &fake.Composite{},
+				desired: desired,
+			},
+			want: &KindNotAllowedError{msg: fmt.Sprintf(errFmtKindNotAllowed, "b", "rbac.authorization.k8s.io/v1, Kind=ClusterRole", "buckets.example.org")},
+		},
+		"VersionNotAllowedByXRD": {
+			reason: "We should return an error if the XRD doesn't allow a desired kind's version.",
+			policy: withXRD([]v1.ComposedResourceKind{
+				{Group: "example.org", Version: "v2", Kind: "Bucket"},
+				{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+			}, nil),
+			args: args{
+				xr:      &fake.Composite{},
+				desired: desired,
+			},
+			want: &KindNotAllowedError{msg: fmt.Sprintf(errFmtKindNotAllowed, "a", "example.org/v1, Kind=Bucket", "buckets.example.org")},
+		},
+		"NotAllowedByNamespace": {
+			reason: "We should return an error if the XR's namespace doesn't allow a desired kind.",
+			policy: withXRD(nil, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "tenant",
+				Annotations: map[string]string{AnnotationKeyAllowedComposedResourceKinds: "Bucket.example.org, ConfigMap"},
+			}}),
+			args: args{
+				xr:      &fake.Composite{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant"}},
+				desired: desired,
+			},
+			want: &KindNotAllowedError{msg: fmt.Sprintf(errFmtKindNotAllowedNamespace, "b", "rbac.authorization.k8s.io/v1, Kind=ClusterRole", "tenant")},
+		},
+		"Allowed": {
+			reason: "We shouldn't return an error if the XRD and namespace allow all desired kinds.",
+			policy: withXRD([]v1.ComposedResourceKind{
+				{Group: "example.org", Version: "v1", Kind: "Bucket"},
+				{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+			}, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "tenant",
+				Annotations: map[string]string{AnnotationKeyAllowedComposedResourceKinds: "*.example.org,ClusterRole.rbac.authorization.k8s.io"},
+			}}),
+			args: args{
+				xr:      &fake.Composite{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant"}},
+				desired: desired,
+			},
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := PolicyComposedResourceKindValidator{}.ValidateComposedResourceKinds(context.Background(), tc.args.xr, tc.policy, tc.args.desired)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateComposedResourceKinds(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// A CompositionPolicy configures what a composite resource may compose, and
+// the identity its composed resources are applied as.
+type CompositionPolicy struct {
+	// Definition is the composite resource's CompositeResourceDefinition.
+	Definition *v1.CompositeResourceDefinition
+
+	// Namespace is the composite resource's namespace. It's nil for cluster
+	// scoped composite resources.
+	Namespace *corev1.Namespace
+}
+
+// A CompositionPolicyFetcher fetches the policy that applies to a composite
+// resource.
+type CompositionPolicyFetcher interface {
+	// FetchCompositionPolicy fetches the policy that applies to the supplied
+	// composite resource.
+	FetchCompositionPolicy(ctx context.Context, xr resource.Composite) (*CompositionPolicy, error)
+}
+
+// A CompositionPolicyFetcherFn fetches the policy that applies to a composite
+// resource.
+type CompositionPolicyFetcherFn func(ctx context.Context, xr resource.Composite) (*CompositionPolicy, error)
+
+// FetchCompositionPolicy fetches the policy that applies to the supplied
+// composite resource.
+func (fn CompositionPolicyFetcherFn) FetchCompositionPolicy(ctx context.Context, xr resource.Composite) (*CompositionPolicy, error) {
+	return fn(ctx, xr)
+}
+
+// A NopCompositionPolicyFetcher returns an empty policy.
+type NopCompositionPolicyFetcher struct{}
+
+// FetchCompositionPolicy returns an empty policy.
+func (n NopCompositionPolicyFetcher) FetchCompositionPolicy(_ context.Context, _ resource.Composite) (*CompositionPolicy, error) {
+	return &CompositionPolicy{Definition: &v1.CompositeResourceDefinition{}}, nil
+}
+
+// An APICompositionPolicyFetcher fetches a composite resource's
+// CompositeResourceDefinition and namespace from the API server.
+type APICompositionPolicyFetcher struct {
+	client client.Reader
+	defRef corev1.ObjectReference
+}
+
+// NewAPICompositionPolicyFetcher returns a CompositionPolicyFetcher that
+// fetches the referenced CompositeResourceDefinition, and the composite
+// resource's namespace.
+func NewAPICompositionPolicyFetcher(c client.Reader, ref corev1.ObjectReference) *APICompositionPolicyFetcher {
+	return &APICompositionPolicyFetcher{client: c, defRef: ref}
+}
+
+// FetchCompositionPolicy fetches the policy that applies to the supplied
+// composite resource.
+func (f *APICompositionPolicyFetcher) FetchCompositionPolicy(ctx context.Context, xr resource.Composite) (*CompositionPolicy, error) {
+	p := &CompositionPolicy{Definition: &v1.CompositeResourceDefinition{}}
+	if err := f.client.Get(ctx, meta.NamespacedNameOf(&f.defRef), p.Definition); err != nil {
+		return nil, errors.Wrap(err, errGetXRD)
+	}
+
+	if xr.GetNamespace() == "" {
+		return p, nil
+	}
+
+	p.Namespace = &corev1.Namespace{}
+	if err := f.client.Get(ctx, client.ObjectKey{Name: xr.GetNamespace()}, p.Namespace); err != nil {
+		return nil, errors.Wrap(err, errGetNamespace)
+	}
+	return p, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestFetchCompositionPolicy(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xrd := &v1.CompositeResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "coolxrs.example.org"}}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant"}}
+
+	type want struct {
+		p   *CompositionPolicy
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		xr     resource.Composite
+		want   want
+	}{
+		"GetXRDError": {
+			reason: "We should return any error encountered getting the XRD.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			xr:     &fake.Composite{},
+			want: want{
+				err: errors.Wrap(errBoom, errGetXRD),
+			},
+		},
+		"ClusterScoped": {
+			reason: "We should only fetch the XRD of a cluster scoped XR.",
+			client: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+				o, ok := obj.(*v1.CompositeResourceDefinition)
+				if !ok {
+					return errBoom
+				}
+				*o = *xrd
+				return nil
+			}},
+			xr: &fake.Composite{},
+			want: want{
+				p: &CompositionPolicy{Definition: xrd},
+			},
+		},
+		"GetNamespaceError": {
+			reason: "We should return any error encountered getting a namespaced XR's namespace.",
+			client: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+				if _, ok := obj.(*corev1.Namespace); ok {
+					return errBoom
+				}
+				return nil
+			}},
+			xr: &fake.Composite{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant"}},
+			want: want{
+				err: errors.Wrap(errBoom, errGetNamespace),
+			},
+		},
+		"Namespaced": {
+			reason: "We should fetch the XRD and namespace of a namespaced XR.",
+			client: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+				switch o := obj.(type) {
+				case *v1.CompositeResourceDefinition:
+					*o = *xrd
+				case *corev1.Namespace:
+					*o = *ns
+				}
+				return nil
+			}},
+			xr: &fake.Composite{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant"}},
+			want: want{
+				p: &CompositionPolicy{Definition: xrd, Namespace: ns},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := NewAPICompositionPolicyFetcher(tc.client, corev1.ObjectReference{Name: "coolxrs.example.org"})
+			p, err := f.FetchCompositionPolicy(context.Background(), tc.xr)
+
+			if diff := cmp.Diff(tc.want.p, p); diff != "" {
+				t.Errorf("\n%s\nFetchCompositionPolicy(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFetchCompositionPolicy(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
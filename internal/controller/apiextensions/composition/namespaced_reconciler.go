@@ -0,0 +1,213 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composition
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v2alpha1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
+)
+
+// Error strings.
+const (
+	errGetNamespaced      = "cannot get NamespacedComposition"
+	errListNamespacedRevs = "cannot list NamespacedCompositionRevisions"
+)
+
+// SetupNamespaced adds a controller that reconciles NamespacedCompositions by
+// creating new NamespacedCompositionRevisions for each revision of the
+// NamespacedComposition's spec.
+func SetupNamespaced(mgr ctrl.Manager, o controller.Options) error {
+	name := "revisions/" + strings.ToLower(v2alpha1.NamespacedCompositionGroupKind)
+
+	r := NewNamespacedReconciler(mgr,
+		WithNamespacedLogger(o.Logger.WithValues("controller", name)),
+		WithNamespacedRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v2alpha1.NamespacedComposition{}).
+		Owns(&v2alpha1.NamespacedCompositionRevision{}).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(r), o.GlobalRateLimiter))
+}
+
+// NamespacedReconcilerOption is used to configure the NamespacedReconciler.
+type NamespacedReconcilerOption func(*NamespacedReconciler)
+
+// WithNamespacedLogger specifies how the NamespacedReconciler should log
+// messages.
+func WithNamespacedLogger(log logging.Logger) NamespacedReconcilerOption {
+	return func(r *NamespacedReconciler) {
+		r.log = log
+	}
+}
+
+// WithNamespacedRecorder specifies how the NamespacedReconciler should record
+// Kubernetes events.
+func WithNamespacedRecorder(er event.Recorder) NamespacedReconcilerOption {
+	return func(r *NamespacedReconciler) {
+		r.record = er
+	}
+}
+
+// NewNamespacedReconciler returns a Reconciler of NamespacedCompositions.
+func NewNamespacedReconciler(mgr manager.Manager, opts ...NamespacedReconcilerOption) *NamespacedReconciler {
+	r := &NamespacedReconciler{
+		client: mgr.GetClient(),
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+	}
+
+	for _, f := range opts {
+		f(r)
+	}
+	return r
+}
+
+// A NamespacedReconciler reconciles NamespacedCompositions by creating new
+// NamespacedCompositionRevisions for each revision of the
+// NamespacedComposition's spec.
+type NamespacedReconciler struct {
+	client client.Client
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// Reconcile a NamespacedComposition.
+func (r *NamespacedReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	comp := &v2alpha1.NamespacedComposition{}
+	if err := r.client.Get(ctx, req.NamespacedName, comp); err != nil {
+		log.Debug(errGetNamespaced, "error", err)
+		r.record.Event(comp, event.Warning(reasonCreateRev, errors.Wrap(err, errGetNamespaced)))
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetNamespaced)
+	}
+
+	if meta.WasDeleted(comp) {
+		return reconcile.Result{}, nil
+	}
+
+	currentHash := comp.Hash()
+
+	log = log.WithValues(
+		"uid", comp.GetUID(),
+		"version", comp.GetResourceVersion(),
+		"name", comp.GetName(),
+		"namespace", comp.GetNamespace(),
+		"spec-hash", currentHash,
+	)
+
+	rl := &v2alpha1.NamespacedCompositionRevisionList{}
+	if err := r.client.List(ctx, rl, client.InNamespace(comp.GetNamespace()), client.MatchingLabels{v1.LabelCompositionName: comp.GetName()}); err != nil {
+		log.Debug(errListNamespacedRevs, "error", err)
+		r.record.Event(comp, event.Warning(reasonCreateRev, errors.Wrap(err, errListNamespacedRevs)))
+		return reconcile.Result{}, errors.Wrap(err, errListNamespacedRevs)
+	}
+
+	var latestRev, existingRev int64
+
+	if lr := v2alpha1.LatestRevision(comp, rl.Items); lr != nil {
+		latestRev = lr.Spec.Revision
+	}
+
+	for i := range rl.Items {
+		rev := &rl.Items[i]
+
+		if !metav1.IsControlledBy(rev, comp) {
+			// Owner references are stripped out when a resource is moved
+			// from one cluster to another (i.e. backup/restore). Make sure
+			// all revisions of this NamespacedComposition are controlled by
+			// it.
+			if err := meta.AddControllerReference(rev, meta.AsController(meta.TypedReferenceTo(comp, v2alpha1.NamespacedCompositionGroupVersionKind))); err != nil {
+				log.Debug(errOwnRev, "error", err)
+				r.record.Event(comp, event.Warning(reasonUpdateRev, err))
+				return reconcile.Result{}, errors.Wrap(err, errOwnRev)
+			}
+			if err := r.client.Update(ctx, rev); err != nil {
+				log.Debug(errOwnRev, "error", err)
+				r.record.Event(comp, event.Warning(reasonUpdateRev, err))
+				return reconcile.Result{}, errors.Wrap(err, errOwnRev)
+			}
+		}
+
+		// This revision does not match our current NamespacedComposition.
+		if rev.GetLabels()[v1.LabelCompositionHash] != currentHash[:63] {
+			continue
+		}
+
+		// This revision matches our current NamespacedComposition. We don't
+		// need a new one.
+		existingRev = rev.Spec.Revision
+
+		// This revision has the highest revision number - it doesn't need updating.
+		if rev.Spec.Revision == latestRev {
+			continue
+		}
+
+		// This revision does not have the highest revision number. Update it so that it does.
+		rev.Spec.Revision = latestRev + 1
+		if err := r.client.Update(ctx, rev); err != nil {
+			log.Debug(errUpdateRevSpec, "error", err)
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			r.record.Event(comp, event.Warning(reasonUpdateRev, err))
+			return reconcile.Result{}, errors.Wrap(err, errUpdateRevSpec)
+		}
+	}
+
+	// We start from revision 1, so 0 indicates we didn't find one.
+	if existingRev > 0 {
+		log.Debug("No new revision needed.", "current-revision", existingRev)
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.client.Create(ctx, NewNamespacedCompositionRevision(comp, latestRev+1)); err != nil {
+		log.Debug(errCreateRev, "error", err)
+		r.record.Event(comp, event.Warning(reasonCreateRev, err))
+		return reconcile.Result{}, errors.Wrap(err, errCreateRev)
+	}
+
+	log.Debug("Created new revision", "revision", latestRev+1)
+	r.record.Event(comp, event.Normal(reasonCreateRev, "Created new revision", "revision", strconv.FormatInt(latestRev+1, 10)))
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composition
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v2alpha1"
+)
+
+func TestNamespacedReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+	ctrl := true
+
+	comp := &v2alpha1.NamespacedComposition{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "tenant",
+			Name:      "cool-composition",
+			UID:       types.UID("no-you-uid"),
+		},
+	}
+
+	// Owned by the above composition, but with an 'older' hash.
+	rev1 := &v2alpha1.NamespacedCompositionRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "tenant",
+			Name:      comp.GetName() + "-1",
+			OwnerReferences: []metav1.OwnerReference{{
+				UID:                comp.GetUID(),
+				Controller:         &ctrl,
+				BlockOwnerDeletion: &ctrl,
+			}},
+			Labels: map[string]string{
+				v1.LabelCompositionHash: "some-older-hash",
+				v1.LabelCompositionName: comp.Name,
+			},
+		},
+		Spec: v2alpha1.NamespacedCompositionRevisionSpec{Revision: 1},
+	}
+
+	// Owned by the above composition, with a current hash.
+	rev2 := &v2alpha1.NamespacedCompositionRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "tenant",
+			Name:      comp.GetName() + "-2",
+			OwnerReferences: []metav1.OwnerReference{{
+				UID:                comp.GetUID(),
+				Controller:         &ctrl,
+				BlockOwnerDeletion: &ctrl,
+			}},
+			Labels: map[string]string{
+				v1.LabelCompositionHash: comp.Hash()[:63],
+				v1.LabelCompositionName: comp.Name,
+			},
+		},
+		Spec: v2alpha1.NamespacedCompositionRevisionSpec{Revision: 2},
+	}
+
+	type args struct {
+		mgr  manager.Manager
+		opts []NamespacedReconcilerOption
+	}
+	type want struct {
+		r   reconcile.Result
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NamespacedCompositionNotFound": {
+			reason: "We should not return an error if the NamespacedComposition was not found.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					},
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"GetNamespacedCompositionError": {
+			reason: "We should return any other error encountered while getting a NamespacedComposition.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(errBoom),
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetNamespaced),
+			},
+		},
+		"ListNamespacedCompositionRevisionsError": {
+			reason: "We should return any error encountered while listing NamespacedCompositionRevisions.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:  test.NewMockGetFn(nil),
+						MockList: test.NewMockListFn(errBoom),
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListNamespacedRevs),
+			},
+		},
+		"SuccessfulNoOp": {
+			reason: "We should not create a new NamespacedCompositionRevision if one exists that matches the NamespacedComposition's hash.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							*obj.(*v2alpha1.NamespacedComposition) = *comp
+							return nil
+						}),
+						MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+							*obj.(*v2alpha1.NamespacedCompositionRevisionList) = v2alpha1.NamespacedCompositionRevisionList{
+								Items: []v2alpha1.NamespacedCompositionRevision{*rev1, *rev2},
+							}
+							return nil
+						}),
+					},
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"SuccessfulCreation": {
+			reason: "We should create a new NamespacedCompositionRevision in the NamespacedComposition's namespace.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							*obj.(*v2alpha1.NamespacedComposition) = *comp
+							return nil
+						}),
+						MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+							lo := &client.ListOptions{}
+							lo.ApplyOptions(opts)
+							if lo.Namespace != comp.GetNamespace() {
+								t.Errorf("List(...): want namespace %q, got %q", comp.GetNamespace(), lo.Namespace)
+							}
+							*obj.(*v2alpha1.NamespacedCompositionRevisionList) = v2alpha1.NamespacedCompositionRevisionList{
+								Items: []v2alpha1.NamespacedCompositionRevision{*rev1},
+							}
+							return nil
+						},
+						MockCreate: test.NewMockCreateFn(nil, func(got client.Object) error {
+							want := NewNamespacedCompositionRevision(comp, rev1.Spec.Revision+1)
+
+							if diff := cmp.Diff(want, got); diff != "" {
+								t.Errorf("Create(): -want, +got:\n%s", diff)
+							}
+
+							return nil
+						}),
+					},
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewNamespacedReconciler(tc.args.mgr, append(tc.args.opts, WithNamespacedLogger(testLog))...)
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v2alpha1"
 )
 
 // NewCompositionRevision creates a new revision of the supplied Composition.
@@ -69,3 +70,48 @@ func NewCompositionRevisionSpec(cs v1.CompositionSpec, revision int64) v1.Compos
 	rs.Revision = revision
 	return rs
 }
+
+// NewNamespacedCompositionRevision creates a new revision of the supplied
+// NamespacedComposition, in the same namespace.
+func NewNamespacedCompositionRevision(c *v2alpha1.NamespacedComposition, revision int64) *v2alpha1.NamespacedCompositionRevision {
+	hash := c.Hash()
+	if len(hash) >= 63 {
+		hash = hash[0:63]
+	}
+
+	nameSuffix := hash
+	if len(nameSuffix) >= 7 {
+		nameSuffix = nameSuffix[0:7]
+	}
+
+	cr := &v2alpha1.NamespacedCompositionRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: c.GetNamespace(),
+			Name:      fmt.Sprintf("%s-%s", c.GetName(), nameSuffix),
+			Labels: map[string]string{
+				v1.LabelCompositionName: c.GetName(),
+				// We cannot have a label value longer than 63 chars
+				// https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#syntax-and-character-set
+				v1.LabelCompositionHash: hash,
+			},
+		},
+		Spec: v2alpha1.NamespacedCompositionRevisionSpec{
+			CompositeTypeRef: c.Spec.CompositeTypeRef,
+			Mode:             c.Spec.Mode,
+			Revision:         revision,
+		},
+	}
+
+	for i := range c.Spec.Pipeline {
+		cr.Spec.Pipeline = append(cr.Spec.Pipeline, *c.Spec.Pipeline[i].DeepCopy())
+	}
+
+	ref := meta.TypedReferenceTo(c, v2alpha1.NamespacedCompositionGroupVersionKind)
+	meta.AddOwnerReference(cr, meta.AsController(ref))
+
+	for k, v := range c.GetLabels() {
+		cr.ObjectMeta.Labels[k] = v
+	}
+
+	return cr
+}
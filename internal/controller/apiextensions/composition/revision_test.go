@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v2alpha1"
 )
 
 func TestNewCompositionRevision(t *testing.T) {
@@ -69,3 +70,56 @@ func TestNewCompositionRevision(t *testing.T) {
 		t.Errorf("NewCompositionRevision(): -want, +got:\n%s", diff)
 	}
 }
+
+func TestNewNamespacedCompositionRevision(t *testing.T) {
+	comp := &v2alpha1.NamespacedComposition{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "tenant",
+			Name:      "coolcomp",
+			Labels: map[string]string{
+				"channel": "dev",
+			},
+		},
+		Spec: v2alpha1.NamespacedCompositionSpec{
+			CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XR"},
+			Mode:             v1.CompositionModePipeline,
+			Pipeline: []v1.PipelineStep{
+				{Step: "run", FunctionRef: v1.FunctionReference{Name: "cool-fn"}},
+			},
+		},
+	}
+
+	var rev int64 = 2
+	hash := comp.Hash()
+
+	ctrl := true
+	want := &v2alpha1.NamespacedCompositionRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "tenant",
+			Name:      fmt.Sprintf("%s-%s", comp.GetName(), hash[0:7]),
+			Labels: map[string]string{
+				v1.LabelCompositionName: comp.GetName(),
+				v1.LabelCompositionHash: hash[0:63],
+				"channel":               "dev",
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         v2alpha1.SchemeGroupVersion.String(),
+				Kind:               v2alpha1.NamespacedCompositionKind,
+				Name:               comp.GetName(),
+				Controller:         &ctrl,
+				BlockOwnerDeletion: &ctrl,
+			}},
+		},
+		Spec: v2alpha1.NamespacedCompositionRevisionSpec{
+			CompositeTypeRef: comp.Spec.CompositeTypeRef,
+			Mode:             comp.Spec.Mode,
+			Pipeline:         comp.Spec.Pipeline,
+			Revision:         rev,
+		},
+	}
+
+	got := NewNamespacedCompositionRevision(comp, rev)
+	if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("NewNamespacedCompositionRevision(): -want, +got:\n%s", diff)
+	}
+}
@@ -18,6 +18,8 @@ limitations under the License.
 package controller
 
 import (
+	"crypto/tls"
+
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 
 	"github.com/crossplane/crossplane/internal/engine"
@@ -33,4 +35,8 @@ type Options struct {
 
 	// FunctionRunner used to run Composition Functions.
 	FunctionRunner xfn.FunctionRunner
+
+	// ClientTLSConfig used to connect to external secret store plugins that
+	// composite resource connection details are published to.
+	ClientTLSConfig *tls.Config
 }
@@ -33,6 +33,7 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v2alpha1"
 )
 
 // EnqueueForCompositionRevision enqueues reconciles for all XRs that will use a
@@ -85,7 +86,61 @@ func EnqueueForCompositionRevision(of schema.GroupVersionKind, s composite.Schem
 
 				// We only care about XRs that reference the
 				// composition this revision derives from.
-				if ref := xr.GetCompositionReference(); ref == nil || ref.Name != compName {
+				if ref := xr.GetCompositionReference(); ref == nil || ref.Kind == v2alpha1.NamespacedCompositionKind || ref.Name != compName {
+					continue
+				}
+
+				q.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+					Name:      xr.GetName(),
+					Namespace: xr.GetNamespace(),
+				}})
+			}
+		},
+	}
+}
+
+// EnqueueForNamespacedCompositionRevision enqueues reconciles for all XRs in
+// the revision's namespace that will use a newly created
+// NamespacedCompositionRevision.
+func EnqueueForNamespacedCompositionRevision(of schema.GroupVersionKind, s composite.Schema, c client.Reader, log logging.Logger) handler.Funcs {
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e kevent.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			rev, ok := e.Object.(*v2alpha1.NamespacedCompositionRevision)
+			if !ok {
+				return
+			}
+
+			compName := rev.Labels[v1.LabelCompositionName]
+			if compName == "" {
+				return
+			}
+
+			if rev.Spec.CompositeTypeRef.APIVersion != of.GroupVersion().String() {
+				return
+			}
+			if rev.Spec.CompositeTypeRef.Kind != of.Kind {
+				return
+			}
+
+			xrs := kunstructured.UnstructuredList{}
+			xrs.SetGroupVersionKind(of)
+			xrs.SetKind(of.Kind + "List")
+			if err := c.List(ctx, &xrs, client.InNamespace(rev.GetNamespace())); err != nil {
+				// Logging is most we can do here. This is a programming error if it happens.
+				log.Info("cannot list in NamespacedCompositionRevision handler", "type", of.String(), "error", err)
+				return
+			}
+
+			for _, u := range xrs.Items {
+				xr := composite.Unstructured{Unstructured: u, Schema: s}
+
+				if pol := xr.GetCompositionUpdatePolicy(); pol != nil && *pol == xpv1.UpdateManual {
+					continue
+				}
+
+				// Only XRs that reference the NamespacedComposition
+				// this revision derives from will use it.
+				if ref := xr.GetCompositionReference(); ref == nil || ref.Kind != v2alpha1.NamespacedCompositionKind || ref.Name != compName {
 					continue
 				}
 
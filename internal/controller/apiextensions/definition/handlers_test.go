@@ -21,6 +21,7 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v2alpha1"
 )
 
 func TestEnqueueForCompositionRevisionFunc(t *testing.T) {
@@ -239,6 +240,111 @@ func TestEnqueueForCompositionRevisionFunc(t *testing.T) {
 	}
 }
 
+func TestEnqueueForNamespacedCompositionRevisionFunc(t *testing.T) {
+	type args struct {
+		of     schema.GroupVersionKind
+		schema composite.Schema
+		reader client.Reader
+		event  kevent.CreateEvent
+	}
+	type want struct {
+		added []any
+	}
+
+	dog := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Dog"}
+
+	rev := &v2alpha1.NamespacedCompositionRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "dachshund-sadfa8",
+			Labels: map[string]string{
+				v1.LabelCompositionName: "dachshund",
+			},
+		},
+		Spec: v2alpha1.NamespacedCompositionRevisionSpec{
+			CompositeTypeRef: v1.TypeReferenceTo(dog),
+		},
+	}
+
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ClusterCompositionRevision": {
+			reason: "A CompositionRevision shouldn't enqueue any reconciles.",
+			args: args{
+				of: dog,
+				event: kevent.CreateEvent{
+					Object: &v1.CompositionRevision{},
+				},
+			},
+			want: want{},
+		},
+		"Multiple": {
+			reason: "Reconciles should be enqueued only for the XRs in the revision's namespace that reference the relevant NamespacedComposition, and have an automatic composition revision update policy.",
+			args: args{
+				of:     dog,
+				schema: composite.SchemaModern,
+				reader: &test.MockClient{
+					MockList: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+						lo := &client.ListOptions{}
+						lo.ApplyOptions(opts)
+						if lo.Namespace != "ns" {
+							t.Errorf("list namespace: want %q, got %q", "ns", lo.Namespace)
+						}
+
+						obj1 := composite.New(composite.WithSchema(composite.SchemaModern))
+						obj1.SetNamespace("ns")
+						obj1.SetName("obj1")
+						automatic := xpv1.UpdateAutomatic
+						obj1.SetCompositionUpdatePolicy(&automatic)
+						obj1.SetCompositionReference(&corev1.ObjectReference{Kind: v2alpha1.NamespacedCompositionKind, Name: "dachshund"})
+
+						// References a cluster scoped Composition
+						// with the same name.
+						obj2 := obj1.DeepCopy()
+						obj2.SetName("obj2")
+						obj2.SetCompositionReference(&corev1.ObjectReference{Name: "dachshund"})
+
+						obj3 := obj1.DeepCopy()
+						obj3.SetName("obj3")
+						manual := xpv1.UpdateManual
+						obj3.SetCompositionUpdatePolicy(&manual)
+
+						list.(*kunstructured.UnstructuredList).Items = []kunstructured.Unstructured{
+							obj1.Unstructured,
+							obj2.Unstructured,
+							obj3.Unstructured,
+						}
+
+						return nil
+					},
+				},
+				event: kevent.CreateEvent{
+					Object: rev,
+				},
+			},
+			want: want{
+				added: []any{
+					reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "obj1"}},
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fns := EnqueueForNamespacedCompositionRevision(tc.args.of, tc.args.schema, tc.args.reader, logging.NewNopLogger())
+			q := rateLimitingQueueMock{}
+			fns.Create(context.TODO(), tc.args.event, &q)
+
+			if diff := cmp.Diff(tc.want.added, q.added); diff != "" {
+				t.Errorf("\n%s\nfns.Create(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 type rateLimitingQueueMock struct {
 	workqueue.TypedRateLimitingInterface[reconcile.Request]
 	added []any
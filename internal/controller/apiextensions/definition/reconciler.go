@@ -19,8 +19,11 @@ package definition
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
+	"sync"
 	"time"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -29,6 +32,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -48,6 +52,7 @@ import (
 
 	ucomposite "github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v2alpha1"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite/watch"
 	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
@@ -68,6 +73,7 @@ const (
 	errStartController                = "cannot start composite resource controller"
 	errStopController                 = "cannot stop composite resource controller"
 	errStartWatches                   = "cannot start composite resource controller watches"
+	errConnectionPublishers           = "cannot configure composite resource connection details publishers"
 	errAddIndex                       = "cannot add composite GVK index"
 	errAddFinalizer                   = "cannot add composite resource finalizer"
 	errRemoveFinalizer                = "cannot remove composite resource finalizer"
@@ -167,11 +173,23 @@ func (fn CRDRenderFn) Render(d *v1.CompositeResourceDefinition) (*extv1.CustomRe
 func Setup(mgr ctrl.Manager, o apiextensionscontroller.Options) error {
 	name := "defined/" + strings.ToLower(v1.CompositeResourceDefinitionGroupKind)
 
-	r := NewReconciler(NewClientApplicator(mgr.GetClient()),
+	ro := []ReconcilerOption{
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		WithControllerEngine(o.ControllerEngine),
-		WithOptions(o))
+		WithRESTConfig(mgr.GetConfig()),
+		WithOptions(o),
+	}
+
+	// Namespaced XRs may only reference a NamespacedComposition when the
+	// feature is enabled.
+	if o.Features.Enabled(features.EnableAlphaNamespacedCompositions) {
+		ro = append(ro, WithCRDRenderer(CRDRenderFn(func(d *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+			return xcrd.ForCompositeResource(d, xcrd.WithNamespacedCompositions())
+		})))
+	}
+
+	r := NewReconciler(NewClientApplicator(mgr.GetClient()), ro...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -222,6 +240,15 @@ func WithControllerEngine(c ControllerEngine) ReconcilerOption {
 	}
 }
 
+// WithRESTConfig specifies the REST config composite resource controllers
+// should use to impersonate service accounts when applying composed
+// resources.
+func WithRESTConfig(cfg *rest.Config) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.config = cfg
+	}
+}
+
 // WithCRDRenderer specifies how the Reconciler should render a
 // CompositeResourceDefinition's corresponding CustomResourceDefinition.
 func WithCRDRenderer(c CRDRenderer) ReconcilerOption {
@@ -248,11 +275,14 @@ func NewReconciler(ca resource.ClientApplicator, opts ...ReconcilerOption) *Reco
 		client: ca,
 
 		composite: definition{
-			CRDRenderer: CRDRenderFn(xcrd.ForCompositeResource),
-			Finalizer:   resource.NewAPIFinalizer(ca, finalizer),
+			CRDRenderer: CRDRenderFn(func(d *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+				return xcrd.ForCompositeResource(d)
+			}),
+			Finalizer: resource.NewAPIFinalizer(ca, finalizer),
 		},
 
 		engine: &NopEngine{},
+		conns:  newControllerConns(),
 
 		log:        logging.NewNopLogger(),
 		record:     event.NewNopRecorder(),
@@ -287,6 +317,78 @@ type Reconciler struct {
 	conditions conditions.Manager
 
 	options apiextensionscontroller.Options
+
+	config *rest.Config
+
+	conns *controllerConns
+}
+
+// controllerConns tracks the gRPC client connections used by each running
+// composite resource controller, so we can close them when it stops. It also
+// tracks the connection details publisher configuration each controller was
+// started with, so we can tell when it changes.
+type controllerConns struct {
+	mx      sync.Mutex
+	conns   map[string]io.Closer
+	configs map[string]string
+}
+
+func newControllerConns() *controllerConns {
+	return &controllerConns{conns: make(map[string]io.Closer), configs: make(map[string]string)}
+}
+
+// Set the connections used by the named controller, and the publisher
+// configuration it was started with, closing any connections previously used
+// by a controller of the same name.
+func (c *controllerConns) Set(name, config string, cl io.Closer) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if old, ok := c.conns[name]; ok {
+		_ = old.Close()
+	}
+	c.conns[name] = cl
+	c.configs[name] = config
+}
+
+// Changed returns true if the named controller was started with a publisher
+// configuration other than the supplied one.
+func (c *controllerConns) Changed(name, config string) bool {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	started, ok := c.configs[name]
+	return ok && started != config
+}
+
+// Close the connections used by the named controller.
+func (c *controllerConns) Close(name string) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if cl, ok := c.conns[name]; ok {
+		// Close only returns an error if the connections are already closed.
+		_ = cl.Close()
+		delete(c.conns, name)
+	}
+	delete(c.configs, name)
+}
+
+// publishersConfig returns a representation of the supplied XRD's connection
+// details publisher configuration that changes when the configuration does.
+func publishersConfig(d *v1.CompositeResourceDefinition) string {
+	if len(d.Spec.ConnectionDetailsPublishers) == 0 {
+		return ""
+	}
+	// Marshalling a slice of structs is deterministic.
+	b, _ := json.Marshal(d.Spec.ConnectionDetailsPublishers)
+	return string(b)
+}
+
+// stop the named composite resource controller, and close its connections.
+func (r *Reconciler) stop(ctx context.Context, name string) error {
+	if err := r.engine.Stop(ctx, name); err != nil {
+		return err
+	}
+	r.conns.Close(name)
+	return nil
 }
 
 // Reconcile a CompositeResourceDefinition by defining a new kind of composite
@@ -350,7 +452,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			// It's likely that we've already stopped this controller on a
 			// previous reconcile, but we try again just in case. This is a
 			// no-op if the controller was already stopped.
-			if err := r.engine.Stop(ctx, composite.ControllerName(d.GetName())); err != nil {
+			if err := r.stop(ctx, composite.ControllerName(d.GetName())); err != nil {
 				err = errors.Wrap(err, errStopController)
 				r.record.Event(d, event.Warning(reasonTerminateXR, err))
 				return reconcile.Result{}, err
@@ -406,7 +508,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 		// The controller must be stopped before the deletion of the CRD so that
 		// it doesn't crash.
-		if err := r.engine.Stop(ctx, composite.ControllerName(d.GetName())); err != nil {
+		if err := r.stop(ctx, composite.ControllerName(d.GetName())); err != nil {
 			err = errors.Wrap(err, errStopController)
 			r.record.Event(d, event.Warning(reasonTerminateXR, err))
 			return reconcile.Result{}, err
@@ -461,7 +563,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	observed := d.Status.Controllers.CompositeResourceTypeRef
 	desired := v1.TypeReferenceTo(d.GetCompositeGroupVersionKind())
 	if observed.APIVersion != "" && observed != desired {
-		if err := r.engine.Stop(ctx, composite.ControllerName(d.GetName())); err != nil {
+		if err := r.stop(ctx, composite.ControllerName(d.GetName())); err != nil {
 			err = errors.Wrap(err, errStopController)
 			r.record.Event(d, event.Warning(reasonEstablishXR, err))
 			return reconcile.Result{}, err
@@ -471,6 +573,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			"desired-version", desired.APIVersion)
 	}
 
+	// Connection details publishers are built when the controller starts, so
+	// we restart it when its publisher configuration changes. Stopping it
+	// closes the connections to publishers that are no longer configured.
+	if r.conns.Changed(composite.ControllerName(d.GetName()), publishersConfig(d)) {
+		if err := r.stop(ctx, composite.ControllerName(d.GetName())); err != nil {
+			err = errors.Wrap(err, errStopController)
+			r.record.Event(d, event.Warning(reasonEstablishXR, err))
+			return reconcile.Result{}, err
+		}
+		log.Debug("Connection details publishers changed; stopped composite resource controller")
+	}
+
 	if r.engine.IsRunning(composite.ControllerName(d.GetName())) {
 		log.Debug("Composite resource controller is running")
 		status.MarkConditions(v1.WatchingComposite())
@@ -479,10 +593,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	runner := composite.NewFetchingFunctionRunner(r.options.FunctionRunner, composite.NewExistingExtraResourcesFetcher(r.engine.GetCached()))
 	fetcher := composite.NewSecretConnectionDetailsFetcher(r.engine.GetCached())
-	fc := composite.NewFunctionComposer(r.engine.GetCached(), r.engine.GetUncached(), runner,
+	ref := *meta.ReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind)
+	fco := []composite.FunctionComposerOption{
 		composite.WithComposedResourceObserver(composite.NewExistingComposedResourceObserver(r.engine.GetCached(), r.engine.GetUncached(), fetcher)),
 		composite.WithCompositeConnectionDetailsFetcher(fetcher),
-	)
+		composite.WithCompositionPolicyFetcher(composite.NewAPICompositionPolicyFetcher(r.engine.GetCached(), ref)),
+		composite.WithComposedResourceLimiter(composite.PolicyComposedResourceLimiter{}),
+		composite.WithComposedResourceKindValidator(composite.PolicyComposedResourceKindValidator{}),
+	}
+
+	// We can only impersonate if we know how to connect to the API server.
+	if r.config != nil {
+		nc := composite.NewImpersonatingClientFn(r.config, client.Options{Scheme: r.engine.GetCached().Scheme(), Mapper: r.engine.GetCached().RESTMapper()})
+		fco = append(fco, composite.WithImpersonator(composite.NewPolicyImpersonator(nc)))
+	}
+
+	fc := composite.NewFunctionComposer(r.engine.GetCached(), r.engine.GetUncached(), runner, fco...)
 
 	// All XRs have modern schema unless their XRD's scope is LegacyCluster.
 	schema := ucomposite.SchemaModern
@@ -490,12 +616,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		schema = ucomposite.SchemaLegacy
 	}
 
+	// XRs of a namespaced XRD may select NamespacedCompositions from their
+	// own namespace if the feature is enabled.
+	namespaced := ptr.Deref(d.Spec.Scope, v1.CompositeResourceScopeLegacyCluster) == v1.CompositeResourceScopeNamespaced &&
+		r.options.Features.Enabled(features.EnableAlphaNamespacedCompositions)
+
+	var resolver composite.CompositionSelector = composite.NewAPILabelSelectorResolver(r.engine.GetCached())
+	if namespaced {
+		resolver = composite.NewAPINamespacedLabelSelectorResolver(r.engine.GetCached())
+	}
+
 	ro := []composite.ReconcilerOption{
 		composite.WithCompositeSchema(schema),
 		composite.WithCompositionSelector(composite.NewCompositionSelectorChain(
 			composite.NewEnforcedCompositionSelector(*d, r.record),
-			composite.NewAPIDefaultCompositionSelector(r.engine.GetCached(), *meta.ReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind), r.record),
-			composite.NewAPILabelSelectorResolver(r.engine.GetCached()),
+			composite.NewAPIDefaultCompositionSelector(r.engine.GetCached(), ref, r.record),
+			resolver,
 		)),
 		composite.WithLogger(r.log.WithValues("controller", composite.ControllerName(d.GetName()))),
 		composite.WithRecorder(r.record.WithAnnotations("controller", composite.ControllerName(d.GetName()))),
@@ -504,12 +640,32 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		composite.WithFeatures(r.options.Features),
 	}
 
+	if namespaced {
+		ca := resource.ClientApplicator{Client: r.engine.GetCached(), Applicator: resource.NewAPIPatchingApplicator(r.engine.GetCached())}
+		ro = append(ro,
+			composite.WithCompositionRevisionFetcher(composite.NewAPINamespacedRevisionFetcher(r.engine.GetCached(), composite.NewAPIRevisionFetcher(ca))),
+		)
+	}
+
 	if schema == ucomposite.SchemaLegacy {
 		ro = append(ro,
 			composite.WithConnectionPublishers(composite.NewAPIFilteredSecretPublisher(r.engine.GetCached(), d.GetConnectionSecretKeys())),
 		)
 	}
 
+	var conns composite.PublisherConns
+	if len(d.Spec.ConnectionDetailsPublishers) > 0 {
+		ps, pc, err := composite.NewConnectionPublishers(r.engine.GetCached(), r.options.ClientTLSConfig, d.Spec.ConnectionDetailsPublishers)
+		if err != nil {
+			log.Debug(errConnectionPublishers, "error", err)
+			err = errors.Wrap(err, errConnectionPublishers)
+			r.record.Event(d, event.Warning(reasonEstablishXR, err))
+			return reconcile.Result{}, err
+		}
+		ro = append(ro, composite.WithConnectionPublishers(ps...))
+		conns = pc
+	}
+
 	// If realtime compositions are enabled we pass the ControllerEngine to the
 	// XR reconciler so that it can start watches for composed resources.
 	if r.options.Features.Enabled(features.EnableBetaRealtimeCompositions) {
@@ -558,12 +714,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	if err := r.engine.Start(name, co...); err != nil {
+		_ = conns.Close()
 		log.Debug(errStartController, "error", err)
 		err = errors.Wrap(err, errStartController)
 		r.record.Event(d, event.Warning(reasonEstablishXR, err))
 		return reconcile.Result{}, err
 	}
 
+	r.conns.Set(name, publishersConfig(d), conns)
+
 	// This must be *unstructured.Unstructured, not *composite.Unstructured.
 	// controller-runtime doesn't support watching types that satisfy the
 	// runtime.Unstructured interface - only *unstructured.Unstructured.
@@ -571,10 +730,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	xr.SetGroupVersionKind(gvk)
 
 	crh := EnqueueForCompositionRevision(gvk, schema, r.engine.GetCached(), log)
-	if err := r.engine.StartWatches(ctx, name,
+	ws := []engine.Watch{
 		engine.WatchFor(xr, engine.WatchTypeCompositeResource, &handler.EnqueueRequestForObject{}),
 		engine.WatchFor(&v1.CompositionRevision{}, engine.WatchTypeCompositionRevision, crh),
-	); err != nil {
+	}
+	if namespaced {
+		nrh := EnqueueForNamespacedCompositionRevision(gvk, schema, r.engine.GetCached(), log)
+		ws = append(ws, engine.WatchFor(&v2alpha1.NamespacedCompositionRevision{}, engine.WatchTypeNamespacedCompositionRevision, nrh))
+	}
+	if err := r.engine.StartWatches(ctx, name, ws...); err != nil {
 		log.Debug(errStartWatches, "error", err)
 		err = errors.Wrap(err, errStartWatches)
 		r.record.Event(d, event.Warning(reasonEstablishXR, err))
@@ -913,3 +913,46 @@ func TestReconcile(t *testing.T) {
 		})
 	}
 }
+
+type mockCloser struct {
+	closed int
+}
+
+func (c *mockCloser) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestControllerConns(t *testing.T) {
+	old := &mockCloser{}
+	current := &mockCloser{}
+
+	c := newControllerConns()
+	c.Set("cool", "", old)
+	c.Set("cool", "publishers", current)
+	if diff := cmp.Diff(1, old.closed); diff != "" {
+		t.Errorf("Set(...): we should close the connections of a controller we replaced: -want closed, +got closed:\n%s", diff)
+	}
+	if diff := cmp.Diff(0, current.closed); diff != "" {
+		t.Errorf("Set(...): we shouldn't close the connections of the current controller: -want closed, +got closed:\n%s", diff)
+	}
+
+	if c.Changed("cool", "publishers") {
+		t.Errorf("Changed(...): the publisher configuration of a controller shouldn't change when it's the same")
+	}
+	if !c.Changed("cool", "") {
+		t.Errorf("Changed(...): the publisher configuration of a controller should change when it's different")
+	}
+	if c.Changed("uncool", "publishers") {
+		t.Errorf("Changed(...): the publisher configuration of a controller that isn't running shouldn't change")
+	}
+
+	c.Close("cool")
+	c.Close("cool")
+	if diff := cmp.Diff(1, current.closed); diff != "" {
+		t.Errorf("Close(...): we should close the connections of a stopped controller once: -want closed, +got closed:\n%s", diff)
+	}
+	if c.Changed("cool", "") {
+		t.Errorf("Changed(...): we should forget the publisher configuration of a stopped controller")
+	}
+}
@@ -18,6 +18,8 @@ limitations under the License.
 package controller
 
 import (
+	"crypto/tls"
+
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 
 	"github.com/crossplane/crossplane/internal/xpkg"
@@ -49,4 +51,8 @@ type Options struct {
 	// MaxConcurrentPackageEstablishers is the maximum number of goroutines to use
 	// for establishing Providers, Configurations and Functions.
 	MaxConcurrentPackageEstablishers int
+
+	// ClientTLSConfig is used to health check packages that are served by
+	// an externally hosted endpoint.
+	ClientTLSConfig *tls.Config
 }
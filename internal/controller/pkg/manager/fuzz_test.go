@@ -38,7 +38,7 @@ func FuzzPackageRevision(f *testing.F) {
 			MockHead: fake.NewMockHeadFn(nil, errors.New("boom")),
 		}
 		r := NewPackageRevisioner(fetcher)
-		_, _ = r.Revision(context.Background(), pkg, "")
+		_, _, _ = r.Revision(context.Background(), pkg, "")
 		n, err := ff.GetString()
 		if err != nil {
 			t.Skip()
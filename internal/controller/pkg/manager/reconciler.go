@@ -27,6 +27,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/utils/ptr"
@@ -39,6 +40,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/conditions"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
@@ -47,6 +49,7 @@ import (
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/features"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
@@ -59,8 +62,19 @@ const (
 	pullWait = 1 * time.Minute
 
 	reconcilePausedMsg = "Reconciliation (including deletion) is paused via the pause annotation"
+
+	// defaultRollbackTimeout is how long an activated revision has to become
+	// healthy before it's rolled back, if its rollback policy doesn't say.
+	defaultRollbackTimeout = 10 * time.Minute
 )
 
+func rollbackTimeout(rp *v1.RollbackPolicy) time.Duration {
+	if rp.Timeout == nil {
+		return defaultRollbackTimeout
+	}
+	return rp.Timeout.Duration
+}
+
 func pullBasedRequeue(p *corev1.PullPolicy) reconcile.Result {
 	if p != nil && *p == corev1.PullAlways {
 		return reconcile.Result{RequeueAfter: pullWait}
@@ -79,6 +93,10 @@ const (
 
 	errUpdateStatus                  = "cannot update package status"
 	errUpdateInactivePackageRevision = "cannot update inactive package revision"
+	errRollbackPackageRevision       = "cannot re-activate last healthy package revision"
+
+	errFmtDigestChanged         = "package %s now resolves to digest %s instead of %s"
+	errFmtDigestPendingApproval = "package %s now resolves to digest %s instead of %s; keeping the package pinned to %s until the new digest is approved"
 
 	errCreateK8sClient = "failed to initialize clientset"
 	errBuildFetcher    = "cannot build fetcher"
@@ -93,6 +111,8 @@ const (
 	reasonInstall            event.Reason = "InstallPackageRevision"
 	reasonPaused             event.Reason = "ReconciliationPaused"
 	reasonImageConfig        event.Reason = "ImageConfigSelection"
+	reasonRollback           event.Reason = "RollbackRevision"
+	reasonDigestChanged      event.Reason = "DigestChanged"
 )
 
 // ReconcilerOption is used to configure the Reconciler.
@@ -134,6 +154,13 @@ func WithConfigStore(c xpkg.ConfigStore) ReconcilerOption {
 	}
 }
 
+// WithFeatures specifies which feature flags should be enabled.
+func WithFeatures(f *feature.Flags) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.features = f
+	}
+}
+
 // WithLogger specifies how the Reconciler should log messages.
 func WithLogger(log logging.Logger) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -156,10 +183,13 @@ type Reconciler struct {
 	log        logging.Logger
 	record     event.Recorder
 	conditions conditions.Manager
+	features   *feature.Flags
 
 	newPackage             func() v1.Package
 	newPackageRevision     func() v1.PackageRevision
 	newPackageRevisionList func() v1.PackageRevisionList
+
+	now func() time.Time
 }
 
 // SetupProvider adds a controller that reconciles Providers.
@@ -185,6 +215,7 @@ func SetupProvider(mgr ctrl.Manager, o controller.Options) error {
 		WithNewPackageRevisionListFn(nrl),
 		WithRevisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
+		WithFeatures(o.Features),
 		WithLogger(log),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	}
@@ -221,6 +252,7 @@ func SetupConfiguration(mgr ctrl.Manager, o controller.Options) error {
 		WithNewPackageRevisionListFn(nrl),
 		WithRevisioner(NewPackageRevisioner(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
+		WithFeatures(o.Features),
 		WithLogger(log),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	)
@@ -257,6 +289,7 @@ func SetupFunction(mgr ctrl.Manager, o controller.Options) error {
 		WithNewPackageRevisionListFn(nrl),
 		WithRevisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
+		WithFeatures(o.Features),
 		WithLogger(log),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	}
@@ -281,6 +314,7 @@ func NewReconciler(mgr ctrl.Manager, opts ...ReconcilerOption) *Reconciler {
 		log:        logging.NewNopLogger(),
 		record:     event.NewNopRecorder(),
 		conditions: conditions.ObservedGenerationPropagationManager{},
+		now:        time.Now,
 	}
 
 	for _, f := range opts {
@@ -378,7 +412,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		p.ClearAppliedImageConfigRef(v1.ImageConfigReasonSetPullSecret)
 	}
 
-	revisionName, err := r.pkg.Revision(ctx, p, secrets...)
+	revisionName, digest, err := r.pkg.Revision(ctx, p, secrets...)
 	if err != nil {
 		err = errors.Wrap(err, errUnpack)
 		status.MarkConditions(v1.Unpacking().WithMessage(err.Error()))
@@ -397,7 +431,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
 	}
 
+	// The package's tag may resolve to a different digest than it did when we
+	// produced the current revision, for example because it was pushed again.
+	// A package that's pinned to its digest keeps its current revision until
+	// the new digest is approved.
+	revisionName = r.checkDigest(p, status, revisionName, digest)
+
 	// Set the current revision and identifier.
+	activated := p.GetCurrentRevision() != revisionName
 	p.SetCurrentRevision(revisionName)
 	// Use the original source as the identifier, even if it was rewritten by
 	// ImageConfig. The revisioning and dependency resolution logic are all
@@ -405,6 +446,29 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// the original until it's time to actually pull an image.
 	p.SetCurrentIdentifier(p.GetSource())
 
+	// If we rolled back the current revision we keep the last healthy
+	// revision active instead, until the package source changes.
+	automatic := p.GetActivationPolicy() == nil || *p.GetActivationPolicy() == v1.AutomaticActivation
+	rp := p.GetRollbackPolicy()
+	if !r.features.Enabled(features.EnableAlphaPackageRollbacks) {
+		rp = nil
+	}
+	if p.GetRolledBackRevision() != revisionName {
+		p.SetRolledBackRevision("")
+	}
+	rollback := rp != nil && automatic && p.GetRolledBackRevision() != ""
+	var previous v1.PackageRevision
+
+	// A revision's rollback timeout counts from when we activated it, not
+	// from when it was created. We may re-activate an old revision, for
+	// example when the package source changes back to a previous version.
+	switch {
+	case rp == nil || !automatic:
+		p.SetRevisionActivationTime(nil)
+	case activated || p.GetRevisionActivationTime() == nil:
+		p.SetRevisionActivationTime(&metav1.Time{Time: r.now()})
+	}
+
 	pr := r.newPackageRevision()
 	maxRevision := int64(0)
 	oldestRevision := int64(math.MaxInt64)
@@ -421,8 +485,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 
 		// Set oldest revision to the lowest numbered revision and
-		// record its index.
-		if revisionNum < oldestRevision {
+		// record its index. We never garbage collect the last healthy
+		// revision, because we may need to roll back to it.
+		if revisionNum < oldestRevision && (rp == nil || rev.GetName() != p.GetLastHealthyRevision()) {
 			oldestRevision = revisionNum
 			oldestRevisionIndex = index
 		}
@@ -434,6 +499,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			// all non-current revisions are inactive.
 			continue
 		}
+		if rev.GetName() == p.GetLastHealthyRevision() {
+			previous = rev
+			if rollback {
+				continue
+			}
+		}
 		if rev.GetDesiredState() == v1.PackageRevisionActive {
 			// If revision is not the current revision, set to
 			// inactive. This should always be done, regardless of
@@ -458,7 +529,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// Check to see if there are revisions eligible for garbage collection.
 	if p.GetRevisionHistoryLimit() != nil &&
 		*p.GetRevisionHistoryLimit() != 0 &&
-		len(revisions) > (int(*p.GetRevisionHistoryLimit())+1) {
+		len(revisions) > (int(*p.GetRevisionHistoryLimit())+1) &&
+		oldestRevisionIndex >= 0 {
 		gcRev := revisions[oldestRevisionIndex]
 		// Find the oldest revision and delete it.
 		if err := r.client.Delete(ctx, gcRev); err != nil {
@@ -468,7 +540,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 	}
 
+	// We can't roll back to a revision that no longer exists.
+	if previous == nil {
+		rollback = false
+	}
+
 	health := v1.PackageHealth(pr)
+	if rp != nil && health.Status == corev1.ConditionTrue {
+		p.SetLastHealthyRevision(pr.GetName())
+	}
 	if health.Status == corev1.ConditionTrue && p.GetCondition(v1.TypeHealthy).Status != corev1.ConditionTrue {
 		// NOTE(phisco): We don't want to spam the user with events if the
 		// package is already healthy.
@@ -491,6 +571,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// manager's lock, which must use the original source to ensure dependency
 	// packages have the expected names even when rewritten.
 	pr.SetSource(p.GetSource())
+	// A pinned package's revision pulls its images by the digest the package
+	// is pinned to rather than by tag.
+	pr.SetDigest("")
+	if dp := p.GetDigestPolicy(); dp != nil && ptr.Deref(dp.Mode, v1.DigestPolicyTrack) == v1.DigestPolicyPin {
+		pr.SetDigest(p.GetResolvedDigest())
+	}
 	pr.SetPackagePullPolicy(p.GetPackagePullPolicy())
 	pr.SetPackagePullSecrets(p.GetPackagePullSecrets())
 	pr.SetIgnoreCrossplaneConstraints(p.GetIgnoreCrossplaneConstraints())
@@ -505,9 +591,26 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		prwr.SetTLSClientSecretName(pwr.GetTLSClientSecretName())
 	}
 
+	// Roll back the current revision if it didn't become healthy in time. We
+	// keep it inactive and re-activate the last healthy revision from the
+	// next reconcile on.
+	var deadline time.Duration
+	if rp != nil && automatic && !rollback && previous != nil && pr.GetUID() != "" && health.Status != corev1.ConditionTrue {
+		deadline = p.GetRevisionActivationTime().Add(rollbackTimeout(rp)).Sub(r.now())
+		if deadline <= 0 {
+			p.SetRolledBackRevision(pr.GetName())
+			log.Debug("Rolling back package revision that did not become healthy", "revision", pr.GetName(), "previous", previous.GetName())
+			r.record.Event(p, event.Warning(reasonRollback, errors.Errorf("Package revision %s did not become healthy within %s; re-activating package revision %s", pr.GetName(), rollbackTimeout(rp), previous.GetName())))
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+		}
+	}
+
 	// If the current revision is not active, and we have an automatic or
-	// undefined activation policy, always activate.
-	if pr.GetDesiredState() != v1.PackageRevisionActive && (p.GetActivationPolicy() == nil || *p.GetActivationPolicy() == v1.AutomaticActivation) {
+	// undefined activation policy, always activate. Unless we rolled it back.
+	switch {
+	case rollback:
+		pr.SetDesiredState(v1.PackageRevisionInactive)
+	case pr.GetDesiredState() != v1.PackageRevisionActive && automatic:
 		pr.SetDesiredState(v1.PackageRevisionActive)
 	}
 
@@ -537,6 +640,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 	}
 
+	if rollback {
+		previous.SetDesiredState(v1.PackageRevisionActive)
+		if err := r.client.Apply(ctx, previous, resource.MustBeControllableBy(p.GetUID())); err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errRollbackPackageRevision)
+			r.record.Event(p, event.Warning(reasonRollback, err))
+			return reconcile.Result{}, err
+		}
+		status.MarkConditions(v1.RolledBack(pr.GetName(), previous.GetName()), v1.PackageHealth(previous))
+		return pullBasedRequeue(p.GetPackagePullPolicy()), errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
+
 	status.MarkConditions(v1.Active())
 
 	// If current revision is still not active, the package is inactive.
@@ -548,7 +665,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// package, the health of the package is not set until the revision reports
 	// its health. If updating from an existing revision, the package health
 	// will match the health of the old revision until the next reconcile.
-	return pullBasedRequeue(p.GetPackagePullPolicy()), errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	res := pullBasedRequeue(p.GetPackagePullPolicy())
+	if deadline > 0 && (res.RequeueAfter == 0 || deadline < res.RequeueAfter) {
+		// Check again once the current revision's time to become healthy
+		// is up.
+		res.RequeueAfter = deadline
+	}
+	return res, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
 }
 
 func enqueueProvidersForImageConfig(kube client.Client, log logging.Logger) handler.EventHandler {
@@ -643,3 +766,60 @@ func enqueueFunctionsForImageConfig(kube client.Client, log logging.Logger) hand
 		return matches
 	})
 }
+
+// checkDigest detects the supplied package's source resolving to a new digest
+// without the source changing. It returns the revision name the package should
+// use.
+func (r *Reconciler) checkDigest(p v1.Package, status conditions.ConditionSet, revisionName, digest string) string {
+	previous := p.GetResolvedDigest()
+	p.SetResolvedDigest(digest)
+
+	if p.GetCurrentIdentifier() != p.GetSource() {
+		// The package source changed, so there's no previous digest to
+		// compare to.
+		p.SetPendingDigest("")
+		if p.GetCondition(v1.TypeDigestUnchanged).Status == corev1.ConditionFalse {
+			status.MarkConditions(v1.DigestUnchanged())
+		}
+		return revisionName
+	}
+
+	if previous == "" || digest == "" {
+		return revisionName
+	}
+
+	if digest == previous {
+		// A package that tracks its tag uses the new digest's revision once
+		// it's current, so the change no longer needs attention.
+		c := p.GetCondition(v1.TypeDigestUnchanged)
+		if c.Reason == v1.ReasonDigestChanged && p.GetCurrentRevision() == revisionName {
+			status.MarkConditions(v1.DigestUnchanged())
+		}
+		return revisionName
+	}
+
+	dp := p.GetDigestPolicy()
+	if dp == nil || ptr.Deref(dp.Mode, v1.DigestPolicyTrack) != v1.DigestPolicyPin {
+		p.SetPendingDigest("")
+		status.MarkConditions(v1.DigestChanged(previous, digest))
+		r.record.Event(p, event.Warning(reasonDigestChanged, errors.Errorf(errFmtDigestChanged, p.GetSource(), digest, previous)))
+		return revisionName
+	}
+
+	if ptr.Deref(dp.ApprovedDigest, "") == digest {
+		p.SetPendingDigest("")
+		status.MarkConditions(v1.DigestApproved(digest))
+		r.record.Event(p, event.Normal(reasonDigestChanged, fmt.Sprintf("Approved digest %s of package %s", digest, p.GetSource())))
+		return revisionName
+	}
+
+	// Only emit an event the first time we see a new digest, not every time
+	// we poll the registry.
+	if p.GetPendingDigest() != digest {
+		r.record.Event(p, event.Warning(reasonDigestChanged, errors.Errorf(errFmtDigestPendingApproval, p.GetSource(), digest, previous, previous)))
+	}
+	p.SetPendingDigest(digest)
+	p.SetResolvedDigest(previous)
+	status.MarkConditions(v1.AwaitingDigestApproval(previous, digest))
+	return p.GetCurrentRevision()
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
@@ -27,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -35,28 +37,36 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/conditions"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/features"
 	"github.com/crossplane/crossplane/internal/xpkg/fake"
 )
 
 var _ Revisioner = &MockRevisioner{}
 
 type MockRevisioner struct {
-	MockRevision func() (string, error)
+	MockRevision func() (string, string, error)
 }
 
-func NewMockRevisionFn(hash string, err error) func() (string, error) {
-	return func() (string, error) {
-		return hash, err
+func NewMockRevisionFn(hash string, err error) func() (string, string, error) {
+	return func() (string, string, error) {
+		return hash, "", err
 	}
 }
 
-func (m *MockRevisioner) Revision(context.Context, v1.Package, ...string) (string, error) {
+func NewMockRevisionDigestFn(hash, digest string, err error) func() (string, string, error) {
+	return func() (string, string, error) {
+		return hash, digest, err
+	}
+}
+
+func (m *MockRevisioner) Revision(context.Context, v1.Package, ...string) (string, string, error) {
 	return m.MockRevision()
 }
 
@@ -66,6 +76,10 @@ func TestReconcile(t *testing.T) {
 	pullAlways := corev1.PullAlways
 	trueVal := true
 	revHistory := int64(1)
+	now := time.Now()
+	activated := metav1.NewTime(now.Add(-time.Hour))
+	rollbacks := &feature.Flags{}
+	rollbacks.Enable(features.EnableAlphaPackageRollbacks)
 
 	type args struct {
 		req reconcile.Request
@@ -933,6 +947,603 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errGCPackageRevision),
 			},
 		},
+		"RollbackUnhealthyRevision": {
+			reason: "We should roll back a revision that didn't become healthy within the rollback timeout if a healthy revision exists.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRollbackPolicy(&v1.RollbackPolicy{})
+								p.SetLastHealthyRevision("test-1234567")
+								p.SetCurrentRevision("test-7654321")
+								p.SetRevisionActivationTime(&activated)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{
+									configurationRevision("test-1234567", 1, v1.PackageRevisionInactive, now.Add(-2*time.Hour), v1.RevisionHealthy()),
+									configurationRevision("test-7654321", 2, v1.PackageRevisionActive, now.Add(-time.Hour), v1.RevisionUnhealthy()),
+								}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff("test-7654321", p.GetRolledBackRevision()); diff != "" {
+									t.Errorf("-want rolled back revision, +got rolled back revision:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							return errors.Errorf("unexpected apply of %s", o.GetName())
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-7654321", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					features:   rollbacks,
+					now:        func() time.Time { return now },
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"AwaitRevisionHealthy": {
+			reason: "We should check again when an unhealthy revision's rollback timeout expires.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRollbackPolicy(&v1.RollbackPolicy{Timeout: &metav1.Duration{Duration: 100 * time.Hour}})
+								p.SetLastHealthyRevision("test-1234567")
+								p.SetCurrentRevision("test-7654321")
+								p.SetRevisionActivationTime(&activated)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{
+									configurationRevision("test-1234567", 1, v1.PackageRevisionInactive, now.Add(-2*time.Hour), v1.RevisionHealthy()),
+									configurationRevision("test-7654321", 2, v1.PackageRevisionActive, now.Add(-time.Hour), v1.RevisionUnhealthy()),
+								}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-7654321", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					features:   rollbacks,
+					now:        func() time.Time { return now },
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 99 * time.Hour},
+			},
+		},
+		"ReactivatedRevision": {
+			reason: "We should count an old revision's rollback timeout from when we re-activate it, not from when it was created.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRollbackPolicy(&v1.RollbackPolicy{Timeout: &metav1.Duration{Duration: 100 * time.Hour}})
+								p.SetLastHealthyRevision("test-1234567")
+								p.SetCurrentRevision("test-1234567")
+								p.SetRevisionActivationTime(&activated)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{
+									configurationRevision("test-1234567", 1, v1.PackageRevisionActive, now.Add(-time.Hour), v1.RevisionHealthy()),
+									configurationRevision("test-7654321", 2, v1.PackageRevisionInactive, now.Add(-200*time.Hour), v1.RevisionUnhealthy()),
+								}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff("", p.GetRolledBackRevision()); diff != "" {
+									t.Errorf("-want rolled back revision, +got rolled back revision:\n%s", diff)
+								}
+								if diff := cmp.Diff(&metav1.Time{Time: now}, p.GetRevisionActivationTime()); diff != "" {
+									t.Errorf("-want activation time, +got activation time:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-7654321", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					features:   rollbacks,
+					now:        func() time.Time { return now },
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 100 * time.Hour},
+			},
+		},
+		"KeepLastHealthyRevision": {
+			reason: "We should never garbage collect the last healthy revision, because we may need to roll back to it.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(&revHistory)
+								p.SetRollbackPolicy(&v1.RollbackPolicy{Timeout: &metav1.Duration{Duration: 100 * time.Hour}})
+								p.SetLastHealthyRevision("test-1234567")
+								p.SetCurrentRevision("test-7654321")
+								p.SetRevisionActivationTime(&activated)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{
+									configurationRevision("test-1234567", 1, v1.PackageRevisionInactive, now.Add(-3*time.Hour), v1.RevisionHealthy()),
+									configurationRevision("missed-the-cut", 2, v1.PackageRevisionInactive, now.Add(-2*time.Hour), v1.RevisionUnhealthy()),
+									configurationRevision("test-7654321", 3, v1.PackageRevisionActive, now.Add(-time.Hour), v1.RevisionUnhealthy()),
+								}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+							MockDelete: func(_ context.Context, o client.Object, _ ...client.DeleteOption) error {
+								if diff := cmp.Diff("missed-the-cut", o.GetName()); diff != "" {
+									t.Errorf("-want deleted revision, +got deleted revision:\n%s", diff)
+								}
+								return nil
+							},
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-7654321", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					features:   rollbacks,
+					now:        func() time.Time { return now },
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 99 * time.Hour},
+			},
+		},
+		"RolledBackRevisionStaysInactive": {
+			reason: "We should keep a rolled back revision inactive and the last healthy revision active until the package changes.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRollbackPolicy(&v1.RollbackPolicy{})
+								p.SetLastHealthyRevision("test-1234567")
+								p.SetRolledBackRevision("test-7654321")
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{
+									configurationRevision("test-1234567", 1, v1.PackageRevisionInactive, now.Add(-2*time.Hour), v1.RevisionHealthy()),
+									configurationRevision("test-7654321", 2, v1.PackageRevisionActive, now.Add(-time.Hour), v1.RevisionUnhealthy()),
+								}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff(v1.RolledBack("test-7654321", "test-1234567"), p.GetCondition(v1.TypeInstalled), test.EquateConditions()); diff != "" {
+									t.Errorf("-want installed condition, +got installed condition:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.Healthy(), p.GetCondition(v1.TypeHealthy), test.EquateConditions()); diff != "" {
+									t.Errorf("-want healthy condition, +got healthy condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-1234567": v1.PackageRevisionActive,
+								"test-7654321": v1.PackageRevisionInactive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(v1.PackageRevision).GetDesiredState()); diff != "" {
+								t.Errorf("%s: -want desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-7654321", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+					features:   rollbacks,
+					now:        func() time.Time { return now },
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"DigestChangedTracked": {
+			reason: "We should create a revision for a tag's new digest and report the change when the package tracks its tag.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetSource("xpkg.crossplane.io/crossplane/test:v1.0.0")
+								p.SetCurrentIdentifier("xpkg.crossplane.io/crossplane/test:v1.0.0")
+								p.SetCurrentRevision("test-1234567")
+								p.SetResolvedDigest("sha256:1234567")
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{
+									configurationRevision("test-1234567", 1, v1.PackageRevisionActive, now.Add(-time.Hour), v1.RevisionHealthy()),
+								}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff("test-7654321", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("-want current revision, +got current revision:\n%s", diff)
+								}
+								if diff := cmp.Diff("sha256:7654321", p.GetResolvedDigest()); diff != "" {
+									t.Errorf("-want resolved digest, +got resolved digest:\n%s", diff)
+								}
+								if diff := cmp.Diff("", p.GetPendingDigest()); diff != "" {
+									t.Errorf("-want pending digest, +got pending digest:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.DigestChanged("sha256:1234567", "sha256:7654321"), p.GetCondition(v1.TypeDigestUnchanged), test.EquateConditions()); diff != "" {
+									t.Errorf("-want digest condition, +got digest condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-1234567": v1.PackageRevisionInactive,
+								"test-7654321": v1.PackageRevisionActive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(v1.PackageRevision).GetDesiredState()); diff != "" {
+								t.Errorf("%s: -want desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionDigestFn("test-7654321", "sha256:7654321", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"DigestChangeTracked": {
+			reason: "We should stop reporting a tag's digest change once a package that tracks its tag uses the new digest's revision.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetSource("xpkg.crossplane.io/crossplane/test:v1.0.0")
+								p.SetCurrentIdentifier("xpkg.crossplane.io/crossplane/test:v1.0.0")
+								p.SetCurrentRevision("test-7654321")
+								p.SetResolvedDigest("sha256:7654321")
+								p.SetConditions(v1.DigestChanged("sha256:1234567", "sha256:7654321"))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{
+									configurationRevision("test-1234567", 1, v1.PackageRevisionInactive, now.Add(-2*time.Hour), v1.RevisionHealthy()),
+									configurationRevision("test-7654321", 2, v1.PackageRevisionActive, now.Add(-time.Hour), v1.RevisionHealthy()),
+								}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff("test-7654321", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("-want current revision, +got current revision:\n%s", diff)
+								}
+								if diff := cmp.Diff("sha256:7654321", p.GetResolvedDigest()); diff != "" {
+									t.Errorf("-want resolved digest, +got resolved digest:\n%s", diff)
+								}
+								if diff := cmp.Diff("", p.GetPendingDigest()); diff != "" {
+									t.Errorf("-want pending digest, +got pending digest:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.DigestUnchanged(), p.GetCondition(v1.TypeDigestUnchanged), test.EquateConditions()); diff != "" {
+									t.Errorf("-want digest condition, +got digest condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-1234567": v1.PackageRevisionInactive,
+								"test-7654321": v1.PackageRevisionActive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(v1.PackageRevision).GetDesiredState()); diff != "" {
+								t.Errorf("%s: -want desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionDigestFn("test-7654321", "sha256:7654321", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"DigestChangedPinned": {
+			reason: "We should keep the current revision and record the pending digest when a pinned package's tag resolves to a new digest.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetSource("xpkg.crossplane.io/crossplane/test:v1.0.0")
+								p.SetCurrentIdentifier("xpkg.crossplane.io/crossplane/test:v1.0.0")
+								p.SetCurrentRevision("test-1234567")
+								p.SetResolvedDigest("sha256:1234567")
+								p.SetDigestPolicy(&v1.DigestPolicy{Mode: ptr.To(v1.DigestPolicyPin)})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{
+									configurationRevision("test-1234567", 1, v1.PackageRevisionActive, now.Add(-time.Hour), v1.RevisionHealthy()),
+								}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff("test-1234567", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("-want current revision, +got current revision:\n%s", diff)
+								}
+								if diff := cmp.Diff("sha256:1234567", p.GetResolvedDigest()); diff != "" {
+									t.Errorf("-want resolved digest, +got resolved digest:\n%s", diff)
+								}
+								if diff := cmp.Diff("sha256:7654321", p.GetPendingDigest()); diff != "" {
+									t.Errorf("-want pending digest, +got pending digest:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.AwaitingDigestApproval("sha256:1234567", "sha256:7654321"), p.GetCondition(v1.TypeDigestUnchanged), test.EquateConditions()); diff != "" {
+									t.Errorf("-want digest condition, +got digest condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							// We shouldn't create a revision for the unapproved digest.
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-1234567": v1.PackageRevisionActive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(v1.PackageRevision).GetDesiredState()); diff != "" {
+								t.Errorf("%s: -want desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							// The revision should stay pinned to the previous digest.
+							if diff := cmp.Diff("sha256:1234567", o.(v1.PackageRevision).GetDigest()); diff != "" {
+								t.Errorf("%s: -want digest, +got digest:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionDigestFn("test-7654321", "sha256:7654321", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"DigestChangedApproved": {
+			reason: "We should create a revision for a pinned package's new digest once it's approved.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetSource("xpkg.crossplane.io/crossplane/test:v1.0.0")
+								p.SetCurrentIdentifier("xpkg.crossplane.io/crossplane/test:v1.0.0")
+								p.SetCurrentRevision("test-1234567")
+								p.SetResolvedDigest("sha256:1234567")
+								p.SetDigestPolicy(&v1.DigestPolicy{Mode: ptr.To(v1.DigestPolicyPin), ApprovedDigest: ptr.To("sha256:7654321")})
+								p.SetPendingDigest("sha256:7654321")
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{
+									configurationRevision("test-1234567", 1, v1.PackageRevisionActive, now.Add(-time.Hour), v1.RevisionHealthy()),
+								}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								if diff := cmp.Diff("test-7654321", p.GetCurrentRevision()); diff != "" {
+									t.Errorf("-want current revision, +got current revision:\n%s", diff)
+								}
+								if diff := cmp.Diff("sha256:7654321", p.GetResolvedDigest()); diff != "" {
+									t.Errorf("-want resolved digest, +got resolved digest:\n%s", diff)
+								}
+								if diff := cmp.Diff("", p.GetPendingDigest()); diff != "" {
+									t.Errorf("-want pending digest, +got pending digest:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.DigestApproved("sha256:7654321"), p.GetCondition(v1.TypeDigestUnchanged), test.EquateConditions()); diff != "" {
+									t.Errorf("-want digest condition, +got digest condition:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-1234567": v1.PackageRevisionInactive,
+								"test-7654321": v1.PackageRevisionActive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(v1.PackageRevision).GetDesiredState()); diff != "" {
+								t.Errorf("%s: -want desired state, +got desired state:\n%s", o.GetName(), diff)
+							}
+							// The new revision should be pinned to the approved digest.
+							if o.GetName() == "test-7654321" {
+								if diff := cmp.Diff("sha256:7654321", o.(v1.PackageRevision).GetDigest()); diff != "" {
+									t.Errorf("%s: -want digest, +got digest:\n%s", o.GetName(), diff)
+								}
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionDigestFn("test-7654321", "sha256:7654321", nil),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   fake.NewMockRewritePathFn("", "", nil),
+					},
+					log:        testLog,
+					record:     event.NewNopRecorder(),
+					conditions: conditions.ObservedGenerationPropagationManager{},
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"PauseReconcile": {
 			reason: "Pause reconciliation if the pause annotation is set",
 			args: args{
@@ -1051,3 +1662,20 @@ func TestReconcile(t *testing.T) {
 		})
 	}
 }
+
+// configurationRevision returns a ConfigurationRevision with the supplied
+// name, revision number, desired state, creation time, and conditions.
+func configurationRevision(name string, rev int64, state v1.PackageRevisionDesiredState, created time.Time, c ...commonv1.Condition) v1.ConfigurationRevision {
+	cr := v1.ConfigurationRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			UID:               types.UID(name),
+			CreationTimestamp: metav1.NewTime(created),
+		},
+	}
+	cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+	cr.SetConditions(c...)
+	cr.SetDesiredState(state)
+	cr.SetRevision(rev)
+	return cr
+}
@@ -33,9 +33,10 @@ const (
 	errFetchPackage = "failed to fetch package digest from remote"
 )
 
-// Revisioner extracts a revision name for a package source.
+// Revisioner extracts a revision name for a package source. It also returns
+// the digest the package source resolves to, if it's known.
 type Revisioner interface {
-	Revision(ctx context.Context, p v1.Package, extraPullSecrets ...string) (string, error)
+	Revision(ctx context.Context, p v1.Package, extraPullSecrets ...string) (name, digest string, err error)
 }
 
 // PackageRevisioner extracts a revision name for a package source.
@@ -65,22 +66,24 @@ func NewPackageRevisioner(fetcher xpkg.Fetcher, opts ...PackageRevisionerOption)
 	return r
 }
 
-// Revision extracts a revision name for a package source.
-func (r *PackageRevisioner) Revision(ctx context.Context, p v1.Package, extraPullSecrets ...string) (string, error) {
+// Revision extracts a revision name for a package source, and returns the
+// digest the package source resolves to. The digest is empty if the package's
+// pull policy is Never, because the revisioner doesn't consult the registry.
+func (r *PackageRevisioner) Revision(ctx context.Context, p v1.Package, extraPullSecrets ...string) (string, string, error) {
 	pullPolicy := p.GetPackagePullPolicy()
 	if pullPolicy != nil && *pullPolicy == corev1.PullNever {
-		return xpkg.FriendlyID(p.GetName(), p.GetSource()), nil
+		return xpkg.FriendlyID(p.GetName(), p.GetSource()), "", nil
 	}
 	if pullPolicy != nil && *pullPolicy == corev1.PullIfNotPresent {
 		if p.GetCurrentIdentifier() == p.GetSource() {
-			return p.GetCurrentRevision(), nil
+			return p.GetCurrentRevision(), p.GetResolvedDigest(), nil
 		}
 	}
 	// Use the package recorded in the status rather than the one in the spec,
 	// since it may have been rewritten by image config.
 	ref, err := name.ParseReference(p.GetResolvedSource(), name.WithDefaultRegistry(r.registry))
 	if err != nil {
-		return "", errors.Wrap(err, errBadReference)
+		return "", "", errors.Wrap(err, errBadReference)
 	}
 
 	ps := v1.RefNames(p.GetPackagePullSecrets())
@@ -89,9 +92,9 @@ func (r *PackageRevisioner) Revision(ctx context.Context, p v1.Package, extraPul
 	}
 	d, err := r.fetcher.Head(ctx, ref, ps...)
 	if err != nil || d == nil {
-		return "", errors.Wrap(err, errFetchPackage)
+		return "", "", errors.Wrap(err, errFetchPackage)
 	}
-	return xpkg.FriendlyID(p.GetName(), d.Digest.Hex), nil
+	return xpkg.FriendlyID(p.GetName(), d.Digest.Hex), d.Digest.String(), nil
 }
 
 // NopRevisioner returns an empty revision name.
@@ -102,7 +105,7 @@ func NewNopRevisioner() *NopRevisioner {
 	return &NopRevisioner{}
 }
 
-// Revision returns an empty revision name and no error.
-func (d *NopRevisioner) Revision(context.Context, v1.Package, ...string) (string, error) {
-	return "", nil
+// Revision returns an empty revision name and digest, and no error.
+func (d *NopRevisioner) Revision(context.Context, v1.Package, ...string) (string, string, error) {
+	return "", "", nil
 }
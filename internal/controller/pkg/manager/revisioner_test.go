@@ -47,6 +47,7 @@ func TestPackageRevisioner(t *testing.T) {
 
 	type want struct {
 		err    error
+		name   string
 		digest string
 	}
 
@@ -76,7 +77,7 @@ func TestPackageRevisioner(t *testing.T) {
 				},
 			},
 			want: want{
-				digest: "provider-aws-my-revision",
+				name: "provider-aws-my-revision",
 			},
 		},
 		"SuccessfulPullIfNotPresentSameSource": {
@@ -97,12 +98,14 @@ func TestPackageRevisioner(t *testing.T) {
 							ResolvedPackage:   "crossplane/provider-aws:latest",
 							CurrentRevision:   "return-me",
 							CurrentIdentifier: "crossplane/provider-aws:latest",
+							ResolvedDigest:    "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
 						},
 					},
 				},
 			},
 			want: want{
-				digest: "return-me",
+				name:   "return-me",
+				digest: "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
 			},
 		},
 		"SuccessfulPullRewrittenImage": {
@@ -139,7 +142,8 @@ func TestPackageRevisioner(t *testing.T) {
 				},
 			},
 			want: want{
-				digest: "provider-aws-ecc25c121431",
+				name:   "provider-aws-ecc25c121431",
+				digest: "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
 			},
 		},
 		"SuccessfulDigest": {
@@ -171,7 +175,8 @@ func TestPackageRevisioner(t *testing.T) {
 				},
 			},
 			want: want{
-				digest: "provider-nop-ecc25c121431",
+				name:   "provider-nop-ecc25c121431",
+				digest: "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
 			},
 		},
 		"ErrParseRef": {
@@ -222,14 +227,17 @@ func TestPackageRevisioner(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			r := NewPackageRevisioner(tc.args.f)
-			h, err := r.Revision(context.TODO(), tc.args.pkg, tc.args.pullSecretFromConfig)
+			h, d, err := r.Revision(context.TODO(), tc.args.pkg, tc.args.pullSecretFromConfig)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nr.Name(...): -want error, +got error:\n%s", tc.reason, diff)
 			}
-			if diff := cmp.Diff(tc.want.digest, h, test.EquateErrors()); diff != "" {
+			if diff := cmp.Diff(tc.want.name, h, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nr.Name(...): -want, +got:\n%s", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.digest, d); diff != "" {
+				t.Errorf("\n%s\nr.Name(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
 		})
 	}
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/crossplane/crossplane/internal/controller/pkg/revision"
 	"github.com/crossplane/crossplane/internal/controller/pkg/runtime"
 	"github.com/crossplane/crossplane/internal/controller/pkg/signature"
+	"github.com/crossplane/crossplane/internal/controller/pkg/updater"
 	"github.com/crossplane/crossplane/internal/features"
 )
 
@@ -56,6 +57,14 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		}...)
 	}
 
+	if o.Features.Enabled(features.EnableAlphaAutomaticPackageUpdates) {
+		setupFuncs = append(setupFuncs, []func(c ctrl.Manager, options controller.Options) error{
+			updater.SetupProvider,
+			updater.SetupConfiguration,
+			updater.SetupFunction,
+		}...)
+	}
+
 	for _, setup := range setupFuncs {
 		if err := setup(mgr, o); err != nil {
 			return err
@@ -20,13 +20,10 @@ package resolver
 import (
 	"context"
 	"fmt"
-	"sort"
 	"strings"
 	"time"
 
-	"github.com/Masterminds/semver"
 	"github.com/google/go-containerregistry/pkg/name"
-	conregv1 "github.com/google/go-containerregistry/pkg/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/kubernetes"
@@ -64,29 +61,24 @@ const (
 	lockName  = "lock"
 	finalizer = "lock.pkg.crossplane.io"
 
-	errGetLock                = "cannot get package lock"
-	errAddFinalizer           = "cannot add lock finalizer"
-	errRemoveFinalizer        = "cannot remove lock finalizer"
-	errBuildDAG               = "cannot build DAG"
-	errSortDAG                = "cannot sort DAG"
-	errFmtMissingDependency   = "missing package (%s) is not a dependency"
-	errInvalidConstraint      = "version constraint on dependency is invalid"
-	errInvalidDependency      = "dependency package is not valid"
-	errFindDependency         = "cannot find dependency version to install"
-	errGetPullConfig          = "cannot get image pull secret from config"
-	errRewriteImage           = "cannot rewrite image path using config"
-	errInvalidRewrite         = "rewritten image path is invalid"
-	errFetchTags              = "cannot fetch dependency package tags"
-	errFindDependencyUpgrade  = "cannot find dependency version to upgrade"
-	errFmtNoValidVersion      = "dependency (%s) does not have a valid version to upgrade that satisfies all constraints. If there is a valid version that requires downgrade, manual intervention is required. Constraints: %v"
-	errGetDependency          = "cannot get dependency package"
-	errConstructDependency    = "cannot construct dependency package"
-	errCreateDependency       = "cannot create dependency package"
-	errUpdateDependency       = "cannot update dependency package"
-	errFmtSplit               = "package should have 2 segments after split but has %d"
-	errFmtDiffConstraintTypes = "a dependency package has different types of parent constraints (%v)"
-	errFmtDiffDigests         = "a dependency package has different digests in parent constraints (%v)"
-	errCannotUpdateStatus     = "cannot update status"
+	errGetLock              = "cannot get package lock"
+	errAddFinalizer         = "cannot add lock finalizer"
+	errRemoveFinalizer      = "cannot remove lock finalizer"
+	errBuildDAG             = "cannot build DAG"
+	errSortDAG              = "cannot sort DAG"
+	errFmtMissingDependency = "missing package (%s) is not a dependency"
+	errInvalidDependency    = "dependency package is not valid"
+	errFindDependency       = "cannot resolve dependency versions"
+	errGetPullConfig        = "cannot get image pull secret from config"
+	errRewriteImage         = "cannot rewrite image path using config"
+	errInvalidRewrite       = "rewritten image path is invalid"
+	errFetchTags            = "cannot fetch dependency package tags"
+	errGetDependency        = "cannot get dependency package"
+	errConstructDependency  = "cannot construct dependency package"
+	errCreateDependency     = "cannot create dependency package"
+	errUpdateDependency     = "cannot update dependency package"
+	errFmtSplit             = "package should have 2 segments after split but has %d"
+	errCannotUpdateStatus   = "cannot update status"
 )
 
 // ReconcilerOption is used to configure the Reconciler.
@@ -159,6 +151,7 @@ type Reconciler struct {
 	registry   string
 	features   *feature.Flags
 	conditions conditions.Manager
+	solver     *Solver
 
 	downgradesEnabled bool
 }
@@ -223,11 +216,20 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 		f(r)
 	}
 
+	var so []SolverOption
+	if r.features.Enabled(features.EnableAlphaDependencyVersionUpgrades) {
+		so = append(so, WithUpgrades())
+	}
+	if r.downgradesEnabled {
+		so = append(so, WithDowngrades())
+	}
+	r.solver = NewSolver(r.versions, so...)
+
 	return r
 }
 
 // Reconcile the lock by resolving dependencies.
-func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) { //nolint:gocognit // we need to handle both pkg installation and upgrade scenarios now
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
 	log := r.log.WithValues("request", req)
 	log.Debug("Reconciling")
 
@@ -297,43 +299,63 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, lock), errCannotUpdateStatus)
 	}
 
-	// If we are missing a node, we want to create it. The resolver never
-	// modifies the Lock. We only create the first implied node as we will
-	// be requeued when it adds itself to the Lock, at which point we will
-	// check for missing nodes again.
-	dep, ok := implied[0].(*v1beta1.Dependency)
-	depID := dep.Identifier()
-	if !ok {
-		log.Debug(errInvalidDependency, "error", errors.Errorf(errFmtMissingDependency, depID))
-		status.MarkConditions(v1beta1.ResolutionFailed(errors.Errorf(errFmtMissingDependency, depID)))
+	for _, n := range implied {
+		if _, ok := n.(*v1beta1.Dependency); !ok {
+			log.Debug(errInvalidDependency, "error", errors.Errorf(errFmtMissingDependency, n.Identifier()))
+			status.MarkConditions(v1beta1.ResolutionFailed(errors.Errorf(errFmtMissingDependency, n.Identifier())))
+			return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, lock), errCannotUpdateStatus)
+		}
+	}
+
+	// At least one dependency is missing or doesn't satisfy its constraints.
+	// Rather than fixing one dependency at a time we solve the whole Lock, so
+	// we never pick a version of one dependency that conflicts with another.
+	// The resolver never modifies the Lock. Packages add themselves to it
+	// once they're installed, at which point we'll solve it again.
+	sel, err := r.solver.Solve(ctx, lock.Packages)
+	var u *UnsatisfiableError
+	if errors.As(err, &u) {
+		// There's no point requeueing. We'll be queued again when the
+		// Lock changes.
+		log.Debug(errFindDependency, "error", err)
+		status.MarkConditions(v1beta1.ResolutionFailed(err))
 		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, lock), errCannotUpdateStatus)
 	}
+	if err != nil {
+		log.Debug(errFindDependency, "error", err)
+		status.MarkConditions(v1beta1.ResolutionFailed(errors.Wrap(err, errFindDependency)))
+		_ = r.client.Status().Update(ctx, lock)
+		return reconcile.Result{}, errors.Wrap(err, errFindDependency)
+	}
+
+	for _, s := range sel {
+		if err := r.apply(ctx, s); err != nil {
+			log.Debug("cannot apply dependency", "error", err, "package", s.Dependency.Identifier(), "version", s.Version)
+			status.MarkConditions(v1beta1.ResolutionFailed(err))
+			_ = r.client.Status().Update(ctx, lock)
+			return reconcile.Result{}, err
+		}
+	}
+
+	status.MarkConditions(v1beta1.ResolutionSucceeded())
+	return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, lock), errCannotUpdateStatus)
+}
 
-	ref, err := name.ParseReference(depID, name.WithDefaultRegistry(r.registry))
+// apply creates the selected package, or updates it to the selected version.
+func (r *Reconciler) apply(ctx context.Context, s Selection) error {
+	ref, err := name.ParseReference(s.Dependency.Identifier(), name.WithDefaultRegistry(r.registry))
 	if err != nil {
-		log.Debug(errInvalidDependency, "error", err)
-		status.MarkConditions(v1beta1.ResolutionFailed(errors.Wrap(err, errInvalidDependency)))
-		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, lock), errCannotUpdateStatus)
+		return errors.Wrap(err, errInvalidDependency)
 	}
 
-	var pkg *unstructured.Unstructured
-	var installedVersion string
 	if r.features.Enabled(features.EnableAlphaDependencyVersionUpgrades) {
-		l, err := NewPackageList(dep)
+		l, err := NewPackageList(&s.Dependency)
 		if err != nil {
-			log.Debug(errGetDependency, "error", err)
-			status.MarkConditions(v1beta1.ResolutionFailed(errors.Wrap(err, errGetDependency)))
-			_ = r.client.Status().Update(ctx, lock)
-			return reconcile.Result{}, errors.Wrap(err, errGetDependency)
+			return errors.Wrap(err, errGetDependency)
 		}
-
 		if err := r.client.List(ctx, l); err != nil {
-			log.Debug(errGetDependency, "error", err)
-			status.MarkConditions(v1beta1.ResolutionFailed(errors.Wrap(err, errGetDependency)))
-			_ = r.client.Status().Update(ctx, lock)
-			return reconcile.Result{}, errors.Wrap(err, errGetDependency)
+			return errors.Wrap(err, errGetDependency)
 		}
-
 		for _, p := range l.Items {
 			source, err := fieldpath.Pave(p.Object).GetString("spec.package")
 			if err != nil {
@@ -343,107 +365,38 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			if err != nil {
 				continue
 			}
-			if pref.Context().Name() == ref.Context().Name() {
-				pkg = &p
-				installedVersion = pref.Identifier()
+			if pref.Context().Name() != ref.Context().Name() {
+				continue
 			}
-		}
-	}
-
-	if pkg == nil {
-		// At this point, we know that the dependency is either missing or does not satisfy the constraints.
-		// Package does not exist. We need to create it.
-		var addVer string
-		if addVer, err = r.findDependencyVersionToInstall(ctx, dep, log, ref); err != nil {
-			log.Debug(errFindDependency, "error", errors.Wrapf(err, depID, dep.Constraints))
-			status.MarkConditions(v1beta1.ResolutionFailed(errors.Wrap(err, errFindDependency)))
-			_ = r.client.Status().Update(ctx, lock)
-			return reconcile.Result{Requeue: false}, errors.Wrap(err, errFindDependency)
-		}
 
-		// NOTE(hasheddan): consider creating event on package revision
-		// dictating constraints.
-		if addVer == "" {
-			log.Debug(errFindDependencyUpgrade, "error", errors.Errorf(errFmtNoValidVersion, depID, dep.Constraints))
-			status.MarkConditions(v1beta1.ResolutionFailed(errors.Errorf(errFmtNoValidVersion, depID, dep.Constraints)))
-			return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, lock), errCannotUpdateStatus)
-		}
-
-		pack, err := NewPackage(dep, addVer, ref)
-		if err != nil {
-			log.Debug(errConstructDependency, "error", err)
-			status.MarkConditions(v1beta1.ResolutionFailed(errors.Wrap(err, errConstructDependency)))
-			_ = r.client.Status().Update(ctx, lock)
-			return reconcile.Result{}, errors.Wrap(err, errConstructDependency)
-		}
-
-		// NOTE(hasheddan): consider making the lock the controller of packages
-		// it creates.
-		if err := r.client.Create(ctx, pack); err != nil && !kerrors.IsAlreadyExists(err) {
-			log.Debug(errCreateDependency, "error", err)
-			status.MarkConditions(v1beta1.ResolutionFailed(errors.Wrap(err, errCreateDependency)))
-			_ = r.client.Status().Update(ctx, lock)
-			return reconcile.Result{}, errors.Wrap(err, errCreateDependency)
+			// The package exists. Update it to the selected version.
+			format := packageTagFmt
+			if strings.HasPrefix(s.Version, "sha256:") {
+				format = packageDigestFmt
+			}
+			_ = fieldpath.Pave(p.Object).SetString("spec.package", fmt.Sprintf(format, ref.String(), s.Version))
+			return errors.Wrap(r.client.Update(ctx, &p), errUpdateDependency)
 		}
-
-		status.MarkConditions(v1beta1.ResolutionSucceeded())
-		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, lock), errCannotUpdateStatus)
 	}
 
-	if !r.features.Enabled(features.EnableAlphaDependencyVersionUpgrades) {
-		return reconcile.Result{}, nil
-	}
-
-	// The package is installed, but does not satisfy the constraints and
-	// upgrade flag is enabled. We need to search for a new version that
-	// satisfies all the constraints.
-
-	n, err := dag.GetNode(depID)
+	pack, err := NewPackage(&s.Dependency, s.Version, ref)
 	if err != nil {
-		log.Debug(errInvalidDependency, "error", errors.Errorf(errFmtMissingDependency, depID))
-		status.MarkConditions(v1beta1.ResolutionFailed(errors.Errorf(errFmtMissingDependency, depID)))
-		_ = r.client.Status().Update(ctx, lock)
-		return reconcile.Result{}, errors.Errorf(errFmtMissingDependency, depID)
+		return errors.Wrap(err, errConstructDependency)
 	}
 
-	newVer, err := r.findDependencyVersionToUpdate(ctx, ref, installedVersion, n, log)
-	if err != nil {
-		log.Debug(errFindDependencyUpgrade, "error", errors.Wrapf(err, depID, dep.Constraints))
-		status.MarkConditions(v1beta1.ResolutionFailed(errors.Wrap(err, errFindDependencyUpgrade)))
-		_ = r.client.Status().Update(ctx, lock)
-		return reconcile.Result{}, errors.Wrap(err, errFindDependencyUpgrade)
+	// NOTE(hasheddan): consider making the lock the controller of packages
+	// it creates.
+	if err := r.client.Create(ctx, pack); err != nil && !kerrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, errCreateDependency)
 	}
-
-	// Update the package with the new version.
-	format := packageTagFmt
-	if strings.HasPrefix(newVer, "sha256:") {
-		format = packageDigestFmt
-	}
-	_ = fieldpath.Pave(pkg.Object).SetString("spec.package", fmt.Sprintf(format, ref.String(), newVer))
-
-	if err := r.client.Update(ctx, pkg); err != nil {
-		log.Debug(errUpdateDependency, "error", err)
-		status.MarkConditions(v1beta1.ResolutionFailed(errors.Wrap(err, errUpdateDependency)))
-		_ = r.client.Status().Update(ctx, lock)
-		return reconcile.Result{}, errors.Wrap(err, errUpdateDependency)
-	}
-
-	status.MarkConditions(v1beta1.ResolutionSucceeded())
-	return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, lock), errCannotUpdateStatus)
+	return nil
 }
 
-func (r *Reconciler) findDependencyVersionToInstall(ctx context.Context, dep *v1beta1.Dependency, log logging.Logger, ref name.Reference) (string, error) {
-	var addVer string
-
-	if digest, err := conregv1.NewHash(dep.Constraints); err == nil {
-		log.Debug("package is pinned to a specific digest, skipping resolution")
-		return digest.String(), nil
-	}
-
-	c, err := semver.NewConstraint(dep.Constraints)
+// versions lists the available versions of the supplied package source.
+func (r *Reconciler) versions(ctx context.Context, source string) ([]string, error) {
+	ref, err := name.ParseReference(source, name.WithDefaultRegistry(r.registry))
 	if err != nil {
-		log.Debug(errInvalidConstraint, "error", err)
-		return "", errors.Wrap(err, errInvalidConstraint)
+		return nil, errors.Wrap(err, errInvalidDependency)
 	}
 
 	// Rewrite the image path if necessary. We need to do this before looking
@@ -451,183 +404,34 @@ func (r *Reconciler) findDependencyVersionToInstall(ctx context.Context, dep *v1
 	// the original.
 	rewriteConfigName, newPath, err := r.config.RewritePath(ctx, ref.String())
 	if err != nil {
-		log.Info("cannot rewrite image path using config", "error", err)
-		return "", errors.Wrap(err, errRewriteImage)
+		r.log.Info("cannot rewrite image path using config", "error", err)
+		return nil, errors.Wrap(err, errRewriteImage)
 	}
 	if newPath != "" {
 		ref, err = name.ParseReference(newPath, name.WithDefaultRegistry(r.registry))
 		if err != nil {
-			log.Info("rewritten image path is invalid", "error", err)
-			return "", errors.Wrap(err, errInvalidRewrite)
+			r.log.Info("rewritten image path is invalid", "error", err)
+			return nil, errors.Wrap(err, errInvalidRewrite)
 		}
 	}
 
 	psConfig, ps, err := r.config.PullSecretFor(ctx, ref.String())
 	if err != nil {
-		log.Info("cannot get pull secret from image config store", "error", err)
-		return "", errors.Wrap(err, errGetPullConfig)
+		r.log.Info("cannot get pull secret from image config store", "error", err)
+		return nil, errors.Wrap(err, errGetPullConfig)
 	}
 
 	var s []string
 	if ps != "" {
-		log.Debug("Selected pull secret from image config store", "image", ref.String(), "pullSecretConfig", psConfig, "pullSecret", ps, "rewriteConfig", rewriteConfigName)
+		r.log.Debug("Selected pull secret from image config store", "image", ref.String(), "pullSecretConfig", psConfig, "pullSecret", ps, "rewriteConfig", rewriteConfigName)
 		s = append(s, ps)
 	}
+
 	// NOTE(hasheddan): we will be unable to fetch tags for private
 	// dependencies because we do not attach any secrets. Consider copying
 	// secrets from parent dependencies.
 	tags, err := r.fetcher.Tags(ctx, ref, s...)
-	if err != nil {
-		log.Debug(errFetchTags, "error", err)
-		return "", errors.Wrap(err, errFetchTags)
-	}
-
-	vs := []*semver.Version{}
-	for _, r := range tags {
-		v, err := semver.NewVersion(r)
-		if err != nil {
-			// We skip any tags that are not valid semantic versions.
-			continue
-		}
-		vs = append(vs, v)
-	}
-
-	sort.Sort(semver.Collection(vs))
-	for _, v := range vs {
-		if c.Check(v) {
-			addVer = v.Original()
-		}
-	}
-
-	return addVer, nil
-}
-
-// findDependencyVersionToUpdate finds a valid version to update the dependency considering the parent constraints.
-func (r *Reconciler) findDependencyVersionToUpdate(ctx context.Context, ref name.Reference, insVer string, dep internaldag.Node, log logging.Logger) (string, error) {
-	// If there is a digest in the parent constraints, we need to make sure that all other parent constraints are the same.
-	digest, err := findDigestToUpdate(dep)
-	if err != nil {
-		log.Debug("cannot find digest to update", "error", err)
-		return "", err
-	}
-
-	if digest != "" {
-		log.Debug("package is pinned to a specific digest, skipping resolution")
-		return digest, nil
-	}
-
-	// Rewrite the image path if necessary. We need to do this before looking
-	// for pull secrets, since the rewritten path may use different secrets than
-	// the original.
-	rewriteConfigName, newPath, err := r.config.RewritePath(ctx, ref.String())
-	if err != nil {
-		log.Info("cannot rewrite image path using config", "error", err)
-		return "", errors.Wrap(err, errRewriteImage)
-	}
-	if newPath != "" {
-		ref, err = name.ParseReference(newPath, name.WithDefaultRegistry(r.registry))
-		if err != nil {
-			log.Info("rewritten image path is invalid", "error", err)
-			return "", errors.Wrap(err, errInvalidRewrite)
-		}
-	}
-
-	psConfig, ps, err := r.config.PullSecretFor(ctx, ref.String())
-	if err != nil {
-		log.Info("cannot get pull secret from image config store", "error", err)
-		return "", errors.Wrap(err, errGetPullConfig)
-	}
-
-	var s []string
-	if ps != "" {
-		log.Debug("Selected pull secret from image config store", "image", ref.String(), "pullSecretConfig", psConfig, "pullSecret", ps, "rewriteConfig", rewriteConfigName)
-		s = append(s, ps)
-	}
-
-	tags, err := r.fetcher.Tags(ctx, ref, s...)
-	if err != nil {
-		log.Debug(errFetchTags, "error", err)
-		return "", errors.Wrap(err, errFetchTags)
-	}
-
-	availableVersions := make([]*semver.Version, 0, len(tags))
-	for _, r := range tags {
-		v, err := semver.NewVersion(r)
-		if err != nil {
-			// We skip any tags that are not valid semantic versions.
-			continue
-		}
-		availableVersions = append(availableVersions, v)
-	}
-
-	parentConstraints := make([]*semver.Constraints, 0, len(dep.GetParentConstraints()))
-	for _, c := range dep.GetParentConstraints() {
-		constraint, err := semver.NewConstraint(c)
-		if err != nil {
-			log.Debug(errInvalidConstraint, "error", err)
-			return "", errors.Wrap(err, errInvalidConstraint)
-		}
-		parentConstraints = append(parentConstraints, constraint)
-	}
-
-	sort.Sort(semver.Collection(availableVersions))
-	currentVersion := semver.MustParse(insVer)
-	var targetVersion *semver.Version
-
-	// We aim to find the lowest version that satisfies all parent constraints and is greater than the current version.
-	for _, v := range availableVersions {
-		valid := true
-		for _, c := range parentConstraints {
-			if !c.Check(v) {
-				valid = false
-				break
-			}
-		}
-
-		// If we're upgrading, we target the first valid version that is greater than the current version.
-		if (v.GreaterThan(currentVersion) || v.Equal(currentVersion)) && valid {
-			return v.Original(), nil
-		}
-
-		// If we're downgrading, we target the largest valid version that is less than the current version.
-		if r.downgradesEnabled && valid {
-			targetVersion = v
-		}
-	}
-
-	if targetVersion != nil {
-		return targetVersion.Original(), nil
-	}
-
-	log.Debug(errFindDependencyUpgrade, "error", errors.Errorf(errFmtNoValidVersion, dep.Identifier(), dep.GetParentConstraints()))
-	return "", errors.Errorf(errFmtNoValidVersion, dep.Identifier(), dep.GetParentConstraints())
-}
-
-// findDigestToUpdate returns the digest to update if all parent constraints are the same digest.
-// It returns an error, if there is at least one digest which is different from other constraints.
-func findDigestToUpdate(node internaldag.Node) (string, error) {
-	foundDigest := ""
-	foundVersion := false
-	for _, c := range node.GetParentConstraints() {
-		if d, err := conregv1.NewHash(c); err == nil {
-			if foundDigest != "" && foundDigest != d.String() {
-				return "", errors.Errorf(errFmtDiffDigests, node.GetParentConstraints())
-			}
-			foundDigest = d.String()
-		} else {
-			foundVersion = true
-		}
-
-		if foundVersion && foundDigest != "" {
-			return "", errors.Errorf(errFmtDiffConstraintTypes, node.GetParentConstraints())
-		}
-	}
-
-	if foundDigest != "" {
-		return foundDigest, nil
-	}
-
-	return "", nil
+	return tags, errors.Wrap(err, errFetchTags)
 }
 
 // NewPackage creates a new package from the given dependency and version.
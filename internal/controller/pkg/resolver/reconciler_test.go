@@ -18,7 +18,6 @@ package resolver
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"testing"
 
@@ -329,6 +328,9 @@ func TestReconcile(t *testing.T) {
 								Type:    ptr.To(v1beta1.ProviderPackageType),
 								Source:  "cool-repo/cool-image",
 								Version: "v0.0.1",
+								Dependencies: []v1beta1.Dependency{{
+									Package: "not.a.valid.package",
+								}},
 							})
 							return nil
 						}),
@@ -356,7 +358,7 @@ func TestReconcile(t *testing.T) {
 			},
 			want: want{
 				r:   reconcile.Result{Requeue: false},
-				err: errors.Wrap(errors.Wrap(errors.New("improper constraint: "), errInvalidConstraint), errFindDependency),
+				err: errors.Wrap(errors.Wrapf(errors.New("improper constraint: "), errFmtConstraintParse, "cool-repo/cool-image", ""), errFindDependency),
 			},
 		},
 		"ErrorGetPullSecretFromImageConfig": {
@@ -374,6 +376,10 @@ func TestReconcile(t *testing.T) {
 								Type:    ptr.To(v1beta1.ProviderPackageType),
 								Source:  "cool-repo/cool-image",
 								Version: "v0.0.1",
+								Dependencies: []v1beta1.Dependency{{
+									Package:     "registry1.com/acme-co/configuration-foo",
+									Constraints: "v0.0.1",
+								}},
 							})
 							return nil
 						}),
@@ -408,7 +414,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 			want: want{
-				err: errors.Wrap(errors.Wrap(errBoom, errGetPullConfig), errFindDependency),
+				err: errors.Wrap(errors.Wrapf(errors.Wrap(errBoom, errGetPullConfig), errFmtListVersions, "registry1.com/acme-co/configuration-foo"), errFindDependency),
 			},
 		},
 		"ErrorRewriteImageWithImageConfig": {
@@ -426,6 +432,10 @@ func TestReconcile(t *testing.T) {
 								Type:    ptr.To(v1beta1.ProviderPackageType),
 								Source:  "cool-repo/cool-image",
 								Version: "v0.0.1",
+								Dependencies: []v1beta1.Dependency{{
+									Package:     "registry1.com/acme-co/configuration-foo",
+									Constraints: "v0.0.1",
+								}},
 							})
 							return nil
 						}),
@@ -460,7 +470,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 			want: want{
-				err: errors.Wrap(errors.Wrap(errBoom, errRewriteImage), errFindDependency),
+				err: errors.Wrap(errors.Wrapf(errors.Wrap(errBoom, errRewriteImage), errFmtListVersions, "registry1.com/acme-co/configuration-foo"), errFindDependency),
 			},
 		},
 		"ErrorInvalidRewriteWithImageConfig": {
@@ -478,6 +488,10 @@ func TestReconcile(t *testing.T) {
 								Type:    ptr.To(v1beta1.ProviderPackageType),
 								Source:  "cool-repo/cool-image",
 								Version: "v0.0.1",
+								Dependencies: []v1beta1.Dependency{{
+									Package:     "registry1.com/acme-co/configuration-foo",
+									Constraints: "v0.0.1",
+								}},
 							})
 							return nil
 						}),
@@ -512,7 +526,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 			want: want{
-				err: errors.Wrap(errors.Wrap(errors.New("could not parse reference: 0"), errInvalidRewrite), errFindDependency),
+				err: errors.Wrap(errors.Wrapf(errors.Wrap(errors.New("could not parse reference: 0"), errInvalidRewrite), errFmtListVersions, "registry1.com/acme-co/configuration-foo"), errFindDependency),
 			},
 		},
 		"ErrorFetchTags": {
@@ -530,6 +544,10 @@ func TestReconcile(t *testing.T) {
 								Type:    ptr.To(v1beta1.ProviderPackageType),
 								Source:  "cool-repo/cool-image",
 								Version: "v0.0.1",
+								Dependencies: []v1beta1.Dependency{{
+									Package:     "hasheddan/config-nop-b",
+									Constraints: "*",
+								}},
 							})
 							return nil
 						}),
@@ -564,7 +582,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 			want: want{
-				err: errors.Wrap(errors.Wrap(errBoom, errFetchTags), errFindDependency),
+				err: errors.Wrap(errors.Wrapf(errors.Wrap(errBoom, errFetchTags), errFmtListVersions, "hasheddan/config-nop-b"), errFindDependency),
 			},
 		},
 		"ErrorNoValidVersion": {
@@ -582,6 +600,10 @@ func TestReconcile(t *testing.T) {
 								Type:    ptr.To(v1beta1.ProviderPackageType),
 								Source:  "cool-repo/cool-image",
 								Version: "v0.0.1",
+								Dependencies: []v1beta1.Dependency{{
+									Package:     "hasheddan/config-nop-b",
+									Constraints: ">v1.0.0",
+								}},
 							})
 							return nil
 						}),
@@ -634,6 +656,11 @@ func TestReconcile(t *testing.T) {
 								Type:    ptr.To(v1beta1.ProviderPackageType),
 								Source:  "cool-repo/cool-image",
 								Version: "v0.0.1",
+								Dependencies: []v1beta1.Dependency{{
+									Package:     "hasheddan/config-nop-c",
+									Constraints: ">v1.0.0",
+									Type:        ptr.To(v1beta1.ConfigurationPackageType),
+								}},
 							})
 							return nil
 						}),
@@ -688,6 +715,11 @@ func TestReconcile(t *testing.T) {
 								Type:    ptr.To(v1beta1.ProviderPackageType),
 								Source:  "cool-repo/cool-image",
 								Version: "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+								Dependencies: []v1beta1.Dependency{{
+									Package:     "hasheddan/config-nop-c",
+									Constraints: "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+									Type:        ptr.To(v1beta1.ConfigurationPackageType),
+								}},
 							})
 							return nil
 						}),
@@ -742,6 +774,11 @@ func TestReconcile(t *testing.T) {
 								Type:    ptr.To(v1beta1.ProviderPackageType),
 								Source:  "cool-repo/cool-image",
 								Version: "v0.0.1",
+								Dependencies: []v1beta1.Dependency{{
+									Package:     "hasheddan/config-nop-c",
+									Constraints: ">v1.0.0",
+									Type:        ptr.To(v1beta1.ConfigurationPackageType),
+								}},
 							})
 							return nil
 						}),
@@ -796,6 +833,11 @@ func TestReconcile(t *testing.T) {
 								Type:    ptr.To(v1beta1.ProviderPackageType),
 								Source:  "cool-repo/cool-image",
 								Version: "v0.0.1",
+								Dependencies: []v1beta1.Dependency{{
+									Package:     "hasheddan/config-nop-c",
+									Constraints: ">v1.0.0",
+									Type:        ptr.To(v1beta1.ConfigurationPackageType),
+								}},
 							})
 							return nil
 						}),
@@ -855,6 +897,11 @@ func TestReconcile(t *testing.T) {
 								Type:    ptr.To(v1beta1.ProviderPackageType),
 								Source:  "cool-repo/cool-image",
 								Version: "v0.0.1",
+								Dependencies: []v1beta1.Dependency{{
+									Package:     "hasheddan/provider-nop-c",
+									Constraints: "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+									Type:        ptr.To(v1beta1.ProviderPackageType),
+								}},
 							})
 							return nil
 						}),
@@ -895,14 +942,35 @@ func TestReconcile(t *testing.T) {
 						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
 							l := o.(*v1beta1.Lock)
 							l.Packages = append(l.Packages, v1beta1.LockPackage{
-								Name:    "cool-package",
+								Name:    "cool-config",
+								Type:    ptr.To(v1beta1.ConfigurationPackageType),
+								Source:  "cool-repo/cool-config",
+								Version: "v0.0.1",
+								Dependencies: []v1beta1.Dependency{{
+									Package:     "cool-repo/cool-image",
+									Constraints: ">v1.0.0",
+									Type:        ptr.To(v1beta1.ProviderPackageType),
+								}},
+							}, v1beta1.LockPackage{
+								Name:    "cool-image",
 								Type:    ptr.To(v1beta1.ProviderPackageType),
 								Source:  "cool-repo/cool-image",
 								Version: "v0.0.1",
 							})
 							return nil
 						}),
-						MockUpdate:       test.NewMockUpdateFn(nil),
+						MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+							u, ok := obj.(*unstructured.Unstructured)
+							if !ok {
+								// The Lock's finalizer.
+								return nil
+							}
+							got, _ := fieldpath.Pave(u.Object).GetString("spec.package")
+							if want := "cool-repo/cool-image:v1.0.1"; got != want {
+								return errors.Errorf("want spec.package %q, got %q", want, got)
+							}
+							return nil
+						}),
 						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
 						MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
 							p := &unstructured.Unstructured{}
@@ -937,15 +1005,6 @@ func TestReconcile(t *testing.T) {
 							MockSort: func() ([]string, error) {
 								return nil, nil
 							},
-							MockGetNode: func(_ string) (dag.Node, error) {
-								return &v1beta1.Dependency{
-									Package: "cool-repo/cool-image",
-									ParentConstraints: []string{
-										">v1.0.0",
-									},
-									Type: ptr.To(v1beta1.ProviderPackageType),
-								}, nil
-							},
 						}
 					}),
 				},
@@ -959,7 +1018,17 @@ func TestReconcile(t *testing.T) {
 						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
 							l := o.(*v1beta1.Lock)
 							l.Packages = append(l.Packages, v1beta1.LockPackage{
-								Name:    "cool-package",
+								Name:    "cool-config",
+								Type:    ptr.To(v1beta1.ConfigurationPackageType),
+								Source:  "cool-repo/cool-config",
+								Version: "v0.0.1",
+								Dependencies: []v1beta1.Dependency{{
+									Package:     "cool-repo/cool-image",
+									Constraints: ">v1.0.0",
+									Type:        ptr.To(v1beta1.ProviderPackageType),
+								}},
+							}, v1beta1.LockPackage{
+								Name:    "cool-image",
 								Type:    ptr.To(v1beta1.ProviderPackageType),
 								Source:  "cool-repo/cool-image",
 								Version: "v0.0.1",
@@ -1006,15 +1075,6 @@ func TestReconcile(t *testing.T) {
 							MockSort: func() ([]string, error) {
 								return nil, nil
 							},
-							MockGetNode: func(_ string) (dag.Node, error) {
-								return &v1beta1.Dependency{
-									Package: "cool-repo/cool-image",
-									ParentConstraints: []string{
-										">v1.0.0",
-									},
-									Type: ptr.To(v1beta1.ProviderPackageType),
-								}, nil
-							},
 						}
 					}),
 				},
@@ -1028,14 +1088,35 @@ func TestReconcile(t *testing.T) {
 						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
 							l := o.(*v1beta1.Lock)
 							l.Packages = append(l.Packages, v1beta1.LockPackage{
-								Name:    "cool-package",
+								Name:    "cool-config",
+								Type:    ptr.To(v1beta1.ConfigurationPackageType),
+								Source:  "cool-repo/cool-config",
+								Version: "v0.0.1",
+								Dependencies: []v1beta1.Dependency{{
+									Package:     "cool-repo/cool-image",
+									Constraints: digest1,
+									Type:        ptr.To(v1beta1.ProviderPackageType),
+								}},
+							}, v1beta1.LockPackage{
+								Name:    "cool-image",
 								Type:    ptr.To(v1beta1.ProviderPackageType),
 								Source:  "cool-repo/cool-image",
 								Version: "v0.0.1",
 							})
 							return nil
 						}),
-						MockUpdate:       test.NewMockUpdateFn(nil),
+						MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+							u, ok := obj.(*unstructured.Unstructured)
+							if !ok {
+								// The Lock's finalizer.
+								return nil
+							}
+							got, _ := fieldpath.Pave(u.Object).GetString("spec.package")
+							if want := "cool-repo/cool-image@" + digest1; got != want {
+								return errors.Errorf("want spec.package %q, got %q", want, got)
+							}
+							return nil
+						}),
 						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
 						MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
 							p := &unstructured.Unstructured{}
@@ -1062,7 +1143,7 @@ func TestReconcile(t *testing.T) {
 								return []dag.Node{
 									&v1beta1.Dependency{
 										Package:     "cool-repo/cool-image",
-										Constraints: ">v1.0.0",
+										Constraints: digest1,
 										Type:        ptr.To(v1beta1.ProviderPackageType),
 									},
 								}, nil
@@ -1070,16 +1151,6 @@ func TestReconcile(t *testing.T) {
 							MockSort: func() ([]string, error) {
 								return nil, nil
 							},
-							MockGetNode: func(_ string) (dag.Node, error) {
-								return &v1beta1.Dependency{
-									Package: "cool-repo/cool-image",
-									ParentConstraints: []string{
-										digest1,
-										digest1,
-									},
-									Type: ptr.To(v1beta1.ProviderPackageType),
-								}, nil
-							},
 						}
 					}),
 				},
@@ -1101,289 +1172,3 @@ func TestReconcile(t *testing.T) {
 		})
 	}
 }
-
-func TestFindDigestToUpdate(t *testing.T) {
-	type args struct {
-		node dag.Node
-	}
-	type want struct {
-		digest string
-		err    error
-	}
-	cases := map[string]struct {
-		reason string
-		args   args
-		want   want
-	}{
-		"AllSameDigests": {
-			reason: "We should be able to find the digest to update.",
-			args: args{
-				node: &v1beta1.Dependency{
-					Package: "cool-repo/cool-image",
-					ParentConstraints: []string{
-						digest1,
-						digest1,
-					},
-				},
-			},
-			want: want{
-				digest: digest1,
-			},
-		},
-		"DifferentDigests": {
-			reason: "We should return an error if digests are different.",
-			args: args{
-				node: &v1beta1.Dependency{
-					Package: "cool-repo/cool-image",
-					ParentConstraints: []string{
-						digest1,
-						digest2,
-					},
-				},
-			},
-			want: want{
-				err: errors.Errorf(errFmtDiffDigests, fmt.Sprintf("[%s %s]", digest1, digest2)),
-			},
-		},
-		"AllVersions": {
-			reason: "We should return an empty string if all parent constraints are versions.",
-			args: args{
-				node: &v1beta1.Dependency{
-					Package:           "cool-repo/cool-image",
-					ParentConstraints: []string{"v0.0.1", "v0.0.2"},
-				},
-			},
-			want: want{
-				digest: "",
-				err:    nil,
-			},
-		},
-		"MixedConstraintTypes": {
-			reason: "We should return an error if both versions and digests are present.",
-			args: args{
-				node: &v1beta1.Dependency{
-					Package: "cool-repo/cool-image",
-					ParentConstraints: []string{
-						"v0.0.1",
-						digest1,
-					},
-				},
-			},
-			want: want{
-				err: errors.Errorf(errFmtDiffConstraintTypes, fmt.Sprintf("[v0.0.1 %s]", digest1)),
-			},
-		},
-	}
-
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			got, err := findDigestToUpdate(tc.args.node)
-
-			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
-			}
-			if diff := cmp.Diff(tc.want.digest, got, test.EquateErrors()); diff != "" {
-				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
-			}
-		})
-	}
-}
-
-func TestReconcilerFindDependencyVersionToUpgrade(t *testing.T) {
-	type args struct {
-		mgr    manager.Manager
-		insVer string
-		dep    dag.Node
-		rec    []ReconcilerOption
-	}
-	type want struct {
-		version string
-		err     error
-	}
-	cases := map[string]struct {
-		reason string
-		args   args
-		want   want
-	}{
-		"SuccessReturnDigest": {
-			reason: "We should be able to find the digest to update.",
-			args: args{
-				mgr:    &fake.Manager{Client: test.NewMockClient()},
-				insVer: "v0.0.1",
-				dep: &v1beta1.Dependency{
-					Package: "cool-repo/cool-image",
-					ParentConstraints: []string{
-						digest1,
-						digest1,
-					},
-				},
-			},
-			want: want{
-				version: digest1,
-			},
-		},
-		"ErrorMixedParentConstraints": {
-			reason: "We should return an error if parent constraints are mixed.",
-			args: args{
-				mgr:    &fake.Manager{Client: test.NewMockClient()},
-				insVer: "v0.0.1",
-				dep: &v1beta1.Dependency{
-					Package: "cool-repo/cool-image",
-					ParentConstraints: []string{
-						digest1,
-						"v0.0.1",
-					},
-				},
-			},
-			want: want{
-				err: errors.Errorf(errFmtDiffConstraintTypes, fmt.Sprintf("[%s v0.0.1]", digest1)),
-			},
-		},
-		"SuccessReturnVersion": {
-			reason: "We should be able to find the version to update.",
-			args: args{
-				mgr:    &fake.Manager{Client: test.NewMockClient()},
-				insVer: "v1.0.0",
-				dep: &v1beta1.Dependency{
-					Package: "cool-repo/cool-image",
-					ParentConstraints: []string{
-						">=v1.0.0",
-						"v2.0.0",
-					},
-				},
-				rec: []ReconcilerOption{
-					WithFetcher(&fakexpkg.MockFetcher{
-						MockTags: fakexpkg.NewMockTagsFn([]string{"v1.0.0", "v1.0.1", "v2.0.0", "v3.0.0"}, nil),
-					}),
-					WithConfigStore(&fakexpkg.MockConfigStore{
-						MockPullSecretFor: fakexpkg.NewMockConfigStorePullSecretForFn("", "", nil),
-						MockRewritePath:   fakexpkg.NewMockRewritePathFn("", "", nil),
-					}),
-				},
-			},
-			want: want{
-				version: "v2.0.0",
-			},
-		},
-		"ErrorNoValidVersion": {
-			reason: "We should return an error if no valid version exists for dependency.",
-			args: args{
-				mgr:    &fake.Manager{Client: test.NewMockClient()},
-				insVer: "v1.0.0",
-				dep: &v1beta1.Dependency{
-					Package: "cool-repo/cool-image",
-					ParentConstraints: []string{
-						">=v1.0.0",
-						"v2.0.0",
-					},
-				},
-				rec: []ReconcilerOption{
-					WithFetcher(&fakexpkg.MockFetcher{
-						MockTags: fakexpkg.NewMockTagsFn([]string{"v1.0.0", "v1.0.1"}, nil),
-					}),
-					WithConfigStore(&fakexpkg.MockConfigStore{
-						MockPullSecretFor: fakexpkg.NewMockConfigStorePullSecretForFn("", "", nil),
-						MockRewritePath:   fakexpkg.NewMockRewritePathFn("", "", nil),
-					}),
-				},
-			},
-			want: want{
-				err: errors.Errorf(errFmtNoValidVersion, "cool-repo/cool-image", "[>=v1.0.0 v2.0.0]"),
-			},
-		},
-		"ErrorNoValidVersionDowngrade": {
-			reason: "We should return an error if no valid version exists for dependency and downgrade is not enabled.",
-			args: args{
-				mgr:    &fake.Manager{Client: test.NewMockClient()},
-				insVer: "v1.0.0",
-				dep: &v1beta1.Dependency{
-					Package: "cool-repo/cool-image",
-					ParentConstraints: []string{
-						"<=v1.0.0",
-						"v0.0.1",
-					},
-				},
-				rec: []ReconcilerOption{
-					WithFetcher(&fakexpkg.MockFetcher{
-						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.0.1", "v1.0.0"}, nil),
-					}),
-					WithConfigStore(&fakexpkg.MockConfigStore{
-						MockPullSecretFor: fakexpkg.NewMockConfigStorePullSecretForFn("", "", nil),
-						MockRewritePath:   fakexpkg.NewMockRewritePathFn("", "", nil),
-					}),
-				},
-			},
-			want: want{
-				err: errors.Errorf(errFmtNoValidVersion, "cool-repo/cool-image", "[<=v1.0.0 v0.0.1]"),
-			},
-		},
-		"UpgradeToSmallestValid": {
-			reason: "We should be able to find the smallest valid version to update to.",
-			args: args{
-				mgr:    &fake.Manager{Client: test.NewMockClient()},
-				insVer: "v2.0.0",
-				dep: &v1beta1.Dependency{
-					Package: "cool-repo/cool-image",
-					ParentConstraints: []string{
-						">v2.0.0",
-						"<=v3.0.0",
-					},
-				},
-				rec: []ReconcilerOption{
-					WithFetcher(&fakexpkg.MockFetcher{
-						MockTags: fakexpkg.NewMockTagsFn([]string{"v1.0.0", "v2.0.0", "v2.1.0", "v3.0.0"}, nil),
-					}),
-					WithConfigStore(&fakexpkg.MockConfigStore{
-						MockPullSecretFor: fakexpkg.NewMockConfigStorePullSecretForFn("", "", nil),
-						MockRewritePath:   fakexpkg.NewMockRewritePathFn("", "", nil),
-					}),
-					WithDowngradesEnabled(),
-				},
-			},
-			want: want{
-				version: "v2.1.0",
-			},
-		},
-		"DowngradeToLargestValid": {
-			reason: "We should return an error if no valid version exists for dependency and downgrade is not allowed.",
-			args: args{
-				mgr:    &fake.Manager{Client: test.NewMockClient()},
-				insVer: "v3.0.0",
-				dep: &v1beta1.Dependency{
-					Package: "cool-repo/cool-image",
-					ParentConstraints: []string{
-						">=v0.0.1",
-						"<v3.0.0",
-					},
-				},
-				rec: []ReconcilerOption{
-					WithFetcher(&fakexpkg.MockFetcher{
-						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.0.1", "v1.0.0", "v2.0.0", "v3.0.0"}, nil),
-					}),
-					WithConfigStore(&fakexpkg.MockConfigStore{
-						MockPullSecretFor: fakexpkg.NewMockConfigStorePullSecretForFn("", "", nil),
-						MockRewritePath:   fakexpkg.NewMockRewritePathFn("", "", nil),
-					}),
-					WithDowngradesEnabled(),
-				},
-			},
-			want: want{
-				version: "v2.0.0",
-			},
-		},
-	}
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			r := NewReconciler(tc.args.mgr, append(tc.args.rec, WithLogger(testLog))...)
-			ref, _ := pkgName.ParseReference(tc.args.dep.Identifier())
-			got, err := r.findDependencyVersionToUpdate(context.Background(), ref, tc.args.insVer, tc.args.dep, testLog)
-
-			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("\n%s\nr.findDependencyVersionToUpdate(...): -want error, +got error:\n%s", tc.reason, diff)
-			}
-			if diff := cmp.Diff(tc.want.version, got, test.EquateErrors()); diff != "" {
-				t.Errorf("\n%s\nr.findDependencyVersionToUpdate(...): -want, +got:\n%s", tc.reason, diff)
-			}
-		})
-	}
-}
@@ -0,0 +1,485 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+// maxSolverSteps bounds the number of versions the solver will try before
+// giving up. Backtracking search is exponential in the worst case, and we'd
+// rather fail a reconcile than hang on a pathological Lock.
+const maxSolverSteps = 10000
+
+const (
+	errFmtListVersions    = "cannot list versions of package %s"
+	errFmtCycle           = "detected cycle involving package %s"
+	errFmtTooComplex      = "gave up after trying %d package versions"
+	errFmtUnsatisfiable   = "no version of package %s satisfies all constraints: %s"
+	errFmtConstraintParse = "%s: invalid constraint %q"
+)
+
+// A ListVersionsFn lists the available versions (i.e. tags) of the supplied
+// package source.
+type ListVersionsFn func(ctx context.Context, source string) ([]string, error)
+
+// A Requirement is a version constraint one package in the Lock places on one
+// of its dependencies.
+type Requirement struct {
+	// Chain is the path through the Lock that leads to the requirement. It
+	// starts at a package that nothing depends on and ends at the package
+	// that imposes the constraint. Each element is formatted source@version.
+	Chain []string
+
+	// Constraints is the semantic version range or digest the dependency
+	// must satisfy.
+	Constraints string
+}
+
+// String returns a human readable representation of the requirement.
+func (r Requirement) String() string {
+	return fmt.Sprintf("%s requires %s", strings.Join(r.Chain, " -> "), r.Constraints)
+}
+
+// An UnsatisfiableError is returned when there is no set of package versions
+// that satisfies all constraints in the Lock.
+type UnsatisfiableError struct {
+	// Package is the source of the package for which no version could be
+	// found.
+	Package string
+
+	// Requirements are the constraints placed on the package at the time
+	// the solver gave up.
+	Requirements []Requirement
+}
+
+// Error returns the full constraint chain that could not be satisfied.
+func (e *UnsatisfiableError) Error() string {
+	rs := make([]string, len(e.Requirements))
+	for i, r := range e.Requirements {
+		rs[i] = r.String()
+	}
+	return fmt.Sprintf(errFmtUnsatisfiable, e.Package, strings.Join(rs, "; "))
+}
+
+// A Selection is a package version chosen by the solver that differs from what
+// is currently in the Lock.
+type Selection struct {
+	// Dependency describes the selected package.
+	Dependency v1beta1.Dependency
+
+	// Version is the selected tag or digest.
+	Version string
+
+	// Installed is true if a (different) version of the package is already
+	// in the Lock.
+	Installed bool
+}
+
+// SolverOption configures a Solver.
+type SolverOption func(*Solver)
+
+// WithUpgrades allows the Solver to select a different version of a package
+// that is already in the Lock.
+func WithUpgrades() SolverOption {
+	return func(s *Solver) {
+		s.upgrades = true
+	}
+}
+
+// WithDowngrades allows the Solver to select an older version of a package
+// that is already in the Lock. It has no effect unless upgrades are allowed.
+func WithDowngrades() SolverOption {
+	return func(s *Solver) {
+		s.downgrades = true
+	}
+}
+
+// A Solver computes a consistent set of package versions for a Lock.
+//
+// Each package in the Lock records the constraints its installed version places
+// on its dependencies. The Solver treats every dependency as a variable whose
+// domain is its available versions, and searches for an assignment that
+// satisfies every constraint, backtracking when it hits a conflict. A package
+// only constrains its dependencies while it's assigned the version that is in
+// the Lock - if the Solver selects a different version of a package the
+// constraints of that version are unknown until it's installed, at which point
+// the Lock will be solved again.
+type Solver struct {
+	versions   ListVersionsFn
+	upgrades   bool
+	downgrades bool
+}
+
+// NewSolver returns a Solver that uses the supplied function to list the
+// available versions of packages.
+func NewSolver(fn ListVersionsFn, opts ...SolverOption) *Solver {
+	s := &Solver{versions: fn}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+type edge struct {
+	from        string
+	constraints string
+}
+
+type solve struct {
+	*Solver
+
+	locked map[string]v1beta1.LockPackage
+	deps   map[string]v1beta1.Dependency
+	reqs   map[string][]edge
+	order  []string
+
+	assigned map[string]string
+	tags     map[string][]*semver.Version
+	steps    int
+
+	// The deepest conflict we've found, used to explain why solving failed.
+	conflict *UnsatisfiableError
+	depth    int
+}
+
+// Solve returns the package versions that must be installed or updated in
+// order for all constraints in the supplied Lock packages to be satisfied. It
+// returns an *UnsatisfiableError explaining the conflicting constraints if no
+// such set of versions exists.
+func (s *Solver) Solve(ctx context.Context, pkgs []v1beta1.LockPackage) ([]Selection, error) {
+	st := &solve{
+		Solver:   s,
+		locked:   make(map[string]v1beta1.LockPackage, len(pkgs)),
+		deps:     make(map[string]v1beta1.Dependency),
+		reqs:     make(map[string][]edge),
+		assigned: make(map[string]string),
+		tags:     make(map[string][]*semver.Version),
+		depth:    -1,
+	}
+	for _, p := range pkgs {
+		st.locked[p.Source] = p
+	}
+	for _, p := range pkgs {
+		for _, d := range p.Dependencies {
+			if _, ok := st.deps[d.Package]; !ok {
+				st.deps[d.Package] = d
+			}
+			st.reqs[d.Package] = append(st.reqs[d.Package], edge{from: p.Source, constraints: d.Constraints})
+		}
+	}
+
+	order, err := st.sort(pkgs)
+	if err != nil {
+		return nil, err
+	}
+	st.order = order
+
+	ok, err := st.search(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, st.conflict
+	}
+
+	sel := make([]Selection, 0)
+	for _, src := range st.order {
+		v := st.assigned[src]
+		l, installed := st.locked[src]
+		if v == "" || (installed && l.Version == v) {
+			continue
+		}
+		sel = append(sel, Selection{Dependency: st.deps[src], Version: v, Installed: installed})
+	}
+	return sel, nil
+}
+
+// sort returns the dependencies in the Lock ordered such that a package always
+// comes after all of the packages that depend on it.
+func (st *solve) sort(pkgs []v1beta1.LockPackage) ([]string, error) {
+	in := make(map[string]int, len(st.reqs))
+	for src, es := range st.reqs {
+		in[src] = len(es)
+	}
+
+	var queue []string
+	for _, p := range pkgs {
+		if in[p.Source] == 0 {
+			queue = append(queue, p.Source)
+		}
+	}
+
+	order := make([]string, 0, len(st.reqs))
+	for len(queue) > 0 {
+		src := queue[0]
+		queue = queue[1:]
+
+		if _, ok := st.reqs[src]; ok {
+			order = append(order, src)
+		}
+		for _, d := range st.locked[src].Dependencies {
+			in[d.Package]--
+			if in[d.Package] == 0 {
+				queue = append(queue, d.Package)
+			}
+		}
+	}
+
+	for src, n := range in {
+		if n > 0 {
+			return nil, errors.Errorf(errFmtCycle, src)
+		}
+	}
+	return order, nil
+}
+
+func (st *solve) search(ctx context.Context, i int) (bool, error) {
+	if i == len(st.order) {
+		return true, nil
+	}
+	src := st.order[i]
+
+	reqs := st.active(src)
+	tried := map[string]bool{}
+
+	// Try the cheap candidates first - typically the version that's already
+	// installed. We only list the available versions of the package if that
+	// doesn't work out.
+	for _, all := range []bool{false, true} {
+		candidates, err := st.candidates(ctx, src, reqs, all)
+		if err != nil {
+			return false, err
+		}
+		for _, v := range candidates {
+			if tried[v] {
+				continue
+			}
+			tried[v] = true
+			st.steps++
+			if st.steps > maxSolverSteps {
+				return false, errors.Errorf(errFmtTooComplex, maxSolverSteps)
+			}
+			st.assigned[src] = v
+			ok, err := st.search(ctx, i+1)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+	}
+	delete(st.assigned, src)
+
+	// Remember the conflict closest to the leaves of the graph. It's the
+	// one we got furthest before failing to resolve, and thus usually the
+	// most useful explanation.
+	if len(tried) == 0 && i > st.depth {
+		st.depth = i
+		st.conflict = &UnsatisfiableError{Package: src, Requirements: st.explain(reqs)}
+	}
+	return false, nil
+}
+
+// active returns the constraints that currently apply to the supplied package.
+// A package only constrains its dependencies while it's assigned the version
+// that is in the Lock.
+func (st *solve) active(src string) []edge {
+	active := make([]edge, 0, len(st.reqs[src]))
+	for _, e := range st.reqs[src] {
+		if v, ok := st.assigned[e.from]; ok && v != st.locked[e.from].Version {
+			continue
+		}
+		active = append(active, e)
+	}
+	return active
+}
+
+// candidates returns the versions of the supplied package that satisfy the
+// supplied constraints, in order of preference. Unless all is true it returns
+// only the candidates that can be determined without listing the available
+// versions of the package.
+func (st *solve) candidates(ctx context.Context, src string, reqs []edge, all bool) ([]string, error) { //nolint:gocognit // Mostly preference ordering.
+	l, installed := st.locked[src]
+
+	switch {
+	// Nothing that is being kept at its current version depends on this
+	// package anymore. Leave it as is.
+	case len(reqs) == 0 && installed:
+		return []string{l.Version}, nil
+	case len(reqs) == 0:
+		return []string{""}, nil
+	// We're not allowed to change packages that are already installed.
+	case installed && !st.upgrades:
+		return []string{l.Version}, nil
+	}
+
+	digest, ok := pinnedDigest(reqs)
+	switch {
+	case !ok:
+		return nil, nil
+	case digest != "":
+		return []string{digest}, nil
+	}
+
+	semvers := make([]*semver.Constraints, 0, len(reqs))
+	for _, e := range reqs {
+		c, err := semver.NewConstraint(e.constraints)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtConstraintParse, e.from, e.constraints)
+		}
+		semvers = append(semvers, c)
+	}
+
+	satisfies := func(v *semver.Version) bool {
+		for _, c := range semvers {
+			if !c.Check(v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Prefer to keep the installed version if it's acceptable.
+	var current *semver.Version
+	if installed {
+		if v, err := semver.NewVersion(l.Version); err == nil {
+			current = v
+		}
+	}
+	if !all {
+		if current != nil && satisfies(current) {
+			return []string{l.Version}, nil
+		}
+		return nil, nil
+	}
+
+	available, err := st.available(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]string, 0, len(available))
+
+	// When installing a package we prefer the newest acceptable version.
+	if !installed {
+		for i := len(available) - 1; i >= 0; i-- {
+			if satisfies(available[i]) {
+				out = append(out, available[i].Original())
+			}
+		}
+		return out, nil
+	}
+
+	// When updating a package we prefer the smallest upgrade.
+	for _, v := range available {
+		if (current == nil || v.GreaterThan(current)) && satisfies(v) {
+			out = append(out, v.Original())
+		}
+	}
+
+	// Then, if allowed, the smallest downgrade.
+	if st.downgrades && current != nil {
+		for i := len(available) - 1; i >= 0; i-- {
+			if available[i].LessThan(current) && satisfies(available[i]) {
+				out = append(out, available[i].Original())
+			}
+		}
+	}
+	return out, nil
+}
+
+// pinnedDigest returns the digest the supplied constraints pin a package to,
+// or an empty string if they're all version ranges. It returns false if the
+// constraints conflict, because a package can't be pinned to two different
+// digests, or be pinned to a digest and a version range.
+func pinnedDigest(reqs []edge) (string, bool) {
+	digest := ""
+	versions := false
+	for _, e := range reqs {
+		d, err := conregv1.NewHash(e.constraints)
+		if err != nil {
+			versions = true
+			continue
+		}
+		if digest != "" && digest != d.String() {
+			return "", false
+		}
+		digest = d.String()
+	}
+	if digest != "" && versions {
+		return "", false
+	}
+	return digest, true
+}
+
+// available returns the sorted semantic versions of the supplied package.
+func (st *solve) available(ctx context.Context, src string) ([]*semver.Version, error) {
+	if vs, ok := st.tags[src]; ok {
+		return vs, nil
+	}
+	tags, err := st.versions(ctx, src)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtListVersions, src)
+	}
+	vs := make([]*semver.Version, 0, len(tags))
+	for _, t := range tags {
+		v, err := semver.NewVersion(t)
+		if err != nil {
+			// We skip any tags that are not valid semantic versions.
+			continue
+		}
+		vs = append(vs, v)
+	}
+	sort.Sort(semver.Collection(vs))
+	st.tags[src] = vs
+	return vs, nil
+}
+
+// explain returns the supplied constraints along with the chain of packages
+// that led to them.
+func (st *solve) explain(reqs []edge) []Requirement {
+	out := make([]Requirement, len(reqs))
+	for i, e := range reqs {
+		out[i] = Requirement{Chain: st.chain(e.from), Constraints: e.constraints}
+	}
+	return out
+}
+
+// chain returns the path from a package nothing depends on to the supplied
+// package, following the first active dependent at each step.
+func (st *solve) chain(src string) []string {
+	v, ok := st.assigned[src]
+	if !ok {
+		v = st.locked[src].Version
+	}
+	self := fmt.Sprintf("%s@%s", src, v)
+
+	parents := st.active(src)
+	if len(parents) == 0 {
+		return []string{self}
+	}
+	return append(st.chain(parents[0].from), self)
+}
@@ -0,0 +1,387 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+func listVersions(tags map[string][]string) ListVersionsFn {
+	return func(_ context.Context, source string) ([]string, error) {
+		t, ok := tags[source]
+		if !ok {
+			return nil, errors.Errorf("unexpected call to list versions of %s", source)
+		}
+		return t, nil
+	}
+}
+
+func TestSolve(t *testing.T) {
+	type args struct {
+		versions ListVersionsFn
+		opts     []SolverOption
+		pkgs     []v1beta1.LockPackage
+	}
+	type want struct {
+		sel []Selection
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NothingToDo": {
+			reason: "We shouldn't select anything, or list any versions, if the Lock is already satisfied.",
+			args: args{
+				versions: listVersions(nil),
+				pkgs: []v1beta1.LockPackage{
+					{Source: "a", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "b", Constraints: ">=v1.0.0"}}},
+					{Source: "b", Version: "v1.2.0"},
+				},
+			},
+			want: want{
+				sel: []Selection{},
+			},
+		},
+		"InstallNewestValidVersion": {
+			reason: "We should install the newest version of a missing dependency that satisfies all of its constraints.",
+			args: args{
+				versions: listVersions(map[string][]string{"c": {"v1.0.0", "v1.1.0", "v1.2.0", "v2.0.0", "latest"}}),
+				pkgs: []v1beta1.LockPackage{
+					{Source: "a", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "c", Constraints: ">=v1.0.0"}}},
+					{Source: "b", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "c", Constraints: "<v1.2.0"}}},
+				},
+			},
+			want: want{
+				sel: []Selection{
+					{Dependency: v1beta1.Dependency{Package: "c", Constraints: ">=v1.0.0"}, Version: "v1.1.0"},
+				},
+			},
+		},
+		"InstallAllMissingDependencies": {
+			reason: "We should select a version of every missing dependency in one pass.",
+			args: args{
+				versions: listVersions(map[string][]string{
+					"b": {"v1.0.0", "v2.0.0"},
+					"c": {"v0.1.0", "v0.2.0"},
+				}),
+				pkgs: []v1beta1.LockPackage{
+					{Source: "a", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{
+						{Package: "b", Constraints: "<v2.0.0"},
+						{Package: "c", Constraints: ">=v0.1.0"},
+					}},
+				},
+			},
+			want: want{
+				sel: []Selection{
+					{Dependency: v1beta1.Dependency{Package: "b", Constraints: "<v2.0.0"}, Version: "v1.0.0"},
+					{Dependency: v1beta1.Dependency{Package: "c", Constraints: ">=v0.1.0"}, Version: "v0.2.0"},
+				},
+			},
+		},
+		"InstallDigest": {
+			reason: "We should install the digest a missing dependency is pinned to without listing versions.",
+			args: args{
+				versions: listVersions(nil),
+				pkgs: []v1beta1.LockPackage{
+					{Source: "a", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "b", Constraints: digest1}}},
+					{Source: "c", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "b", Constraints: digest1}}},
+				},
+			},
+			want: want{
+				sel: []Selection{
+					{Dependency: v1beta1.Dependency{Package: "b", Constraints: digest1}, Version: digest1},
+				},
+			},
+		},
+		"DifferentDigests": {
+			reason: "We should return a conflict if a dependency is pinned to different digests.",
+			args: args{
+				versions: listVersions(nil),
+				pkgs: []v1beta1.LockPackage{
+					{Source: "a", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "b", Constraints: digest1}}},
+					{Source: "c", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "b", Constraints: digest2}}},
+				},
+			},
+			want: want{
+				err: &UnsatisfiableError{Package: "b", Requirements: []Requirement{
+					{Chain: []string{"a@v1.0.0"}, Constraints: digest1},
+					{Chain: []string{"c@v1.0.0"}, Constraints: digest2},
+				}},
+			},
+		},
+		"MixedConstraintTypes": {
+			reason: "We should return a conflict if a dependency is pinned to a digest and a version range.",
+			args: args{
+				versions: listVersions(nil),
+				pkgs: []v1beta1.LockPackage{
+					{Source: "a", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "b", Constraints: ">=v1.0.0"}}},
+					{Source: "c", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "b", Constraints: digest1}}},
+				},
+			},
+			want: want{
+				err: &UnsatisfiableError{Package: "b", Requirements: []Requirement{
+					{Chain: []string{"a@v1.0.0"}, Constraints: ">=v1.0.0"},
+					{Chain: []string{"c@v1.0.0"}, Constraints: digest1},
+				}},
+			},
+		},
+		"ConflictExplainsChain": {
+			reason: "We should explain a conflict with the full chain of packages that led to each constraint.",
+			args: args{
+				versions: listVersions(map[string][]string{"c": {"v1.0.0", "v2.0.0"}}),
+				pkgs: []v1beta1.LockPackage{
+					{Source: "a", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{
+						{Package: "b", Constraints: ">=v1.0.0"},
+						{Package: "c", Constraints: ">=v2.0.0"},
+					}},
+					{Source: "b", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "c", Constraints: "<v2.0.0"}}},
+				},
+			},
+			want: want{
+				err: &UnsatisfiableError{Package: "c", Requirements: []Requirement{
+					{Chain: []string{"a@v1.0.0"}, Constraints: ">=v2.0.0"},
+					{Chain: []string{"a@v1.0.0", "b@v1.0.0"}, Constraints: "<v2.0.0"},
+				}},
+			},
+		},
+		"UpgradeToSmallestValid": {
+			reason: "We should upgrade an installed dependency to the smallest version that satisfies its constraints.",
+			args: args{
+				versions: listVersions(map[string][]string{"b": {"v1.0.0", "v2.0.0", "v2.1.0", "v3.0.0"}}),
+				opts:     []SolverOption{WithUpgrades()},
+				pkgs: []v1beta1.LockPackage{
+					{Source: "a", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "b", Constraints: ">v2.0.0"}}},
+					{Source: "b", Version: "v2.0.0"},
+				},
+			},
+			want: want{
+				sel: []Selection{
+					{Dependency: v1beta1.Dependency{Package: "b", Constraints: ">v2.0.0"}, Version: "v2.1.0", Installed: true},
+				},
+			},
+		},
+		"NoUpgradesAllowed": {
+			reason: "We shouldn't change an installed dependency unless upgrades are allowed.",
+			args: args{
+				versions: listVersions(nil),
+				pkgs: []v1beta1.LockPackage{
+					{Source: "a", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "b", Constraints: ">v2.0.0"}}},
+					{Source: "b", Version: "v2.0.0"},
+				},
+			},
+			want: want{
+				sel: []Selection{},
+			},
+		},
+		"UpgradeToDigest": {
+			reason: "We should update an installed dependency to the digest it's pinned to without listing versions.",
+			args: args{
+				versions: listVersions(nil),
+				opts:     []SolverOption{WithUpgrades()},
+				pkgs: []v1beta1.LockPackage{
+					{Source: "a", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "b", Constraints: digest1}}},
+					{Source: "b", Version: "v1.0.0"},
+				},
+			},
+			want: want{
+				sel: []Selection{
+					{Dependency: v1beta1.Dependency{Package: "b", Constraints: digest1}, Version: digest1, Installed: true},
+				},
+			},
+		},
+		"DowngradeToLargestValid": {
+			reason: "We should downgrade an installed dependency to the largest valid version if downgrades are allowed.",
+			args: args{
+				versions: listVersions(map[string][]string{"b": {"v0.0.1", "v1.0.0", "v2.0.0", "v3.0.0"}}),
+				opts:     []SolverOption{WithUpgrades(), WithDowngrades()},
+				pkgs: []v1beta1.LockPackage{
+					{Source: "a", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "b", Constraints: "<v3.0.0"}}},
+					{Source: "b", Version: "v3.0.0"},
+				},
+			},
+			want: want{
+				sel: []Selection{
+					{Dependency: v1beta1.Dependency{Package: "b", Constraints: "<v3.0.0"}, Version: "v2.0.0", Installed: true},
+				},
+			},
+		},
+		"NoDowngradesAllowed": {
+			reason: "We should return a conflict if satisfying a constraint requires a downgrade that isn't allowed.",
+			args: args{
+				versions: listVersions(map[string][]string{"b": {"v0.0.1", "v1.0.0"}}),
+				opts:     []SolverOption{WithUpgrades()},
+				pkgs: []v1beta1.LockPackage{
+					{Source: "a", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "b", Constraints: "<=v0.0.1"}}},
+					{Source: "b", Version: "v1.0.0"},
+				},
+			},
+			want: want{
+				err: &UnsatisfiableError{Package: "b", Requirements: []Requirement{
+					{Chain: []string{"a@v1.0.0"}, Constraints: "<=v0.0.1"},
+				}},
+			},
+		},
+		"BacktrackToUpgradeDependent": {
+			reason: "We should upgrade an intermediate package whose installed version's constraints conflict with the rest of the graph.",
+			args: args{
+				versions: listVersions(map[string][]string{
+					"b": {"v1.0.0", "v2.0.0"},
+					"c": {"v1.0.0", "v2.0.0"},
+				}),
+				opts: []SolverOption{WithUpgrades()},
+				pkgs: []v1beta1.LockPackage{
+					{Source: "a", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{
+						{Package: "b", Constraints: ">=v1.0.0"},
+						{Package: "c", Constraints: ">=v2.0.0"},
+					}},
+					{Source: "b", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "c", Constraints: "<v2.0.0"}}},
+					{Source: "c", Version: "v1.0.0"},
+				},
+			},
+			want: want{
+				sel: []Selection{
+					{Dependency: v1beta1.Dependency{Package: "b", Constraints: ">=v1.0.0"}, Version: "v2.0.0", Installed: true},
+					{Dependency: v1beta1.Dependency{Package: "c", Constraints: ">=v2.0.0"}, Version: "v2.0.0", Installed: true},
+				},
+			},
+		},
+		"ListVersionsError": {
+			reason: "We should return any error encountered listing versions.",
+			args: args{
+				versions: func(_ context.Context, _ string) ([]string, error) { return nil, errBoom },
+				pkgs: []v1beta1.LockPackage{
+					{Source: "a", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "b", Constraints: ">=v1.0.0"}}},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtListVersions, "b"),
+			},
+		},
+		"Cycle": {
+			reason: "We should return an error if the Lock contains a cycle.",
+			args: args{
+				versions: listVersions(nil),
+				pkgs: []v1beta1.LockPackage{
+					{Source: "a", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{{Package: "a", Constraints: ">=v1.0.0"}}},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtCycle, "a"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := NewSolver(tc.args.versions, tc.args.opts...)
+			got, err := s.Solve(context.Background(), tc.args.pkgs)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.Solve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.sel, got); diff != "" {
+				t.Errorf("\n%s\ns.Solve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPinnedDigest(t *testing.T) {
+	type args struct {
+		reqs []edge
+	}
+	type want struct {
+		digest string
+		ok     bool
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AllSameDigests": {
+			reason: "We should return the digest if all constraints are the same digest.",
+			args: args{
+				reqs: []edge{{from: "a", constraints: digest1}, {from: "b", constraints: digest1}},
+			},
+			want: want{
+				digest: digest1,
+				ok:     true,
+			},
+		},
+		"DifferentDigests": {
+			reason: "We should return a conflict if the constraints are different digests.",
+			args: args{
+				reqs: []edge{{from: "a", constraints: digest1}, {from: "b", constraints: digest2}},
+			},
+			want: want{
+				ok: false,
+			},
+		},
+		"AllVersions": {
+			reason: "We should return an empty digest if all constraints are version ranges.",
+			args: args{
+				reqs: []edge{{from: "a", constraints: "v0.0.1"}, {from: "b", constraints: ">=v0.0.2"}},
+			},
+			want: want{
+				digest: "",
+				ok:     true,
+			},
+		},
+		"MixedConstraintTypes": {
+			reason: "We should return a conflict if a version range precedes a digest.",
+			args: args{
+				reqs: []edge{{from: "a", constraints: "v0.0.1"}, {from: "b", constraints: digest1}},
+			},
+			want: want{
+				ok: false,
+			},
+		},
+		"MixedConstraintTypesDigestFirst": {
+			reason: "We should return a conflict if a digest precedes a version range.",
+			args: args{
+				reqs: []edge{{from: "a", constraints: digest1}, {from: "b", constraints: "v0.0.1"}},
+			},
+			want: want{
+				ok: false,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			digest, ok := pinnedDigest(tc.args.reqs)
+
+			if diff := cmp.Diff(tc.want.digest, digest); diff != "" {
+				t.Errorf("\n%s\npinnedDigest(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\npinnedDigest(...): -want ok, +got ok:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/internal/initializer"
+)
+
+const (
+	errFmtListCustomResources = "cannot list custom resources of CRD %s"
+	errFmtMigrateStorage      = "cannot migrate custom resources of CRD %s to storage version %s"
+	errFmtMigrateNoStorage    = "cannot migrate custom resources of CRD %s: the update removes storage version %s"
+	errFmtUnsafeCRDUpgrade    = "refusing to update CRD %s: %s"
+)
+
+// An UnsafeCRDUpgradeError indicates that updating a CRD could strand its
+// existing custom resources.
+type UnsafeCRDUpgradeError struct {
+	CRD      string
+	Problems []string
+}
+
+// Error returns a description of why the CRD upgrade is unsafe.
+func (e *UnsafeCRDUpgradeError) Error() string {
+	return fmt.Sprintf(errFmtUnsafeCRDUpgrade, e.CRD, strings.Join(e.Problems, "; "))
+}
+
+// A CRDUpgradeGuard checks whether a CRD can safely be updated from its
+// current state to its desired state.
+type CRDUpgradeGuard interface {
+	Guard(ctx context.Context, current, desired *extv1.CustomResourceDefinition) error
+}
+
+// A CRDUpgradeGuardFn is a function that satisfies the CRDUpgradeGuard
+// interface.
+type CRDUpgradeGuardFn func(ctx context.Context, current, desired *extv1.CustomResourceDefinition) error
+
+// Guard checks whether a CRD can safely be updated.
+func (fn CRDUpgradeGuardFn) Guard(ctx context.Context, current, desired *extv1.CustomResourceDefinition) error {
+	return fn(ctx, current, desired)
+}
+
+// NewNopCRDUpgradeGuard returns a CRDUpgradeGuard that allows every update.
+func NewNopCRDUpgradeGuard() CRDUpgradeGuardFn {
+	return func(_ context.Context, _, _ *extv1.CustomResourceDefinition) error { return nil }
+}
+
+// An APICRDUpgradeGuard refuses to update a CRD if doing so could strand its
+// existing custom resources. It refuses to remove a version custom resources
+// may be stored at, to stop serving a version while custom resources exist,
+// and to make the schema of a version stricter while custom resources exist.
+// It never writes to the API server.
+type APICRDUpgradeGuard struct {
+	client  client.Reader
+	log     logging.Logger
+	migrate bool
+}
+
+// An APICRDUpgradeGuardOption configures an APICRDUpgradeGuard.
+type APICRDUpgradeGuardOption func(g *APICRDUpgradeGuard)
+
+// WithStorageVersionMigration configures an APICRDUpgradeGuard to allow the
+// desired CRD to remove versions custom resources may be stored at, as long as
+// it keeps the current storage version. Use it when a CRDStorageMigrator will
+// migrate custom resources to the storage version before the CRD is updated.
+func WithStorageVersionMigration() APICRDUpgradeGuardOption {
+	return func(g *APICRDUpgradeGuard) {
+		g.migrate = true
+	}
+}
+
+// WithGuardLogger configures the logger an APICRDUpgradeGuard uses to report
+// schema changes it can't tell are safe.
+func WithGuardLogger(l logging.Logger) APICRDUpgradeGuardOption {
+	return func(g *APICRDUpgradeGuard) {
+		g.log = l
+	}
+}
+
+// NewAPICRDUpgradeGuard returns a new APICRDUpgradeGuard.
+func NewAPICRDUpgradeGuard(c client.Reader, o ...APICRDUpgradeGuardOption) *APICRDUpgradeGuard {
+	g := &APICRDUpgradeGuard{client: c, log: logging.NewNopLogger()}
+	for _, fn := range o {
+		fn(g)
+	}
+	return g
+}
+
+// Guard returns an *UnsafeCRDUpgradeError if updating the current CRD to the
+// desired CRD could strand its existing custom resources.
+func (g *APICRDUpgradeGuard) Guard(ctx context.Context, current, desired *extv1.CustomResourceDefinition) error {
+	storage := storageVersion(current)
+	desiredVersions := map[string]extv1.CustomResourceDefinitionVersion{}
+	for _, v := range desired.Spec.Versions {
+		desiredVersions[v.Name] = v
+	}
+
+	var problems []string
+
+	if removed := removedStoredVersions(current, desired); len(removed) > 0 {
+		// Custom resources stored at a removed version can only be migrated
+		// if the desired CRD keeps the current storage version.
+		if _, kept := desiredVersions[storage]; !g.migrate || !kept {
+			problems = append(problems, fmt.Sprintf("custom resources may be stored at removed versions %s", strings.Join(removed, ", ")))
+		}
+	}
+
+	// The remaining checks only matter if there are custom resources.
+	exist, err := g.customResourcesExist(ctx, current, storage)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return unsafe(current.GetName(), problems)
+	}
+
+	var unserved []string
+	for _, v := range current.Spec.Versions {
+		if v.Served && !desiredVersions[v.Name].Served {
+			unserved = append(unserved, v.Name)
+		}
+	}
+	if len(unserved) > 0 {
+		problems = append(problems, fmt.Sprintf("custom resources exist but versions %s would no longer be served", strings.Join(unserved, ", ")))
+	}
+
+	for _, cv := range current.Spec.Versions {
+		dv, ok := desiredVersions[cv.Name]
+		if !ok || cv.Schema == nil || dv.Schema == nil {
+			continue
+		}
+		t, changed := tightenedSchema("", cv.Schema.OpenAPIV3Schema, dv.Schema.OpenAPIV3Schema)
+		if len(t) > 0 {
+			problems = append(problems, fmt.Sprintf("custom resources exist but the schema of version %s would be stricter: %s", cv.Name, strings.Join(t, ", ")))
+		}
+		if len(changed) > 0 {
+			g.log.Info("Cannot tell whether CRD schema changes are stricter", "crd", current.GetName(), "version", cv.Name, "changes", changed)
+		}
+	}
+
+	return unsafe(current.GetName(), problems)
+}
+
+func (g *APICRDUpgradeGuard) customResourcesExist(ctx context.Context, crd *extv1.CustomResourceDefinition, version string) (bool, error) {
+	kind := crd.Spec.Names.ListKind
+	if kind == "" {
+		kind = crd.Spec.Names.Kind + "List"
+	}
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(schema.GroupVersionKind{Group: crd.Spec.Group, Version: version, Kind: kind})
+	if err := g.client.List(ctx, l, client.Limit(1)); err != nil {
+		return false, errors.Wrapf(err, errFmtListCustomResources, crd.GetName())
+	}
+	return len(l.Items) > 0, nil
+}
+
+func unsafe(crd string, problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return &UnsafeCRDUpgradeError{CRD: crd, Problems: problems}
+}
+
+// A CRDStorageMigrator migrates the custom resources of a CRD to its storage
+// version before the CRD is updated to remove versions they may be stored at.
+type CRDStorageMigrator interface {
+	MigrateStorage(ctx context.Context, current, desired *extv1.CustomResourceDefinition) error
+}
+
+// A CRDStorageMigratorFn is a function that satisfies the CRDStorageMigrator
+// interface.
+type CRDStorageMigratorFn func(ctx context.Context, current, desired *extv1.CustomResourceDefinition) error
+
+// MigrateStorage migrates the custom resources of a CRD to its storage version.
+func (fn CRDStorageMigratorFn) MigrateStorage(ctx context.Context, current, desired *extv1.CustomResourceDefinition) error {
+	return fn(ctx, current, desired)
+}
+
+// An APICRDStorageMigrator migrates custom resources by rewriting each of them
+// at the CRD's current storage version, then removing every other version from
+// the CRD's stored versions.
+type APICRDStorageMigrator struct {
+	client client.Client
+}
+
+// NewAPICRDStorageMigrator returns a new APICRDStorageMigrator.
+func NewAPICRDStorageMigrator(c client.Client) *APICRDStorageMigrator {
+	return &APICRDStorageMigrator{client: c}
+}
+
+// MigrateStorage migrates the custom resources of the current CRD to its
+// storage version if the desired CRD removes versions they may be stored at.
+// It refreshes the current CRD once it's done.
+func (m *APICRDStorageMigrator) MigrateStorage(ctx context.Context, current, desired *extv1.CustomResourceDefinition) error {
+	if len(removedStoredVersions(current, desired)) == 0 {
+		return nil
+	}
+	storage := storageVersion(current)
+	if !hasVersion(desired, storage) {
+		return errors.Errorf(errFmtMigrateNoStorage, current.GetName(), storage)
+	}
+	return errors.Wrapf(initializer.MigrateStorageVersion(ctx, m.client, current), errFmtMigrateStorage, current.GetName(), storage)
+}
+
+// removedStoredVersions returns the versions custom resources of the current
+// CRD may be stored at that the desired CRD removes.
+func removedStoredVersions(current, desired *extv1.CustomResourceDefinition) []string {
+	var removed []string
+	for _, v := range current.Status.StoredVersions {
+		if !hasVersion(desired, v) {
+			removed = append(removed, v)
+		}
+	}
+	return removed
+}
+
+func hasVersion(crd *extv1.CustomResourceDefinition, version string) bool {
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version {
+			return true
+		}
+	}
+	return false
+}
+
+func storageVersion(crd *extv1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	return ""
+}
+
+// tightenedSchema returns a description of each way the desired schema is
+// stricter than the current schema, i.e. each way in which an object that's
+// valid according to the current schema may be invalid according to the
+// desired schema. It also returns a description of each change that may or
+// may not be stricter, such as a changed pattern.
+func tightenedSchema(path string, current, desired *extv1.JSONSchemaProps) (out, changed []string) { //nolint:gocognit // Only slightly over.
+	if current == nil || desired == nil {
+		return nil, nil
+	}
+	if current.Type != "" && desired.Type != current.Type {
+		return []string{fmt.Sprintf("%s type changed from %q to %q", fieldPath(path), current.Type, desired.Type)}, nil
+	}
+
+	required := map[string]bool{}
+	for _, r := range current.Required {
+		required[r] = true
+	}
+	for _, r := range desired.Required {
+		if !required[r] {
+			out = append(out, fmt.Sprintf("%s is newly required", fieldPath(path+"."+r)))
+		}
+	}
+
+	if len(desired.Enum) > 0 {
+		allowed := map[string]bool{}
+		for _, v := range desired.Enum {
+			allowed[string(v.Raw)] = true
+		}
+		if len(current.Enum) == 0 {
+			out = append(out, fmt.Sprintf("%s is newly restricted to an enum", fieldPath(path)))
+		}
+		for _, v := range current.Enum {
+			if !allowed[string(v.Raw)] {
+				out = append(out, fmt.Sprintf("%s no longer allows %s", fieldPath(path), v.Raw))
+			}
+		}
+	}
+
+	// We can't tell whether one pattern matches everything another does, so
+	// only a newly added pattern is definitely stricter.
+	switch {
+	case desired.Pattern == "" || desired.Pattern == current.Pattern:
+	case current.Pattern == "":
+		out = append(out, fmt.Sprintf("%s is newly restricted to pattern %q", fieldPath(path), desired.Pattern))
+	default:
+		changed = append(changed, fmt.Sprintf("%s pattern changed from %q to %q", fieldPath(path), current.Pattern, desired.Pattern))
+	}
+	if tighterMax(current.MaxLength, desired.MaxLength) || tighterMin(current.MinLength, desired.MinLength) {
+		out = append(out, fmt.Sprintf("%s allowed length is narrower", fieldPath(path)))
+	}
+	if tighterMax(current.MaxItems, desired.MaxItems) || tighterMin(current.MinItems, desired.MinItems) {
+		out = append(out, fmt.Sprintf("%s allowed number of items is narrower", fieldPath(path)))
+	}
+
+	names := make([]string, 0, len(current.Properties))
+	for name := range current.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cp := current.Properties[name]
+		dp, ok := desired.Properties[name]
+		if !ok {
+			// Unknown fields are pruned, not rejected, unless the desired
+			// schema preserves them.
+			if desired.XPreserveUnknownFields == nil || !*desired.XPreserveUnknownFields {
+				out = append(out, fmt.Sprintf("%s was removed", fieldPath(path+"."+name)))
+			}
+			continue
+		}
+		t, c := tightenedSchema(path+"."+name, &cp, &dp)
+		out = append(out, t...)
+		changed = append(changed, c...)
+	}
+
+	if current.Items != nil && desired.Items != nil {
+		t, c := tightenedSchema(path+"[*]", current.Items.Schema, desired.Items.Schema)
+		out = append(out, t...)
+		changed = append(changed, c...)
+	}
+
+	return out, changed
+}
+
+func fieldPath(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
+
+func tighterMax(current, desired *int64) bool {
+	return desired != nil && (current == nil || *desired < *current)
+}
+
+func tighterMin(current, desired *int64) bool {
+	return desired != nil && (current == nil || *desired > *current)
+}
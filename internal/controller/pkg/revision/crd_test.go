@@ -0,0 +1,386 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestAPICRDUpgradeGuard(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type crdVersion struct {
+		name    string
+		served  bool
+		storage bool
+		schema  *extv1.JSONSchemaProps
+	}
+	crd := func(stored []string, versions ...crdVersion) *extv1.CustomResourceDefinition {
+		c := &extv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "nopresources.nop.crossplane.io"},
+			Spec: extv1.CustomResourceDefinitionSpec{
+				Group: "nop.crossplane.io",
+				Names: extv1.CustomResourceDefinitionNames{Kind: "NopResource", ListKind: "NopResourceList"},
+			},
+			Status: extv1.CustomResourceDefinitionStatus{StoredVersions: stored},
+		}
+		for _, v := range versions {
+			c.Spec.Versions = append(c.Spec.Versions, extv1.CustomResourceDefinitionVersion{
+				Name:    v.name,
+				Served:  v.served,
+				Storage: v.storage,
+				Schema:  &extv1.CustomResourceValidation{OpenAPIV3Schema: v.schema},
+			})
+		}
+		return c
+	}
+	list := func(n int) func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			l := obj.(*unstructured.UnstructuredList)
+			for range n {
+				l.Items = append(l.Items, unstructured.Unstructured{})
+			}
+			return nil
+		}
+	}
+	loose := &extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{
+		"spec": {Type: "object", Properties: map[string]extv1.JSONSchemaProps{"size": {Type: "string"}}},
+	}}
+	strict := &extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{
+		"spec": {Type: "object", Required: []string{"size"}, Properties: map[string]extv1.JSONSchemaProps{"size": {Type: "string"}}},
+	}}
+
+	type args struct {
+		kube    client.Client
+		o       []APICRDUpgradeGuardOption
+		current *extv1.CustomResourceDefinition
+		desired *extv1.CustomResourceDefinition
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"SafeUpgrade": {
+			reason: "We should allow an update that adds a version.",
+			args: args{
+				kube:    &test.MockClient{MockList: list(1)},
+				current: crd([]string{"v1"}, crdVersion{name: "v1", served: true, storage: true, schema: loose}),
+				desired: crd(nil, crdVersion{name: "v1", served: true, schema: loose}, crdVersion{name: "v2", served: true, storage: true, schema: loose}),
+			},
+		},
+		"RemovedStoredVersion": {
+			reason: "We should refuse to remove a version custom resources may be stored at.",
+			args: args{
+				kube:    &test.MockClient{MockList: list(0)},
+				current: crd([]string{"v1", "v2"}, crdVersion{name: "v1", served: true}, crdVersion{name: "v2", served: true, storage: true}),
+				desired: crd(nil, crdVersion{name: "v2", served: true, storage: true}),
+			},
+			want: &UnsafeCRDUpgradeError{
+				CRD:      "nopresources.nop.crossplane.io",
+				Problems: []string{"custom resources may be stored at removed versions v1"},
+			},
+		},
+		"RemovedStoredVersionWithMigration": {
+			reason: "We should allow removing a version custom resources may be stored at if they'll be migrated to the storage version.",
+			args: args{
+				kube:    &test.MockClient{MockList: list(0)},
+				o:       []APICRDUpgradeGuardOption{WithStorageVersionMigration()},
+				current: crd([]string{"v1", "v2"}, crdVersion{name: "v1", served: true}, crdVersion{name: "v2", served: true, storage: true}),
+				desired: crd(nil, crdVersion{name: "v2", served: true, storage: true}),
+			},
+		},
+		"RemovedStorageVersionWithMigration": {
+			reason: "We should refuse to remove the storage version even if custom resources will be migrated, because there's no version to migrate them to.",
+			args: args{
+				kube:    &test.MockClient{MockList: list(0)},
+				o:       []APICRDUpgradeGuardOption{WithStorageVersionMigration()},
+				current: crd([]string{"v1", "v2"}, crdVersion{name: "v1", served: true}, crdVersion{name: "v2", served: true, storage: true}),
+				desired: crd(nil, crdVersion{name: "v1", served: true, storage: true}),
+			},
+			want: &UnsafeCRDUpgradeError{
+				CRD:      "nopresources.nop.crossplane.io",
+				Problems: []string{"custom resources may be stored at removed versions v2"},
+			},
+		},
+		"UnservedVersionWithoutCustomResources": {
+			reason: "We should allow an update that stops serving a version if there are no custom resources.",
+			args: args{
+				kube:    &test.MockClient{MockList: list(0)},
+				current: crd([]string{"v2"}, crdVersion{name: "v1", served: true}, crdVersion{name: "v2", served: true, storage: true}),
+				desired: crd(nil, crdVersion{name: "v1"}, crdVersion{name: "v2", served: true, storage: true}),
+			},
+		},
+		"UnservedVersionWithCustomResources": {
+			reason: "We should refuse to stop serving a version while custom resources exist.",
+			args: args{
+				kube:    &test.MockClient{MockList: list(1)},
+				current: crd([]string{"v2"}, crdVersion{name: "v1", served: true}, crdVersion{name: "v2", served: true, storage: true}),
+				desired: crd(nil, crdVersion{name: "v1"}, crdVersion{name: "v2", served: true, storage: true}),
+			},
+			want: &UnsafeCRDUpgradeError{
+				CRD:      "nopresources.nop.crossplane.io",
+				Problems: []string{"custom resources exist but versions v1 would no longer be served"},
+			},
+		},
+		"TightenedSchemaWithCustomResources": {
+			reason: "We should refuse to make a schema stricter while custom resources exist.",
+			args: args{
+				kube:    &test.MockClient{MockList: list(1)},
+				current: crd([]string{"v1"}, crdVersion{name: "v1", served: true, storage: true, schema: loose}),
+				desired: crd(nil, crdVersion{name: "v1", served: true, storage: true, schema: strict}),
+			},
+			want: &UnsafeCRDUpgradeError{
+				CRD:      "nopresources.nop.crossplane.io",
+				Problems: []string{"custom resources exist but the schema of version v1 would be stricter: .spec.size is newly required"},
+			},
+		},
+		"ListError": {
+			reason: "We should return an error if we can't tell whether custom resources exist.",
+			args: args{
+				kube:    &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				current: crd([]string{"v1"}, crdVersion{name: "v1", served: true, storage: true}),
+				desired: crd(nil, crdVersion{name: "v1", served: true, storage: true}),
+			},
+			want: errors.Wrapf(errBoom, errFmtListCustomResources, "nopresources.nop.crossplane.io"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			g := NewAPICRDUpgradeGuard(tc.args.kube, tc.args.o...)
+			err := g.Guard(context.Background(), tc.args.current, tc.args.desired)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGuard(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTightenedSchema(t *testing.T) {
+	props := func(p extv1.JSONSchemaProps) *extv1.JSONSchemaProps { return &p }
+	enum := func(vals ...string) []extv1.JSON {
+		out := make([]extv1.JSON, len(vals))
+		for i, v := range vals {
+			out[i] = extv1.JSON{Raw: []byte(`"` + v + `"`)}
+		}
+		return out
+	}
+
+	type args struct {
+		current *extv1.JSONSchemaProps
+		desired *extv1.JSONSchemaProps
+	}
+	type want struct {
+		tightened []string
+		changed   []string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Unchanged": {
+			reason: "An unchanged schema isn't stricter.",
+			args: args{
+				current: props(extv1.JSONSchemaProps{Type: "string"}),
+				desired: props(extv1.JSONSchemaProps{Type: "string"}),
+			},
+		},
+		"Loosened": {
+			reason: "Removing a required field and widening an enum isn't stricter.",
+			args: args{
+				current: props(extv1.JSONSchemaProps{Type: "object", Required: []string{"a"}, Properties: map[string]extv1.JSONSchemaProps{
+					"a": {Type: "string", Enum: enum("x")},
+				}}),
+				desired: props(extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{
+					"a": {Type: "string", Enum: enum("x", "y")},
+				}}),
+			},
+		},
+		"TypeChanged": {
+			reason: "Changing a field's type is stricter.",
+			args: args{
+				current: props(extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{"a": {Type: "string"}}}),
+				desired: props(extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{"a": {Type: "integer"}}}),
+			},
+			want: want{tightened: []string{`.a type changed from "string" to "integer"`}},
+		},
+		"NarrowedEnum": {
+			reason: "Removing an allowed enum value is stricter.",
+			args: args{
+				current: props(extv1.JSONSchemaProps{Type: "string", Enum: enum("x", "y")}),
+				desired: props(extv1.JSONSchemaProps{Type: "string", Enum: enum("x")}),
+			},
+			want: want{tightened: []string{`. no longer allows "y"`}},
+		},
+		"RemovedField": {
+			reason: "Removing a field is stricter unless unknown fields are preserved.",
+			args: args{
+				current: props(extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{
+					"a": {Type: "object", Properties: map[string]extv1.JSONSchemaProps{"b": {Type: "string"}}},
+					"c": {Type: "object", Properties: map[string]extv1.JSONSchemaProps{"d": {Type: "string"}}},
+				}}),
+				desired: props(extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{
+					"a": {Type: "object"},
+					"c": {Type: "object", XPreserveUnknownFields: ptr.To(true)},
+				}}),
+			},
+			want: want{tightened: []string{".a.b was removed"}},
+		},
+		"ArrayItems": {
+			reason: "Making the items of an array stricter is stricter.",
+			args: args{
+				current: props(extv1.JSONSchemaProps{Type: "array", Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{Type: "string"}}}),
+				desired: props(extv1.JSONSchemaProps{Type: "array", MaxItems: ptr.To[int64](3), Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{Type: "string", MaxLength: ptr.To[int64](8)}}}),
+			},
+			want: want{tightened: []string{". allowed number of items is narrower", "[*] allowed length is narrower"}},
+		},
+		"PatternAdded": {
+			reason: "Adding a pattern is stricter.",
+			args: args{
+				current: props(extv1.JSONSchemaProps{Type: "string"}),
+				desired: props(extv1.JSONSchemaProps{Type: "string", Pattern: "^[a-z]+$"}),
+			},
+			want: want{tightened: []string{`. is newly restricted to pattern "^[a-z]+$"`}},
+		},
+		"PatternRemoved": {
+			reason: "Removing a pattern isn't stricter.",
+			args: args{
+				current: props(extv1.JSONSchemaProps{Type: "string", Pattern: "^[a-z]+$"}),
+				desired: props(extv1.JSONSchemaProps{Type: "string"}),
+			},
+		},
+		"PatternChanged": {
+			reason: "We can't tell whether changing a pattern is stricter.",
+			args: args{
+				current: props(extv1.JSONSchemaProps{Type: "string", Pattern: "^[a-z]+$"}),
+				desired: props(extv1.JSONSchemaProps{Type: "string", Pattern: "^[a-z0-9]+$"}),
+			},
+			want: want{changed: []string{`. pattern changed from "^[a-z]+$" to "^[a-z0-9]+$"`}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, changed := tightenedSchema("", tc.args.current, tc.args.desired)
+			if diff := cmp.Diff(tc.want.tightened, got); diff != "" {
+				t.Errorf("\n%s\ntightenedSchema(...): -want tightened, +got tightened:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.changed, changed); diff != "" {
+				t.Errorf("\n%s\ntightenedSchema(...): -want changed, +got changed:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAPICRDStorageMigrator(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	crd := func(stored []string, versions ...string) *extv1.CustomResourceDefinition {
+		c := &extv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "nopresources.nop.crossplane.io"},
+			Spec: extv1.CustomResourceDefinitionSpec{
+				Group: "nop.crossplane.io",
+				Names: extv1.CustomResourceDefinitionNames{Kind: "NopResource", ListKind: "NopResourceList"},
+			},
+			Status: extv1.CustomResourceDefinitionStatus{StoredVersions: stored},
+		}
+		for i, v := range versions {
+			// The last version is the storage version.
+			c.Spec.Versions = append(c.Spec.Versions, extv1.CustomResourceDefinitionVersion{Name: v, Served: true, Storage: i == len(versions)-1})
+		}
+		return c
+	}
+
+	type args struct {
+		kube    client.Client
+		current *extv1.CustomResourceDefinition
+		desired *extv1.CustomResourceDefinition
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NoRemovedStoredVersions": {
+			reason: "We shouldn't migrate anything if the desired CRD keeps every stored version.",
+			args: args{
+				kube:    &test.MockClient{},
+				current: crd([]string{"v1", "v2"}, "v1", "v2"),
+				desired: crd(nil, "v1", "v2", "v3"),
+			},
+		},
+		"Migrate": {
+			reason: "We should migrate custom resources to the storage version before removing a version they may be stored at.",
+			args: args{
+				kube: &test.MockClient{
+					MockList:        test.NewMockListFn(nil),
+					MockStatusPatch: test.NewMockSubResourcePatchFn(nil),
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.(*extv1.CustomResourceDefinition).Status.StoredVersions = []string{"v2"}
+						return nil
+					}),
+				},
+				current: crd([]string{"v1", "v2"}, "v1", "v2"),
+				desired: crd(nil, "v2"),
+			},
+		},
+		"MigrateError": {
+			reason: "We should return an error if we can't migrate custom resources.",
+			args: args{
+				kube: &test.MockClient{
+					MockList:        test.NewMockListFn(nil),
+					MockStatusPatch: test.NewMockSubResourcePatchFn(errBoom),
+				},
+				current: crd([]string{"v1", "v2"}, "v1", "v2"),
+				desired: crd(nil, "v2"),
+			},
+			want: errors.Wrapf(errors.Wrapf(errBoom, "couldn't update %s crd", "nopresources.nop.crossplane.io"), errFmtMigrateStorage, "nopresources.nop.crossplane.io", "v2"),
+		},
+		"RemovedStorageVersion": {
+			reason: "We can't migrate custom resources if the desired CRD removes the storage version.",
+			args: args{
+				kube:    &test.MockClient{},
+				current: crd([]string{"v1", "v2"}, "v1", "v2"),
+				desired: crd(nil, "v1"),
+			},
+			want: errors.Errorf(errFmtMigrateNoStorage, "nopresources.nop.crossplane.io", "v2"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewAPICRDStorageMigrator(tc.args.kube)
+			err := m.MigrateStorage(context.Background(), tc.args.current, tc.args.desired)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nMigrateStorage(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
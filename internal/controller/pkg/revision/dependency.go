@@ -85,29 +85,9 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, meta pkgmetav1.P
 	}
 
 	// Copy package dependencies into Lock Dependencies.
-	sources := make([]v1beta1.Dependency, len(meta.GetDependencies()))
-	for i, dep := range meta.GetDependencies() {
-		pdep := v1beta1.Dependency{}
-		switch {
-		// If the GVK and package are specified explicitly they take precedence.
-		case dep.APIVersion != nil && dep.Kind != nil && dep.Package != nil:
-			pdep.APIVersion = dep.APIVersion
-			pdep.Kind = dep.Kind
-			pdep.Package = *dep.Package
-		case dep.Configuration != nil:
-			pdep.Package = *dep.Configuration
-			pdep.Type = ptr.To(v1beta1.ConfigurationPackageType)
-		case dep.Provider != nil:
-			pdep.Package = *dep.Provider
-			pdep.Type = ptr.To(v1beta1.ProviderPackageType)
-		case dep.Function != nil:
-			pdep.Package = *dep.Function
-			pdep.Type = ptr.To(v1beta1.FunctionPackageType)
-		default:
-			return 0, 0, 0, errors.Errorf("encountered an invalid dependency: package dependencies must specify either a valid type, or an explicit apiVersion, kind, and package")
-		}
-		pdep.Constraints = dep.Version
-		sources[i] = pdep
+	sources, err := xpkg.LockDependencies(meta)
+	if err != nil {
+		return 0, 0, 0, err
 	}
 
 	found = len(sources)
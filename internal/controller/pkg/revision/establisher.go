@@ -87,22 +87,50 @@ func (*NopEstablisher) ReleaseObjects(_ context.Context, _ v1.PackageRevision) e
 type APIEstablisher struct {
 	client                           client.Client
 	namespace                        string
+	crds                             CRDUpgradeGuard
+	migrator                         CRDStorageMigrator
 	MaxConcurrentPackageEstablishers int
 }
 
+// An APIEstablisherOption configures an APIEstablisher.
+type APIEstablisherOption func(e *APIEstablisher)
+
+// WithCRDUpgradeGuard configures how an APIEstablisher checks whether it's
+// safe to update a CRD it controls.
+func WithCRDUpgradeGuard(g CRDUpgradeGuard) APIEstablisherOption {
+	return func(e *APIEstablisher) {
+		e.crds = g
+	}
+}
+
+// WithCRDStorageMigrator configures how an APIEstablisher migrates the custom
+// resources of a CRD it controls before updating the CRD to remove versions
+// they may be stored at. An APIEstablisher doesn't migrate custom resources
+// unless a CRDStorageMigrator is configured.
+func WithCRDStorageMigrator(m CRDStorageMigrator) APIEstablisherOption {
+	return func(e *APIEstablisher) {
+		e.migrator = m
+	}
+}
+
 // NewAPIEstablisher creates a new APIEstablisher.
-func NewAPIEstablisher(client client.Client, namespace string, maxConcurrentPackageEstablishers int) *APIEstablisher {
-	return &APIEstablisher{
+func NewAPIEstablisher(client client.Client, namespace string, maxConcurrentPackageEstablishers int, o ...APIEstablisherOption) *APIEstablisher {
+	e := &APIEstablisher{
 		client:                           client,
 		namespace:                        namespace,
+		crds:                             NewNopCRDUpgradeGuard(),
 		MaxConcurrentPackageEstablishers: maxConcurrentPackageEstablishers,
 	}
+	for _, fn := range o {
+		fn(e)
+	}
+	return e
 }
 
-// currentDesired caches resources while checking for control or ownership so
+// CurrentDesired caches resources while checking for control or ownership so
 // that they do not have to be fetched from the API server again when control or
 // ownership is established.
-type currentDesired struct {
+type CurrentDesired struct {
 	Current resource.Object
 	Desired resource.Object
 	Exists  bool
@@ -115,10 +143,22 @@ func (e *APIEstablisher) Establish(ctx context.Context, objs []runtime.Object, p
 	if err != nil {
 		return nil, err
 	}
-	allObjs, err := e.validate(ctx, objs, parent, control)
+	if control {
+		if err := e.guardCRDs(ctx, objs); err != nil {
+			return nil, err
+		}
+	}
+	allObjs, err := e.Validate(ctx, objs, parent, control)
 	if err != nil {
 		return nil, err
 	}
+	if control {
+		// Only migrate custom resources once we know we can establish
+		// every resource. Migration rewrites every custom resource.
+		if err := e.migrateCRDs(ctx, allObjs); err != nil {
+			return nil, err
+		}
+	}
 
 	resourceRefs, err := e.establish(ctx, allObjs, parent, control)
 	if err != nil {
@@ -202,6 +242,81 @@ func (e *APIEstablisher) ReleaseObjects(ctx context.Context, parent v1.PackageRe
 	return g.Wait()
 }
 
+// guardCRDs checks that each CRD that exists can safely be updated to the
+// desired CRD.
+func (e *APIEstablisher) guardCRDs(ctx context.Context, objs []runtime.Object) error {
+	for _, obj := range objs {
+		desired, ok := obj.(*extv1.CustomResourceDefinition)
+		if !ok {
+			continue
+		}
+		current := &extv1.CustomResourceDefinition{}
+		if err := e.client.Get(ctx, types.NamespacedName{Name: desired.GetName()}, current); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, errFmtGetOwnedObject, desired.Kind, desired.GetName())
+		}
+		if err := e.crds.Guard(ctx, current, desired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateCRDs migrates the custom resources of each CRD that exists to its
+// storage version, if the desired CRD removes versions they may be stored at.
+func (e *APIEstablisher) migrateCRDs(ctx context.Context, allObjs []CurrentDesired) error {
+	if e.migrator == nil {
+		return nil
+	}
+	for _, cd := range allObjs {
+		current, ok := cd.Current.(*extv1.CustomResourceDefinition)
+		if !ok || !cd.Exists {
+			continue
+		}
+		desired, ok := cd.Desired.(*extv1.CustomResourceDefinition)
+		if !ok {
+			continue
+		}
+		if err := e.migrator.MigrateStorage(ctx, current, desired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dryRunObject returns the object to dry run an update to. The API server
+// won't let a CRD remove a version custom resources may be stored at. If we'll
+// migrate those custom resources before updating the CRD, we dry run the
+// update with those versions kept but no longer served.
+func (e *APIEstablisher) dryRunObject(current, desired resource.Object) resource.Object {
+	cc, ok := current.(*extv1.CustomResourceDefinition)
+	if !ok || e.migrator == nil {
+		return desired
+	}
+	dc, ok := desired.(*extv1.CustomResourceDefinition)
+	if !ok {
+		return desired
+	}
+	removed := removedStoredVersions(cc, dc)
+	if len(removed) == 0 {
+		return desired
+	}
+	dry := dc.DeepCopy()
+	for _, v := range cc.Spec.Versions {
+		for _, r := range removed {
+			if v.Name != r {
+				continue
+			}
+			v.Served = false
+			v.Storage = false
+			dry.Spec.Versions = append(dry.Spec.Versions, v)
+		}
+	}
+	return dry
+}
+
 func (e *APIEstablisher) addLabels(objs []runtime.Object, parent v1.PackageRevision) error {
 	commonLabels := parent.GetCommonLabels()
 	for _, obj := range objs {
@@ -222,7 +337,11 @@ func (e *APIEstablisher) addLabels(objs []runtime.Object, parent v1.PackageRevis
 	return nil
 }
 
-func (e *APIEstablisher) validate(ctx context.Context, objs []runtime.Object, parent v1.PackageRevision, control bool) (allObjs []currentDesired, err error) { //nolint:gocognit // TODO(negz): Refactor this to break up complexity.
+// Validate checks that control or ownership of resources can be established
+// by parent without establishing it. Resources are created or updated using a
+// server-side dry run. It returns each resource as it exists in the API server
+// and as parent desires it to be.
+func (e *APIEstablisher) Validate(ctx context.Context, objs []runtime.Object, parent v1.PackageRevision, control bool) (allObjs []CurrentDesired, err error) { //nolint:gocognit // TODO(negz): Refactor this to break up complexity.
 	var webhookTLSCert []byte
 	if parentWithRuntime, ok := parent.(v1.PackageRevisionWithRuntime); ok && control {
 		webhookTLSCert, err = e.getWebhookTLSCert(ctx, parentWithRuntime)
@@ -233,7 +352,7 @@ func (e *APIEstablisher) validate(ctx context.Context, objs []runtime.Object, pa
 
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(e.MaxConcurrentPackageEstablishers)
-	out := make(chan currentDesired, len(objs))
+	out := make(chan CurrentDesired, len(objs))
 	for _, res := range objs {
 		g.Go(func() error {
 			// Assert desired object to resource.Object so that we can access its
@@ -273,19 +392,19 @@ func (e *APIEstablisher) validate(ctx context.Context, objs []runtime.Object, pa
 				}
 				// Add to objects as not existing.
 				select {
-				case out <- currentDesired{Desired: desired, Current: nil, Exists: false}:
+				case out <- CurrentDesired{Desired: desired, Current: nil, Exists: false}:
 					return nil
 				case <-ctx.Done():
 					return ctx.Err()
 				}
 			}
 
-			if err := e.update(ctx, current, desired, parent, control, client.DryRunAll); err != nil {
+			if err := e.update(ctx, current, e.dryRunObject(current, desired), parent, control, client.DryRunAll); err != nil {
 				return err
 			}
 			// Add to objects as existing.
 			select {
-			case out <- currentDesired{Desired: desired, Current: current, Exists: true}:
+			case out <- CurrentDesired{Desired: desired, Current: current, Exists: true}:
 				return nil
 			case <-ctx.Done():
 				return ctx.Err()
@@ -387,7 +506,7 @@ func (e *APIEstablisher) getWebhookTLSCert(ctx context.Context, parentWithRuntim
 	return webhookTLSCert, nil
 }
 
-func (e *APIEstablisher) establish(ctx context.Context, allObjs []currentDesired, parent client.Object, control bool) ([]xpv1.TypedReference, error) {
+func (e *APIEstablisher) establish(ctx context.Context, allObjs []CurrentDesired, parent client.Object, control bool) ([]xpv1.TypedReference, error) {
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(e.MaxConcurrentPackageEstablishers)
 	out := make(chan xpv1.TypedReference, len(allObjs))
@@ -428,6 +428,86 @@ func TestAPIEstablisherEstablish(t *testing.T) {
 				err: errBoom,
 			},
 		},
+		"UnsafeCRDUpgrade": {
+			reason: "Cannot establish control of a CRD if updating it is unsafe.",
+			args: args{
+				est: NewAPIEstablisher(&test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				}, "", 10, WithCRDUpgradeGuard(CRDUpgradeGuardFn(func(_ context.Context, _, _ *extv1.CustomResourceDefinition) error {
+					return errBoom
+				}))),
+				objs: []runtime.Object{
+					&extv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "ref-me",
+						},
+					},
+				},
+				parent: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+				},
+				control: true,
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
+		"NoMigrationIfValidationFails": {
+			reason: "We shouldn't migrate custom resources unless every resource can be established.",
+			args: args{
+				est: NewAPIEstablisher(&test.MockClient{
+					MockGet:    test.NewMockGetFn(nil),
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				}, "", 10, WithCRDStorageMigrator(CRDStorageMigratorFn(func(_ context.Context, _, _ *extv1.CustomResourceDefinition) error {
+					return errors.New("this shouldn't be called")
+				}))),
+				objs: []runtime.Object{
+					&extv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "ref-me",
+						},
+					},
+				},
+				parent: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+				},
+				control: true,
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
+		"MigrateError": {
+			reason: "Cannot establish control of a CRD if we can't migrate its custom resources.",
+			args: args{
+				est: NewAPIEstablisher(&test.MockClient{
+					MockGet:    test.NewMockGetFn(nil),
+					MockUpdate: test.NewMockUpdateFn(nil),
+				}, "", 10, WithCRDStorageMigrator(CRDStorageMigratorFn(func(_ context.Context, _, _ *extv1.CustomResourceDefinition) error {
+					return errBoom
+				}))),
+				objs: []runtime.Object{
+					&extv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "ref-me",
+						},
+					},
+				},
+				parent: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+				},
+				control: true,
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -830,6 +910,7 @@ func TestGetPackageOwnerReference(t *testing.T) {
 func newAPIEstablisher(client client.Client) *APIEstablisher {
 	return &APIEstablisher{
 		client:                           client,
+		crds:                             NewNopCRDUpgradeGuard(),
 		MaxConcurrentPackageEstablishers: 10, // Use the current default
 	}
 }
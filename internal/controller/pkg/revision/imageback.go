@@ -37,6 +37,7 @@ const (
 	errBadReference            = "package tag is not a valid reference"
 	errFetchPackage            = "failed to fetch package from remote"
 	errGetManifest             = "failed to get package image manifest from remote"
+	errGetDigest               = "failed to get package image digest"
 	errFetchLayer              = "failed to fetch annotated base layer from remote"
 	errGetUncompressed         = "failed to get uncompressed contents from layer"
 	errMultipleAnnotatedLayers = "package is invalid due to multiple annotated base layers"
@@ -53,6 +54,23 @@ const (
 	maxLayers = 256
 )
 
+// An imageReadCloser reads package content extracted from an image.
+type imageReadCloser struct {
+	io.ReadCloser
+
+	// digest of the image the content was extracted from.
+	digest string
+}
+
+// imageDigest returns the digest of the image the supplied package content
+// was extracted from, if known.
+func imageDigest(rc io.ReadCloser) string {
+	if i, ok := rc.(*imageReadCloser); ok {
+		return i.digest
+	}
+	return ""
+}
+
 // ImageBackend is a backend for parser.
 type ImageBackend struct {
 	registry string
@@ -109,6 +127,10 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 	if err != nil {
 		return nil, errors.Wrap(err, errFetchPackage)
 	}
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, errors.Wrap(err, errGetDigest)
+	}
 	// Get image manifest.
 	manifest, err := img.Manifest()
 	if err != nil {
@@ -177,7 +199,7 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 	// resources allocated to the underlying ReadCloser. See
 	// https://github.com/google/go-containerregistry/blob/329563766ce8131011c25fd8758a25d94d9ad81b/pkg/v1/mutate/mutate.go#L222
 	// for more info.
-	return xpkg.JoinedReadCloser(t, tarc), nil
+	return &imageReadCloser{ReadCloser: xpkg.JoinedReadCloser(t, tarc), digest: digest.String()}, nil
 }
 
 // nestedBackend is a nop parser backend that conforms to the parser backend
@@ -25,6 +25,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/go-containerregistry/pkg/name"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/kubernetes"
@@ -74,8 +75,9 @@ const (
 	errAddFinalizer    = "cannot add package revision finalizer"
 	errRemoveFinalizer = "cannot remove package revision finalizer"
 
-	errGetPullConfig = "cannot get image pull secret from config"
-	errRewriteImage  = "cannot rewrite image path using config"
+	errGetPullConfig  = "cannot get image pull secret from config"
+	errRewriteImage   = "cannot rewrite image path using config"
+	errParseImagePath = "cannot parse image path"
 
 	errDeactivateRevision = "cannot deactivate package revision"
 
@@ -255,6 +257,21 @@ type Reconciler struct {
 	newPackageRevision func() v1.PackageRevision
 }
 
+// establisherOptions returns the options of the APIEstablisher used by each
+// package revision reconciler.
+func establisherOptions(c client.Client, l logging.Logger, f *feature.Flags) []APIEstablisherOption {
+	if !f.Enabled(features.EnableAlphaSafeCRDUpgrades) {
+		return nil
+	}
+	if !f.Enabled(features.EnableAlphaCRDStorageVersionMigration) {
+		return []APIEstablisherOption{WithCRDUpgradeGuard(NewAPICRDUpgradeGuard(c, WithGuardLogger(l)))}
+	}
+	return []APIEstablisherOption{
+		WithCRDUpgradeGuard(NewAPICRDUpgradeGuard(c, WithGuardLogger(l), WithStorageVersionMigration())),
+		WithCRDStorageMigrator(NewAPICRDStorageMigrator(c)),
+	}
+}
+
 // SetupProviderRevision adds a controller that reconciles ProviderRevisions.
 func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 	name := "packages/" + strings.ToLower(v1.ProviderRevisionGroupKind)
@@ -289,7 +306,7 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 	ro := []ReconcilerOption{
 		WithCache(o.Cache),
 		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1.ProviderGroupVersionKind, log)),
-		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers)),
+		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers, establisherOptions(mgr.GetClient(), o.Logger, o.Features)...)),
 		WithNewPackageRevisionFn(nr),
 		WithParser(parser.New(metaScheme, objScheme)),
 		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
@@ -334,7 +351,7 @@ func SetupConfigurationRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithCache(o.Cache),
 		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1.ConfigurationGroupVersionKind, log)),
 		WithNewPackageRevisionFn(nr),
-		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers)),
+		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers, establisherOptions(mgr.GetClient(), o.Logger, o.Features)...)),
 		WithParser(parser.New(metaScheme, objScheme)),
 		WithParserBackend(NewImageBackend(f, WithDefaultRegistry(o.DefaultRegistry))),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
@@ -389,7 +406,7 @@ func SetupFunctionRevision(mgr ctrl.Manager, o controller.Options) error {
 	ro := []ReconcilerOption{
 		WithCache(o.Cache),
 		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1.FunctionGroupVersionKind, log)),
-		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers)),
+		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers, establisherOptions(mgr.GetClient(), o.Logger, o.Features)...)),
 		WithNewPackageRevisionFn(nr),
 		WithParser(parser.New(metaScheme, objScheme)),
 		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
@@ -531,6 +548,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		pr.ClearAppliedImageConfigRef(v1.ImageConfigReasonRewrite)
 	}
 
+	// A revision that's pinned to a digest pulls its image by that digest,
+	// so that the image it runs doesn't change if its tag is pushed again.
+	if d := pr.GetDigest(); d != "" {
+		ref, err := name.ParseReference(imagePath)
+		if err != nil {
+			err = errors.Wrap(err, errParseImagePath)
+			status.MarkConditions(v1.RevisionUnhealthy().WithMessage(err.Error()))
+			_ = r.client.Status().Update(ctx, pr)
+
+			return reconcile.Result{}, err
+		}
+		imagePath = xpkg.ParsePackageSourceFromReference(ref) + "@" + d
+	}
+
 	// Ensure the rewritten image path is persisted before we proceed.
 	if pr.GetResolvedSource() != imagePath {
 		pr.SetResolvedSource(imagePath)
@@ -652,12 +683,25 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		id = pr.GetSource()
 	}
 
+	// We only read cached content that was extracted from the revision's
+	// image. We don't know which image that is until we've fetched it once,
+	// unless the revision is pinned to a digest. Content for a package with
+	// packagePullPolicy Never is pre-loaded by the user, so there's no image
+	// to verify it against.
+	digest := pr.GetDigest()
+	if digest == "" {
+		digest = pr.GetImageDigest()
+	}
+	if pullPolicyNever {
+		digest = ""
+	}
+
 	var rc io.ReadCloser
 	cacheWrite := make(chan error)
 
-	if r.cache.Has(id) {
+	if (pullPolicyNever || digest != "") && r.cache.Has(id, digest) {
 		var err error
-		rc, err = r.cache.Get(id)
+		rc, err = r.cache.Get(id, digest)
 		if err != nil {
 			// If package contents are in the cache, but we cannot access them,
 			// we clear them and try again.
@@ -704,12 +748,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			return reconcile.Result{}, err
 		}
 
+		// Record which image the package content was extracted from, so we
+		// can tell whether cached content was extracted from it.
+		digest = imageDigest(imgrc)
+		pr.SetImageDigest(digest)
+
 		// Package is not in cache, so we write it to the cache while parsing.
 		pipeR, pipeW := io.Pipe()
 		rc = xpkg.TeeReadCloser(imgrc, pipeW)
+		name := pr.GetName()
 		go func() {
 			defer pipeR.Close() //nolint:errcheck // Not much we can do if this fails.
-			if err := r.cache.Store(pr.GetName(), pipeR); err != nil {
+			if err := r.cache.Store(name, digest, pipeR); err != nil {
 				_ = pipeR.CloseWithError(err)
 				cacheWrite <- err
 				return
@@ -834,7 +884,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 
 		err = errors.Wrap(err, errEstablishControl)
-		status.MarkConditions(v1.RevisionUnhealthy().WithMessage(err.Error()))
+		c := v1.RevisionUnhealthy().WithMessage(err.Error())
+		uerr := &UnsafeCRDUpgradeError{}
+		if errors.As(err, &uerr) {
+			c = v1.RevisionUnsafeCRDUpgrade(err)
+		}
+		status.MarkConditions(c)
 		_ = r.client.Status().Update(ctx, pr)
 
 		r.record.Event(pr, event.Warning(reasonSync, err))
@@ -343,6 +343,7 @@ func TestReconcile(t *testing.T) {
 								pr := o.(*v1.ProviderRevision)
 								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
 								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetImageDigest("sha256:digest")
 								return nil
 							}),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(_ client.Object) error {
@@ -445,6 +446,7 @@ func TestReconcile(t *testing.T) {
 								pr := o.(*v1.ProviderRevision)
 								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
 								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetImageDigest("sha256:digest")
 								return nil
 							}),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(_ client.Object) error {
@@ -579,12 +581,14 @@ func TestReconcile(t *testing.T) {
 								pr := o.(*v1.ProviderRevision)
 								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
 								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetImageDigest("sha256:digest")
 								return nil
 							}),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
 								want := &v1.ProviderRevision{}
 								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetImageDigest("sha256:digest")
 								want.SetConditions(v1.RevisionUnhealthy().WithMessage("cannot parse package contents: boom"))
 
 								if diff := cmp.Diff(want, o); diff != "" {
@@ -787,7 +791,7 @@ func TestReconcile(t *testing.T) {
 					WithLinter(&MockLinter{MockLint: NewMockLintFn(errBoom)}),
 					WithCache(&xpkgfake.MockCache{
 						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
+						MockStore: func(_, _ string, rc io.ReadCloser) error {
 							_, err := io.ReadAll(rc)
 							return err
 						},
@@ -847,7 +851,7 @@ func TestReconcile(t *testing.T) {
 					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
 					WithCache(&xpkgfake.MockCache{
 						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
+						MockStore: func(_, _ string, rc io.ReadCloser) error {
 							_, err := io.ReadAll(rc)
 							return err
 						},
@@ -950,7 +954,7 @@ func TestReconcile(t *testing.T) {
 					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
 					WithCache(&xpkgfake.MockCache{
 						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
+						MockStore: func(_, _ string, rc io.ReadCloser) error {
 							_, err := io.ReadAll(rc)
 							return err
 						},
@@ -1017,7 +1021,7 @@ func TestReconcile(t *testing.T) {
 					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
 					WithCache(&xpkgfake.MockCache{
 						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
+						MockStore: func(_, _ string, rc io.ReadCloser) error {
 							_, err := io.ReadAll(rc)
 							return err
 						},
@@ -1082,7 +1086,7 @@ func TestReconcile(t *testing.T) {
 					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
 					WithCache(&xpkgfake.MockCache{
 						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
+						MockStore: func(_, _ string, rc io.ReadCloser) error {
 							_, err := io.ReadAll(rc)
 							return err
 						},
@@ -1139,7 +1143,7 @@ func TestReconcile(t *testing.T) {
 					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
 					WithCache(&xpkgfake.MockCache{
 						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
+						MockStore: func(_, _ string, rc io.ReadCloser) error {
 							_, err := io.ReadAll(rc)
 							return err
 						},
@@ -1156,6 +1160,51 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
+		"SuccessfulActiveRevisionPinnedDigest": {
+			reason: "A revision that's pinned to a digest should resolve its (rewritten) image to that digest.",
+			args: args{
+				mgr: &fake.Manager{},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ProviderRevision)
+								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.0.0")
+								pr.SetDigest("sha256:1234567")
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.0.0")
+								want.SetDigest("sha256:1234567")
+								want.SetResolvedSource("registry.example.org/crossplane/provider-nop@sha256:1234567")
+								want.SetAppliedImageConfigRefs(v1.ImageConfigRef{
+									Name:   "imageConfigName",
+									Reason: v1.ImageConfigReasonRewrite,
+								})
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithConfigStore(&xpkgfake.MockConfigStore{
+						MockPullSecretFor: xpkgfake.NewMockConfigStorePullSecretForFn("", "", nil),
+						MockRewritePath:   xpkgfake.NewMockRewritePathFn("imageConfigName", "registry.example.org/crossplane/provider-nop:v1.0.0", nil),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
 		"SuccessfulActiveRevisionImageConfigRewritten": {
 			reason: "An active revision should install when its image has been rewritten by an image config on a previous reconcile.",
 			args: args{
@@ -1220,7 +1269,7 @@ func TestReconcile(t *testing.T) {
 					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
 					WithCache(&xpkgfake.MockCache{
 						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
+						MockStore: func(_, _ string, rc io.ReadCloser) error {
 							_, err := io.ReadAll(rc)
 							return err
 						},
@@ -1289,7 +1338,7 @@ func TestReconcile(t *testing.T) {
 					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
 					WithCache(&xpkgfake.MockCache{
 						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
+						MockStore: func(_, _ string, rc io.ReadCloser) error {
 							_, err := io.ReadAll(rc)
 							return err
 						},
@@ -1355,7 +1404,7 @@ func TestReconcile(t *testing.T) {
 					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
 					WithCache(&xpkgfake.MockCache{
 						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
+						MockStore: func(_, _ string, rc io.ReadCloser) error {
 							_, err := io.ReadAll(rc)
 							return err
 						},
@@ -1493,7 +1542,7 @@ func TestReconcile(t *testing.T) {
 					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
 					WithCache(&xpkgfake.MockCache{
 						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
+						MockStore: func(_, _ string, rc io.ReadCloser) error {
 							_, err := io.ReadAll(rc)
 							return err
 						},
@@ -1617,7 +1666,7 @@ func TestReconcile(t *testing.T) {
 					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
 					WithCache(&xpkgfake.MockCache{
 						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
+						MockStore: func(_, _ string, rc io.ReadCloser) error {
 							_, err := io.ReadAll(rc)
 							return err
 						},
@@ -1679,7 +1728,7 @@ func TestReconcile(t *testing.T) {
 					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
 					WithCache(&xpkgfake.MockCache{
 						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
+						MockStore: func(_, _ string, rc io.ReadCloser) error {
 							_, err := io.ReadAll(rc)
 							return err
 						},
@@ -1841,7 +1890,7 @@ func TestReconcile(t *testing.T) {
 					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
 					WithCache(&xpkgfake.MockCache{
 						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
+						MockStore: func(_, _ string, rc io.ReadCloser) error {
 							_, err := io.ReadAll(rc)
 							return err
 						},
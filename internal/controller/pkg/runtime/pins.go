@@ -0,0 +1,217 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v2alpha1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xfn"
+)
+
+const (
+	errListCompositionRevisions           = "cannot list composition revisions"
+	errListNamespacedCompositionRevisions = "cannot list namespaced composition revisions"
+)
+
+const (
+	// pinnedFunctionsIndex is an index of the Functions a composition
+	// revision's pipeline steps pin to a specific revision.
+	pinnedFunctionsIndex = "pinnedFunctions"
+)
+
+// IndexPinnedFunctions is an IndexerFunc that assumes the passed object is a
+// CompositionRevision or NamespacedCompositionRevision. It returns the name of
+// every Function its pipeline steps pin to a specific revision.
+func IndexPinnedFunctions(o client.Object) []string {
+	var steps []apiextensionsv1.PipelineStep
+	switch cr := o.(type) {
+	case *apiextensionsv1.CompositionRevision:
+		steps = cr.Spec.Pipeline
+	case *v2alpha1.NamespacedCompositionRevision:
+		steps = cr.Spec.Pipeline
+	default:
+		return nil // should never happen
+	}
+
+	keys := make([]string, 0, len(steps))
+	seen := map[string]bool{}
+	for _, s := range steps {
+		if _, ok := xfn.RevisionSelectorFor(s.FunctionRef); !ok || seen[s.FunctionRef.Name] {
+			continue
+		}
+		seen[s.FunctionRef.Name] = true
+		keys = append(keys, s.FunctionRef.Name)
+	}
+	return keys
+}
+
+// A PinChecker checks whether a package revision is pinned. The runtime of a
+// pinned package revision keeps serving while the revision is inactive.
+type PinChecker interface {
+	IsPinned(ctx context.Context, pr v1.PackageRevisionWithRuntime) (bool, error)
+}
+
+// A PinCheckerFn checks whether a package revision is pinned.
+type PinCheckerFn func(ctx context.Context, pr v1.PackageRevisionWithRuntime) (bool, error)
+
+// IsPinned checks whether a package revision is pinned.
+func (fn PinCheckerFn) IsPinned(ctx context.Context, pr v1.PackageRevisionWithRuntime) (bool, error) {
+	return fn(ctx, pr)
+}
+
+// NopPinChecker is a PinChecker that never considers a package revision
+// pinned.
+type NopPinChecker struct{}
+
+// NewNopPinChecker creates a new NopPinChecker.
+func NewNopPinChecker() *NopPinChecker {
+	return &NopPinChecker{}
+}
+
+// IsPinned always returns false.
+func (n *NopPinChecker) IsPinned(_ context.Context, _ v1.PackageRevisionWithRuntime) (bool, error) {
+	return false, nil
+}
+
+// A CompositionPinChecker considers a FunctionRevision pinned if any
+// Composition pipeline step pins its Function to it. Its reader must index
+// composition revisions using IndexPinnedFunctions.
+type CompositionPinChecker struct {
+	client     client.Reader
+	namespaced bool
+}
+
+// A CompositionPinCheckerOption configures a CompositionPinChecker.
+type CompositionPinCheckerOption func(c *CompositionPinChecker)
+
+// WithNamespacedCompositions configures the CompositionPinChecker to also
+// consider the pipeline steps of NamespacedCompositions.
+func WithNamespacedCompositions() CompositionPinCheckerOption {
+	return func(c *CompositionPinChecker) {
+		c.namespaced = true
+	}
+}
+
+// NewCompositionPinChecker creates a new CompositionPinChecker.
+func NewCompositionPinChecker(c client.Reader, o ...CompositionPinCheckerOption) *CompositionPinChecker {
+	pc := &CompositionPinChecker{client: c}
+	for _, fn := range o {
+		fn(pc)
+	}
+	return pc
+}
+
+// IsPinned returns true if any revision of any Composition pins the supplied
+// FunctionRevision. A pin that specifies a version constraint only pins the
+// FunctionRevision it currently selects.
+func (c *CompositionPinChecker) IsPinned(ctx context.Context, pr v1.PackageRevisionWithRuntime) (bool, error) {
+	fn := pr.GetLabels()[v1.LabelParentPackage]
+
+	steps, err := c.pipelines(ctx, fn)
+	if err != nil {
+		return false, err
+	}
+
+	var sels []xfn.RevisionSelector
+	for _, s := range steps {
+		if s.FunctionRef.Name != fn {
+			continue
+		}
+		if sel, ok := xfn.RevisionSelectorFor(s.FunctionRef); ok {
+			sels = append(sels, sel)
+		}
+	}
+	if len(sels) == 0 {
+		return false, nil
+	}
+
+	l := &v1.FunctionRevisionList{}
+	if err := c.client.List(ctx, l, client.MatchingLabels{v1.LabelParentPackage: fn}); err != nil {
+		return false, errors.Wrap(err, errListFunctionRevisions)
+	}
+
+	for _, sel := range sels {
+		// A pin that doesn't select any revision doesn't pin this one.
+		rev, err := xfn.SelectRevision(l.Items, sel)
+		if err != nil {
+			continue
+		}
+		if rev.GetName() == pr.GetName() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// pipelines returns the pipeline steps of the composition revisions that pin
+// the supplied Function.
+func (c *CompositionPinChecker) pipelines(ctx context.Context, fn string) ([]apiextensionsv1.PipelineStep, error) {
+	crl := &apiextensionsv1.CompositionRevisionList{}
+	if err := c.client.List(ctx, crl, client.MatchingFields{pinnedFunctionsIndex: fn}); err != nil {
+		return nil, errors.Wrap(err, errListCompositionRevisions)
+	}
+
+	var steps []apiextensionsv1.PipelineStep
+	for _, cr := range crl.Items {
+		steps = append(steps, cr.Spec.Pipeline...)
+	}
+
+	if !c.namespaced {
+		return steps, nil
+	}
+
+	ncrl := &v2alpha1.NamespacedCompositionRevisionList{}
+	if err := c.client.List(ctx, ncrl, client.MatchingFields{pinnedFunctionsIndex: fn}); err != nil {
+		return nil, errors.Wrap(err, errListNamespacedCompositionRevisions)
+	}
+	for _, cr := range ncrl.Items {
+		steps = append(steps, cr.Spec.Pipeline...)
+	}
+
+	return steps, nil
+}
+
+// EnqueueFunctionRevisionsForPipeline enqueues the FunctionRevisions of each
+// Function a composition revision's pipeline pins to a specific revision.
+func EnqueueFunctionRevisionsForPipeline(kube client.Reader, log logging.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		var matches []reconcile.Request
+		for _, fn := range IndexPinnedFunctions(o) {
+			l := &v1.FunctionRevisionList{}
+			if err := kube.List(ctx, l, client.MatchingLabels{v1.LabelParentPackage: fn}); err != nil {
+				log.Debug("Cannot list function revisions while attempting to enqueue from composition revision", "error", err)
+				continue
+			}
+			for _, rev := range l.Items {
+				matches = append(matches, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&rev)})
+			}
+		}
+
+		return matches
+	})
+}
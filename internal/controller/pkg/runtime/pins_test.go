@@ -0,0 +1,293 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v2alpha1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestCompositionPinCheckerIsPinned(t *testing.T) {
+	revs := []v1.FunctionRevision{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "function-foo-1"},
+			Spec: v1.FunctionRevisionSpec{PackageRevisionSpec: v1.PackageRevisionSpec{
+				Package:  "xpkg.crossplane.io/crossplane-contrib/function-foo:v1.0.0",
+				Revision: 1,
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "function-foo-2"},
+			Spec: v1.FunctionRevisionSpec{PackageRevisionSpec: v1.PackageRevisionSpec{
+				Package:  "xpkg.crossplane.io/crossplane-contrib/function-foo:v1.1.0",
+				Revision: 2,
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "function-foo-3"},
+			Spec: v1.FunctionRevisionSpec{PackageRevisionSpec: v1.PackageRevisionSpec{
+				Package:      "xpkg.crossplane.io/crossplane-contrib/function-foo:v2.0.0",
+				Revision:     3,
+				DesiredState: v1.PackageRevisionActive,
+			}},
+		},
+	}
+
+	pipeline := func(refs ...apiextensionsv1.FunctionReference) func(client.ObjectList) error {
+		return func(l client.ObjectList) error {
+			switch l := l.(type) {
+			case *apiextensionsv1.CompositionRevisionList:
+				cr := apiextensionsv1.CompositionRevision{}
+				for _, ref := range refs {
+					cr.Spec.Pipeline = append(cr.Spec.Pipeline, apiextensionsv1.PipelineStep{FunctionRef: ref})
+				}
+				l.Items = []apiextensionsv1.CompositionRevision{cr}
+			case *v1.FunctionRevisionList:
+				l.Items = revs
+			}
+			return nil
+		}
+	}
+
+	type params struct {
+		client client.Reader
+		o      []CompositionPinCheckerOption
+	}
+	type args struct {
+		rev v1.PackageRevisionWithRuntime
+	}
+	type want struct {
+		pinned bool
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		params params
+		args   args
+		want   want
+	}{
+		"ErrListCompositionRevisions": {
+			reason: "We should return any error encountered listing composition revisions.",
+			params: params{
+				client: &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				},
+			},
+			args: args{
+				rev: &v1.FunctionRevision{ObjectMeta: metav1.ObjectMeta{Name: "function-foo-1", Labels: map[string]string{v1.LabelParentPackage: "function-foo"}}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListCompositionRevisions),
+			},
+		},
+		"ListByPinnedFunction": {
+			reason: "We should only list the composition revisions that pin the revision's function.",
+			params: params{
+				client: &test.MockClient{
+					MockList: func(_ context.Context, l client.ObjectList, opts ...client.ListOption) error {
+						if _, ok := l.(*apiextensionsv1.CompositionRevisionList); ok {
+							lo := &client.ListOptions{}
+							lo.ApplyOptions(opts)
+							want := fields.OneTermEqualSelector(pinnedFunctionsIndex, "function-foo").String()
+							if diff := cmp.Diff(want, lo.FieldSelector.String()); diff != "" {
+								t.Errorf("List(...): -want field selector, +got field selector:\n%s", diff)
+							}
+						}
+						return pipeline(apiextensionsv1.FunctionReference{Name: "function-foo", Revision: ptr.To("function-foo-1")})(l)
+					},
+				},
+			},
+			args: args{
+				rev: &v1.FunctionRevision{ObjectMeta: metav1.ObjectMeta{Name: "function-foo-1", Labels: map[string]string{v1.LabelParentPackage: "function-foo"}}},
+			},
+			want: want{
+				pinned: true,
+			},
+		},
+		"NotPinned": {
+			reason: "A revision shouldn't be pinned if no pipeline step pins its function.",
+			params: params{
+				client: &test.MockClient{
+					MockList: test.NewMockListFn(nil, pipeline(
+						apiextensionsv1.FunctionReference{Name: "function-foo"},
+						apiextensionsv1.FunctionReference{Name: "function-bar", Revision: ptr.To("function-foo-1")},
+					)),
+				},
+			},
+			args: args{
+				rev: &v1.FunctionRevision{ObjectMeta: metav1.ObjectMeta{Name: "function-foo-1", Labels: map[string]string{v1.LabelParentPackage: "function-foo"}}},
+			},
+			want: want{
+				pinned: false,
+			},
+		},
+		"PinnedByRevision": {
+			reason: "A revision should be pinned if a pipeline step pins its function to it by name.",
+			params: params{
+				client: &test.MockClient{
+					MockList: test.NewMockListFn(nil, pipeline(
+						apiextensionsv1.FunctionReference{Name: "function-foo", Revision: ptr.To("function-foo-1")},
+					)),
+				},
+			},
+			args: args{
+				rev: &v1.FunctionRevision{ObjectMeta: metav1.ObjectMeta{Name: "function-foo-1", Labels: map[string]string{v1.LabelParentPackage: "function-foo"}}},
+			},
+			want: want{
+				pinned: true,
+			},
+		},
+		"PinnedByVersion": {
+			reason: "A revision should be pinned if it's the highest version that satisfies a pipeline step's version constraint.",
+			params: params{
+				client: &test.MockClient{
+					MockList: test.NewMockListFn(nil, pipeline(
+						apiextensionsv1.FunctionReference{Name: "function-foo", Version: ptr.To("^1.0.0")},
+					)),
+				},
+			},
+			args: args{
+				rev: &v1.FunctionRevision{ObjectMeta: metav1.ObjectMeta{Name: "function-foo-2", Labels: map[string]string{v1.LabelParentPackage: "function-foo"}}},
+			},
+			want: want{
+				pinned: true,
+			},
+		},
+		"NotPinnedBySupersededVersion": {
+			reason: "A revision shouldn't be pinned if a higher version also satisfies a pipeline step's version constraint.",
+			params: params{
+				client: &test.MockClient{
+					MockList: test.NewMockListFn(nil, pipeline(
+						apiextensionsv1.FunctionReference{Name: "function-foo", Version: ptr.To("^1.0.0")},
+					)),
+				},
+			},
+			args: args{
+				rev: &v1.FunctionRevision{ObjectMeta: metav1.ObjectMeta{Name: "function-foo-1", Labels: map[string]string{v1.LabelParentPackage: "function-foo"}}},
+			},
+			want: want{
+				pinned: false,
+			},
+		},
+		"PinnedByNamespacedComposition": {
+			reason: "A revision should be pinned if a namespaced composition's pipeline step pins it, when namespaced compositions are enabled.",
+			params: params{
+				client: &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(l client.ObjectList) error {
+						switch l := l.(type) {
+						case *v2alpha1.NamespacedCompositionRevisionList:
+							l.Items = []v2alpha1.NamespacedCompositionRevision{{
+								Spec: v2alpha1.NamespacedCompositionRevisionSpec{
+									Pipeline: []apiextensionsv1.PipelineStep{{
+										FunctionRef: apiextensionsv1.FunctionReference{Name: "function-foo", Revision: ptr.To("function-foo-1")},
+									}},
+								},
+							}}
+						case *v1.FunctionRevisionList:
+							l.Items = revs
+						}
+						return nil
+					}),
+				},
+				o: []CompositionPinCheckerOption{WithNamespacedCompositions()},
+			},
+			args: args{
+				rev: &v1.FunctionRevision{ObjectMeta: metav1.ObjectMeta{Name: "function-foo-1", Labels: map[string]string{v1.LabelParentPackage: "function-foo"}}},
+			},
+			want: want{
+				pinned: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewCompositionPinChecker(tc.params.client, tc.params.o...)
+			pinned, err := c.IsPinned(context.Background(), tc.args.rev)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.IsPinned(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pinned, pinned); diff != "" {
+				t.Errorf("\n%s\nc.IsPinned(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIndexPinnedFunctions(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      client.Object
+		want   []string
+	}{
+		"NotACompositionRevision": {
+			reason: "We should return nothing for an object that isn't a composition revision.",
+			o:      &v1.FunctionRevision{},
+			want:   nil,
+		},
+		"CompositionRevision": {
+			reason: "We should return each function a composition revision's pipeline pins, once.",
+			o: &apiextensionsv1.CompositionRevision{
+				Spec: apiextensionsv1.CompositionRevisionSpec{
+					Pipeline: []apiextensionsv1.PipelineStep{
+						{FunctionRef: apiextensionsv1.FunctionReference{Name: "function-foo", Revision: ptr.To("function-foo-1")}},
+						{FunctionRef: apiextensionsv1.FunctionReference{Name: "function-bar"}},
+						{FunctionRef: apiextensionsv1.FunctionReference{Name: "function-baz", Version: ptr.To("^1.0.0")}},
+						{FunctionRef: apiextensionsv1.FunctionReference{Name: "function-foo", Revision: ptr.To("function-foo-1")}},
+					},
+				},
+			},
+			want: []string{"function-foo", "function-baz"},
+		},
+		"NamespacedCompositionRevision": {
+			reason: "We should return each function a namespaced composition revision's pipeline pins.",
+			o: &v2alpha1.NamespacedCompositionRevision{
+				Spec: v2alpha1.NamespacedCompositionRevisionSpec{
+					Pipeline: []apiextensionsv1.PipelineStep{
+						{FunctionRef: apiextensionsv1.FunctionReference{Name: "function-foo", Revision: ptr.To("function-foo-1")}},
+					},
+				},
+			},
+			want: []string{"function-foo"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IndexPinnedFunctions(tc.o)
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nIndexPinnedFunctions(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -40,6 +40,8 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v2alpha1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
@@ -60,6 +62,8 @@ const (
 	errManifestBuilderOptions = "cannot prepare runtime manifest builder options"
 	errPreHook                = "pre establish runtime hook failed for package"
 	errPostHook               = "post establish runtime hook failed for package"
+	errCheckPinned            = "cannot determine whether package revision is pinned"
+	errIndexPinnedFunctions   = "cannot index composition revisions by pinned functions"
 
 	errNoRuntimeConfig   = "no deployment runtime config set"
 	errGetRuntimeConfig  = "cannot get referenced deployment runtime config"
@@ -106,6 +110,15 @@ func WithRuntimeHooks(h Hooks) ReconcilerOption {
 	}
 }
 
+// WithEndpointHooks specifies how the Reconciler should perform preparations
+// and cleanup for packages whose DeploymentRuntimeConfig configures an
+// externally hosted endpoint rather than a Deployment.
+func WithEndpointHooks(h Hooks) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.endpointHook = h
+	}
+}
+
 // WithNamespace specifies the namespace in which the Reconciler should create
 // runtime resources.
 func WithNamespace(n string) ReconcilerOption {
@@ -136,15 +149,25 @@ func WithDeploymentSelectorMigrator(m DeploymentSelectorMigrator) ReconcilerOpti
 	}
 }
 
+// WithPinChecker specifies how to determine whether an inactive package
+// revision is pinned, and thus whether its runtime should keep serving.
+func WithPinChecker(p PinChecker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.pins = p
+	}
+}
+
 // Reconciler reconciles packages.
 type Reconciler struct {
 	client         client.Client
 	log            logging.Logger
 	runtimeHook    Hooks
+	endpointHook   Hooks
 	record         event.Recorder
 	conditions     conditions.Manager
 	features       *feature.Flags
 	migrator       DeploymentSelectorMigrator
+	pins           PinChecker
 	namespace      string
 	serviceAccount string
 
@@ -173,6 +196,7 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithNamespace(o.Namespace),
 		WithServiceAccount(o.ServiceAccount),
 		WithRuntimeHooks(NewProviderHooks(mgr.GetClient(), o.DefaultRegistry)),
+		WithEndpointHooks(NewEndpointHooks(mgr.GetClient(), NewAsyncEndpointHealthChecker(NewGRPCHealthChecker(o.ClientTLSConfig)))),
 		WithFeatureFlags(o.Features),
 		WithDeploymentSelectorMigrator(NewDeletingDeploymentSelectorMigrator(mgr.GetClient(), log)),
 	}
@@ -207,9 +231,26 @@ func SetupFunctionRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithNamespace(o.Namespace),
 		WithServiceAccount(o.ServiceAccount),
 		WithRuntimeHooks(NewFunctionHooks(mgr.GetClient(), o.DefaultRegistry)),
+		WithEndpointHooks(NewEndpointHooks(mgr.GetClient(), NewAsyncEndpointHealthChecker(NewGRPCHealthChecker(o.ClientTLSConfig)))),
 		WithFeatureFlags(o.Features),
 	}
 
+	// Composition pipeline steps may pin a Function to one of its inactive
+	// revisions, which then keeps serving.
+	var po []CompositionPinCheckerOption
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &apiextensionsv1.CompositionRevision{}, pinnedFunctionsIndex, IndexPinnedFunctions); err != nil {
+		return errors.Wrap(err, errIndexPinnedFunctions)
+	}
+	cb = cb.Watches(&apiextensionsv1.CompositionRevision{}, EnqueueFunctionRevisionsForPipeline(mgr.GetClient(), log))
+	if o.Features.Enabled(features.EnableAlphaNamespacedCompositions) {
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v2alpha1.NamespacedCompositionRevision{}, pinnedFunctionsIndex, IndexPinnedFunctions); err != nil {
+			return errors.Wrap(err, errIndexPinnedFunctions)
+		}
+		po = append(po, WithNamespacedCompositions())
+		cb = cb.Watches(&v2alpha1.NamespacedCompositionRevision{}, EnqueueFunctionRevisionsForPipeline(mgr.GetClient(), log))
+	}
+	ro = append(ro, WithPinChecker(NewCompositionPinChecker(mgr.GetClient(), po...)))
+
 	if o.Features.Enabled(features.EnableBetaDeploymentRuntimeConfigs) {
 		cb = cb.Watches(&v1beta1.DeploymentRuntimeConfig{}, EnqueuePackageRevisionsForRuntimeConfig(mgr.GetClient(), &v1.FunctionRevisionList{}, log))
 	}
@@ -226,6 +267,7 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 		record:     event.NewNopRecorder(),
 		conditions: conditions.ObservedGenerationPropagationManager{},
 		migrator:   NewNopDeploymentSelectorMigrator(),
+		pins:       NewNopPinChecker(),
 	}
 
 	for _, f := range opts {
@@ -306,7 +348,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	// Initialize the runtime manifest builder with the package revision
-	opts, err := r.builderOptions(ctx, pr)
+	opts, rc, err := r.builderOptions(ctx, pr)
 	if err != nil {
 		log.Debug(errManifestBuilderOptions, "error", err)
 		err = errors.Wrap(err, errManifestBuilderOptions)
@@ -320,9 +362,33 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 	builder := NewDeploymentRuntimeBuilder(pr, r.namespace, opts...)
 
-	// Deactivate revision if it is inactive.
+	// Packages served by an externally hosted endpoint don't need a
+	// Deployment, but we periodically check that the endpoint is healthy.
+	hooks := r.runtimeHook
+	var requeueAfter time.Duration
+	if rc != nil && rc.Spec.Endpoint != nil && r.endpointHook != nil {
+		hooks = r.endpointHook
+		requeueAfter = endpointHealthCheckInterval
+	}
+
+	// Deactivate revision if it is inactive, unless it's pinned. The runtime
+	// of a pinned revision keeps serving while the revision is inactive.
+	pinned := false
 	if pr.GetDesiredState() == v1.PackageRevisionInactive {
-		if err := r.runtimeHook.Deactivate(ctx, pr, builder); err != nil {
+		pinned, err = r.pins.IsPinned(ctx, pr)
+		if err != nil {
+			err = errors.Wrap(err, errCheckPinned)
+			r.log.Info("Error", "error", err)
+			return reconcile.Result{}, err
+		}
+	}
+	if pr.GetDesiredState() == v1.PackageRevisionInactive && !pinned {
+		if err := hooks.Deactivate(ctx, pr, builder); err != nil {
+			pending := &deactivationPendingError{}
+			if errors.As(err, &pending) {
+				log.Debug("Waiting to deactivate package revision", "reason", pending.reason)
+				return reconcile.Result{RequeueAfter: pending.retryAfter}, nil
+			}
 			err := errors.Wrap(err, "failed to run deactivation hook")
 			r.log.Info("Error", "error", err)
 			return reconcile.Result{}, err
@@ -340,7 +406,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	// Run pre-establish hooks
-	if err := r.runtimeHook.Pre(ctx, pr, builder); err != nil {
+	if err := hooks.Pre(ctx, pr, builder); err != nil {
 		if kerrors.IsConflict(err) {
 			return reconcile.Result{Requeue: true}, nil
 		}
@@ -360,10 +426,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	// Run post-establish hooks
-	if err := r.runtimeHook.Post(ctx, pr, builder); err != nil {
+	if err := hooks.Post(ctx, pr, builder); err != nil {
 		if kerrors.IsConflict(err) {
 			return reconcile.Result{Requeue: true}, nil
 		}
+		pending := &healthCheckPendingError{}
+		if errors.As(err, &pending) {
+			log.Debug("Waiting for package runtime health check", "reason", pending.Error())
+			return reconcile.Result{RequeueAfter: pending.retryAfter}, nil
+		}
 		err = errors.Wrap(err, errPostHook)
 		status.MarkConditions(v1.RuntimeUnhealthy().WithMessage(err.Error()))
 		_ = r.client.Status().Update(ctx, pr)
@@ -378,21 +449,24 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	status.MarkConditions(v1.RuntimeHealthy())
-	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+	return reconcile.Result{RequeueAfter: requeueAfter}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
 }
 
-func (r *Reconciler) builderOptions(ctx context.Context, pwr v1.PackageRevisionWithRuntime) ([]BuilderOption, error) {
+// builderOptions returns the options used to build runtime manifests for the
+// supplied package revision, and its DeploymentRuntimeConfig if any.
+func (r *Reconciler) builderOptions(ctx context.Context, pwr v1.PackageRevisionWithRuntime) ([]BuilderOption, *v1beta1.DeploymentRuntimeConfig, error) {
 	var opts []BuilderOption
+	var rc *v1beta1.DeploymentRuntimeConfig
 
 	if r.features.Enabled(features.EnableBetaDeploymentRuntimeConfigs) {
 		rcRef := pwr.GetRuntimeConfigRef()
 		if rcRef == nil {
-			return nil, errors.New(errNoRuntimeConfig)
+			return nil, nil, errors.New(errNoRuntimeConfig)
 		}
 
-		rc := &v1beta1.DeploymentRuntimeConfig{}
+		rc = &v1beta1.DeploymentRuntimeConfig{}
 		if err := r.client.Get(ctx, types.NamespacedName{Name: rcRef.Name}, rc); err != nil {
-			return nil, errors.Wrap(err, errGetRuntimeConfig)
+			return nil, nil, errors.Wrap(err, errGetRuntimeConfig)
 		}
 		opts = append(opts, BuilderWithRuntimeConfig(rc))
 	}
@@ -402,11 +476,11 @@ func (r *Reconciler) builderOptions(ctx context.Context, pwr v1.PackageRevisionW
 	// We will append them to the list of ImagePullSecrets for the runtime
 	// ServiceAccount.
 	if err := r.client.Get(ctx, types.NamespacedName{Namespace: r.namespace, Name: r.serviceAccount}, sa); err != nil {
-		return nil, errors.Wrap(err, errGetServiceAccount)
+		return nil, nil, errors.Wrap(err, errGetServiceAccount)
 	}
 	if len(sa.ImagePullSecrets) > 0 {
 		opts = append(opts, BuilderWithServiceAccountPullSecrets(sa.ImagePullSecrets))
 	}
 
-	return opts, nil
+	return opts, rc, nil
 }
@@ -401,6 +401,53 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errPostHook),
 			},
 		},
+		"PostHookHealthCheckPending": {
+			reason: "We should requeue without error or a status update if the runtime hasn't been health checked yet.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							switch obj := o.(type) {
+							case *v1.FunctionRevision:
+								obj.SetGroupVersionKind(v1.FunctionRevisionGroupVersionKind)
+								obj.SetDesiredState(v1.PackageRevisionActive)
+								obj.SetLabels(map[string]string{v1.LabelParentPackage: "test-function"})
+								obj.SetConditions(v1.RevisionHealthy())
+								return nil
+							case *corev1.ServiceAccount:
+								obj.Name = crossplaneName
+								obj.Namespace = testNamespace
+								return nil
+							}
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(_ client.Object) error {
+							t.Errorf("unexpected status update")
+							return nil
+						}),
+					},
+				},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionWithRuntimeFn(func() v1.PackageRevisionWithRuntime { return &v1.FunctionRevision{} }),
+					WithLogger(testLog),
+					WithRecorder(event.NewNopRecorder()),
+					WithNamespace(testNamespace),
+					WithServiceAccount(crossplaneName),
+					WithRuntimeHooks(&MockHooks{
+						MockPre: func(_ context.Context, _ v1.PackageRevisionWithRuntime, _ ManifestBuilder) error {
+							return nil
+						},
+						MockPost: func(_ context.Context, _ v1.PackageRevisionWithRuntime, _ ManifestBuilder) error {
+							return &healthCheckPendingError{address: testEndpoint, retryAfter: endpointHealthCheckPollInterval}
+						},
+					}),
+					WithDeploymentSelectorMigrator(NewNopDeploymentSelectorMigrator()),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: endpointHealthCheckPollInterval},
+			},
+		},
 		"ErrDeactivateRevision": {
 			reason: "We should return an error if deactivation fails.",
 			args: args{
@@ -440,6 +487,145 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, "failed to run deactivation hook"),
 			},
 		},
+		"DeactivationPending": {
+			reason: "We should requeue without error if the revision can't be deactivated yet.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							switch obj := o.(type) {
+							case *v1.FunctionRevision:
+								obj.SetGroupVersionKind(v1.FunctionRevisionGroupVersionKind)
+								obj.SetDesiredState(v1.PackageRevisionInactive)
+								obj.SetLabels(map[string]string{v1.LabelParentPackage: "test-function"})
+								return nil
+							case *corev1.ServiceAccount:
+								obj.Name = crossplaneName
+								obj.Namespace = testNamespace
+								return nil
+							}
+							return nil
+						}),
+					},
+				},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionWithRuntimeFn(func() v1.PackageRevisionWithRuntime { return &v1.FunctionRevision{} }),
+					WithLogger(testLog),
+					WithRecorder(event.NewNopRecorder()),
+					WithNamespace(testNamespace),
+					WithServiceAccount(crossplaneName),
+					WithRuntimeHooks(&MockHooks{
+						MockDeactivate: func(_ context.Context, _ v1.PackageRevisionWithRuntime, _ ManifestBuilder) error {
+							return &deactivationPendingError{reason: "waiting", retryAfter: 10 * time.Second}
+						},
+					}),
+					WithDeploymentSelectorMigrator(NewNopDeploymentSelectorMigrator()),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 10 * time.Second},
+			},
+		},
+		"ErrCheckPinned": {
+			reason: "We should return any error encountered determining whether an inactive revision is pinned.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							switch obj := o.(type) {
+							case *v1.FunctionRevision:
+								obj.SetGroupVersionKind(v1.FunctionRevisionGroupVersionKind)
+								obj.SetDesiredState(v1.PackageRevisionInactive)
+								obj.SetLabels(map[string]string{v1.LabelParentPackage: "test-function"})
+								return nil
+							case *corev1.ServiceAccount:
+								obj.Name = crossplaneName
+								obj.Namespace = testNamespace
+								return nil
+							}
+							return nil
+						}),
+					},
+				},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionWithRuntimeFn(func() v1.PackageRevisionWithRuntime { return &v1.FunctionRevision{} }),
+					WithLogger(testLog),
+					WithRecorder(event.NewNopRecorder()),
+					WithNamespace(testNamespace),
+					WithServiceAccount(crossplaneName),
+					WithRuntimeHooks(&MockHooks{}),
+					WithPinChecker(PinCheckerFn(func(_ context.Context, _ v1.PackageRevisionWithRuntime) (bool, error) {
+						return false, errBoom
+					})),
+					WithDeploymentSelectorMigrator(NewNopDeploymentSelectorMigrator()),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errCheckPinned),
+			},
+		},
+		"SuccessfulPinnedInactiveRevision": {
+			reason: "An inactive revision that's pinned should keep its runtime serving rather than being deactivated.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							switch obj := o.(type) {
+							case *v1.FunctionRevision:
+								obj.SetGroupVersionKind(v1.FunctionRevisionGroupVersionKind)
+								obj.SetDesiredState(v1.PackageRevisionInactive)
+								obj.SetLabels(map[string]string{v1.LabelParentPackage: "test-function"})
+								obj.SetConditions(v1.RevisionHealthy())
+								return nil
+							case *corev1.ServiceAccount:
+								obj.Name = crossplaneName
+								obj.Namespace = testNamespace
+								return nil
+							}
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+							want := &v1.FunctionRevision{}
+							want.SetGroupVersionKind(v1.FunctionRevisionGroupVersionKind)
+							want.SetDesiredState(v1.PackageRevisionInactive)
+							want.SetLabels(map[string]string{v1.LabelParentPackage: "test-function"})
+							want.SetConditions(v1.RevisionHealthy())
+							want.SetConditions(v1.RuntimeHealthy())
+
+							if diff := cmp.Diff(want, o); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+				},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionWithRuntimeFn(func() v1.PackageRevisionWithRuntime { return &v1.FunctionRevision{} }),
+					WithLogger(testLog),
+					WithRecorder(event.NewNopRecorder()),
+					WithNamespace(testNamespace),
+					WithServiceAccount(crossplaneName),
+					WithRuntimeHooks(&MockHooks{
+						MockPre: func(_ context.Context, _ v1.PackageRevisionWithRuntime, _ ManifestBuilder) error {
+							return nil
+						},
+						MockPost: func(_ context.Context, _ v1.PackageRevisionWithRuntime, _ ManifestBuilder) error {
+							return nil
+						},
+						MockDeactivate: func(_ context.Context, _ v1.PackageRevisionWithRuntime, _ ManifestBuilder) error {
+							return errors.New("a pinned revision should not be deactivated")
+						},
+					}),
+					WithPinChecker(PinCheckerFn(func(_ context.Context, _ v1.PackageRevisionWithRuntime) (bool, error) {
+						return true, nil
+					})),
+					WithDeploymentSelectorMigrator(NewNopDeploymentSelectorMigrator()),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"ErrNoRuntimeConfig": {
 			reason: "Should return error when beta deployment runtime configs are enabled but no runtime config is referenced.",
 			args: args{
@@ -530,6 +716,73 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errors.Wrap(errors.New("runtime config not found"), errGetRuntimeConfig), errManifestBuilderOptions),
 			},
 		},
+		"SuccessfulEndpointRuntime": {
+			reason: "Should use the endpoint hooks and periodically requeue when the runtime config configures an endpoint.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							switch obj := o.(type) {
+							case *v1.FunctionRevision:
+								obj.SetGroupVersionKind(v1.FunctionRevisionGroupVersionKind)
+								obj.SetDesiredState(v1.PackageRevisionActive)
+								obj.SetLabels(map[string]string{v1.LabelParentPackage: "test-function"})
+								obj.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "test-runtime-config"})
+								obj.SetConditions(v1.RevisionHealthy())
+								return nil
+							case *v1beta1.DeploymentRuntimeConfig:
+								obj.Spec.Endpoint = &v1beta1.RuntimeEndpoint{Address: "dns:///function.example.org:9443"}
+								return nil
+							case *corev1.ServiceAccount:
+								obj.Name = crossplaneName
+								obj.Namespace = testNamespace
+								return nil
+							}
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+							want := &v1.FunctionRevision{}
+							want.SetGroupVersionKind(v1.FunctionRevisionGroupVersionKind)
+							want.SetDesiredState(v1.PackageRevisionActive)
+							want.SetLabels(map[string]string{v1.LabelParentPackage: "test-function"})
+							want.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "test-runtime-config"})
+							want.SetConditions(v1.RevisionHealthy())
+							want.SetConditions(v1.RuntimeHealthy())
+
+							if diff := cmp.Diff(want, o); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+				},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionWithRuntimeFn(func() v1.PackageRevisionWithRuntime { return &v1.FunctionRevision{} }),
+					WithLogger(testLog),
+					WithRecorder(event.NewNopRecorder()),
+					WithNamespace(testNamespace),
+					WithServiceAccount(crossplaneName),
+					WithRuntimeHooks(&MockHooks{
+						MockPre: func(_ context.Context, _ v1.PackageRevisionWithRuntime, _ ManifestBuilder) error {
+							return errors.New("should not use the deployment runtime hooks")
+						},
+					}),
+					WithEndpointHooks(&MockHooks{
+						MockPre: func(_ context.Context, _ v1.PackageRevisionWithRuntime, _ ManifestBuilder) error {
+							return nil
+						},
+						MockPost: func(_ context.Context, _ v1.PackageRevisionWithRuntime, _ ManifestBuilder) error {
+							return nil
+						},
+					}),
+					WithFeatureFlags(flagsWithFeatures(features.EnableBetaDeploymentRuntimeConfigs)),
+					WithDeploymentSelectorMigrator(NewNopDeploymentSelectorMigrator()),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: endpointHealthCheckInterval},
+			},
+		},
 		"MigratorNop": {
 			reason: "Should use nop migrator for function revisions (no migration needed).",
 			args: args{
@@ -17,10 +17,16 @@ limitations under the License.
 package runtime
 
 import (
+	"time"
+
 	"golang.org/x/net/context"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
@@ -54,6 +60,14 @@ const (
 	// ServiceEndpointFmt is the format string for service endpoints.
 	ServiceEndpointFmt = "dns:///%s.%s:%d"
 
+	// LabelRevision is the pod label that identifies a package revision.
+	LabelRevision = "pkg.crossplane.io/revision"
+
+	// DefaultFunctionRequestsMetricName is the default name of the external
+	// metric used to scale functions on the rate of requests Crossplane
+	// sends them.
+	DefaultFunctionRequestsMetricName = "crossplane_composition_run_function_requests_per_second"
+
 	// ESSTLSCertDirEnvVar is the environment variable for ESS TLS certificate directory.
 	ESSTLSCertDirEnvVar = "ESS_TLS_CERTS_DIR"
 
@@ -98,6 +112,14 @@ type ManifestBuilder interface {
 	TLSClientSecret() *corev1.Secret
 	// TLSServerSecret builds and returns the TLS server secret manifest.
 	TLSServerSecret() *corev1.Secret
+	// HorizontalPodAutoscaler builds and returns the horizontal pod
+	// autoscaler manifest for the supplied deployment, or nil if the package
+	// shouldn't be autoscaled.
+	HorizontalPodAutoscaler(deployment string) *autoscalingv2.HorizontalPodAutoscaler
+	// PodDisruptionBudget builds and returns the pod disruption budget
+	// manifest for the supplied deployment, or nil if the package shouldn't
+	// have a pod disruption budget.
+	PodDisruptionBudget(deployment string) *policyv1.PodDisruptionBudget
 }
 
 // A Hooks performs runtime operations before and after a revision
@@ -113,6 +135,17 @@ type Hooks interface {
 	Deactivate(ctx context.Context, pr v1.PackageRevisionWithRuntime, b ManifestBuilder) error
 }
 
+// A deactivationPendingError indicates that a package revision can't be
+// deactivated yet, and that deactivation should be retried later.
+type deactivationPendingError struct {
+	reason     string
+	retryAfter time.Duration
+}
+
+func (e *deactivationPendingError) Error() string {
+	return e.reason
+}
+
 // DeploymentRuntimeBuilder builds the Deployment runtime manifests for
 // a package revision.
 type DeploymentRuntimeBuilder struct {
@@ -257,6 +290,11 @@ func (b *DeploymentRuntimeBuilder) Deployment(serviceAccount string, overrides .
 		allOverrides = append(allOverrides, DeploymentRuntimeWithTLSServerSecret(*b.revision.GetTLSServerSecretName()))
 	}
 
+	if b.runtimeConfig != nil && b.runtimeConfig.Spec.HorizontalPodAutoscalerTemplate != nil {
+		// The HorizontalPodAutoscaler manages the Deployment's replicas.
+		allOverrides = append(allOverrides, DeploymentWithoutReplicas())
+	}
+
 	// We append the overrides passed to the function last so that they can
 	// override the above ones.
 	allOverrides = append(allOverrides, overrides...)
@@ -327,9 +365,78 @@ func (b *DeploymentRuntimeBuilder) TLSServerSecret() *corev1.Secret {
 	}
 }
 
+// HorizontalPodAutoscaler builds and returns the HorizontalPodAutoscaler
+// manifest for the supplied Deployment. It returns nil if the runtime config
+// doesn't specify a HorizontalPodAutoscaler template.
+func (b *DeploymentRuntimeBuilder) HorizontalPodAutoscaler(deployment string) *autoscalingv2.HorizontalPodAutoscaler {
+	if b.runtimeConfig == nil || b.runtimeConfig.Spec.HorizontalPodAutoscalerTemplate == nil {
+		return nil
+	}
+	tmpl := b.runtimeConfig.Spec.HorizontalPodAutoscalerTemplate
+
+	hpa := horizontalPodAutoscalerFromRuntimeConfig(tmpl)
+	if hpa.Name == "" {
+		hpa.Name = deployment
+	}
+	hpa.Namespace = b.namespace
+	hpa.OwnerReferences = []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(b.revision, b.revision.GetObjectKind().GroupVersionKind()))}
+	hpa.Spec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{
+		APIVersion: appsv1.SchemeGroupVersion.String(),
+		Kind:       "Deployment",
+		Name:       deployment,
+	}
+
+	if fr := tmpl.Spec.FunctionRequests; fr != nil && b.packageType() == "function" {
+		target := fr.TargetAverageValue.DeepCopy()
+		metric := fr.MetricName
+		if metric == "" {
+			metric = DefaultFunctionRequestsMetricName
+		}
+		hpa.Spec.Metrics = append(hpa.Spec.Metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ExternalMetricSourceType,
+			External: &autoscalingv2.ExternalMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{
+					Name: metric,
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"function_name": b.packageName()},
+					},
+				},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: &target,
+				},
+			},
+		})
+	}
+
+	return hpa
+}
+
+// PodDisruptionBudget builds and returns the PodDisruptionBudget manifest for
+// the supplied Deployment. It returns nil if the runtime config doesn't
+// specify a PodDisruptionBudget template.
+func (b *DeploymentRuntimeBuilder) PodDisruptionBudget(deployment string) *policyv1.PodDisruptionBudget {
+	if b.runtimeConfig == nil || b.runtimeConfig.Spec.PodDisruptionBudgetTemplate == nil {
+		return nil
+	}
+
+	pdb := podDisruptionBudgetFromRuntimeConfig(b.runtimeConfig.Spec.PodDisruptionBudgetTemplate)
+	if pdb.Name == "" {
+		pdb.Name = deployment
+	}
+	pdb.Namespace = b.namespace
+	pdb.OwnerReferences = []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(b.revision, b.revision.GetObjectKind().GroupVersionKind()))}
+	pdb.Spec.Selector = &metav1.LabelSelector{MatchLabels: b.podSelectors()}
+	if pdb.Spec.MinAvailable == nil && pdb.Spec.MaxUnavailable == nil {
+		pdb.Spec.MaxUnavailable = ptr.To(intstr.FromInt32(1))
+	}
+
+	return pdb
+}
+
 func (b *DeploymentRuntimeBuilder) podSelectors() map[string]string {
 	return map[string]string{
-		"pkg.crossplane.io/revision":           b.revision.GetName(),
+		LabelRevision:                          b.revision.GetName(),
 		"pkg.crossplane.io/" + b.packageType(): b.packageName(),
 	}
 }
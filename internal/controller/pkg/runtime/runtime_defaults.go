@@ -18,7 +18,9 @@ package runtime
 
 import (
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 )
@@ -80,3 +82,46 @@ func serviceFromRuntimeConfig(tmpl *v1beta1.ServiceTemplate) *corev1.Service {
 
 	return svc
 }
+
+func horizontalPodAutoscalerFromRuntimeConfig(tmpl *v1beta1.HorizontalPodAutoscalerTemplate) *autoscalingv2.HorizontalPodAutoscaler {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+
+	if meta := tmpl.Metadata; meta != nil {
+		if meta.Name != nil {
+			hpa.Name = *meta.Name
+		}
+		hpa.Annotations = meta.Annotations
+		hpa.Labels = meta.Labels
+	}
+
+	// Use DeepCopy to prevent modifications to the template itself.
+	spec := tmpl.Spec.DeepCopy()
+	hpa.Spec.MinReplicas = spec.MinReplicas
+	hpa.Spec.MaxReplicas = spec.MaxReplicas
+	hpa.Spec.Metrics = spec.Metrics
+	hpa.Spec.Behavior = spec.Behavior
+
+	return hpa
+}
+
+func podDisruptionBudgetFromRuntimeConfig(tmpl *v1beta1.PodDisruptionBudgetTemplate) *policyv1.PodDisruptionBudget {
+	pdb := &policyv1.PodDisruptionBudget{}
+
+	if meta := tmpl.Metadata; meta != nil {
+		if meta.Name != nil {
+			pdb.Name = *meta.Name
+		}
+		pdb.Annotations = meta.Annotations
+		pdb.Labels = meta.Labels
+	}
+
+	if spec := tmpl.Spec; spec != nil {
+		// Use DeepCopy to prevent modifications to the template itself.
+		spec = spec.DeepCopy()
+		pdb.Spec.MinAvailable = spec.MinAvailable
+		pdb.Spec.MaxUnavailable = spec.MaxUnavailable
+		pdb.Spec.UnhealthyPodEvictionPolicy = spec.UnhealthyPodEvictionPolicy
+	}
+
+	return pdb
+}
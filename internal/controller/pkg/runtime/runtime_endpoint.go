@@ -0,0 +1,341 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/initializer"
+)
+
+const (
+	// endpointHealthCheckTimeout is how long we wait for an endpoint to
+	// respond to a health check.
+	endpointHealthCheckTimeout = 10 * time.Second
+
+	// endpointHealthCheckInterval is how often we health check an endpoint
+	// that was healthy.
+	endpointHealthCheckInterval = 1 * time.Minute
+
+	// endpointHealthCheckPollInterval is how often we check whether an
+	// endpoint's first health check has completed.
+	endpointHealthCheckPollInterval = 2 * time.Second
+)
+
+const (
+	errNoEndpoint             = "deployment runtime config has no endpoint"
+	errApplyEndpointSecret    = "cannot apply package TLS secret"
+	errDeleteEndpointDeploy   = "cannot delete package deployment"
+	errDeleteEndpointService  = "cannot delete package service"
+	errFmtEndpointUnhealthy   = "package endpoint %q is unhealthy"
+	errFmtEndpointNotServing  = "health check returned status %s"
+	errDialEndpoint           = "cannot create gRPC client for endpoint"
+	errFmtGenerateEndpointTLS = "cannot generate TLS certificates for %q"
+	errFmtHealthCheckPending  = "waiting for endpoint %q to be health checked"
+)
+
+// An EndpointHealthChecker checks whether a gRPC endpoint is healthy.
+type EndpointHealthChecker interface {
+	// CheckHealth returns an error if the supplied endpoint isn't healthy.
+	CheckHealth(ctx context.Context, address string) error
+}
+
+// An EndpointHealthCheckerFn checks whether a gRPC endpoint is healthy.
+type EndpointHealthCheckerFn func(ctx context.Context, address string) error
+
+// CheckHealth returns an error if the supplied endpoint isn't healthy.
+func (fn EndpointHealthCheckerFn) CheckHealth(ctx context.Context, address string) error {
+	return fn(ctx, address)
+}
+
+// A GRPCHealthChecker checks endpoint health using the gRPC health checking
+// protocol.
+type GRPCHealthChecker struct {
+	creds credentials.TransportCredentials
+}
+
+// NewGRPCHealthChecker returns a GRPCHealthChecker that connects using the
+// supplied TLS config. It connects without TLS if the config is nil.
+func NewGRPCHealthChecker(cfg *tls.Config) *GRPCHealthChecker {
+	if cfg == nil {
+		return &GRPCHealthChecker{creds: insecure.NewCredentials()}
+	}
+	return &GRPCHealthChecker{creds: credentials.NewTLS(cfg)}
+}
+
+// CheckHealth returns an error unless the supplied endpoint reports it is
+// serving. Endpoints that don't implement the gRPC health checking protocol
+// are considered healthy if they respond at all.
+func (c *GRPCHealthChecker) CheckHealth(ctx context.Context, address string) error {
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(c.creds))
+	if err != nil {
+		return errors.Wrap(err, errDialEndpoint)
+	}
+	defer conn.Close() //nolint:errcheck // Nothing useful to do with this error.
+
+	ctx, cancel := context.WithTimeout(ctx, endpointHealthCheckTimeout)
+	defer cancel()
+
+	rsp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if rsp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return errors.Errorf(errFmtEndpointNotServing, rsp.GetStatus())
+	}
+	return nil
+}
+
+// A healthCheckPendingError indicates that an endpoint's health isn't known
+// yet, and that it should be checked again later.
+type healthCheckPendingError struct {
+	address    string
+	retryAfter time.Duration
+}
+
+func (e *healthCheckPendingError) Error() string {
+	return fmt.Sprintf(errFmtHealthCheckPending, e.address)
+}
+
+// An AsyncEndpointHealthChecker checks endpoint health in the background, so
+// that callers don't wait on slow or unresponsive endpoints. It returns the
+// result of the most recent health check of an endpoint, and starts a new
+// one.
+type AsyncEndpointHealthChecker struct {
+	wrapped EndpointHealthChecker
+
+	mx      sync.Mutex
+	results map[string]*endpointHealth
+
+	// checks tracks running health checks.
+	checks sync.WaitGroup
+}
+
+type endpointHealth struct {
+	checked bool
+	running bool
+	err     error
+}
+
+// NewAsyncEndpointHealthChecker returns an EndpointHealthChecker that checks
+// endpoint health in the background using the supplied checker.
+func NewAsyncEndpointHealthChecker(hc EndpointHealthChecker) *AsyncEndpointHealthChecker {
+	return &AsyncEndpointHealthChecker{wrapped: hc, results: make(map[string]*endpointHealth)}
+}
+
+// CheckHealth returns the result of the most recent health check of the
+// supplied endpoint, and starts a new health check unless one is running. It
+// returns a healthCheckPendingError if the endpoint hasn't been checked yet.
+func (c *AsyncEndpointHealthChecker) CheckHealth(_ context.Context, address string) error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	h, ok := c.results[address]
+	if !ok {
+		h = &endpointHealth{}
+		c.results[address] = h
+	}
+
+	if !h.running {
+		h.running = true
+		c.checks.Add(1)
+		go func() {
+			defer c.checks.Done()
+
+			// The wrapped checker bounds how long the check may take. We
+			// don't use the caller's context, which ends when it returns.
+			err := c.wrapped.CheckHealth(context.Background(), address)
+
+			c.mx.Lock()
+			defer c.mx.Unlock()
+			h.checked, h.running, h.err = true, false, err
+		}()
+	}
+
+	if !h.checked {
+		return &healthCheckPendingError{address: address, retryAfter: endpointHealthCheckPollInterval}
+	}
+	return h.err
+}
+
+// EndpointHooks performs runtime operations for packages served by an
+// externally hosted gRPC endpoint, per their DeploymentRuntimeConfig.
+type EndpointHooks struct {
+	client resource.ClientApplicator
+	health EndpointHealthChecker
+}
+
+// NewEndpointHooks returns a new EndpointHooks.
+func NewEndpointHooks(client client.Client, hc EndpointHealthChecker) *EndpointHooks {
+	return &EndpointHooks{
+		client: resource.ClientApplicator{
+			Client:     client,
+			Applicator: resource.NewAPIPatchingApplicator(client),
+		},
+		health: hc,
+	}
+}
+
+// Pre issues the package's TLS certificates, and publishes the endpoint of
+// function packages.
+func (h *EndpointHooks) Pre(ctx context.Context, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) error {
+	if pr.GetDesiredState() != v1.PackageRevisionActive {
+		return nil
+	}
+
+	ep, err := h.endpoint(ctx, pr)
+	if err != nil {
+		return err
+	}
+
+	// N.B.: We expect the revision to be applied by the caller.
+	if fRev, ok := pr.(*v1.FunctionRevision); ok {
+		fRev.Status.Endpoint = ep.Address
+	}
+
+	secServer := build.TLSServerSecret()
+	if secServer == nil {
+		// We should wait for the revision reconciler to set the secret
+		// names before proceeding creating the TLS secrets.
+		return nil
+	}
+	if err := h.client.Apply(ctx, secServer); err != nil {
+		return errors.Wrap(err, errApplyEndpointSecret)
+	}
+
+	opts := []initializer.TLSCertificateGeneratorOption{
+		initializer.TLSCertificateGeneratorWithOwner(pr.GetOwnerReferences()),
+		initializer.TLSCertificateGeneratorWithServerSecretName(secServer.GetName(), []string{endpointHost(ep.Address)}),
+		// The endpoint may change, or the package may have used the
+		// Deployment runtime, whose certificate is for its Service.
+		initializer.TLSCertificateGeneratorWithServerCertificateReissue(),
+	}
+	if secClient := build.TLSClientSecret(); secClient != nil {
+		if err := h.client.Apply(ctx, secClient); err != nil {
+			return errors.Wrap(err, errApplyEndpointSecret)
+		}
+		opts = append(opts, initializer.TLSCertificateGeneratorWithClientSecretName(secClient.GetName(), []string{pr.GetName()}))
+	}
+
+	if err := initializer.NewTLSCertificateGenerator(secServer.Namespace, initializer.RootCACertSecretName, opts...).Run(ctx, h.client); err != nil {
+		return errors.Wrapf(err, errFmtGenerateEndpointTLS, pr.GetLabels()[v1.LabelParentPackage])
+	}
+	return nil
+}
+
+// Post removes anything left over from when the package used the Deployment
+// runtime, then health checks the package's endpoint.
+func (h *EndpointHooks) Post(ctx context.Context, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) error {
+	if pr.GetDesiredState() != v1.PackageRevisionActive {
+		return nil
+	}
+
+	ep, err := h.endpoint(ctx, pr)
+	if err != nil {
+		return err
+	}
+
+	if err := h.deleteDeploymentRuntime(ctx, pr, build); err != nil {
+		return err
+	}
+
+	// The Deployment runtime's Service is shared by all of the package's
+	// revisions. Nothing uses it once the active revision is served by an
+	// endpoint.
+	if err := h.client.Delete(ctx, build.Service()); resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errDeleteEndpointService)
+	}
+
+	err = h.health.CheckHealth(ctx, ep.Address)
+	pending := &healthCheckPendingError{}
+	if errors.As(err, &pending) {
+		return err
+	}
+	return errors.Wrapf(err, errFmtEndpointUnhealthy, ep.Address)
+}
+
+// Deactivate removes anything left over from when the revision used the
+// Deployment runtime. Crossplane doesn't manage the endpoint itself.
+func (h *EndpointHooks) Deactivate(ctx context.Context, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) error {
+	return h.deleteDeploymentRuntime(ctx, pr, build)
+}
+
+// deleteDeploymentRuntime deletes the supplied revision's Deployment, its
+// scaling resources, and any Service specific to the revision.
+func (h *EndpointHooks) deleteDeploymentRuntime(ctx context.Context, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) error {
+	d := build.Deployment(build.ServiceAccount().Name)
+	if err := h.client.Delete(ctx, d); resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errDeleteEndpointDeploy)
+	}
+	if err := deleteScaling(ctx, h.client, build, d); err != nil {
+		return err
+	}
+	if err := h.client.Delete(ctx, build.Service(ServiceWithName(pr.GetName()))); resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errDeleteEndpointService)
+	}
+	return nil
+}
+
+func (h *EndpointHooks) endpoint(ctx context.Context, pr v1.PackageRevisionWithRuntime) (*v1beta1.RuntimeEndpoint, error) {
+	ref := pr.GetRuntimeConfigRef()
+	if ref == nil {
+		return nil, errors.New(errNoRuntimeConfig)
+	}
+	rc := &v1beta1.DeploymentRuntimeConfig{}
+	if err := h.client.Get(ctx, types.NamespacedName{Name: ref.Name}, rc); err != nil {
+		return nil, errors.Wrap(err, errGetRuntimeConfig)
+	}
+	if rc.Spec.Endpoint == nil {
+		return nil, errors.New(errNoEndpoint)
+	}
+	return rc.Spec.Endpoint, nil
+}
+
+// endpointHost returns the host name of the supplied gRPC target, for example
+// example.org given dns:///example.org:9443.
+func endpointHost(address string) string {
+	if i := strings.LastIndex(address, "/"); i >= 0 {
+		address = address[i+1:]
+	}
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
@@ -0,0 +1,453 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const testEndpoint = "dns:///function.example.org:9443"
+
+func TestEndpointPreHook(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		client    client.Client
+		rev       v1.PackageRevisionWithRuntime
+		manifests ManifestBuilder
+	}
+
+	type want struct {
+		err error
+		rev v1.PackageRevisionWithRuntime
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Inactive": {
+			reason: "We should do nothing if the revision is inactive.",
+			args: args{
+				rev: &v1.FunctionRevision{
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionInactive,
+						},
+					},
+				},
+			},
+			want: want{
+				rev: &v1.FunctionRevision{
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionInactive,
+						},
+					},
+				},
+			},
+		},
+		"ErrGetRuntimeConfig": {
+			reason: "We should return an error if we can't get the revision's runtime config.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+				rev: &v1.FunctionRevision{
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
+						PackageRevisionRuntimeSpec: v1.PackageRevisionRuntimeSpec{
+							PackageRuntimeSpec: v1.PackageRuntimeSpec{
+								RuntimeConfigReference: &v1.RuntimeConfigReference{Name: "endpoint"},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetRuntimeConfig),
+				rev: &v1.FunctionRevision{
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
+						PackageRevisionRuntimeSpec: v1.PackageRevisionRuntimeSpec{
+							PackageRuntimeSpec: v1.PackageRuntimeSpec{
+								RuntimeConfigReference: &v1.RuntimeConfigReference{Name: "endpoint"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"NoEndpoint": {
+			reason: "We should return an error if the revision's runtime config doesn't configure an endpoint.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				},
+				rev: &v1.FunctionRevision{
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
+						PackageRevisionRuntimeSpec: v1.PackageRevisionRuntimeSpec{
+							PackageRuntimeSpec: v1.PackageRuntimeSpec{
+								RuntimeConfigReference: &v1.RuntimeConfigReference{Name: "endpoint"},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.New(errNoEndpoint),
+				rev: &v1.FunctionRevision{
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
+						PackageRevisionRuntimeSpec: v1.PackageRevisionRuntimeSpec{
+							PackageRuntimeSpec: v1.PackageRuntimeSpec{
+								RuntimeConfigReference: &v1.RuntimeConfigReference{Name: "endpoint"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"SetFunctionEndpoint": {
+			reason: "We should publish the configured endpoint in the function revision's status.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						if rc, ok := o.(*v1beta1.DeploymentRuntimeConfig); ok {
+							rc.Spec.Endpoint = &v1beta1.RuntimeEndpoint{Address: testEndpoint}
+						}
+						return nil
+					}),
+				},
+				rev: &v1.FunctionRevision{
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
+						PackageRevisionRuntimeSpec: v1.PackageRevisionRuntimeSpec{
+							PackageRuntimeSpec: v1.PackageRuntimeSpec{
+								RuntimeConfigReference: &v1.RuntimeConfigReference{Name: "endpoint"},
+							},
+						},
+					},
+				},
+				manifests: &MockManifestBuilder{
+					TLSServerSecretFn: func() *corev1.Secret { return nil },
+				},
+			},
+			want: want{
+				rev: &v1.FunctionRevision{
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
+						PackageRevisionRuntimeSpec: v1.PackageRevisionRuntimeSpec{
+							PackageRuntimeSpec: v1.PackageRuntimeSpec{
+								RuntimeConfigReference: &v1.RuntimeConfigReference{Name: "endpoint"},
+							},
+						},
+					},
+					Status: v1.FunctionRevisionStatus{
+						Endpoint: testEndpoint,
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewEndpointHooks(tc.args.client, nil)
+			err := h.Pre(context.TODO(), tc.args.rev, tc.args.manifests)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nh.Pre(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rev, tc.args.rev); diff != "" {
+				t.Errorf("\n%s\nh.Pre(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEndpointPostHook(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	rev := &v1.FunctionRevision{
+		Spec: v1.FunctionRevisionSpec{
+			PackageRevisionSpec: v1.PackageRevisionSpec{
+				DesiredState: v1.PackageRevisionActive,
+			},
+			PackageRevisionRuntimeSpec: v1.PackageRevisionRuntimeSpec{
+				PackageRuntimeSpec: v1.PackageRuntimeSpec{
+					RuntimeConfigReference: &v1.RuntimeConfigReference{Name: "endpoint"},
+				},
+			},
+		},
+	}
+
+	getEndpoint := test.NewMockGetFn(nil, func(o client.Object) error {
+		if rc, ok := o.(*v1beta1.DeploymentRuntimeConfig); ok {
+			rc.Spec.Endpoint = &v1beta1.RuntimeEndpoint{Address: testEndpoint}
+		}
+		return nil
+	})
+
+	manifests := &MockManifestBuilder{
+		ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
+			return &corev1.ServiceAccount{}
+		},
+		DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
+			return &appsv1.Deployment{}
+		},
+		ServiceFn: func(_ ...ServiceOverride) *corev1.Service {
+			return &corev1.Service{}
+		},
+	}
+
+	type args struct {
+		client client.Client
+		health EndpointHealthChecker
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"ErrDeleteDeployment": {
+			reason: "We should return an error if we can't delete a leftover Deployment.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    getEndpoint,
+					MockDelete: test.NewMockDeleteFn(errBoom),
+				},
+			},
+			want: errors.Wrap(errBoom, errDeleteEndpointDeploy),
+		},
+		"ErrDeleteService": {
+			reason: "We should return an error if we can't delete a leftover Service.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: getEndpoint,
+					MockDelete: func(_ context.Context, o client.Object, _ ...client.DeleteOption) error {
+						if _, ok := o.(*corev1.Service); ok {
+							return errBoom
+						}
+						return nil
+					},
+				},
+			},
+			want: errors.Wrap(errBoom, errDeleteEndpointService),
+		},
+		"HealthCheckPending": {
+			reason: "We should return the pending error unwrapped if the endpoint hasn't been health checked yet.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    getEndpoint,
+					MockDelete: test.NewMockDeleteFn(nil),
+				},
+				health: EndpointHealthCheckerFn(func(_ context.Context, address string) error {
+					return &healthCheckPendingError{address: address, retryAfter: endpointHealthCheckPollInterval}
+				}),
+			},
+			want: &healthCheckPendingError{address: testEndpoint, retryAfter: endpointHealthCheckPollInterval},
+		},
+		"Unhealthy": {
+			reason: "We should return an error if the endpoint isn't healthy.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    getEndpoint,
+					MockDelete: test.NewMockDeleteFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				},
+				health: EndpointHealthCheckerFn(func(_ context.Context, _ string) error {
+					return errBoom
+				}),
+			},
+			want: errors.Wrapf(errBoom, errFmtEndpointUnhealthy, testEndpoint),
+		},
+		"Healthy": {
+			reason: "We should return no error if the endpoint is healthy.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    getEndpoint,
+					MockDelete: test.NewMockDeleteFn(nil),
+				},
+				health: EndpointHealthCheckerFn(func(_ context.Context, address string) error {
+					if address != testEndpoint {
+						return errors.Errorf("unexpected address %q", address)
+					}
+					return nil
+				}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewEndpointHooks(tc.args.client, tc.args.health)
+			err := h.Post(context.TODO(), rev.DeepCopy(), manifests)
+
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nh.Post(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAsyncEndpointHealthChecker(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		health error
+		want   error
+	}{
+		"Healthy": {
+			reason: "We should return no error once a healthy endpoint has been checked.",
+		},
+		"Unhealthy": {
+			reason: "We should return the health check error once an unhealthy endpoint has been checked.",
+			health: errBoom,
+			want:   errBoom,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			release := make(chan struct{})
+			c := NewAsyncEndpointHealthChecker(EndpointHealthCheckerFn(func(_ context.Context, _ string) error {
+				<-release
+				return tc.health
+			}))
+
+			// The first check should not block on the running health check.
+			err := c.CheckHealth(context.Background(), testEndpoint)
+			pending := &healthCheckPendingError{address: testEndpoint, retryAfter: endpointHealthCheckPollInterval}
+			if diff := cmp.Diff(pending, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCheckHealth(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			close(release)
+			c.checks.Wait()
+
+			err = c.CheckHealth(context.Background(), testEndpoint)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCheckHealth(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			c.checks.Wait()
+		})
+	}
+}
+
+func TestGRPCHealthChecker(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		status healthpb.HealthCheckResponse_ServingStatus
+		health bool
+		want   error
+	}{
+		"Serving": {
+			reason: "We should return no error if the endpoint reports it is serving.",
+			status: healthpb.HealthCheckResponse_SERVING,
+			health: true,
+		},
+		"NotServing": {
+			reason: "We should return an error if the endpoint reports it isn't serving.",
+			status: healthpb.HealthCheckResponse_NOT_SERVING,
+			health: true,
+			want:   cmpopts.AnyError,
+		},
+		"Unimplemented": {
+			reason: "We should return no error if the endpoint doesn't implement health checks.",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			srv := grpc.NewServer()
+			if tc.health {
+				hs := health.NewServer()
+				hs.SetServingStatus("", tc.status)
+				healthpb.RegisterHealthServer(srv, hs)
+			}
+			go srv.Serve(lis) //nolint:errcheck // Serve returns when we stop the server.
+			defer srv.Stop()
+
+			err = NewGRPCHealthChecker(nil).CheckHealth(context.Background(), lis.Addr().String())
+			if diff := cmp.Diff(tc.want, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCheckHealth(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEndpointHost(t *testing.T) {
+	cases := map[string]struct {
+		address string
+		want    string
+	}{
+		"DNSScheme":    {address: "dns:///function.example.org:9443", want: "function.example.org"},
+		"HostPort":     {address: "function.example.org:9443", want: "function.example.org"},
+		"HostOnly":     {address: "function.example.org", want: "function.example.org"},
+		"IPv6":         {address: "[::1]:9443", want: "::1"},
+		"DNSNoPort":    {address: "dns:///function.example.org", want: "function.example.org"},
+		"DNSAuthority": {address: "dns://8.8.8.8/function.example.org:9443", want: "function.example.org"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, endpointHost(tc.address)); diff != "" {
+				t.Errorf("endpointHost(%q): -want, +got:\n%s", tc.address, diff)
+			}
+		})
+	}
+}
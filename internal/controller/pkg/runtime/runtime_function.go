@@ -19,11 +19,13 @@ package runtime
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -41,9 +43,31 @@ const (
 	errApplyFunctionSecret                    = "cannot apply function package secret"
 	errApplyFunctionSA                        = "cannot apply function package service account"
 	errApplyFunctionService                   = "cannot apply function package service"
+	errDeleteFunctionService                  = "cannot delete function package revision service"
 	errFmtUnavailableFunctionDeployment       = "function package deployment is unavailable with message: %s"
 	errNoAvailableConditionFunctionDeployment = "function package deployment has no condition of type \"Available\" yet"
 	errParseFunctionImage                     = "cannot parse function package image"
+	errGetFunctionService                     = "cannot get function package service"
+	errListFunctionRevisions                  = "cannot list function package revisions"
+
+	errFmtCutoverPending = "waiting for function package service to cut over from revision %q"
+	errFmtDrainPending   = "waiting for connections to revision %q to drain"
+)
+
+const (
+	// AnnotationCutoverTime is set on a function's Service when it cuts over
+	// to a new revision. It records when the cutover happened, in RFC 3339
+	// format.
+	AnnotationCutoverTime = "pkg.crossplane.io/cutover-time"
+
+	// functionDrainPeriod is how long we keep a deactivated function
+	// revision's Deployment around after the function's Service cuts over
+	// to a new revision, giving in-flight requests time to complete.
+	functionDrainPeriod = 30 * time.Second
+
+	// functionCutoverPollInterval is how often we check whether a
+	// deactivated function revision's Deployment can be deleted.
+	functionCutoverPollInterval = 10 * time.Second
 )
 
 // FunctionHooks performs runtime operations for function packages.
@@ -63,12 +87,10 @@ func NewFunctionHooks(client client.Client, defaultRegistry string) *FunctionHoo
 	}
 }
 
-// Pre performs operations meant to happen before establishing objects.
+// Pre performs operations meant to happen before establishing objects. It's
+// only called for an inactive revision if a Composition pins it, in which case
+// the revision keeps serving using its own Service.
 func (h *FunctionHooks) Pre(ctx context.Context, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) error {
-	if pr.GetDesiredState() != v1.PackageRevisionActive {
-		return nil
-	}
-
 	// Ensure Prerequisites
 	// Note(turkenh): We need certificates have generated when we get to the
 	// establish step, i.e., we want to inject the CA to CRDs (webhook caBundle).
@@ -76,21 +98,32 @@ func (h *FunctionHooks) Pre(ctx context.Context, pr v1.PackageRevisionWithRuntim
 	// generating certificates requires the service to be defined. This is why
 	// we're creating the service here but service account and deployment in the
 	// post-establish.
-	svc := build.Service(
-		// We want a headless service so that our gRPC client (i.e. the Crossplane
-		// FunctionComposer) can load balance across the endpoints.
-		// https://kubernetes.io/docs/concepts/services-networking/service/#headless-services
-		ServiceWithClusterIP(corev1.ClusterIPNone),
-		ServiceWithAdditionalPorts([]corev1.ServicePort{
-			{
-				Name:       GRPCPortName,
-				Protocol:   corev1.ProtocolTCP,
-				Port:       GRPCPort,
-				TargetPort: intstr.FromString(GRPCPortName),
-			},
-		}))
-	if err := h.client.Apply(ctx, svc); err != nil {
-		return errors.Wrap(err, errApplyFunctionService)
+	svc := functionService(build)
+	endpoint := svc
+
+	if pr.GetDesiredState() == v1.PackageRevisionActive {
+		// The Service is shared by all of the function's revisions. If it
+		// still selects the pods of another revision we don't cut it over to
+		// this one until this revision's Deployment is available. See Post.
+		current := &corev1.Service{}
+		err := h.client.Get(ctx, types.NamespacedName{Name: svc.GetName(), Namespace: svc.GetNamespace()}, current)
+		if resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errGetFunctionService)
+		}
+		if rev, ok := current.Spec.Selector[LabelRevision]; err == nil && ok && rev != pr.GetName() {
+			svc.Spec.Selector = current.Spec.Selector
+		}
+
+		if err := h.client.Apply(ctx, svc); err != nil {
+			return errors.Wrap(err, errApplyFunctionService)
+		}
+	} else {
+		// A pinned revision that's inactive leaves the shared Service to
+		// the active revision.
+		endpoint = revisionService(build, pr)
+		if err := h.client.Apply(ctx, endpoint); err != nil {
+			return errors.Wrap(err, errApplyFunctionService)
+		}
 	}
 
 	// N.B.: We expect the revision to be applied by the caller
@@ -99,7 +132,7 @@ func (h *FunctionHooks) Pre(ctx context.Context, pr v1.PackageRevisionWithRuntim
 		return errors.Errorf("cannot apply function package hooks to %T", pr)
 	}
 
-	fRev.Status.Endpoint = fmt.Sprintf(ServiceEndpointFmt, svc.Name, svc.Namespace, GRPCPort)
+	fRev.Status.Endpoint = fmt.Sprintf(ServiceEndpointFmt, endpoint.Name, endpoint.Namespace, GRPCPort)
 
 	secServer := build.TLSServerSecret()
 	if err := h.client.Apply(ctx, secServer); err != nil {
@@ -115,12 +148,9 @@ func (h *FunctionHooks) Pre(ctx context.Context, pr v1.PackageRevisionWithRuntim
 	return nil
 }
 
-// Post performs operations meant to happen after establishing objects.
+// Post performs operations meant to happen after establishing objects. Like
+// Pre, it's only called for an inactive revision if a Composition pins it.
 func (h *FunctionHooks) Post(ctx context.Context, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) error {
-	if pr.GetDesiredState() != v1.PackageRevisionActive {
-		return nil
-	}
-
 	sa := build.ServiceAccount()
 
 	// Determine the function's image, taking into account the default registry.
@@ -142,28 +172,66 @@ func (h *FunctionHooks) Post(ctx context.Context, pr v1.PackageRevisionWithRunti
 	if err := h.client.Apply(ctx, d); err != nil {
 		return errors.Wrap(err, errApplyFunctionDeployment)
 	}
+	if err := applyScaling(ctx, h.client, build, d); err != nil {
+		return err
+	}
 
 	for _, c := range d.Status.Conditions {
 		if c.Type == appsv1.DeploymentAvailable {
-			if c.Status == corev1.ConditionTrue {
+			if c.Status != corev1.ConditionTrue {
+				return errors.Errorf(errFmtUnavailableFunctionDeployment, c.Message)
+			}
+			if pr.GetDesiredState() != v1.PackageRevisionActive {
 				return nil
 			}
-			return errors.Errorf(errFmtUnavailableFunctionDeployment, c.Message)
+			return h.cutover(ctx, pr, build)
 		}
 	}
 	return errors.New(errNoAvailableConditionFunctionDeployment)
 }
 
+// cutover points the function's Service at the supplied revision's pods.
+func (h *FunctionHooks) cutover(ctx context.Context, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) error {
+	svc := functionService(build)
+
+	current := &corev1.Service{}
+	err := h.client.Get(ctx, types.NamespacedName{Name: svc.GetName(), Namespace: svc.GetNamespace()}, current)
+	if resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errGetFunctionService)
+	}
+	if rev, ok := current.Spec.Selector[LabelRevision]; err == nil && ok && rev != pr.GetName() {
+		meta.AddAnnotations(svc, map[string]string{AnnotationCutoverTime: time.Now().UTC().Format(time.RFC3339)})
+	}
+
+	return errors.Wrap(h.client.Apply(ctx, svc), errApplyFunctionService)
+}
+
 // Deactivate performs operations meant to happen before deactivating a revision.
-func (h *FunctionHooks) Deactivate(ctx context.Context, _ v1.PackageRevisionWithRuntime, build ManifestBuilder) error {
+// It keeps the revision's Deployment running until the function's Service has
+// cut over to the new active revision, and connections to this revision have
+// had time to drain.
+func (h *FunctionHooks) Deactivate(ctx context.Context, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) error {
+	if err := h.cutoverPending(ctx, pr, build); err != nil {
+		return err
+	}
+
 	sa := build.ServiceAccount()
 	// Delete the deployment if it exists.
 	// Different from the Post runtimeHook, we don't need to pass the
 	// "functionDeploymentOverrides()" here, because we're only interested
 	// in the name and namespace of the deployment to delete it.
-	if err := h.client.Delete(ctx, build.Deployment(sa.Name)); resource.IgnoreNotFound(err) != nil {
+	d := build.Deployment(sa.Name)
+	if err := h.client.Delete(ctx, d); resource.IgnoreNotFound(err) != nil {
 		return errors.Wrap(err, errDeleteFunctionDeployment)
 	}
+	if err := deleteScaling(ctx, h.client, build, d); err != nil {
+		return err
+	}
+
+	// Delete the revision's own Service, if it was pinned.
+	if err := h.client.Delete(ctx, revisionService(build, pr)); resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errDeleteFunctionService)
+	}
 
 	// NOTE(turkenh): We don't delete the service account here because it might
 	// be used by other package revisions, e.g. user might have specified a
@@ -177,6 +245,68 @@ func (h *FunctionHooks) Deactivate(ctx context.Context, _ v1.PackageRevisionWith
 	return nil
 }
 
+// cutoverPending returns a deactivationPendingError if the function's Service
+// still selects the supplied revision's pods, or cut over from them too
+// recently for connections to have drained.
+func (h *FunctionHooks) cutoverPending(ctx context.Context, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) error {
+	svc := build.Service()
+	current := &corev1.Service{}
+	if err := h.client.Get(ctx, types.NamespacedName{Name: svc.GetName(), Namespace: svc.GetNamespace()}, current); err != nil {
+		return errors.Wrap(resource.IgnoreNotFound(err), errGetFunctionService)
+	}
+
+	if current.Spec.Selector[LabelRevision] == pr.GetName() {
+		// The Service will only cut over if another revision is active.
+		l := &v1.FunctionRevisionList{}
+		if err := h.client.List(ctx, l, client.MatchingLabels{v1.LabelParentPackage: pr.GetLabels()[v1.LabelParentPackage]}); err != nil {
+			return errors.Wrap(err, errListFunctionRevisions)
+		}
+		for _, rev := range l.Items {
+			if rev.GetName() != pr.GetName() && rev.GetDesiredState() == v1.PackageRevisionActive {
+				return &deactivationPendingError{reason: fmt.Sprintf(errFmtCutoverPending, pr.GetName()), retryAfter: functionCutoverPollInterval}
+			}
+		}
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, current.GetAnnotations()[AnnotationCutoverTime])
+	if err != nil {
+		// The Service didn't record when it cut over.
+		return nil //nolint:nilerr // We don't need to wait for connections to drain.
+	}
+	if remaining := functionDrainPeriod - time.Since(t); remaining > 0 {
+		return &deactivationPendingError{reason: fmt.Sprintf(errFmtDrainPending, pr.GetName()), retryAfter: remaining}
+	}
+	return nil
+}
+
+// functionService returns the function's Service, selecting the pods of the
+// revision the supplied builder builds manifests for.
+func functionService(build ManifestBuilder) *corev1.Service {
+	return build.Service(
+		// We want a headless service so that our gRPC client (i.e. the Crossplane
+		// FunctionComposer) can load balance across the endpoints.
+		// https://kubernetes.io/docs/concepts/services-networking/service/#headless-services
+		ServiceWithClusterIP(corev1.ClusterIPNone),
+		ServiceWithAdditionalPorts([]corev1.ServicePort{
+			{
+				Name:       GRPCPortName,
+				Protocol:   corev1.ProtocolTCP,
+				Port:       GRPCPort,
+				TargetPort: intstr.FromString(GRPCPortName),
+			},
+		}))
+}
+
+// revisionService returns a Service that selects only the pods of the
+// supplied revision. Unlike the function's Service, it's named after the
+// revision.
+func revisionService(build ManifestBuilder, pr v1.PackageRevisionWithRuntime) *corev1.Service {
+	svc := functionService(build)
+	svc.SetName(pr.GetName())
+	return svc
+}
+
 func functionDeploymentOverrides(image string) []DeploymentOverride {
 	do := []DeploymentOverride{
 		DeploymentRuntimeWithAdditionalPorts([]corev1.ContainerPort{
@@ -18,8 +18,10 @@ package runtime
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
@@ -113,6 +115,144 @@ func TestFunctionPreHook(t *testing.T) {
 				},
 			},
 		},
+		"WaitForCutover": {
+			reason: "The function's service should keep selecting the previous revision until this revision is ready.",
+			args: args{
+				pkg: &pkgmetav1.Function{
+					Spec: pkgmetav1.FunctionSpec{},
+				},
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "function-foo-new",
+					},
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
+						PackageRevisionRuntimeSpec: v1.PackageRevisionRuntimeSpec{
+							TLSServerSecretName: ptr.To("some-server-secret"),
+						},
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceFn: func(_ ...ServiceOverride) *corev1.Service {
+						return &corev1.Service{
+							ObjectMeta: metav1.ObjectMeta{Name: "some-service", Namespace: "some-namespace"},
+							Spec:       corev1.ServiceSpec{Selector: map[string]string{LabelRevision: "function-foo-new"}},
+						}
+					},
+					TLSServerSecretFn: func() *corev1.Secret {
+						return &corev1.Secret{}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						if svc, ok := obj.(*corev1.Service); ok {
+							svc.Spec.Selector = map[string]string{LabelRevision: "function-foo-old"}
+						}
+						return nil
+					},
+					MockPatch: func(_ context.Context, obj client.Object, p client.Patch, _ ...client.PatchOption) error {
+						if _, ok := obj.(*corev1.Service); !ok {
+							return nil
+						}
+						svc := &corev1.Service{}
+						if err := unmarshalPatch(p, obj, svc); err != nil {
+							return err
+						}
+						if svc.Spec.Selector[LabelRevision] != "function-foo-old" {
+							return errors.New("service should select the old revision")
+						}
+						return nil
+					},
+					MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+						return nil
+					},
+				},
+			},
+			want: want{
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "function-foo-new",
+					},
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
+						PackageRevisionRuntimeSpec: v1.PackageRevisionRuntimeSpec{
+							TLSServerSecretName: ptr.To("some-server-secret"),
+						},
+					},
+					Status: v1.FunctionRevisionStatus{
+						Endpoint: fmt.Sprintf(ServiceEndpointFmt, "some-service", "some-namespace", revision.ServicePort),
+					},
+				},
+			},
+		},
+		"PinnedFunctionInactive": {
+			reason: "An inactive revision that's pinned should serve using its own service, leaving the function's service alone.",
+			args: args{
+				pkg: &pkgmetav1.Function{
+					Spec: pkgmetav1.FunctionSpec{},
+				},
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "function-foo-old",
+					},
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionInactive,
+						},
+						PackageRevisionRuntimeSpec: v1.PackageRevisionRuntimeSpec{
+							TLSServerSecretName: ptr.To("some-server-secret"),
+						},
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceFn: func(overrides ...ServiceOverride) *corev1.Service {
+						s := &corev1.Service{
+							ObjectMeta: metav1.ObjectMeta{Name: "some-service", Namespace: "some-namespace"},
+							Spec:       corev1.ServiceSpec{Selector: map[string]string{LabelRevision: "function-foo-old"}},
+						}
+						for _, o := range overrides {
+							o(s)
+						}
+						return s
+					},
+					TLSServerSecretFn: func() *corev1.Secret {
+						return &corev1.Secret{}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						if _, ok := obj.(*corev1.Service); ok && obj.GetName() != "function-foo-old" {
+							return errors.New("function's service should not be changed")
+						}
+						return nil
+					},
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+			want: want{
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "function-foo-old",
+					},
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionInactive,
+						},
+						PackageRevisionRuntimeSpec: v1.PackageRevisionRuntimeSpec{
+							TLSServerSecretName: ptr.To("some-server-secret"),
+						},
+					},
+					Status: v1.FunctionRevisionStatus{
+						Endpoint: fmt.Sprintf(ServiceEndpointFmt, "function-foo-old", "some-namespace", revision.ServicePort),
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -148,25 +288,66 @@ func TestFunctionPostHook(t *testing.T) {
 		args   args
 		want   want
 	}{
-		"FunctionInactive": {
-			reason: "Should do nothing if function revision is inactive.",
+		"PinnedFunctionInactive": {
+			reason: "Should run the deployment of an inactive revision that's pinned, without cutting the function's service over to it.",
 			args: args{
 				pkg: &pkgmetav1.Function{},
 				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "function-foo-old",
+					},
 					Spec: v1.FunctionRevisionSpec{
 						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      functionImage,
 							DesiredState: v1.PackageRevisionInactive,
 						},
 					},
+					Status: v1.FunctionRevisionStatus{
+						PackageRevisionStatus: v1.PackageRevisionStatus{
+							ResolvedPackage: functionImage,
+						},
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{}
+					},
+					DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
+						return &appsv1.Deployment{}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						switch o := obj.(type) {
+						case *appsv1.Deployment:
+							o.Status.Conditions = []appsv1.DeploymentCondition{{
+								Type:   appsv1.DeploymentAvailable,
+								Status: corev1.ConditionTrue,
+							}}
+						case *corev1.Service:
+							return errors.New("function's service should not be cut over to an inactive revision")
+						}
+						return nil
+					},
 				},
 			},
 			want: want{
 				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "function-foo-old",
+					},
 					Spec: v1.FunctionRevisionSpec{
 						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      functionImage,
 							DesiredState: v1.PackageRevisionInactive,
 						},
 					},
+					Status: v1.FunctionRevisionStatus{
+						PackageRevisionStatus: v1.PackageRevisionStatus{
+							ResolvedPackage: functionImage,
+						},
+					},
 				},
 			},
 		},
@@ -409,6 +590,9 @@ func TestFunctionPostHook(t *testing.T) {
 					DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
 						return &appsv1.Deployment{}
 					},
+					ServiceFn: func(_ ...ServiceOverride) *corev1.Service {
+						return &corev1.Service{}
+					},
 				},
 				client: &test.MockClient{
 					MockGet: func(_ context.Context, _ client.ObjectKey, _ client.Object) error {
@@ -442,6 +626,86 @@ func TestFunctionPostHook(t *testing.T) {
 				},
 			},
 		},
+		"SuccessfulCutover": {
+			reason: "Should cut the function's service over to this revision once its deployment is ready.",
+			args: args{
+				pkg: &pkgmetav1.Function{},
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "function-foo-new",
+					},
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      functionImage,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+					Status: v1.FunctionRevisionStatus{
+						PackageRevisionStatus: v1.PackageRevisionStatus{
+							ResolvedPackage: functionImage,
+						},
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{}
+					},
+					DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
+						return &appsv1.Deployment{}
+					},
+					ServiceFn: func(_ ...ServiceOverride) *corev1.Service {
+						return &corev1.Service{Spec: corev1.ServiceSpec{Selector: map[string]string{LabelRevision: "function-foo-new"}}}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						if svc, ok := o.(*corev1.Service); ok {
+							svc.Spec.Selector = map[string]string{LabelRevision: "function-foo-old"}
+						}
+						return nil
+					}),
+					MockPatch: func(_ context.Context, obj client.Object, p client.Patch, _ ...client.PatchOption) error {
+						switch o := obj.(type) {
+						case *appsv1.Deployment:
+							o.Status.Conditions = []appsv1.DeploymentCondition{{
+								Type:   appsv1.DeploymentAvailable,
+								Status: corev1.ConditionTrue,
+							}}
+						case *corev1.Service:
+							svc := &corev1.Service{}
+							if err := unmarshalPatch(p, o, svc); err != nil {
+								return err
+							}
+							if svc.Spec.Selector[LabelRevision] != "function-foo-new" {
+								return errors.New("service should select the new revision")
+							}
+							if svc.GetAnnotations()[AnnotationCutoverTime] == "" {
+								return errors.New("service should record the cutover time")
+							}
+						}
+						return nil
+					},
+				},
+			},
+			want: want{
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "function-foo-new",
+					},
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      functionImage,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+					Status: v1.FunctionRevisionStatus{
+						PackageRevisionStatus: v1.PackageRevisionStatus{
+							ResolvedPackage: functionImage,
+						},
+					},
+				},
+			},
+		},
 		"SuccessWithExtraSecret": {
 			reason: "Should not return error if successfully applied service account with additional secret.",
 			args: args{
@@ -466,6 +730,9 @@ func TestFunctionPostHook(t *testing.T) {
 					DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
 						return &appsv1.Deployment{}
 					},
+					ServiceFn: func(_ ...ServiceOverride) *corev1.Service {
+						return &corev1.Service{}
+					},
 				},
 				client: &test.MockClient{
 					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
@@ -526,6 +793,9 @@ func TestFunctionPostHook(t *testing.T) {
 					DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
 						return &appsv1.Deployment{}
 					},
+					ServiceFn: func(_ ...ServiceOverride) *corev1.Service {
+						return &corev1.Service{}
+					},
 				},
 				client: &test.MockClient{
 					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
@@ -614,6 +884,11 @@ func TestFunctionDeactivateHook(t *testing.T) {
 		"ErrDeleteDeployment": {
 			reason: "Should return error if we fail to delete deployment.",
 			args: args{
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "some-name",
+					},
+				},
 				manifests: &MockManifestBuilder{
 					ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
 						return &corev1.ServiceAccount{}
@@ -621,8 +896,12 @@ func TestFunctionDeactivateHook(t *testing.T) {
 					DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
 						return &appsv1.Deployment{}
 					},
+					ServiceFn: func(_ ...ServiceOverride) *corev1.Service {
+						return &corev1.Service{}
+					},
 				},
 				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(corev1.Resource("service"), "")),
 					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
 						if _, ok := obj.(*appsv1.Deployment); ok {
 							return errBoom
@@ -633,11 +912,21 @@ func TestFunctionDeactivateHook(t *testing.T) {
 			},
 			want: want{
 				err: errors.Wrap(errBoom, errDeleteFunctionDeployment),
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "some-name",
+					},
+				},
 			},
 		},
 		"Successful": {
 			reason: "Should not return error if successfully deleted service account and deployment.",
 			args: args{
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "some-name",
+					},
+				},
 				manifests: &MockManifestBuilder{
 					ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
 						return &corev1.ServiceAccount{
@@ -666,6 +955,7 @@ func TestFunctionDeactivateHook(t *testing.T) {
 					},
 				},
 				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(corev1.Resource("service"), "")),
 					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
 						switch obj.(type) {
 						case *corev1.ServiceAccount:
@@ -675,11 +965,136 @@ func TestFunctionDeactivateHook(t *testing.T) {
 								return errors.New("unexpected deployment name")
 							}
 							return nil
+						case *corev1.Service:
+							if obj.GetName() != "some-name" {
+								return errors.New("only the revision's own service should be deleted")
+							}
+							return nil
 						}
 						return errors.New("unexpected object type")
 					},
 				},
 			},
+			want: want{
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "some-name",
+					},
+				},
+			},
+		},
+		"WaitForCutover": {
+			reason: "Should not delete the deployment while the function's service still selects this revision and another revision is active.",
+			args: args{
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "function-foo-old",
+						Labels: map[string]string{v1.LabelParentPackage: "function-foo"},
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceFn: func(_ ...ServiceOverride) *corev1.Service {
+						return &corev1.Service{}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						o.(*corev1.Service).Spec.Selector = map[string]string{LabelRevision: "function-foo-old"}
+						return nil
+					}),
+					MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+						o.(*v1.FunctionRevisionList).Items = []v1.FunctionRevision{
+							{
+								ObjectMeta: metav1.ObjectMeta{Name: "function-foo-old"},
+							},
+							{
+								ObjectMeta: metav1.ObjectMeta{Name: "function-foo-new"},
+								Spec: v1.FunctionRevisionSpec{
+									PackageRevisionSpec: v1.PackageRevisionSpec{DesiredState: v1.PackageRevisionActive},
+								},
+							},
+						}
+						return nil
+					}),
+				},
+			},
+			want: want{
+				err: &deactivationPendingError{reason: fmt.Sprintf(errFmtCutoverPending, "function-foo-old"), retryAfter: functionCutoverPollInterval},
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "function-foo-old",
+						Labels: map[string]string{v1.LabelParentPackage: "function-foo"},
+					},
+				},
+			},
+		},
+		"WaitForDrain": {
+			reason: "Should not delete the deployment until connections have had time to drain after the function's service cut over.",
+			args: args{
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "function-foo-old",
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceFn: func(_ ...ServiceOverride) *corev1.Service {
+						return &corev1.Service{}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						o.(*corev1.Service).Spec.Selector = map[string]string{LabelRevision: "function-foo-new"}
+						o.SetAnnotations(map[string]string{AnnotationCutoverTime: time.Now().UTC().Format(time.RFC3339)})
+						return nil
+					}),
+				},
+			},
+			want: want{
+				err: &deactivationPendingError{reason: fmt.Sprintf(errFmtDrainPending, "function-foo-old")},
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "function-foo-old",
+					},
+				},
+			},
+		},
+		"SuccessfulAfterDrain": {
+			reason: "Should delete the deployment once connections have had time to drain after the function's service cut over.",
+			args: args{
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "function-foo-old",
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{}
+					},
+					DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
+						return &appsv1.Deployment{}
+					},
+					ServiceFn: func(_ ...ServiceOverride) *corev1.Service {
+						return &corev1.Service{}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						if svc, ok := o.(*corev1.Service); ok {
+							svc.Spec.Selector = map[string]string{LabelRevision: "function-foo-new"}
+							svc.SetAnnotations(map[string]string{AnnotationCutoverTime: time.Now().Add(-functionDrainPeriod).UTC().Format(time.RFC3339)})
+						}
+						return nil
+					}),
+					MockDelete: test.NewMockDeleteFn(nil),
+				},
+			},
+			want: want{
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "function-foo-old",
+					},
+				},
+			},
 		},
 	}
 
@@ -697,3 +1112,12 @@ func TestFunctionDeactivateHook(t *testing.T) {
 		})
 	}
 }
+
+// unmarshalPatch unmarshals the supplied patch of the supplied object into out.
+func unmarshalPatch(p client.Patch, obj client.Object, out any) error {
+	data, err := p.Data(obj)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
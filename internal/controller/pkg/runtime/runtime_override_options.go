@@ -119,6 +119,14 @@ func DeploymentWithOptionalReplicas(replicas int32) DeploymentOverride {
 	}
 }
 
+// DeploymentWithoutReplicas removes the replicas of a Deployment, leaving them
+// to be managed by something else, e.g. a HorizontalPodAutoscaler.
+func DeploymentWithoutReplicas() DeploymentOverride {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Replicas = nil
+	}
+}
+
 // DeploymentWithSelectors overrides the selectors of a Deployment. It also
 // ensures that the pod template labels always contains the deployment selector.
 func DeploymentWithSelectors(selectors map[string]string) DeploymentOverride {
@@ -22,7 +22,9 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -269,6 +271,59 @@ func TestProviderPostHook(t *testing.T) {
 				err: errors.Wrap(errors.Wrap(errBoom, "cannot patch object"), errApplyProviderDeployment),
 			},
 		},
+		"ErrApplyHorizontalPodAutoscaler": {
+			reason: "Should return error if we fail to apply the horizontal pod autoscaler for active provider revision.",
+			args: args{
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      providerImage,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+					Status: v1.PackageRevisionStatus{
+						ResolvedPackage: providerImage,
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{}
+					},
+					DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
+						return &appsv1.Deployment{}
+					},
+					HPAFn: func(_ string) *autoscalingv2.HorizontalPodAutoscaler {
+						return &autoscalingv2.HorizontalPodAutoscaler{}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, _ client.Object) error {
+						return nil
+					},
+					MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						if _, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler); ok {
+							return errBoom
+						}
+						return nil
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      providerImage,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+					Status: v1.PackageRevisionStatus{
+						ResolvedPackage: providerImage,
+					},
+				},
+				err: errors.Wrap(errors.Wrap(errBoom, "cannot patch object"), errApplyHPA),
+			},
+		},
 		"ErrDeploymentNoAvailableConditionYet": {
 			reason: "Should return error if deployment for active provider revision has no available condition yet.",
 			args: args{
@@ -424,6 +479,140 @@ func TestProviderPostHook(t *testing.T) {
 				},
 			},
 		},
+		"SuccessfulPinnedDigest": {
+			reason: "A provider revision that's pinned to a digest should run its controller image by that digest.",
+			args: args{
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      providerImage,
+							Digest:       providerDigest,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+					Status: v1.PackageRevisionStatus{
+						ResolvedPackage: providerPinnedImage,
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{}
+					},
+					DeploymentFn: func(_ string, overrides ...DeploymentOverride) *appsv1.Deployment {
+						d := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{}}}}}}
+						for _, o := range overrides {
+							o(d)
+						}
+						return d
+					},
+				},
+				client: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, _ client.Object) error {
+						return nil
+					},
+					MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						if d, ok := obj.(*appsv1.Deployment); ok {
+							if diff := cmp.Diff(xpkg.DefaultRegistry+"/"+providerPinnedImage, d.Spec.Template.Spec.Containers[0].Image); diff != "" {
+								t.Errorf("-want image, +got image:\n%s", diff)
+							}
+							d.Status.Conditions = []appsv1.DeploymentCondition{{
+								Type:   appsv1.DeploymentAvailable,
+								Status: corev1.ConditionTrue,
+							}}
+						}
+						return nil
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      providerImage,
+							Digest:       providerDigest,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+					Status: v1.PackageRevisionStatus{
+						ResolvedPackage: providerPinnedImage,
+					},
+				},
+			},
+		},
+		"SuccessfulScalingTemplatesRemoved": {
+			reason: "Should delete the horizontal pod autoscaler and pod disruption budget the revision controls once their templates are removed, but not ones it doesn't control.",
+			args: args{
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      providerImage,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+					Status: v1.PackageRevisionStatus{
+						ResolvedPackage: providerImage,
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{}
+					},
+					DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
+						return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+							Name:            providerRevisionName,
+							Namespace:       namespace,
+							OwnerReferences: []metav1.OwnerReference{{UID: "revision-uid", Controller: ptr.To(true)}},
+						}}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						switch obj.(type) {
+						case *autoscalingv2.HorizontalPodAutoscaler:
+							if key.Name != providerRevisionName || key.Namespace != namespace {
+								t.Errorf("unexpected horizontal pod autoscaler %s", key)
+							}
+							obj.SetOwnerReferences([]metav1.OwnerReference{{UID: "revision-uid", Controller: ptr.To(true)}})
+						case *policyv1.PodDisruptionBudget:
+							// A user created this PDB; we don't control it.
+							obj.SetOwnerReferences([]metav1.OwnerReference{{UID: "someone-else", Controller: ptr.To(true)}})
+						}
+						return nil
+					},
+					MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						if d, ok := obj.(*appsv1.Deployment); ok {
+							d.Status.Conditions = []appsv1.DeploymentCondition{{
+								Type:   appsv1.DeploymentAvailable,
+								Status: corev1.ConditionTrue,
+							}}
+						}
+						return nil
+					},
+					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+						if _, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler); ok {
+							return nil
+						}
+						t.Errorf("unexpected delete of %T %s", obj, obj.GetName())
+						return nil
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      providerImage,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+					Status: v1.PackageRevisionStatus{
+						ResolvedPackage: providerImage,
+					},
+				},
+			},
+		},
 		"SuccessWithExtraSecret": {
 			reason: "Should not return error if successfully applied service account with additional secret.",
 			args: args{
@@ -609,6 +798,7 @@ func TestProviderDeactivateHook(t *testing.T) {
 					},
 				},
 				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
 					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
 						if _, ok := obj.(*appsv1.Deployment); ok {
 							return errBoom
@@ -621,6 +811,34 @@ func TestProviderDeactivateHook(t *testing.T) {
 				err: errors.Wrap(errBoom, errDeleteProviderDeployment),
 			},
 		},
+		"ErrDeletePodDisruptionBudget": {
+			reason: "Should return error if we fail to delete the pod disruption budget.",
+			args: args{
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{}
+					},
+					DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
+						return &appsv1.Deployment{}
+					},
+					PDBFn: func(_ string) *policyv1.PodDisruptionBudget {
+						return &policyv1.PodDisruptionBudget{}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+						if _, ok := obj.(*policyv1.PodDisruptionBudget); ok {
+							return errBoom
+						}
+						return nil
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDeletePDB),
+			},
+		},
 		"Successful": {
 			reason: "Should not return error if successfully deleted service account and deployment.",
 			args: args{
@@ -657,6 +875,7 @@ func TestProviderDeactivateHook(t *testing.T) {
 					},
 				},
 				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
 					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
 						switch obj.(type) {
 						case *corev1.ServiceAccount:
@@ -0,0 +1,111 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errApplyHPA  = "cannot apply package horizontal pod autoscaler"
+	errDeleteHPA = "cannot delete package horizontal pod autoscaler"
+	errApplyPDB  = "cannot apply package pod disruption budget"
+	errDeletePDB = "cannot delete package pod disruption budget"
+)
+
+// applyScaling applies the HorizontalPodAutoscaler and PodDisruptionBudget for
+// the supplied Deployment, if the package should have them. They're owned by
+// the package revision, and thus garbage collected with it. If the package
+// shouldn't have them, for example because their template was removed from
+// the runtime config, it deletes any the package revision has.
+func applyScaling(ctx context.Context, cl resource.ClientApplicator, build ManifestBuilder, d *appsv1.Deployment) error {
+	if hpa := build.HorizontalPodAutoscaler(d.GetName()); hpa != nil {
+		if err := cl.Apply(ctx, hpa); err != nil {
+			return errors.Wrap(err, errApplyHPA)
+		}
+	} else if err := deleteControlled(ctx, cl, d, defaultHPA(d)); err != nil {
+		return errors.Wrap(err, errDeleteHPA)
+	}
+	if pdb := build.PodDisruptionBudget(d.GetName()); pdb != nil {
+		if err := cl.Apply(ctx, pdb); err != nil {
+			return errors.Wrap(err, errApplyPDB)
+		}
+	} else if err := deleteControlled(ctx, cl, d, defaultPDB(d)); err != nil {
+		return errors.Wrap(err, errDeletePDB)
+	}
+	return nil
+}
+
+// deleteScaling deletes the HorizontalPodAutoscaler and PodDisruptionBudget
+// for the supplied Deployment, if the package has them.
+func deleteScaling(ctx context.Context, cl resource.ClientApplicator, build ManifestBuilder, d *appsv1.Deployment) error {
+	if hpa := build.HorizontalPodAutoscaler(d.GetName()); hpa != nil {
+		if err := cl.Delete(ctx, hpa); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteHPA)
+		}
+	} else if err := deleteControlled(ctx, cl, d, defaultHPA(d)); err != nil {
+		return errors.Wrap(err, errDeleteHPA)
+	}
+	if pdb := build.PodDisruptionBudget(d.GetName()); pdb != nil {
+		if err := cl.Delete(ctx, pdb); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeletePDB)
+		}
+	} else if err := deleteControlled(ctx, cl, d, defaultPDB(d)); err != nil {
+		return errors.Wrap(err, errDeletePDB)
+	}
+	return nil
+}
+
+// defaultHPA returns a HorizontalPodAutoscaler with the name and namespace
+// the supplied Deployment's HorizontalPodAutoscaler has unless its template
+// overrides its name.
+func defaultHPA(d *appsv1.Deployment) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: d.GetName(), Namespace: d.GetNamespace()}}
+}
+
+// defaultPDB returns a PodDisruptionBudget with the name and namespace the
+// supplied Deployment's PodDisruptionBudget has unless its template overrides
+// its name.
+func defaultPDB(d *appsv1.Deployment) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: d.GetName(), Namespace: d.GetNamespace()}}
+}
+
+// deleteControlled deletes the supplied object if it exists and is controlled
+// by the same package revision as the supplied Deployment. We don't delete
+// objects we don't control, for example a HorizontalPodAutoscaler a user
+// created for the Deployment before runtime configs supported them.
+func deleteControlled(ctx context.Context, cl client.Client, d *appsv1.Deployment, o client.Object) error {
+	if err := cl.Get(ctx, types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}, o); err != nil {
+		return resource.IgnoreNotFound(err)
+	}
+	want := metav1.GetControllerOf(d)
+	got := metav1.GetControllerOf(o)
+	if want == nil || got == nil || want.UID != got.UID {
+		return nil
+	}
+	return resource.IgnoreNotFound(cl.Delete(ctx, o))
+}
@@ -21,7 +21,9 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -36,6 +38,8 @@ const (
 	namespace = "crossplane-system"
 
 	providerImage        = "crossplane/provider-foo:v1.2.3"
+	providerDigest       = "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d7f1f0f4e1e8c1a"
+	providerPinnedImage  = "crossplane/provider-foo@" + providerDigest
 	providerName         = "upbound-provider-foo"
 	providerMetaName     = "provider-foo"
 	providerRevisionName = "provider-foo-1234"
@@ -184,6 +188,32 @@ func TestRuntimeManifestBuilderDeployment(t *testing.T) {
 				}),
 			},
 		},
+		"ProviderDeploymentWithHorizontalPodAutoscaler": {
+			reason: "The deployment shouldn't specify replicas when they're managed by a horizontal pod autoscaler",
+			args: args{
+				builder: &DeploymentRuntimeBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							HorizontalPodAutoscalerTemplate: &v1beta1.HorizontalPodAutoscalerTemplate{
+								Spec: v1beta1.HorizontalPodAutoscalerSpec{
+									MaxReplicas: 3,
+								},
+							},
+						},
+					},
+				},
+				serviceAccountName: providerRevisionName,
+				overrides:          providerDeploymentOverrides(providerRevision, providerImage),
+			},
+			want: want{
+				want: deploymentProvider(providerName, providerRevisionName, providerImage, DeploymentWithSelectors(map[string]string{
+					"pkg.crossplane.io/provider": providerName,
+					"pkg.crossplane.io/revision": providerRevisionName,
+				}), DeploymentWithoutReplicas()),
+			},
+		},
 		"ProviderDeploymentNoScrapeAnnotation": {
 			reason: "It should be possible to disable default scrape annotations",
 			args: args{
@@ -421,6 +451,282 @@ func TestRuntimeManifestBuilderService(t *testing.T) {
 	}
 }
 
+func TestRuntimeManifestBuilderHorizontalPodAutoscaler(t *testing.T) {
+	type args struct {
+		builder    ManifestBuilder
+		deployment string
+	}
+	type want struct {
+		want *autoscalingv2.HorizontalPodAutoscaler
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoRuntimeConfig": {
+			reason: "No runtime config on the builder should result in no horizontal pod autoscaler.",
+			args: args{
+				builder: &DeploymentRuntimeBuilder{
+					revision:  functionRevision,
+					namespace: namespace,
+				},
+				deployment: functionRevisionName,
+			},
+			want: want{},
+		},
+		"FunctionRequests": {
+			reason: "A function's horizontal pod autoscaler should scale its deployment, including on the rate of requests to the function.",
+			args: args{
+				builder: &DeploymentRuntimeBuilder{
+					revision:  functionRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							HorizontalPodAutoscalerTemplate: &v1beta1.HorizontalPodAutoscalerTemplate{
+								Metadata: &v1beta1.ObjectMeta{
+									Labels: map[string]string{"foo": "bar"},
+								},
+								Spec: v1beta1.HorizontalPodAutoscalerSpec{
+									MinReplicas: ptr.To[int32](2),
+									MaxReplicas: 10,
+									FunctionRequests: &v1beta1.FunctionRequestsMetric{
+										TargetAverageValue: resource.MustParse("50"),
+									},
+								},
+							},
+						},
+					},
+				},
+				deployment: functionRevisionName,
+			},
+			want: want{
+				want: &autoscalingv2.HorizontalPodAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      functionRevisionName,
+						Namespace: namespace,
+						Labels:    map[string]string{"foo": "bar"},
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								APIVersion:         "pkg.crossplane.io/v1beta1",
+								Kind:               "FunctionRevision",
+								Name:               functionRevisionName,
+								Controller:         ptr.To(true),
+								BlockOwnerDeletion: ptr.To(true),
+							},
+						},
+					},
+					Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+						ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+							APIVersion: "apps/v1",
+							Kind:       "Deployment",
+							Name:       functionRevisionName,
+						},
+						MinReplicas: ptr.To[int32](2),
+						MaxReplicas: 10,
+						Metrics: []autoscalingv2.MetricSpec{
+							{
+								Type: autoscalingv2.ExternalMetricSourceType,
+								External: &autoscalingv2.ExternalMetricSource{
+									Metric: autoscalingv2.MetricIdentifier{
+										Name: DefaultFunctionRequestsMetricName,
+										Selector: &metav1.LabelSelector{
+											MatchLabels: map[string]string{"function_name": functionName},
+										},
+									},
+									Target: autoscalingv2.MetricTarget{
+										Type:         autoscalingv2.AverageValueMetricType,
+										AverageValue: ptr.To(resource.MustParse("50")),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"ProviderIgnoresFunctionRequests": {
+			reason: "A provider's horizontal pod autoscaler shouldn't scale on the rate of function requests.",
+			args: args{
+				builder: &DeploymentRuntimeBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							HorizontalPodAutoscalerTemplate: &v1beta1.HorizontalPodAutoscalerTemplate{
+								Metadata: &v1beta1.ObjectMeta{
+									Name: ptr.To("custom-hpa"),
+								},
+								Spec: v1beta1.HorizontalPodAutoscalerSpec{
+									MaxReplicas: 3,
+									FunctionRequests: &v1beta1.FunctionRequestsMetric{
+										TargetAverageValue: resource.MustParse("50"),
+									},
+								},
+							},
+						},
+					},
+				},
+				deployment: providerRevisionName,
+			},
+			want: want{
+				want: &autoscalingv2.HorizontalPodAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "custom-hpa",
+						Namespace: namespace,
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								APIVersion:         "pkg.crossplane.io/v1",
+								Kind:               "ProviderRevision",
+								Name:               providerRevisionName,
+								Controller:         ptr.To(true),
+								BlockOwnerDeletion: ptr.To(true),
+							},
+						},
+					},
+					Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+						ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+							APIVersion: "apps/v1",
+							Kind:       "Deployment",
+							Name:       providerRevisionName,
+						},
+						MaxReplicas: 3,
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.args.builder.HorizontalPodAutoscaler(tc.args.deployment)
+			if diff := cmp.Diff(tc.want.want, got); diff != "" {
+				t.Errorf("\n%s\nHorizontalPodAutoscaler(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRuntimeManifestBuilderPodDisruptionBudget(t *testing.T) {
+	type args struct {
+		builder    ManifestBuilder
+		deployment string
+	}
+	type want struct {
+		want *policyv1.PodDisruptionBudget
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoRuntimeConfig": {
+			reason: "No runtime config on the builder should result in no pod disruption budget.",
+			args: args{
+				builder: &DeploymentRuntimeBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+				},
+				deployment: providerRevisionName,
+			},
+			want: want{},
+		},
+		"DefaultMaxUnavailable": {
+			reason: "A pod disruption budget that doesn't specify minAvailable or maxUnavailable should allow one unavailable pod.",
+			args: args{
+				builder: &DeploymentRuntimeBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							PodDisruptionBudgetTemplate: &v1beta1.PodDisruptionBudgetTemplate{},
+						},
+					},
+				},
+				deployment: providerRevisionName,
+			},
+			want: want{
+				want: &policyv1.PodDisruptionBudget{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      providerRevisionName,
+						Namespace: namespace,
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								APIVersion:         "pkg.crossplane.io/v1",
+								Kind:               "ProviderRevision",
+								Name:               providerRevisionName,
+								Controller:         ptr.To(true),
+								BlockOwnerDeletion: ptr.To(true),
+							},
+						},
+					},
+					Spec: policyv1.PodDisruptionBudgetSpec{
+						MaxUnavailable: ptr.To(intstr.FromInt32(1)),
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"pkg.crossplane.io/provider": providerName,
+								"pkg.crossplane.io/revision": providerRevisionName,
+							},
+						},
+					},
+				},
+			},
+		},
+		"MinAvailable": {
+			reason: "A pod disruption budget should use the minAvailable from the runtime config.",
+			args: args{
+				builder: &DeploymentRuntimeBuilder{
+					revision:  functionRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							PodDisruptionBudgetTemplate: &v1beta1.PodDisruptionBudgetTemplate{
+								Spec: &v1beta1.PodDisruptionBudgetSpec{
+									MinAvailable: ptr.To(intstr.FromString("50%")),
+								},
+							},
+						},
+					},
+				},
+				deployment: functionRevisionName,
+			},
+			want: want{
+				want: &policyv1.PodDisruptionBudget{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      functionRevisionName,
+						Namespace: namespace,
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								APIVersion:         "pkg.crossplane.io/v1beta1",
+								Kind:               "FunctionRevision",
+								Name:               functionRevisionName,
+								Controller:         ptr.To(true),
+								BlockOwnerDeletion: ptr.To(true),
+							},
+						},
+					},
+					Spec: policyv1.PodDisruptionBudgetSpec{
+						MinAvailable: ptr.To(intstr.FromString("50%")),
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"pkg.crossplane.io/function": functionName,
+								"pkg.crossplane.io/revision": functionRevisionName,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.args.builder.PodDisruptionBudget(tc.args.deployment)
+			if diff := cmp.Diff(tc.want.want, got); diff != "" {
+				t.Errorf("\n%s\nPodDisruptionBudget(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func deploymentProvider(provider string, rev string, image string, overrides ...DeploymentOverride) *appsv1.Deployment {
 	d := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -700,6 +1006,8 @@ type MockManifestBuilder struct {
 	ServiceFn         func(overrides ...ServiceOverride) *corev1.Service
 	TLSClientSecretFn func() *corev1.Secret
 	TLSServerSecretFn func() *corev1.Secret
+	HPAFn             func(deployment string) *autoscalingv2.HorizontalPodAutoscaler
+	PDBFn             func(deployment string) *policyv1.PodDisruptionBudget
 }
 
 // ServiceAccount returns the result of calling ServiceAccountFn.
@@ -726,3 +1034,21 @@ func (b *MockManifestBuilder) TLSClientSecret() *corev1.Secret {
 func (b *MockManifestBuilder) TLSServerSecret() *corev1.Secret {
 	return b.TLSServerSecretFn()
 }
+
+// HorizontalPodAutoscaler returns the result of calling HPAFn, or nil if it
+// isn't set.
+func (b *MockManifestBuilder) HorizontalPodAutoscaler(deployment string) *autoscalingv2.HorizontalPodAutoscaler {
+	if b.HPAFn == nil {
+		return nil
+	}
+	return b.HPAFn(deployment)
+}
+
+// PodDisruptionBudget returns the result of calling PDBFn, or nil if it isn't
+// set.
+func (b *MockManifestBuilder) PodDisruptionBudget(deployment string) *policyv1.PodDisruptionBudget {
+	if b.PDBFn == nil {
+		return nil
+	}
+	return b.PDBFn(deployment)
+}
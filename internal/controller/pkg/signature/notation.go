@@ -0,0 +1,403 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signature
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	ggcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/registry"
+	"github.com/notaryproject/notation-go/verifier"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	// ArtifactTypeNotation is the artifact type of a Notation signature.
+	ArtifactTypeNotation = "application/vnd.cncf.notary.signature"
+
+	// maxNotationSignatures is the maximum number of signatures we'll try
+	// to verify for an image.
+	maxNotationSignatures = 50
+)
+
+const (
+	errUnsupportedProvider    = "unsupported image verification provider"
+	errNoNotationConfig       = "no notation verification config"
+	errNoCosignConfig         = "no cosign verification config"
+	errNewAuthChain           = "cannot create registry auth chain"
+	errReadTrustPolicy        = "cannot read notation trust policy"
+	errParseTrustPolicy       = "cannot parse notation trust policy"
+	errFmtReadTrustStore      = "cannot read notation trust store %q"
+	errFmtParseTrustStore     = "cannot parse notation trust store %q"
+	errFmtNoTrustStore        = "notation trust store %q not found"
+	errNewNotationVerifier    = "cannot create notation verifier"
+	errNotationVerification   = "notation signature verification failed"
+	errNoSource               = "neither a secretRef nor a configMapRef is set"
+	errFmtGetSecret           = "cannot get secret %q"
+	errFmtGetConfigMap        = "cannot get configmap %q"
+	errFmtNoKey               = "no data found for key %q in %q"
+	errNoCertificates         = "no PEM encoded certificates found"
+	errFmtSignatureLayers     = "signature manifest must have exactly one layer, got %d"
+	errUnmarshalSignature     = "cannot unmarshal signature manifest"
+	errFetchSignatureManifest = "cannot fetch signature manifest"
+	errFetchSignatureBlob     = "cannot fetch signature blob"
+)
+
+// NewNotationValidator returns a new NotationValidator. It reads trust
+// policies and trust stores from the supplied namespace, and authenticates to
+// registries using the supplied Keychainer. The supplied reader should read
+// directly from the API server; a cached reader would watch every ConfigMap
+// in the cluster.
+func NewNotationValidator(c client.Reader, kc Keychainer, namespace string) *NotationValidator {
+	return &NotationValidator{
+		client:    c,
+		keychain:  kc,
+		namespace: namespace,
+	}
+}
+
+// NotationValidator validates image signatures using Notation (Notary v2).
+type NotationValidator struct {
+	client    client.Reader
+	keychain  Keychainer
+	namespace string
+}
+
+// Validate validates the image signature against the trust policy and trust
+// stores of the supplied config. Notation doesn't support attestations, so it
+// never returns attestation results.
+func (n *NotationValidator) Validate(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, pullSecrets ...string) ([]v1.AttestationResult, error) {
+	if config.Provider != v1beta1.ImageVerificationProviderNotation {
+		return nil, errors.New(errUnsupportedProvider)
+	}
+	if config.Notation == nil {
+		return nil, errors.New(errNoNotationConfig)
+	}
+
+	auth, err := n.keychain.Keychain(ctx, ref, pullSecrets...)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewAuthChain)
+	}
+
+	return n.ValidateWithOptions(ctx, ref, config, remote.WithAuthFromKeychain(auth))
+}
+
+// ValidateWithOptions validates the image signature like Validate, accessing
+// the registry using the supplied remote options.
+func (n *NotationValidator) ValidateWithOptions(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, o ...remote.Option) ([]v1.AttestationResult, error) {
+	if config.Provider != v1beta1.ImageVerificationProviderNotation {
+		return nil, errors.New(errUnsupportedProvider)
+	}
+	if config.Notation == nil {
+		return nil, errors.New(errNoNotationConfig)
+	}
+
+	b, err := n.read(ctx, config.Notation.TrustPolicy)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadTrustPolicy)
+	}
+	doc := &trustpolicy.Document{}
+	if err := json.Unmarshal(b, doc); err != nil {
+		return nil, errors.Wrap(err, errParseTrustPolicy)
+	}
+
+	ts := trustStore{}
+	for _, s := range config.Notation.TrustStores {
+		id := string(s.Type) + ":" + s.Name
+		b, err := n.read(ctx, s.Certificates)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtReadTrustStore, id)
+		}
+		certs, err := parseCertificates(b)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParseTrustStore, id)
+		}
+		ts[id] = append(ts[id], certs...)
+	}
+
+	v, err := verifier.New(doc, ts, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewNotationVerifier)
+	}
+
+	repo := NewRepository(ref.Context(), o...)
+	_, _, err = notation.Verify(ctx, v, repo, notation.VerifyOptions{
+		ArtifactReference:    artifactReference(ref),
+		MaxSignatureAttempts: maxNotationSignatures,
+	})
+	return nil, errors.Wrap(err, errNotationVerification)
+}
+
+// read returns the data referenced by the supplied source.
+func (n *NotationValidator) read(ctx context.Context, src v1beta1.NotationSource) ([]byte, error) {
+	switch {
+	case src.SecretRef != nil:
+		s := &corev1.Secret{}
+		if err := n.client.Get(ctx, types.NamespacedName{Name: src.SecretRef.Name, Namespace: n.namespace}, s); err != nil {
+			return nil, errors.Wrapf(err, errFmtGetSecret, src.SecretRef.Name)
+		}
+		if v := s.Data[src.SecretRef.Key]; len(v) > 0 {
+			return v, nil
+		}
+		return nil, errors.Errorf(errFmtNoKey, src.SecretRef.Key, src.SecretRef.Name)
+	case src.ConfigMapRef != nil:
+		cm := &corev1.ConfigMap{}
+		if err := n.client.Get(ctx, types.NamespacedName{Name: src.ConfigMapRef.Name, Namespace: n.namespace}, cm); err != nil {
+			return nil, errors.Wrapf(err, errFmtGetConfigMap, src.ConfigMapRef.Name)
+		}
+		if v := cm.Data[src.ConfigMapRef.Key]; v != "" {
+			return []byte(v), nil
+		}
+		if v := cm.BinaryData[src.ConfigMapRef.Key]; len(v) > 0 {
+			return v, nil
+		}
+		return nil, errors.Errorf(errFmtNoKey, src.ConfigMapRef.Key, src.ConfigMapRef.Name)
+	default:
+		return nil, errors.New(errNoSource)
+	}
+}
+
+// artifactReference returns the supplied reference in the form Notation
+// matches against trust policy registry scopes, i.e. registry/repository
+// followed by a tag or digest.
+func artifactReference(ref name.Reference) string {
+	if d, ok := ref.(name.Digest); ok {
+		return ref.Context().Name() + "@" + d.DigestStr()
+	}
+	return ref.Context().Name() + ":" + ref.Identifier()
+}
+
+// parseCertificates parses all PEM encoded certificates in the supplied data.
+func parseCertificates(b []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var blk *pem.Block
+		blk, b = pem.Decode(b)
+		if blk == nil {
+			break
+		}
+		if blk.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(blk.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, c)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New(errNoCertificates)
+	}
+	return certs, nil
+}
+
+// A trustStore is an in-memory Notation trust store. Certificates are keyed by
+// <type>:<name>, the form used to refer to trust stores in a trust policy.
+type trustStore map[string][]*x509.Certificate
+
+// GetCertificates returns the certificates of the supplied named store.
+func (ts trustStore) GetCertificates(_ context.Context, t truststore.Type, namedStore string) ([]*x509.Certificate, error) {
+	id := string(t) + ":" + namedStore
+	certs, ok := ts[id]
+	if !ok {
+		return nil, truststore.TrustStoreError{Msg: errors.Errorf(errFmtNoTrustStore, id).Error()}
+	}
+	return certs, nil
+}
+
+// NewRepository returns a Notation registry.Repository backed by the supplied
+// OCI repository. Signatures are discovered using the OCI referrers API.
+func NewRepository(r name.Repository, o ...remote.Option) *Repository {
+	return &Repository{repo: r, opts: o}
+}
+
+// A Repository is a Notation registry.Repository backed by go-containerregistry.
+type Repository struct {
+	repo name.Repository
+	opts []remote.Option
+}
+
+var _ registry.Repository = &Repository{}
+
+func (r *Repository) options(ctx context.Context, o ...remote.Option) []remote.Option {
+	return append(append([]remote.Option{remote.WithContext(ctx)}, r.opts...), o...)
+}
+
+// Resolve resolves the supplied tag or digest to a manifest descriptor.
+func (r *Repository) Resolve(ctx context.Context, reference string) (ocispec.Descriptor, error) {
+	var ref name.Reference = r.repo.Tag(reference)
+	if _, err := digest.Parse(reference); err == nil {
+		ref = r.repo.Digest(reference)
+	}
+	d, err := remote.Head(ref, r.options(ctx)...)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return toOCI(*d), nil
+}
+
+// ListSignatures lists the signature manifests that refer to the supplied
+// manifest descriptor.
+func (r *Repository) ListSignatures(ctx context.Context, desc ocispec.Descriptor, fn func(signatureManifests []ocispec.Descriptor) error) error {
+	idx, err := remote.Referrers(r.repo.Digest(desc.Digest.String()), r.options(ctx, remote.WithFilter("artifactType", ArtifactTypeNotation))...)
+	if err != nil {
+		return err
+	}
+	m, err := idx.IndexManifest()
+	if err != nil {
+		return err
+	}
+	sigs := make([]ocispec.Descriptor, 0, len(m.Manifests))
+	for _, d := range m.Manifests {
+		sigs = append(sigs, toOCI(d))
+	}
+	return fn(sigs)
+}
+
+// FetchSignatureBlob returns the signature envelope of the supplied signature
+// manifest descriptor.
+func (r *Repository) FetchSignatureBlob(ctx context.Context, desc ocispec.Descriptor) ([]byte, ocispec.Descriptor, error) {
+	d, err := remote.Get(r.repo.Digest(desc.Digest.String()), r.options(ctx)...)
+	if err != nil {
+		return nil, ocispec.Descriptor{}, errors.Wrap(err, errFetchSignatureManifest)
+	}
+	m := &ocispec.Manifest{}
+	if err := json.Unmarshal(d.Manifest, m); err != nil {
+		return nil, ocispec.Descriptor{}, errors.Wrap(err, errUnmarshalSignature)
+	}
+	if len(m.Layers) != 1 {
+		return nil, ocispec.Descriptor{}, errors.Errorf(errFmtSignatureLayers, len(m.Layers))
+	}
+	l, err := remote.Layer(r.repo.Digest(m.Layers[0].Digest.String()), r.options(ctx)...)
+	if err != nil {
+		return nil, ocispec.Descriptor{}, errors.Wrap(err, errFetchSignatureBlob)
+	}
+	rc, err := l.Compressed()
+	if err != nil {
+		return nil, ocispec.Descriptor{}, errors.Wrap(err, errFetchSignatureBlob)
+	}
+	defer rc.Close() //nolint:errcheck // Only reading.
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, ocispec.Descriptor{}, errors.Wrap(err, errFetchSignatureBlob)
+	}
+	return b, m.Layers[0], nil
+}
+
+// PushSignature pushes a signature envelope, and a signature manifest that
+// refers to the supplied subject.
+func (r *Repository) PushSignature(ctx context.Context, mediaType string, blob []byte, subject ocispec.Descriptor, annotations map[string]string) (ocispec.Descriptor, ocispec.Descriptor, error) {
+	envelope := static.NewLayer(blob, ggcrtypes.MediaType(mediaType))
+	cfg := static.NewLayer([]byte("{}"), ggcrtypes.MediaType(ArtifactTypeNotation))
+	for _, l := range []ggcrv1.Layer{envelope, cfg} {
+		if err := remote.WriteLayer(r.repo, l, r.options(ctx)...); err != nil {
+			return ocispec.Descriptor{}, ocispec.Descriptor{}, err
+		}
+	}
+
+	blobDesc := ocispec.Descriptor{MediaType: mediaType, Digest: digest.FromBytes(blob), Size: int64(len(blob))}
+	m := ocispec.Manifest{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: ArtifactTypeNotation,
+		// Registries that predate the artifactType field use the config
+		// media type as the artifact type of a referrer.
+		Config:      ocispec.Descriptor{MediaType: ArtifactTypeNotation, Digest: digest.FromString("{}"), Size: 2},
+		Layers:      []ocispec.Descriptor{blobDesc},
+		Subject:     &subject,
+		Annotations: annotations,
+	}
+	m.SchemaVersion = 2
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
+	}
+	md := ocispec.Descriptor{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: ArtifactTypeNotation,
+		Digest:       digest.FromBytes(raw),
+		Size:         int64(len(raw)),
+		Annotations:  annotations,
+	}
+	if err := remote.Put(r.repo.Digest(md.Digest.String()), rawManifest{raw: raw, mediaType: ggcrtypes.OCIManifestSchema1}, r.options(ctx)...); err != nil {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
+	}
+	return blobDesc, md, nil
+}
+
+// A rawManifest is a manifest that can be pushed using remote.Put.
+type rawManifest struct {
+	raw       []byte
+	mediaType ggcrtypes.MediaType
+}
+
+func (m rawManifest) RawManifest() ([]byte, error)            { return m.raw, nil }
+func (m rawManifest) MediaType() (ggcrtypes.MediaType, error) { return m.mediaType, nil }
+
+func toOCI(d ggcrv1.Descriptor) ocispec.Descriptor {
+	return ocispec.Descriptor{
+		MediaType:    string(d.MediaType),
+		ArtifactType: d.ArtifactType,
+		Digest:       digest.Digest(d.Digest.String()),
+		Size:         d.Size,
+		Annotations:  d.Annotations,
+	}
+}
+
+// A ProviderValidator validates image signatures using the Validator for the
+// provider of the supplied image verification config.
+type ProviderValidator map[v1beta1.ImageVerificationProvider]Validator
+
+// Validate validates the image signature using the Validator for the
+// configured provider.
+func (pv ProviderValidator) Validate(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, pullSecrets ...string) ([]v1.AttestationResult, error) {
+	v, ok := pv[config.Provider]
+	if !ok {
+		return nil, errors.Errorf("%s %q", errUnsupportedProvider, config.Provider)
+	}
+	return v.Validate(ctx, ref, config, pullSecrets...)
+}
+
+// ValidateWithOptions validates the image signature using the Validator for
+// the configured provider, accessing the registry using the supplied remote
+// options.
+func (pv ProviderValidator) ValidateWithOptions(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, o ...remote.Option) ([]v1.AttestationResult, error) {
+	v, ok := pv[config.Provider]
+	if !ok {
+		return nil, errors.Errorf("%s %q", errUnsupportedProvider, config.Provider)
+	}
+	return v.ValidateWithOptions(ctx, ref, config, o...)
+}
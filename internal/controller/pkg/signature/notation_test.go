@@ -0,0 +1,254 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signature
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/signer"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const trustPolicyFmt = `{
+	"version": "1.0",
+	"trustPolicies": [{
+		"name": "crossplane",
+		"registryScopes": ["%s"],
+		"signatureVerification": {"level": "strict"},
+		"trustStores": ["ca:crossplane"],
+		"trustedIdentities": ["*"]
+	}]
+}`
+
+func TestNotationValidator(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(registry.New(registry.WithReferrersSupport(true), registry.Logger(log.New(io.Discard, "", 0))))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	signed := pushRandomImage(t, host+"/crossplane/signed:v1.0.0")
+	unsigned := pushRandomImage(t, host+"/crossplane/unsigned:v1.0.0")
+
+	ca, caKey := newCertificate(t, nil, nil)
+	leaf, leafKey := newCertificate(t, ca, caKey)
+	untrusted, _ := newCertificate(t, nil, nil)
+
+	s, err := signer.NewGenericSigner(leafKey, []*x509.Certificate{leaf, ca})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := notation.Sign(ctx, s, NewRepository(signed.Context(), remote.WithContext(ctx)), notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: "application/jose+json"},
+		ArtifactReference: signed.String(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := fmt.Sprintf(trustPolicyFmt, host+"/crossplane/signed")
+	cfg := &v1beta1.ImageVerification{
+		Provider: v1beta1.ImageVerificationProviderNotation,
+		Notation: &v1beta1.NotationVerificationConfig{
+			TrustPolicy: v1beta1.NotationSource{
+				ConfigMapRef: &v1beta1.LocalConfigMapKeySelector{Name: "notation", Key: "trustpolicy.json"},
+			},
+			TrustStores: []v1beta1.NotationTrustStore{{
+				Type: v1beta1.NotationTrustStoreCA,
+				Name: "crossplane",
+				Certificates: v1beta1.NotationSource{
+					SecretRef: &v1beta1.LocalSecretKeySelector{
+						LocalSecretReference: xpv1.LocalSecretReference{Name: "notation"},
+						Key:                  "ca.crt",
+					},
+				},
+			}},
+		},
+	}
+
+	type args struct {
+		ref    name.Reference
+		policy string
+		ca     *x509.Certificate
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"Verified": {
+			reason: "We should verify an image signed by a certificate issued by a trusted CA.",
+			args: args{
+				ref:    signed,
+				policy: policy,
+				ca:     ca,
+			},
+		},
+		"UntrustedCA": {
+			reason: "We should return an error if the image was signed by a certificate that isn't issued by a trusted CA.",
+			args: args{
+				ref:    signed,
+				policy: policy,
+				ca:     untrusted,
+			},
+			want: cmpopts.AnyError,
+		},
+		"Unsigned": {
+			reason: "We should return an error if the image isn't signed.",
+			args: args{
+				ref:    unsigned,
+				policy: fmt.Sprintf(trustPolicyFmt, host+"/crossplane/unsigned"),
+				ca:     ca,
+			},
+			want: cmpopts.AnyError,
+		},
+		"NoApplicablePolicy": {
+			reason: "We should return an error if no trust policy applies to the image.",
+			args: args{
+				ref:    unsigned,
+				policy: policy,
+				ca:     ca,
+			},
+			want: cmpopts.AnyError,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					switch o := obj.(type) {
+					case *corev1.ConfigMap:
+						o.Data = map[string]string{"trustpolicy.json": tc.args.policy}
+					case *corev1.Secret:
+						o.Data = map[string][]byte{"ca.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tc.args.ca.Raw})}
+					}
+					return nil
+				},
+			}
+			f, err := xpkg.NewK8sFetcher(fake.NewSimpleClientset(), xpkg.WithNamespace("crossplane-system"), xpkg.WithServiceAccount("crossplane"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			v := NewNotationValidator(c, f, "crossplane-system")
+
+			res, err := v.Validate(ctx, tc.args.ref, cfg)
+			if diff := cmp.Diff(tc.want, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff([]v1.AttestationResult(nil), res); diff != "" {
+				t.Errorf("\n%s\nValidate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// pushRandomImage pushes a random image to the supplied reference and returns
+// a digest reference to it.
+func pushRandomImage(t *testing.T, ref string) name.Reference {
+	t.Helper()
+
+	r, err := name.ParseReference(ref, name.Insecure)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(r, img); err != nil {
+		t.Fatal(err)
+	}
+	d, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r.Context().Digest(d.String())
+}
+
+// newCertificate returns a code signing certificate issued by the supplied
+// parent, or a self-signed CA certificate if the parent is nil.
+func newCertificate(t *testing.T, parent *x509.Certificate, parentKey crypto.Signer) (*x509.Certificate, crypto.Signer) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "Crossplane Test", Organization: []string{"Crossplane"}, Country: []string{"US"}, Province: []string{"WA"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	if parent == nil {
+		tmpl.Subject.CommonName = "Crossplane Test CA"
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = tmpl, key
+	} else {
+		tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, key.Public(), parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, key
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signature
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sigstore/cosign/v2/pkg/oci"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	errUnmarshalPayload   = "cannot unmarshal attestation payload"
+	errUnmarshalResult    = "cannot unmarshal vulnerability scanner result"
+	errNotVulnPredicate   = "attestation predicate is not a cosign vulnerability scan result"
+	errUnsupportedResult  = "unsupported vulnerability scanner result format: must be SARIF, Trivy JSON, or Grype JSON"
+	errFmtSeverity        = "found %d vulnerabilities more severe than %s: %s"
+	errFmtInvalidSeverity = "invalid security-severity %q for rule %q"
+)
+
+// severities ranks vulnerability severities. Scanners use different names and
+// cases. Severities we don't know, like Unknown, rank as severityUnknown.
+var severities = map[string]int{
+	"none":       0,
+	"negligible": 0,
+	"low":        1,
+	"medium":     2,
+	"moderate":   2,
+	"high":       3,
+	"critical":   4,
+}
+
+// severityUnknown ranks vulnerabilities of unknown severity above Critical, so
+// that no policy allows them unless they're explicitly ignored.
+const severityUnknown = 5
+
+// rank returns the rank of the supplied severity.
+func rank(severity string) int {
+	r, ok := severities[strings.ToLower(severity)]
+	if !ok {
+		return severityUnknown
+	}
+	return r
+}
+
+// verifyAttestations verifies each of the attestations the supplied authority
+// requires against the supplied verified attestations. It returns the result
+// of verifying each attestation, and an error unless all of them passed.
+func verifyAttestations(ctx context.Context, a v1beta1.CosignAuthority, verified []oci.Signature) ([]v1.AttestationResult, error) {
+	results := make([]v1.AttestationResult, 0, len(a.Attestations))
+	var errs []error
+	for _, att := range a.Attestations {
+		r := verifyAttestation(ctx, att, verified)
+		r.Authority = a.Name
+		results = append(results, r)
+		if r.Outcome != v1.AttestationPassed {
+			errs = append(errs, errors.Errorf("authority %q: attestation %q: %s", a.Name, att.Name, r.Message))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// verifyAttestation passes if at least one of the supplied verified
+// attestations is of the required predicate type and satisfies the required
+// policy, if any.
+func verifyAttestation(ctx context.Context, att v1beta1.Attestation, verified []oci.Signature) v1.AttestationResult {
+	r := v1.AttestationResult{
+		Name:          att.Name,
+		PredicateType: att.PredicateType,
+		Outcome:       v1.AttestationMissing,
+		Message:       fmt.Sprintf("no attestation of type %q found", att.PredicateType),
+	}
+	for _, s := range verified {
+		b, _, err := attestationToPayloadJSON(ctx, att.PredicateType, s)
+		if err != nil {
+			// Don't obscure why an attestation of the required type failed
+			// the policy with an error converting some other attestation.
+			if r.Outcome != v1.AttestationFailed {
+				r.Message = fmt.Sprintf("cannot convert attestation to payload JSON: %v", err)
+			}
+			continue
+		}
+		if len(b) == 0 {
+			continue
+		}
+		if err := checkAttestationPolicy(b, att.Policy); err != nil {
+			if r.Outcome != v1.AttestationFailed {
+				r.Outcome = v1.AttestationFailed
+				r.Message = err.Error()
+			}
+			continue
+		}
+		r.Outcome = v1.AttestationPassed
+		r.Message = ""
+		return r
+	}
+	return r
+}
+
+// checkAttestationPolicy returns an error if the supplied attestation payload,
+// as returned by attestationToPayloadJSON, doesn't satisfy the supplied policy.
+func checkAttestationPolicy(payload []byte, p *v1beta1.AttestationPolicy) error {
+	if p == nil || p.MaxVulnerabilitySeverity == nil {
+		return nil
+	}
+
+	// A cosign vulnerability attestation wraps the scanner's own result. See
+	// https://github.com/sigstore/cosign/blob/main/specs/COSIGN_VULN_ATTESTATION_SPEC.md
+	statement := struct {
+		Predicate struct {
+			Scanner struct {
+				Result json.RawMessage `json:"result"`
+			} `json:"scanner"`
+		} `json:"predicate"`
+	}{}
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return errors.Wrap(err, errUnmarshalPayload)
+	}
+	result := statement.Predicate.Scanner.Result
+	if len(result) == 0 || string(result) == "null" {
+		return errors.New(errNotVulnPredicate)
+	}
+
+	vulns, err := vulnerabilities(result)
+	if err != nil {
+		return err
+	}
+
+	ignored := map[string]bool{}
+	for _, id := range p.IgnoredVulnerabilities {
+		ignored[id] = true
+	}
+
+	limit := rank(string(*p.MaxVulnerabilitySeverity))
+	found := map[string]bool{}
+	for _, v := range vulns {
+		if ignored[v.id] || rank(v.severity) <= limit {
+			continue
+		}
+		found[v.id] = true
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(found))
+	for id := range found {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return errors.Errorf(errFmtSeverity, len(ids), *p.MaxVulnerabilitySeverity, strings.Join(ids, ", "))
+}
+
+type vulnerability struct {
+	id       string
+	severity string
+}
+
+// vulnerabilities returns the vulnerabilities reported by the supplied scanner
+// result, which may be a SARIF log, a Trivy JSON report, or a Grype JSON
+// report.
+func vulnerabilities(result []byte) ([]vulnerability, error) {
+	r := struct {
+		Runs []sarifRun `json:"runs"`
+
+		// Trivy omits Results when it finds nothing to scan.
+		TrivySchemaVersion *int          `json:"SchemaVersion"`
+		Results            []trivyResult `json:"Results"`
+
+		Matches []grypeMatch `json:"matches"`
+	}{}
+	if err := json.Unmarshal(result, &r); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalResult)
+	}
+
+	var out []vulnerability
+	switch {
+	case r.Runs != nil:
+		for _, run := range r.Runs {
+			v, err := run.vulnerabilities()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v...)
+		}
+	case r.TrivySchemaVersion != nil || r.Results != nil:
+		for _, res := range r.Results {
+			for _, v := range res.Vulnerabilities {
+				out = append(out, vulnerability{id: v.VulnerabilityID, severity: v.Severity})
+			}
+		}
+	case r.Matches != nil:
+		for _, m := range r.Matches {
+			out = append(out, vulnerability{id: m.Vulnerability.ID, severity: m.Vulnerability.Severity})
+		}
+	default:
+		return nil, errors.New(errUnsupportedResult)
+	}
+	return out, nil
+}
+
+// A sarifRun is a trimmed down SARIF run. Each result is a vulnerability,
+// identified by its rule.
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Rules []struct {
+				ID         string `json:"id"`
+				Properties struct {
+					SecuritySeverity string `json:"security-severity"`
+				} `json:"properties"`
+			} `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []struct {
+		RuleID string `json:"ruleId"`
+	} `json:"results"`
+}
+
+// vulnerabilities returns the vulnerabilities reported by the run. SARIF has
+// no severity of its own, so scanners report a CVSS score as the
+// security-severity property of each rule. Severities are derived from the
+// score the same way GitHub code scanning derives them.
+func (r sarifRun) vulnerabilities() ([]vulnerability, error) {
+	scores := map[string]string{}
+	for _, rule := range r.Tool.Driver.Rules {
+		scores[rule.ID] = rule.Properties.SecuritySeverity
+	}
+
+	out := make([]vulnerability, 0, len(r.Results))
+	for _, res := range r.Results {
+		v := vulnerability{id: res.RuleID}
+		if s := scores[res.RuleID]; s != "" {
+			score, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtInvalidSeverity, s, res.RuleID)
+			}
+			switch {
+			case score >= 9.0:
+				v.severity = "critical"
+			case score >= 7.0:
+				v.severity = "high"
+			case score >= 4.0:
+				v.severity = "medium"
+			case score >= 0.1:
+				v.severity = "low"
+			default:
+				v.severity = "none"
+			}
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// A trivyResult is a trimmed down Trivy JSON report result.
+type trivyResult struct {
+	Vulnerabilities []struct {
+		VulnerabilityID string `json:"VulnerabilityID"`
+		Severity        string `json:"Severity"`
+	} `json:"Vulnerabilities"`
+}
+
+// A grypeMatch is a trimmed down Grype JSON report match.
+type grypeMatch struct {
+	Vulnerability struct {
+		ID       string `json:"id"`
+		Severity string `json:"severity"`
+	} `json:"vulnerability"`
+}
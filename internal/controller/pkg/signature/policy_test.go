@@ -0,0 +1,261 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signature
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sigstore/cosign/v2/pkg/cosign/attestation"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"k8s.io/utils/ptr"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+// trivyReport is a trimmed down Trivy JSON report.
+const trivyReport = `{
+	"SchemaVersion": 2,
+	"Results": [{
+		"Target": "package.xpkg",
+		"Vulnerabilities": [
+			{"VulnerabilityID": "CVE-2024-0001", "Severity": "LOW"},
+			{"VulnerabilityID": "CVE-2024-0002", "Severity": "HIGH"},
+			{"VulnerabilityID": "CVE-2024-0003", "Severity": "CRITICAL"}
+		]
+	}]
+}`
+
+// sarifLog is a trimmed down SARIF log, as reported by Trivy.
+const sarifLog = `{
+	"version": "2.1.0",
+	"runs": [{
+		"tool": {"driver": {"rules": [
+			{"id": "CVE-2024-0004", "properties": {"security-severity": "5.3"}},
+			{"id": "CVE-2024-0005", "properties": {"security-severity": "9.8"}},
+			{"id": "CVE-2024-0006"}
+		]}},
+		"results": [
+			{"ruleId": "CVE-2024-0004"},
+			{"ruleId": "CVE-2024-0005"},
+			{"ruleId": "CVE-2024-0006"}
+		]
+	}]
+}`
+
+// grypeReport is a trimmed down Grype JSON report.
+const grypeReport = `{
+	"matches": [
+		{"vulnerability": {"id": "CVE-2024-0007", "severity": "Medium"}},
+		{"vulnerability": {"id": "CVE-2024-0008", "severity": "Unknown"}}
+	]
+}`
+
+func TestVerifyAttestation(t *testing.T) {
+	vuln := newAttestation(t, attestation.CosignVulnProvenanceV01, map[string]any{
+		"scanner": map[string]any{"result": json.RawMessage(trivyReport)},
+	})
+	sarif := newAttestation(t, attestation.CosignVulnProvenanceV01, map[string]any{
+		"scanner": map[string]any{"result": json.RawMessage(sarifLog)},
+	})
+	grype := newAttestation(t, attestation.CosignVulnProvenanceV01, map[string]any{
+		"scanner": map[string]any{"result": json.RawMessage(grypeReport)},
+	})
+	unsupported := newAttestation(t, attestation.CosignVulnProvenanceV01, map[string]any{
+		"scanner": map[string]any{"result": map[string]any{"findings": []any{map[string]any{"id": "CVE-2024-0009", "severity": "Critical"}}}},
+	})
+	notVuln := newAttestation(t, attestation.CosignVulnProvenanceV01, map[string]any{
+		"vulnerabilities": []any{map[string]any{"id": "CVE-2024-0009", "severity": "Critical"}},
+	})
+	sbom := newAttestation(t, "https://spdx.dev/Document", map[string]any{"spdxVersion": "SPDX-2.3"})
+
+	cases := map[string]struct {
+		reason   string
+		att      v1beta1.Attestation
+		verified []oci.Signature
+		want     v1.AttestationResult
+	}{
+		"SBOMPresent": {
+			reason:   "An SBOM attestation should pass if one is present.",
+			att:      v1beta1.Attestation{Name: "sbom", PredicateType: "spdx"},
+			verified: []oci.Signature{vuln, sbom},
+			want:     v1.AttestationResult{Name: "sbom", PredicateType: "spdx", Outcome: v1.AttestationPassed},
+		},
+		"SBOMMissing": {
+			reason:   "An SBOM attestation should be missing if none is present.",
+			att:      v1beta1.Attestation{Name: "sbom", PredicateType: "spdx"},
+			verified: []oci.Signature{vuln},
+			want:     v1.AttestationResult{Name: "sbom", PredicateType: "spdx", Outcome: v1.AttestationMissing, Message: `no attestation of type "spdx" found`},
+		},
+		"NoCriticalVulnerabilities": {
+			reason: "A vulnerability attestation should fail if it reports vulnerabilities more severe than allowed.",
+			att: v1beta1.Attestation{
+				Name:          "vulns",
+				PredicateType: "vuln",
+				Policy:        &v1beta1.AttestationPolicy{MaxVulnerabilitySeverity: ptr.To(v1beta1.VulnerabilitySeverityHigh)},
+			},
+			verified: []oci.Signature{vuln},
+			want: v1.AttestationResult{
+				Name:          "vulns",
+				PredicateType: "vuln",
+				Outcome:       v1.AttestationFailed,
+				Message:       "found 1 vulnerabilities more severe than High: CVE-2024-0003",
+			},
+		},
+		"IgnoredVulnerabilities": {
+			reason: "A vulnerability attestation should pass if the only vulnerabilities more severe than allowed are ignored.",
+			att: v1beta1.Attestation{
+				Name:          "vulns",
+				PredicateType: "vuln",
+				Policy: &v1beta1.AttestationPolicy{
+					MaxVulnerabilitySeverity: ptr.To(v1beta1.VulnerabilitySeverityMedium),
+					IgnoredVulnerabilities:   []string{"CVE-2024-0002", "CVE-2024-0003"},
+				},
+			},
+			verified: []oci.Signature{vuln},
+			want:     v1.AttestationResult{Name: "vulns", PredicateType: "vuln", Outcome: v1.AttestationPassed},
+		},
+		"SARIF": {
+			reason: "Severities should be derived from the security-severity of SARIF rules, and rules without one should be of unknown severity.",
+			att: v1beta1.Attestation{
+				Name:          "vulns",
+				PredicateType: "vuln",
+				Policy:        &v1beta1.AttestationPolicy{MaxVulnerabilitySeverity: ptr.To(v1beta1.VulnerabilitySeverityMedium)},
+			},
+			verified: []oci.Signature{sarif},
+			want: v1.AttestationResult{
+				Name:          "vulns",
+				PredicateType: "vuln",
+				Outcome:       v1.AttestationFailed,
+				Message:       "found 2 vulnerabilities more severe than Medium: CVE-2024-0005, CVE-2024-0006",
+			},
+		},
+		"UnknownSeverity": {
+			reason: "A vulnerability of unknown severity should be more severe than any policy allows.",
+			att: v1beta1.Attestation{
+				Name:          "vulns",
+				PredicateType: "vuln",
+				Policy:        &v1beta1.AttestationPolicy{MaxVulnerabilitySeverity: ptr.To(v1beta1.VulnerabilitySeverityCritical)},
+			},
+			verified: []oci.Signature{grype},
+			want: v1.AttestationResult{
+				Name:          "vulns",
+				PredicateType: "vuln",
+				Outcome:       v1.AttestationFailed,
+				Message:       "found 1 vulnerabilities more severe than Critical: CVE-2024-0008",
+			},
+		},
+		"UnsupportedResult": {
+			reason: "A vulnerability attestation should fail if we can't parse its scanner result.",
+			att: v1beta1.Attestation{
+				Name:          "vulns",
+				PredicateType: "vuln",
+				Policy:        &v1beta1.AttestationPolicy{MaxVulnerabilitySeverity: ptr.To(v1beta1.VulnerabilitySeverityCritical)},
+			},
+			verified: []oci.Signature{unsupported},
+			want: v1.AttestationResult{
+				Name:          "vulns",
+				PredicateType: "vuln",
+				Outcome:       v1.AttestationFailed,
+				Message:       errUnsupportedResult,
+			},
+		},
+		"NotAVulnerabilityScan": {
+			reason: "A vulnerability attestation should fail if its predicate has no scanner result.",
+			att: v1beta1.Attestation{
+				Name:          "vulns",
+				PredicateType: "vuln",
+				Policy:        &v1beta1.AttestationPolicy{MaxVulnerabilitySeverity: ptr.To(v1beta1.VulnerabilitySeverityCritical)},
+			},
+			verified: []oci.Signature{notVuln},
+			want: v1.AttestationResult{
+				Name:          "vulns",
+				PredicateType: "vuln",
+				Outcome:       v1.AttestationFailed,
+				Message:       errNotVulnPredicate,
+			},
+		},
+		"KeepFirstFailure": {
+			reason: "We should report why the first attestation of the required type failed, not why a later one couldn't be converted.",
+			att: v1beta1.Attestation{
+				Name:          "vulns",
+				PredicateType: "vuln",
+				Policy:        &v1beta1.AttestationPolicy{MaxVulnerabilitySeverity: ptr.To(v1beta1.VulnerabilitySeverityHigh)},
+			},
+			verified: []oci.Signature{vuln, badAttestation(t)},
+			want: v1.AttestationResult{
+				Name:          "vulns",
+				PredicateType: "vuln",
+				Outcome:       v1.AttestationFailed,
+				Message:       "found 1 vulnerabilities more severe than High: CVE-2024-0003",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := verifyAttestation(context.Background(), tc.att, tc.verified)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nverifyAttestation(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// newAttestation returns a DSSE attestation of an in-toto statement with the
+// supplied predicate.
+func newAttestation(t *testing.T, predicateType string, predicate any) oci.Signature {
+	t.Helper()
+
+	statement, err := json.Marshal(map[string]any{
+		"_type":         "https://in-toto.io/Statement/v0.1",
+		"predicateType": predicateType,
+		"subject":       []any{},
+		"predicate":     predicate,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope, err := json.Marshal(map[string]any{
+		"payloadType": "application/vnd.in-toto+json",
+		"payload":     base64.StdEncoding.EncodeToString(statement),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := static.NewAttestation(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// badAttestation returns an attestation whose payload isn't a DSSE envelope.
+func badAttestation(t *testing.T) oci.Signature {
+	t.Helper()
+
+	s, err := static.NewAttestation([]byte("not JSON"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -40,6 +41,7 @@ import (
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/features"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
@@ -51,11 +53,29 @@ const (
 	errGetRevision           = "cannot get package revision"
 	errParseReference        = "cannot parse package image reference"
 	errNewKubernetesClient   = "cannot create new Kubernetes clientset"
+	errBuildFetcher          = "cannot build fetcher"
 	errGetVerificationConfig = "cannot get image verification config"
 	errGetConfigPullSecret   = "cannot get image config pull secret for image"
 	errFailedVerification    = "signature verification failed"
+	errGetMirrors            = "cannot get registry mirrors"
+	errMirrorOptions         = "cannot get registry mirror options"
+	errFmtMirror             = "mirror %q"
 )
 
+// A MirrorAccessor returns the remote options used to access a registry
+// mirror configured by an ImageConfig.
+type MirrorAccessor interface {
+	MirrorOptions(ctx context.Context, imageConfig string, m xpkg.Mirror) ([]remote.Option, error)
+}
+
+// A MirrorAccessorFn is a function that satisfies MirrorAccessor.
+type MirrorAccessorFn func(ctx context.Context, imageConfig string, m xpkg.Mirror) ([]remote.Option, error)
+
+// MirrorOptions returns the remote options used to access the supplied mirror.
+func (fn MirrorAccessorFn) MirrorOptions(ctx context.Context, imageConfig string, m xpkg.Mirror) ([]remote.Option, error) {
+	return fn(ctx, imageConfig, m)
+}
+
 // ReconcilerOption is used to configure the Reconciler.
 type ReconcilerOption func(*Reconciler)
 
@@ -103,6 +123,16 @@ func WithServiceAccount(sa string) ReconcilerOption {
 	}
 }
 
+// WithMirrors specifies the ConfigStore to use for finding the mirrors of an
+// image whose registry is unavailable, and the MirrorAccessor to use for
+// accessing them. Mirrors aren't used if no ConfigStore is specified.
+func WithMirrors(c xpkg.ConfigStore, a MirrorAccessor) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.mirrors = c
+		r.mirrorAccess = a
+	}
+}
+
 // WithValidator specifies the Validator to use for verifying signatures.
 func WithValidator(v Validator) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -114,6 +144,8 @@ func WithValidator(v Validator) ReconcilerOption {
 type Reconciler struct {
 	client         client.Client
 	config         xpkg.ConfigStore
+	mirrors        xpkg.ConfigStore
+	mirrorAccess   MirrorAccessor
 	validator      Validator
 	log            logging.Logger
 	serviceAccount string
@@ -134,10 +166,21 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 		return errors.Wrap(err, errNewKubernetesClient)
 	}
 
-	cosignValidator, err := NewCosignValidator(mgr.GetClient(), clientset, o.Namespace, o.ServiceAccount)
+	// The validators authenticate to registries the same way as the fetcher,
+	// including using any configured credential providers.
+	f, err := xpkg.NewK8sFetcher(clientset, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
+	if err != nil {
+		return errors.Wrap(err, errBuildFetcher)
+	}
+
+	cosignValidator, err := NewCosignValidator(mgr.GetAPIReader(), f, o.Namespace)
 	if err != nil {
 		return errors.Wrap(err, "cannot create cosign validator")
 	}
+	validator := ProviderValidator{
+		v1beta1.ImageVerificationProviderCosign:   cosignValidator,
+		v1beta1.ImageVerificationProviderNotation: NewNotationValidator(mgr.GetAPIReader(), f, o.Namespace),
+	}
 
 	log := o.Logger.WithValues("controller", n)
 	cb := ctrl.NewControllerManagedBy(mgr).
@@ -151,9 +194,12 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithServiceAccount(o.ServiceAccount),
 		WithDefaultRegistry(o.DefaultRegistry),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
-		WithValidator(cosignValidator),
+		WithValidator(validator),
 		WithLogger(log),
 	}
+	if o.Features.Enabled(features.EnableAlphaRegistryMirrors) {
+		ro = append(ro, WithMirrors(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace), f))
+	}
 
 	return cb.WithOptions(o.ForControllerRuntime()).
 		Complete(ratelimiter.NewReconciler(n, errors.WithSilentRequeueOnConflict(NewReconciler(mgr.GetClient(), ro...)), o.GlobalRateLimiter))
@@ -169,10 +215,21 @@ func SetupConfigurationRevision(mgr ctrl.Manager, o controller.Options) error {
 		return errors.Wrap(err, errNewKubernetesClient)
 	}
 
-	cosignValidator, err := NewCosignValidator(mgr.GetClient(), clientset, o.Namespace, o.ServiceAccount)
+	// The validators authenticate to registries the same way as the fetcher,
+	// including using any configured credential providers.
+	f, err := xpkg.NewK8sFetcher(clientset, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
+	if err != nil {
+		return errors.Wrap(err, errBuildFetcher)
+	}
+
+	cosignValidator, err := NewCosignValidator(mgr.GetAPIReader(), f, o.Namespace)
 	if err != nil {
 		return errors.Wrap(err, "cannot create cosign validator")
 	}
+	validator := ProviderValidator{
+		v1beta1.ImageVerificationProviderCosign:   cosignValidator,
+		v1beta1.ImageVerificationProviderNotation: NewNotationValidator(mgr.GetAPIReader(), f, o.Namespace),
+	}
 
 	log := o.Logger.WithValues("controller", n)
 	cb := ctrl.NewControllerManagedBy(mgr).
@@ -186,9 +243,12 @@ func SetupConfigurationRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithServiceAccount(o.ServiceAccount),
 		WithDefaultRegistry(o.DefaultRegistry),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
-		WithValidator(cosignValidator),
+		WithValidator(validator),
 		WithLogger(log),
 	}
+	if o.Features.Enabled(features.EnableAlphaRegistryMirrors) {
+		ro = append(ro, WithMirrors(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace), f))
+	}
 
 	return cb.WithOptions(o.ForControllerRuntime()).
 		Complete(ratelimiter.NewReconciler(n, errors.WithSilentRequeueOnConflict(NewReconciler(mgr.GetClient(), ro...)), o.GlobalRateLimiter))
@@ -204,10 +264,21 @@ func SetupFunctionRevision(mgr ctrl.Manager, o controller.Options) error {
 		return errors.Wrap(err, errNewKubernetesClient)
 	}
 
-	cosignValidator, err := NewCosignValidator(mgr.GetClient(), clientset, o.Namespace, o.ServiceAccount)
+	// The validators authenticate to registries the same way as the fetcher,
+	// including using any configured credential providers.
+	f, err := xpkg.NewK8sFetcher(clientset, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
+	if err != nil {
+		return errors.Wrap(err, errBuildFetcher)
+	}
+
+	cosignValidator, err := NewCosignValidator(mgr.GetAPIReader(), f, o.Namespace)
 	if err != nil {
 		return errors.Wrap(err, "cannot create cosign validator")
 	}
+	validator := ProviderValidator{
+		v1beta1.ImageVerificationProviderCosign:   cosignValidator,
+		v1beta1.ImageVerificationProviderNotation: NewNotationValidator(mgr.GetAPIReader(), f, o.Namespace),
+	}
 
 	log := o.Logger.WithValues("controller", n)
 	cb := ctrl.NewControllerManagedBy(mgr).
@@ -221,9 +292,12 @@ func SetupFunctionRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithServiceAccount(o.ServiceAccount),
 		WithDefaultRegistry(o.DefaultRegistry),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
-		WithValidator(cosignValidator),
+		WithValidator(validator),
 		WithLogger(log),
 	}
+	if o.Features.Enabled(features.EnableAlphaRegistryMirrors) {
+		ro = append(ro, WithMirrors(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace), f))
+	}
 
 	return cb.WithOptions(o.ForControllerRuntime()).
 		Complete(ratelimiter.NewReconciler(n, errors.WithSilentRequeueOnConflict(NewReconciler(mgr.GetClient(), ro...)), o.GlobalRateLimiter))
@@ -302,12 +376,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		_ = r.client.Status().Update(ctx, pr)
 		return reconcile.Result{}, errors.Wrap(err, errGetVerificationConfig)
 	}
-	if vc == nil || vc.Cosign == nil {
+	if vc == nil {
 		// No verification config found for this image, so, we will skip
 		// verification.
 		log.Debug("No signature verification config found for image, skipping verification")
 		status.MarkConditions(v1.VerificationSkipped())
 		pr.ClearAppliedImageConfigRef(v1.ImageConfigReasonVerify)
+		pr.SetAttestationResults(nil)
 		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, pr), "cannot update package status")
 	}
 
@@ -340,7 +415,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		pullSecrets = append(pullSecrets, s)
 	}
 
-	if err = r.validator.Validate(ctx, ref, vc, pullSecrets...); err != nil {
+	results, err := r.validator.Validate(ctx, ref, vc, pullSecrets...)
+	if r.mirrors != nil && xpkg.IsUnavailable(err) {
+		log.Debug("Image registry is unavailable, verifying signature using mirrors", "error", err)
+		results, err = r.validateMirrors(ctx, imagePath, vc, results, err)
+	}
+	pr.SetAttestationResults(results)
+	if err != nil {
 		log.Debug("Signature verification failed", "error", err)
 		status.MarkConditions(v1.VerificationFailed(ic, err))
 		if sErr := r.client.Status().Update(ctx, pr); sErr != nil {
@@ -353,6 +434,40 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, pr), "cannot update status with successful verification")
 }
 
+// validateMirrors validates the signature of the supplied image using each of
+// its mirrors, in order, until one succeeds or definitively fails verification.
+// It's called with the results and error of validating the signature using the
+// image's own, unavailable registry.
+func (r *Reconciler) validateMirrors(ctx context.Context, imagePath string, vc *v1beta1.ImageVerification, results []v1.AttestationResult, err error) ([]v1.AttestationResult, error) {
+	ic, mirrors, mErr := r.mirrors.MirrorsFor(ctx, imagePath)
+	if mErr != nil {
+		return results, errors.Join(err, errors.Wrap(mErr, errGetMirrors))
+	}
+
+	errs := []error{err}
+	for _, m := range mirrors {
+		ref, pErr := name.ParseReference(m.Image, name.WithDefaultRegistry(r.registry))
+		if pErr != nil {
+			errs = append(errs, errors.Wrapf(errors.Wrap(pErr, errParseReference), errFmtMirror, m.Image))
+			continue
+		}
+
+		o, oErr := r.mirrorAccess.MirrorOptions(ctx, ic, m)
+		if oErr != nil {
+			errs = append(errs, errors.Wrapf(errors.Wrap(oErr, errMirrorOptions), errFmtMirror, m.Image))
+			continue
+		}
+
+		res, vErr := r.validator.ValidateWithOptions(ctx, ref, vc, o...)
+		if vErr == nil || !xpkg.IsUnavailable(vErr) {
+			// The mirror was available, so its result is definitive.
+			return res, errors.Wrapf(vErr, errFmtMirror, m.Image)
+		}
+		errs = append(errs, errors.Wrapf(vErr, errFmtMirror, m.Image))
+	}
+	return results, errors.Join(errs...)
+}
+
 func enqueuePackageRevisionsForImageConfig(kube client.Client, log logging.Logger, list v1.PackageRevisionList) handler.EventHandler {
 	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
 		ic, ok := o.(*v1beta1.ImageConfig)
@@ -2,10 +2,13 @@ package signature
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -18,6 +21,7 @@ import (
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xpkg"
 	xpkgfake "github.com/crossplane/crossplane/internal/xpkg/fake"
 )
 
@@ -249,14 +253,14 @@ func TestReconcile(t *testing.T) {
 						}, nil),
 					}),
 					WithValidator(&MockValidator{
-						ValidateFn: func(_ context.Context, _ name.Reference, _ *v1beta1.ImageVerification, pullSecrets ...string) error {
+						ValidateFn: func(_ context.Context, _ name.Reference, _ *v1beta1.ImageVerification, pullSecrets ...string) ([]v1.AttestationResult, error) {
 							expected := []string{"pull-secret-from-package", "pull-secret-from-image-config"}
 
 							if diff := cmp.Diff(expected, pullSecrets); diff != "" {
 								t.Errorf("-want, +got:\n%s", diff)
 							}
 
-							return nil
+							return nil, nil
 						},
 					}),
 				},
@@ -293,8 +297,8 @@ func TestReconcile(t *testing.T) {
 						}, nil),
 					}),
 					WithValidator(&MockValidator{
-						ValidateFn: func(_ context.Context, _ name.Reference, _ *v1beta1.ImageVerification, _ ...string) error {
-							return errBoom
+						ValidateFn: func(_ context.Context, _ name.Reference, _ *v1beta1.ImageVerification, _ ...string) ([]v1.AttestationResult, error) {
+							return []v1.AttestationResult{{Authority: "a", Name: "sbom", PredicateType: "spdx", Outcome: v1.AttestationMissing, Message: "no attestation"}}, errBoom
 						},
 					}),
 				},
@@ -307,6 +311,7 @@ func TestReconcile(t *testing.T) {
 						want := testRevision(
 							withConditions(v1.VerificationFailed(imageConfigName, errBoom)),
 							withAppliedImageConfigRef(imageConfigName),
+							withAttestationResults(v1.AttestationResult{Authority: "a", Name: "sbom", PredicateType: "spdx", Outcome: v1.AttestationMissing, Message: "no attestation"}),
 						)
 
 						if diff := cmp.Diff(&want, o); diff != "" {
@@ -318,6 +323,102 @@ func TestReconcile(t *testing.T) {
 			},
 			want: want{err: errors.Wrap(errBoom, errFailedVerification)},
 		},
+		"ProviderConfigMissing": {
+			reason: "If the config of the selected provider is missing, we should fail verification rather than use another provider's config.",
+			args: args{
+				opts: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithConfigStore(&xpkgfake.MockConfigStore{
+						MockPullSecretFor: xpkgfake.NewMockConfigStorePullSecretForFn(imageConfigName, "", nil),
+						MockImageVerificationConfigFor: xpkgfake.NewMockConfigStoreImageVerificationConfigForFn(imageConfigName, &v1beta1.ImageVerification{
+							Provider: v1beta1.ImageVerificationProviderCosign,
+							Notation: &v1beta1.NotationVerificationConfig{},
+						}, nil),
+					}),
+					WithValidator(ProviderValidator{
+						v1beta1.ImageVerificationProviderCosign: &CosignValidator{},
+					}),
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						*o.(*v1.ConfigurationRevision) = testRevision()
+						return nil
+					}),
+					MockStatusUpdate: func(_ context.Context, o client.Object, _ ...client.SubResourceUpdateOption) error {
+						want := testRevision(
+							withConditions(v1.VerificationFailed(imageConfigName, errors.New(errNoCosignConfig))),
+							withAppliedImageConfigRef(imageConfigName),
+						)
+
+						if diff := cmp.Diff(&want, o); diff != "" {
+							t.Errorf("-want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				},
+			},
+			want: want{err: errors.Wrap(errors.New(errNoCosignConfig), errFailedVerification)},
+		},
+		"SuccessfulVerificationUsingMirror": {
+			reason: "If the image's registry is unavailable, we should verify the image using its mirrors with their remote options.",
+			args: args{
+				opts: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithConfigStore(&xpkgfake.MockConfigStore{
+						MockPullSecretFor: xpkgfake.NewMockConfigStorePullSecretForFn(imageConfigName, "", nil),
+						MockImageVerificationConfigFor: xpkgfake.NewMockConfigStoreImageVerificationConfigForFn(imageConfigName, &v1beta1.ImageVerification{
+							Provider: v1beta1.ImageVerificationProviderCosign,
+							Cosign:   &v1beta1.CosignVerificationConfig{},
+						}, nil),
+					}),
+					WithMirrors(&xpkgfake.MockConfigStore{
+						MockMirrorsFor: xpkgfake.NewMockMirrorsForFn("mirrors", []xpkg.Mirror{
+							{Image: "mirror-a.example.org/crossplane/signature-verification-unit-test:v0.0.1"},
+							{
+								Image:          "mirror-b.example.org/crossplane/signature-verification-unit-test:v0.0.1",
+								Authentication: &v1beta1.RegistryAuthentication{PullSecretRef: corev1.LocalObjectReference{Name: "mirror-b"}},
+							},
+						}, nil),
+					}, MirrorAccessorFn(func(_ context.Context, imageConfig string, m xpkg.Mirror) ([]remote.Option, error) {
+						if imageConfig != "mirrors" {
+							t.Errorf("MirrorOptions(...): want image config %q, got %q", "mirrors", imageConfig)
+						}
+						return []remote.Option{remote.WithUserAgent(m.PullSecret())}, nil
+					})),
+					WithValidator(&MockValidator{
+						ValidateFn: func(_ context.Context, _ name.Reference, _ *v1beta1.ImageVerification, _ ...string) ([]v1.AttestationResult, error) {
+							return nil, &transport.Error{StatusCode: http.StatusServiceUnavailable}
+						},
+						ValidateWithOptionsFn: func(_ context.Context, ref name.Reference, _ *v1beta1.ImageVerification, o ...remote.Option) ([]v1.AttestationResult, error) {
+							if ref.Context().RegistryStr() != "mirror-b.example.org" {
+								return nil, &transport.Error{StatusCode: http.StatusServiceUnavailable}
+							}
+							if len(o) != 1 {
+								t.Errorf("ValidateWithOptions(...): want the mirror's remote options, got %d options", len(o))
+							}
+							return nil, nil
+						},
+					}),
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						*o.(*v1.ConfigurationRevision) = testRevision()
+						return nil
+					}),
+					MockStatusUpdate: func(_ context.Context, o client.Object, _ ...client.SubResourceUpdateOption) error {
+						want := testRevision(
+							withConditions(v1.VerificationSucceeded(imageConfigName)),
+							withAppliedImageConfigRef(imageConfigName),
+						)
+
+						if diff := cmp.Diff(&want, o); diff != "" {
+							t.Errorf("-want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				},
+			},
+		},
 		"SuccessfulVerification": {
 			reason: "A successful verification should return a result with no error.",
 			args: args{
@@ -331,8 +432,8 @@ func TestReconcile(t *testing.T) {
 						}, nil),
 					}),
 					WithValidator(&MockValidator{
-						ValidateFn: func(_ context.Context, _ name.Reference, _ *v1beta1.ImageVerification, _ ...string) error {
-							return nil
+						ValidateFn: func(_ context.Context, _ name.Reference, _ *v1beta1.ImageVerification, _ ...string) ([]v1.AttestationResult, error) {
+							return []v1.AttestationResult{{Authority: "a", Name: "sbom", PredicateType: "spdx", Outcome: v1.AttestationPassed}}, nil
 						},
 					}),
 				},
@@ -345,6 +446,7 @@ func TestReconcile(t *testing.T) {
 						want := testRevision(
 							withConditions(v1.VerificationSucceeded(imageConfigName)),
 							withAppliedImageConfigRef(imageConfigName),
+							withAttestationResults(v1.AttestationResult{Authority: "a", Name: "sbom", PredicateType: "spdx", Outcome: v1.AttestationPassed}),
 						)
 
 						if diff := cmp.Diff(&want, o); diff != "" {
@@ -373,15 +475,26 @@ func TestReconcile(t *testing.T) {
 }
 
 type MockValidator struct {
-	ValidateFn func(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, pullSecrets ...string) error
+	ValidateFn            func(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, pullSecrets ...string) ([]v1.AttestationResult, error)
+	ValidateWithOptionsFn func(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, o ...remote.Option) ([]v1.AttestationResult, error)
 }
 
-func (v *MockValidator) Validate(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, pullSecrets ...string) error {
+func (v *MockValidator) Validate(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, pullSecrets ...string) ([]v1.AttestationResult, error) {
 	return v.ValidateFn(ctx, ref, config, pullSecrets...)
 }
 
+func (v *MockValidator) ValidateWithOptions(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, o ...remote.Option) ([]v1.AttestationResult, error) {
+	return v.ValidateWithOptionsFn(ctx, ref, config, o...)
+}
+
 type revisionOption func(r *v1.ConfigurationRevision)
 
+func withAttestationResults(res ...v1.AttestationResult) revisionOption {
+	return func(r *v1.ConfigurationRevision) {
+		r.SetAttestationResults(res)
+	}
+}
+
 func withResolvedSource(s string) revisionOption {
 	return func(r *v1.ConfigurationRevision) {
 		r.SetResolvedSource(s)
@@ -7,7 +7,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/google/go-containerregistry/pkg/authn/k8schain"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
@@ -17,11 +17,11 @@ import (
 	"github.com/sigstore/sigstore/pkg/signature"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 )
 
@@ -29,11 +29,24 @@ const fetchCertTimeout = 30 * time.Second
 
 // Validator validates image signatures.
 type Validator interface {
-	Validate(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, pullSecrets ...string) error
+	Validate(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, pullSecrets ...string) ([]v1.AttestationResult, error)
+
+	// ValidateWithOptions validates the image signature, accessing the
+	// registry using the supplied remote options rather than pull secrets.
+	ValidateWithOptions(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, o ...remote.Option) ([]v1.AttestationResult, error)
+}
+
+// A Keychainer returns the keychain used to authenticate to the registry that
+// hosts an image.
+type Keychainer interface {
+	Keychain(ctx context.Context, ref name.Reference, pullSecrets ...string) (authn.Keychain, error)
 }
 
-// NewCosignValidator returns a new CosignValidator.
-func NewCosignValidator(c client.Reader, k kubernetes.Interface, namespace, serviceAccount string) (*CosignValidator, error) {
+// NewCosignValidator returns a new CosignValidator. It reads keys from
+// Secrets in the supplied namespace, and authenticates to registries using
+// the supplied Keychainer. The supplied reader should read directly from the
+// API server; a cached reader would watch every Secret in the cluster.
+func NewCosignValidator(c client.Reader, kc Keychainer, namespace string) (*CosignValidator, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), fetchCertTimeout)
 	defer cancel()
 
@@ -58,10 +71,9 @@ func NewCosignValidator(c client.Reader, k kubernetes.Interface, namespace, serv
 	}
 
 	return &CosignValidator{
-		client:         c,
-		clientset:      k,
-		namespace:      namespace,
-		serviceAccount: serviceAccount,
+		client:    c,
+		keychain:  kc,
+		namespace: namespace,
 
 		baseCheckOpts: opts,
 	}, nil
@@ -69,32 +81,46 @@ func NewCosignValidator(c client.Reader, k kubernetes.Interface, namespace, serv
 
 // CosignValidator validates image signatures using cosign.
 type CosignValidator struct {
-	client         client.Reader
-	clientset      kubernetes.Interface
-	namespace      string
-	serviceAccount string
+	client    client.Reader
+	keychain  Keychainer
+	namespace string
 
 	baseCheckOpts cosign.CheckOpts
 }
 
-// Validate validates the image signature.
-func (c *CosignValidator) Validate(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, pullSecrets ...string) error {
+// Validate validates the image signature. It returns the result of verifying
+// each attestation required by the authority that verified the image or, if no
+// authority did, by all authorities.
+func (c *CosignValidator) Validate(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, pullSecrets ...string) ([]v1.AttestationResult, error) {
 	if config.Provider != v1beta1.ImageVerificationProviderCosign {
-		return errors.New("unsupported image verification provider")
+		return nil, errors.New(errUnsupportedProvider)
+	}
+	if config.Cosign == nil {
+		return nil, errors.New(errNoCosignConfig)
 	}
 
-	auth, err := k8schain.New(ctx, c.clientset, k8schain.Options{
-		Namespace:          c.namespace,
-		ServiceAccountName: c.serviceAccount,
-		ImagePullSecrets:   pullSecrets,
-	})
+	auth, err := c.keychain.Keychain(ctx, ref, pullSecrets...)
 	if err != nil {
-		return errors.Wrap(err, "cannot create k8s auth chain")
+		return nil, errors.Wrap(err, errNewAuthChain)
+	}
+
+	return c.ValidateWithOptions(ctx, ref, config, remote.WithAuthFromKeychain(auth))
+}
+
+// ValidateWithOptions validates the image signature like Validate, accessing
+// the registry using the supplied remote options.
+func (c *CosignValidator) ValidateWithOptions(ctx context.Context, ref name.Reference, config *v1beta1.ImageVerification, o ...remote.Option) ([]v1.AttestationResult, error) {
+	if config.Provider != v1beta1.ImageVerificationProviderCosign {
+		return nil, errors.New(errUnsupportedProvider)
+	}
+	if config.Cosign == nil {
+		return nil, errors.New(errNoCosignConfig)
 	}
 
+	var results []v1.AttestationResult
 	var errs []error
 	for _, a := range config.Cosign.Authorities {
-		co, err := c.buildCosignCheckOpts(ctx, a, ociremote.WithRemoteOptions(remote.WithAuthFromKeychain(auth)))
+		co, err := c.buildCosignCheckOpts(ctx, a, ociremote.WithRemoteOptions(o...))
 		if err != nil {
 			errs = append(errs, errors.Errorf("authority %q: cannot build cosign check options %v", a.Name, err))
 			continue
@@ -109,7 +135,7 @@ func (c *CosignValidator) Validate(ctx context.Context, ref name.Reference, conf
 
 		res, ok, err := verify(ctx, ref, co)
 		if err != nil {
-			errs = append(errs, errors.Errorf("authority %q: signature verification failed with %v", a.Name, err))
+			errs = append(errs, errors.Errorf("authority %q: signature verification failed with %w", a.Name, err))
 			continue
 		}
 
@@ -121,35 +147,25 @@ func (c *CosignValidator) Validate(ctx context.Context, ref name.Reference, conf
 		// If there are no attestations, return success given that the signature
 		// verification was successful for this authority.
 		if len(a.Attestations) == 0 {
-			return nil
+			return nil, nil
 		}
 
-		// If there are attestations to be verified, check if the attestation
-		// is valid for at least one of the resulting/checked
-		// signatures/attestations.
-		for _, att := range a.Attestations {
-			for _, s := range res {
-				b, _, err := attestationToPayloadJSON(ctx, att.PredicateType, s)
-				if err != nil {
-					errs = append(errs, errors.Errorf("authority %q: cannot convert attestation %q to payload JSON: %v", a.Name, att.Name, err))
-					continue
-				}
-				if len(b) == 0 {
-					errs = append(errs, errors.Errorf("authority %q: no attestation of type %q found for %q", a.Name, att.PredicateType, att.Name))
-					continue
-				}
-				// If the attestation is valid for at least one of the resulting
-				// payloads, return nil. Otherwise, continue with the next
-				// signature.
-				return nil
-			}
+		// If there are attestations to be verified, each of them must be
+		// found among, and satisfy its policy for at least one of, the
+		// resulting attestations.
+		ar, err := verifyAttestations(ctx, a, res)
+		if err != nil {
+			results = append(results, ar...)
+			errs = append(errs, err)
+			continue
 		}
+		return ar, nil
 	}
 
 	// If we reach this point, none of the authorities were able to verify the
 	// image signature or attestations. So, return an error with all the errors
 	// encountered.
-	return errors.Join(errs...)
+	return results, errors.Join(errs...)
 }
 
 func (c *CosignValidator) buildCosignCheckOpts(ctx context.Context, a v1beta1.CosignAuthority, remoteOpts ...ociremote.Option) (*cosign.CheckOpts, error) {
@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package updater
+
+import (
+	"time"
+
+	"github.com/Masterminds/semver"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	errParseChannel   = "cannot parse update channel"
+	errLoadTimeZone   = "cannot load update window time zone"
+	errParseStart     = "cannot parse update window start time"
+	errWindowDuration = "update window duration must be positive"
+)
+
+// updateInterval returns how often to check for an update.
+func updateInterval(s *v1.UpdateSchedule) time.Duration {
+	switch ptr.Deref(s, v1.UpdateScheduleDaily) {
+	case v1.UpdateScheduleHourly:
+		return time.Hour
+	case v1.UpdateScheduleWeekly:
+		return 7 * 24 * time.Hour
+	case v1.UpdateScheduleDaily:
+		fallthrough
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// windowWait returns how long until the supplied update window next opens, or
+// zero if it's open now. A nil window is always open.
+func windowWait(now time.Time, w *v1.UpdateWindow) (time.Duration, error) {
+	if w == nil {
+		return 0, nil
+	}
+	if w.Duration.Duration <= 0 {
+		return 0, errors.New(errWindowDuration)
+	}
+
+	loc := time.UTC
+	if w.TimeZone != nil {
+		l, err := time.LoadLocation(*w.TimeZone)
+		if err != nil {
+			return 0, errors.Wrap(err, errLoadTimeZone)
+		}
+		loc = l
+	}
+
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return 0, errors.Wrap(err, errParseStart)
+	}
+
+	days := map[string]bool{}
+	for _, d := range w.Days {
+		days[d] = true
+	}
+
+	// Consider every opening from a week ago, in case a long window that
+	// opened then is still open, to a week from now.
+	local := now.In(loc)
+	var next time.Duration = -1
+	for offset := -7; offset <= 7; offset++ {
+		d := local.AddDate(0, 0, offset)
+		open := time.Date(d.Year(), d.Month(), d.Day(), start.Hour(), start.Minute(), 0, 0, loc)
+		if len(days) > 0 && !days[open.Weekday().String()] {
+			continue
+		}
+		if !now.Before(open) && now.Before(open.Add(w.Duration.Duration)) {
+			return 0, nil
+		}
+		if now.Before(open) && (next < 0 || open.Sub(now) < next) {
+			next = open.Sub(now)
+		}
+	}
+	return next, nil
+}
+
+// newestVersion returns the newest of the supplied tags that satisfies the
+// supplied channel, is newer than the current tag, and hasn't been rejected.
+// It returns false if there is no such tag. Tags that aren't semantic versions
+// are ignored.
+func newestVersion(tags []string, channel, current string, rejected []string) (string, bool, error) {
+	c, err := semver.NewConstraint(channel)
+	if err != nil {
+		return "", false, errors.Wrap(err, errParseChannel)
+	}
+
+	skip := map[string]bool{}
+	for _, t := range rejected {
+		skip[t] = true
+	}
+
+	// We only update to a version newer than the current one. If the current
+	// tag isn't a semantic version we'll take any version in the channel.
+	newest, _ := semver.NewVersion(current)
+	tag := ""
+	for _, t := range tags {
+		if skip[t] {
+			continue
+		}
+		v, err := semver.NewVersion(t)
+		if err != nil {
+			continue
+		}
+		if !c.Check(v) {
+			continue
+		}
+		if newest != nil && !v.GreaterThan(newest) {
+			continue
+		}
+		newest, tag = v, t
+	}
+	return tag, tag != "", nil
+}
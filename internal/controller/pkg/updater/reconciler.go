@@ -0,0 +1,388 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package updater implements the controller that automatically updates
+// packages according to their update policy.
+package updater
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+)
+
+const (
+	errGetPackage          = "cannot get package"
+	errParseReference      = "cannot parse package image reference"
+	errRewriteImage        = "cannot rewrite image path using config"
+	errInvalidRewrite      = "rewritten image path is invalid"
+	errGetPullConfig       = "cannot get image pull secret from config"
+	errFetchTags           = "cannot fetch package tags"
+	errSelectVersion       = "cannot select package version"
+	errWindow              = "invalid update window"
+	errUpdatePackage       = "cannot update package"
+	errUpdateStatus        = "cannot update package status"
+	errNewKubernetesClient = "cannot create new Kubernetes clientset"
+	errBuildFetcher        = "cannot build fetcher"
+)
+
+// Event reasons.
+const (
+	reasonUpdate   event.Reason = "UpdatePackage"
+	reasonRollback event.Reason = "RollbackPackage"
+)
+
+// ReconcilerOption is used to configure the Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(log logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = log
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record Kubernetes events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// WithNewPackageFn determines the type of package being reconciled.
+func WithNewPackageFn(f func() v1.Package) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.newPackage = f
+	}
+}
+
+// WithFetcher specifies how the Reconciler should fetch package tags.
+func WithFetcher(f xpkg.Fetcher) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.fetcher = f
+	}
+}
+
+// WithConfigStore specifies the ConfigStore to use for fetching image
+// configurations.
+func WithConfigStore(c xpkg.ConfigStore) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.config = c
+	}
+}
+
+// WithDefaultRegistry specifies the registry to use for packages that don't
+// specify one.
+func WithDefaultRegistry(registry string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.registry = registry
+	}
+}
+
+// Reconciler automatically updates packages according to their update policy.
+type Reconciler struct {
+	client   client.Client
+	fetcher  xpkg.Fetcher
+	config   xpkg.ConfigStore
+	log      logging.Logger
+	record   event.Recorder
+	registry string
+
+	newPackage func() v1.Package
+	now        func() time.Time
+}
+
+// SetupProvider adds a controller that updates Providers.
+func SetupProvider(mgr ctrl.Manager, o controller.Options) error {
+	n := "package-updater/" + strings.ToLower(v1.ProviderGroupKind)
+	np := func() v1.Package { return &v1.Provider{} }
+	return setup(mgr, o, n, np, &v1.Provider{})
+}
+
+// SetupConfiguration adds a controller that updates Configurations.
+func SetupConfiguration(mgr ctrl.Manager, o controller.Options) error {
+	n := "package-updater/" + strings.ToLower(v1.ConfigurationGroupKind)
+	np := func() v1.Package { return &v1.Configuration{} }
+	return setup(mgr, o, n, np, &v1.Configuration{})
+}
+
+// SetupFunction adds a controller that updates Functions.
+func SetupFunction(mgr ctrl.Manager, o controller.Options) error {
+	n := "package-updater/" + strings.ToLower(v1.FunctionGroupKind)
+	np := func() v1.Package { return &v1.Function{} }
+	return setup(mgr, o, n, np, &v1.Function{})
+}
+
+func setup(mgr ctrl.Manager, o controller.Options, n string, np func() v1.Package, obj client.Object) error {
+	cs, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return errors.Wrap(err, errNewKubernetesClient)
+	}
+	f, err := xpkg.NewK8sFetcher(cs, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
+	if err != nil {
+		return errors.Wrap(err, errBuildFetcher)
+	}
+
+	ro := []ReconcilerOption{
+		WithNewPackageFn(np),
+		WithFetcher(f),
+		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
+		WithDefaultRegistry(o.DefaultRegistry),
+		WithLogger(o.Logger.WithValues("controller", n)),
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(n))),
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(n).
+		For(obj).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(ratelimiter.NewReconciler(n, errors.WithSilentRequeueOnConflict(NewReconciler(mgr.GetClient(), ro...)), o.GlobalRateLimiter))
+}
+
+// NewReconciler creates a new package updater reconciler.
+func NewReconciler(c client.Client, opts ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client:   c,
+		fetcher:  xpkg.NewNopFetcher(),
+		log:      logging.NewNopLogger(),
+		record:   event.NewNopRecorder(),
+		registry: xpkg.DefaultRegistry,
+		now:      time.Now,
+	}
+
+	for _, f := range opts {
+		f(r)
+	}
+
+	return r
+}
+
+// Reconcile a package's update policy.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, reconcileTimeout)
+	defer cancel()
+
+	pkg := r.newPackage()
+	if err := r.client.Get(ctx, req.NamespacedName, pkg); err != nil {
+		// There's no need to requeue if we no longer exist. Otherwise
+		// we'll be requeued implicitly because we return an error.
+		log.Debug(errGetPackage, "error", err)
+		return reconcile.Result{}, errors.Wrap(client.IgnoreNotFound(err), errGetPackage)
+	}
+
+	p := pkg.GetUpdatePolicy()
+	if p == nil || meta.WasDeleted(pkg) {
+		return reconcile.Result{}, nil
+	}
+
+	st := pkg.GetUpdateStatus()
+	if st == nil {
+		st = &v1.UpdateStatus{}
+	}
+	now := r.now()
+
+	// We updated the package and are waiting to see whether it becomes
+	// healthy. Someone else may have changed the package since. The package
+	// manager owns rolling back revisions that don't become healthy - we just
+	// follow its lead. Until it either rolls back or the package becomes
+	// healthy we keep checking for updates.
+	switch {
+	case st.PreviousPackage == "" || st.UpdatedPackage != pkg.GetSource():
+		st.PreviousPackage = ""
+	case rolledBack(pkg):
+		return r.reject(ctx, log, pkg, p, st)
+	case healthy(pkg):
+		log.Debug("Updated package is healthy", "package", pkg.GetSource())
+		st.PreviousPackage = ""
+		pkg.SetUpdateStatus(st)
+		return reconcile.Result{RequeueAfter: updateInterval(p.Schedule)}, errors.Wrap(r.client.Status().Update(ctx, pkg), errUpdateStatus)
+	}
+
+	interval := updateInterval(p.Schedule)
+	if st.LastCheckTime != nil {
+		if next := st.LastCheckTime.Add(interval); now.Before(next) {
+			return reconcile.Result{RequeueAfter: next.Sub(now)}, nil
+		}
+	}
+
+	wait, err := windowWait(now, p.Window)
+	if err != nil {
+		log.Debug(errWindow, "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errWindow)
+	}
+	if wait > 0 {
+		log.Debug("Update window is closed", "opens-in", wait)
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
+	ref, err := name.ParseReference(pkg.GetSource(), name.WithDefaultRegistry(r.registry))
+	if err != nil {
+		log.Debug(errParseReference, "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errParseReference)
+	}
+	if _, ok := ref.(name.Digest); ok {
+		// A package pinned to a digest can't follow a channel.
+		log.Debug("Not updating package pinned to a digest")
+		return reconcile.Result{}, nil
+	}
+
+	tags, err := r.tags(ctx, ref, pkg.GetPackagePullSecrets())
+	if err != nil {
+		log.Debug(errFetchTags, "error", err)
+		return reconcile.Result{}, err
+	}
+
+	version, ok, err := newestVersion(tags, p.Channel, ref.Identifier(), st.RejectedVersions)
+	if err != nil {
+		log.Debug(errSelectVersion, "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errSelectVersion)
+	}
+
+	st.LastCheckTime = &metav1.Time{Time: now}
+	if !ok {
+		log.Debug("Package is up to date", "channel", p.Channel)
+		pkg.SetUpdateStatus(st)
+		return reconcile.Result{RequeueAfter: interval}, errors.Wrap(r.client.Status().Update(ctx, pkg), errUpdateStatus)
+	}
+
+	from := pkg.GetSource()
+	to := strings.TrimSuffix(from, ":"+ref.Identifier()) + ":" + version
+	pkg.SetSource(to)
+	if err := r.client.Update(ctx, pkg); err != nil {
+		log.Debug(errUpdatePackage, "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errUpdatePackage)
+	}
+
+	// If the package we're updating from never became healthy we keep the
+	// package before it, which is what the package manager will roll back to.
+	if st.PreviousPackage == "" {
+		st.PreviousPackage = from
+	}
+	st.UpdatedPackage = to
+	st.LastUpdateTime = &metav1.Time{Time: now}
+	pkg.SetUpdateStatus(st)
+
+	log.Debug("Updated package", "from", from, "to", to)
+	r.record.Event(pkg, event.Normal(reasonUpdate, fmt.Sprintf("Updated package from %s to %s", from, to)))
+
+	return reconcile.Result{RequeueAfter: interval}, errors.Wrap(r.client.Status().Update(ctx, pkg), errUpdateStatus)
+}
+
+// healthy returns true if the package manager produced a healthy revision for
+// the package's current source.
+func healthy(pkg v1.Package) bool {
+	// The package manager records the package it most recently produced a
+	// revision for. Until it does, the package's health is that of the
+	// revision we updated from.
+	return pkg.GetCurrentIdentifier() == pkg.GetSource() &&
+		pkg.GetCondition(v1.TypeHealthy).Status == corev1.ConditionTrue &&
+		pkg.GetCondition(v1.TypeInstalled).Reason != v1.ReasonRolledBack
+}
+
+// rolledBack returns true if the package manager rolled back the revision for
+// the package's current source, because it didn't become healthy in time.
+func rolledBack(pkg v1.Package) bool {
+	return pkg.GetCurrentIdentifier() == pkg.GetSource() && pkg.GetRolledBackRevision() != ""
+}
+
+// reject restores the package we updated from after the package manager
+// rolled back the updated package, and makes sure we never update to it again.
+func (r *Reconciler) reject(ctx context.Context, log logging.Logger, pkg v1.Package, p *v1.UpdatePolicy, st *v1.UpdateStatus) (reconcile.Result, error) {
+	ref, err := name.ParseReference(pkg.GetSource(), name.WithDefaultRegistry(r.registry))
+	if err != nil {
+		log.Debug(errParseReference, "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errParseReference)
+	}
+
+	// The package manager keeps the last healthy revision active until
+	// spec.package changes. Restoring the package we updated from makes
+	// spec.package match the revision that's actually running.
+	from := pkg.GetSource()
+	to := st.PreviousPackage
+	pkg.SetSource(to)
+	if err := r.client.Update(ctx, pkg); err != nil {
+		log.Debug(errUpdatePackage, "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errUpdatePackage)
+	}
+
+	st.RejectedVersions = append(st.RejectedVersions, ref.Identifier())
+	st.PreviousPackage = ""
+	st.UpdatedPackage = ""
+	pkg.SetUpdateStatus(st)
+
+	log.Debug("Restored package after the package manager rolled back its update", "from", from, "to", to)
+	r.record.Event(pkg, event.Warning(reasonRollback, errors.Errorf("Restored package %s because the package manager rolled back %s", to, from)))
+
+	return reconcile.Result{RequeueAfter: updateInterval(p.Schedule)}, errors.Wrap(r.client.Status().Update(ctx, pkg), errUpdateStatus)
+}
+
+// tags returns the tags of the supplied package, using any image config that
+// applies to it.
+func (r *Reconciler) tags(ctx context.Context, ref name.Reference, secrets []corev1.LocalObjectReference) ([]string, error) {
+	// Rewrite the image path if necessary. We need to do this before looking
+	// for pull secrets, since the rewritten path may use different secrets than
+	// the original.
+	_, newPath, err := r.config.RewritePath(ctx, ref.String())
+	if err != nil {
+		return nil, errors.Wrap(err, errRewriteImage)
+	}
+	if newPath != "" {
+		if ref, err = name.ParseReference(newPath, name.WithDefaultRegistry(r.registry)); err != nil {
+			return nil, errors.Wrap(err, errInvalidRewrite)
+		}
+	}
+
+	s := make([]string, 0, len(secrets)+1)
+	for _, ps := range secrets {
+		s = append(s, ps.Name)
+	}
+	_, ps, err := r.config.PullSecretFor(ctx, ref.String())
+	if err != nil {
+		return nil, errors.Wrap(err, errGetPullConfig)
+	}
+	if ps != "" {
+		s = append(s, ps)
+	}
+
+	tags, err := r.fetcher.Tags(ctx, ref, s...)
+	return tags, errors.Wrap(err, errFetchTags)
+}
@@ -0,0 +1,514 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package updater
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+	fakexpkg "github.com/crossplane/crossplane/internal/xpkg/fake"
+)
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Date(2025, time.June, 2, 12, 0, 0, 0, time.UTC) // A Monday.
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	weekly := v1.UpdateScheduleWeekly
+
+	config := &fakexpkg.MockConfigStore{
+		MockRewritePath:   fakexpkg.NewMockRewritePathFn("", "", nil),
+		MockPullSecretFor: fakexpkg.NewMockConfigStorePullSecretForFn("", "", nil),
+	}
+
+	type args struct {
+		kube client.Client
+		f    xpkg.Fetcher
+	}
+	type want struct {
+		r   reconcile.Result
+		pkg *v1.Provider
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoPolicy": {
+			reason: "We should do nothing if the package has no update policy.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"GetPackageError": {
+			reason: "We should return an error if we can't get the package.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetPackage),
+			},
+		},
+		"NotYetDue": {
+			reason: "We should wait until the schedule says it's time to check for an update.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						p := o.(*v1.Provider)
+						p.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.4.0")
+						p.SetUpdatePolicy(&v1.UpdatePolicy{Channel: "^1.4"})
+						p.SetUpdateStatus(&v1.UpdateStatus{LastCheckTime: &earlier})
+						return nil
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 23 * time.Hour},
+			},
+		},
+		"OutsideWindow": {
+			reason: "We should wait for the update window to open.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						p := o.(*v1.Provider)
+						p.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.4.0")
+						p.SetUpdatePolicy(&v1.UpdatePolicy{
+							Channel: "^1.4",
+							Window:  &v1.UpdateWindow{Days: []string{"Monday"}, Start: "14:00", Duration: metav1.Duration{Duration: time.Hour}},
+						})
+						return nil
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 2 * time.Hour},
+			},
+		},
+		"FetchTagsError": {
+			reason: "We should return an error if we can't fetch the package's tags.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						p := o.(*v1.Provider)
+						p.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.4.0")
+						p.SetUpdatePolicy(&v1.UpdatePolicy{Channel: "^1.4"})
+						return nil
+					}),
+				},
+				f: &fakexpkg.MockFetcher{
+					MockTags: fakexpkg.NewMockTagsFn(nil, errBoom),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFetchTags),
+			},
+		},
+		"UpdatePackage": {
+			reason: "We should update the package to the newest version in its channel.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						p := o.(*v1.Provider)
+						p.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.4.0")
+						p.SetUpdatePolicy(&v1.UpdatePolicy{Channel: "^1.4", Schedule: &weekly})
+						return nil
+					}),
+					MockUpdate:       test.NewMockUpdateFn(nil),
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+				f: &fakexpkg.MockFetcher{
+					MockTags: fakexpkg.NewMockTagsFn([]string{"v1.3.0", "v1.4.0", "v1.5.1", "v1.5.0", "v2.0.0", "latest"}, nil),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 7 * 24 * time.Hour},
+				pkg: func() *v1.Provider {
+					p := &v1.Provider{}
+					p.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.5.1")
+					p.SetUpdatePolicy(&v1.UpdatePolicy{Channel: "^1.4", Schedule: &weekly})
+					p.SetUpdateStatus(&v1.UpdateStatus{
+						LastCheckTime:   &metav1.Time{Time: now},
+						LastUpdateTime:  &metav1.Time{Time: now},
+						PreviousPackage: "xpkg.crossplane.io/crossplane/provider-nop:v1.4.0",
+						UpdatedPackage:  "xpkg.crossplane.io/crossplane/provider-nop:v1.5.1",
+					})
+					return p
+				}(),
+			},
+		},
+		"UpToDate": {
+			reason: "We should record that we checked if the package is already up to date.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						p := o.(*v1.Provider)
+						p.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.5.1")
+						p.SetUpdatePolicy(&v1.UpdatePolicy{Channel: "^1.4"})
+						p.SetUpdateStatus(&v1.UpdateStatus{RejectedVersions: []string{"v1.6.0"}})
+						return nil
+					}),
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+				f: &fakexpkg.MockFetcher{
+					MockTags: fakexpkg.NewMockTagsFn([]string{"v1.5.1", "v1.6.0"}, nil),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 24 * time.Hour},
+				pkg: func() *v1.Provider {
+					p := &v1.Provider{}
+					p.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.5.1")
+					p.SetUpdatePolicy(&v1.UpdatePolicy{Channel: "^1.4"})
+					p.SetUpdateStatus(&v1.UpdateStatus{
+						LastCheckTime:    &metav1.Time{Time: now},
+						RejectedVersions: []string{"v1.6.0"},
+					})
+					return p
+				}(),
+			},
+		},
+		"ConfirmUpdate": {
+			reason: "We should stop watching an updated package once it becomes healthy.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						p := o.(*v1.Provider)
+						p.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.5.1")
+						p.SetCurrentIdentifier("xpkg.crossplane.io/crossplane/provider-nop:v1.5.1")
+						p.SetConditions(v1.Healthy())
+						p.SetUpdatePolicy(&v1.UpdatePolicy{Channel: "^1.4"})
+						p.SetUpdateStatus(&v1.UpdateStatus{
+							LastUpdateTime:  &earlier,
+							PreviousPackage: "xpkg.crossplane.io/crossplane/provider-nop:v1.4.0",
+							UpdatedPackage:  "xpkg.crossplane.io/crossplane/provider-nop:v1.5.1",
+						})
+						return nil
+					}),
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 24 * time.Hour},
+				pkg: func() *v1.Provider {
+					p := &v1.Provider{}
+					p.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.5.1")
+					p.SetCurrentIdentifier("xpkg.crossplane.io/crossplane/provider-nop:v1.5.1")
+					p.SetConditions(v1.Healthy())
+					p.SetUpdatePolicy(&v1.UpdatePolicy{Channel: "^1.4"})
+					p.SetUpdateStatus(&v1.UpdateStatus{
+						LastUpdateTime: &earlier,
+						UpdatedPackage: "xpkg.crossplane.io/crossplane/provider-nop:v1.5.1",
+					})
+					return p
+				}(),
+			},
+		},
+		"AwaitHealthy": {
+			reason: "We should keep checking for updates on schedule while an updated package isn't yet healthy.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						p := o.(*v1.Provider)
+						p.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.5.1")
+						p.SetUpdatePolicy(&v1.UpdatePolicy{Channel: "^1.4"})
+						p.SetUpdateStatus(&v1.UpdateStatus{
+							LastCheckTime:   &earlier,
+							LastUpdateTime:  &earlier,
+							PreviousPackage: "xpkg.crossplane.io/crossplane/provider-nop:v1.4.0",
+							UpdatedPackage:  "xpkg.crossplane.io/crossplane/provider-nop:v1.5.1",
+						})
+						return nil
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 23 * time.Hour},
+			},
+		},
+		"UpdateUnhealthyPackage": {
+			reason: "We should keep the package we'd roll back to if we update a package that never became healthy.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						p := o.(*v1.Provider)
+						p.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.5.1")
+						p.SetUpdatePolicy(&v1.UpdatePolicy{Channel: "^1.4"})
+						p.SetUpdateStatus(&v1.UpdateStatus{
+							LastUpdateTime:  &earlier,
+							PreviousPackage: "xpkg.crossplane.io/crossplane/provider-nop:v1.4.0",
+							UpdatedPackage:  "xpkg.crossplane.io/crossplane/provider-nop:v1.5.1",
+						})
+						return nil
+					}),
+					MockUpdate:       test.NewMockUpdateFn(nil),
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+				f: &fakexpkg.MockFetcher{
+					MockTags: fakexpkg.NewMockTagsFn([]string{"v1.4.0", "v1.5.1", "v1.5.2"}, nil),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 24 * time.Hour},
+				pkg: func() *v1.Provider {
+					p := &v1.Provider{}
+					p.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.5.2")
+					p.SetUpdatePolicy(&v1.UpdatePolicy{Channel: "^1.4"})
+					p.SetUpdateStatus(&v1.UpdateStatus{
+						LastCheckTime:   &metav1.Time{Time: now},
+						LastUpdateTime:  &metav1.Time{Time: now},
+						PreviousPackage: "xpkg.crossplane.io/crossplane/provider-nop:v1.4.0",
+						UpdatedPackage:  "xpkg.crossplane.io/crossplane/provider-nop:v1.5.2",
+					})
+					return p
+				}(),
+			},
+		},
+		"RolledBack": {
+			reason: "We should restore the package we updated from if the package manager rolls back the updated package, and never update to it again.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						p := o.(*v1.Provider)
+						p.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.5.1")
+						p.SetCurrentIdentifier("xpkg.crossplane.io/crossplane/provider-nop:v1.5.1")
+						p.SetRolledBackRevision("provider-nop-c3a9c5e2f1d0")
+						p.SetUpdatePolicy(&v1.UpdatePolicy{Channel: "^1.4"})
+						p.SetUpdateStatus(&v1.UpdateStatus{
+							LastUpdateTime:  &earlier,
+							PreviousPackage: "xpkg.crossplane.io/crossplane/provider-nop:v1.4.0",
+							UpdatedPackage:  "xpkg.crossplane.io/crossplane/provider-nop:v1.5.1",
+						})
+						return nil
+					}),
+					MockUpdate:       test.NewMockUpdateFn(nil),
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 24 * time.Hour},
+				pkg: func() *v1.Provider {
+					p := &v1.Provider{}
+					p.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.4.0")
+					p.SetCurrentIdentifier("xpkg.crossplane.io/crossplane/provider-nop:v1.5.1")
+					p.SetRolledBackRevision("provider-nop-c3a9c5e2f1d0")
+					p.SetUpdatePolicy(&v1.UpdatePolicy{Channel: "^1.4"})
+					p.SetUpdateStatus(&v1.UpdateStatus{
+						LastUpdateTime:   &earlier,
+						RejectedVersions: []string{"v1.5.1"},
+					})
+					return p
+				}(),
+			},
+		},
+		"RolledBackUpdateError": {
+			reason: "We should return an error if we can't restore the package we updated from.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						p := o.(*v1.Provider)
+						p.SetSource("xpkg.crossplane.io/crossplane/provider-nop:v1.5.1")
+						p.SetCurrentIdentifier("xpkg.crossplane.io/crossplane/provider-nop:v1.5.1")
+						p.SetRolledBackRevision("provider-nop-c3a9c5e2f1d0")
+						p.SetUpdatePolicy(&v1.UpdatePolicy{Channel: "^1.4"})
+						p.SetUpdateStatus(&v1.UpdateStatus{
+							LastUpdateTime:  &earlier,
+							PreviousPackage: "xpkg.crossplane.io/crossplane/provider-nop:v1.4.0",
+							UpdatedPackage:  "xpkg.crossplane.io/crossplane/provider-nop:v1.5.1",
+						})
+						return nil
+					}),
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdatePackage),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got *v1.Provider
+			if mc, ok := tc.args.kube.(*test.MockClient); ok && mc.MockStatusUpdate != nil {
+				su := mc.MockStatusUpdate
+				mc.MockStatusUpdate = func(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+					got = obj.(*v1.Provider).DeepCopy()
+					return su(ctx, obj, opts...)
+				}
+			}
+
+			f := tc.args.f
+			if f == nil {
+				f = xpkg.NewNopFetcher()
+			}
+			r := NewReconciler(tc.args.kube,
+				WithNewPackageFn(func() v1.Package { return &v1.Provider{} }),
+				WithFetcher(f),
+				WithConfigStore(config),
+			)
+			r.now = func() time.Time { return now }
+
+			res, err := r.Reconcile(context.Background(), reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, res); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pkg, got, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want package, +got package:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWindowWait(t *testing.T) {
+	now := time.Date(2025, time.June, 2, 12, 0, 0, 0, time.UTC) // A Monday.
+
+	type want struct {
+		wait time.Duration
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		w      *v1.UpdateWindow
+		want   want
+	}{
+		"NoWindow": {
+			reason: "A package without an update window may be updated at any time.",
+		},
+		"Open": {
+			reason: "We shouldn't wait if the window is open.",
+			w:      &v1.UpdateWindow{Start: "11:00", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+		},
+		"OpenSinceYesterday": {
+			reason: "We shouldn't wait if a window that opened yesterday is still open.",
+			w:      &v1.UpdateWindow{Days: []string{"Sunday"}, Start: "22:00", Duration: metav1.Duration{Duration: 16 * time.Hour}},
+		},
+		"LaterToday": {
+			reason: "We should wait for a window that opens later today.",
+			w:      &v1.UpdateWindow{Start: "14:30", Duration: metav1.Duration{Duration: time.Hour}},
+			want:   want{wait: 150 * time.Minute},
+		},
+		"NextWeek": {
+			reason: "We should wait for a window that next opens on another day.",
+			w:      &v1.UpdateWindow{Days: []string{"Saturday", "Sunday"}, Start: "00:00", Duration: metav1.Duration{Duration: time.Hour}},
+			want:   want{wait: 4*24*time.Hour + 12*time.Hour},
+		},
+		"TimeZone": {
+			reason: "We should interpret the start time in the window's time zone.",
+			w:      &v1.UpdateWindow{Start: "13:00", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: ptr.To("Asia/Tokyo")},
+			want:   want{wait: 16 * time.Hour},
+		},
+		"InvalidStart": {
+			reason: "We should return an error if the start time is invalid.",
+			w:      &v1.UpdateWindow{Start: "noon", Duration: metav1.Duration{Duration: time.Hour}},
+			want:   want{err: cmpopts.AnyError},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := windowWait(now, tc.w)
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nwindowWait(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.wait, got); diff != "" {
+				t.Errorf("\n%s\nwindowWait(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewestVersion(t *testing.T) {
+	tags := []string{"v1.3.0", "v1.4.0", "v1.4.2", "v1.5.0-rc.1", "v1.5.0", "v2.0.0", "latest"}
+
+	type want struct {
+		Version string
+		OK      bool
+	}
+
+	cases := map[string]struct {
+		reason   string
+		channel  string
+		current  string
+		rejected []string
+		want     want
+	}{
+		"Newest": {
+			reason:  "We should pick the newest version that satisfies the channel.",
+			channel: "^1.4",
+			current: "v1.4.0",
+			want:    want{Version: "v1.5.0", OK: true},
+		},
+		"Rejected": {
+			reason:   "We should not pick a version that was rolled back.",
+			channel:  "^1.4",
+			current:  "v1.4.0",
+			rejected: []string{"v1.5.0"},
+			want:     want{Version: "v1.4.2", OK: true},
+		},
+		"NoDowngrade": {
+			reason:  "We should never pick a version older than the current one.",
+			channel: "~1.3",
+			current: "v1.4.0",
+			want:    want{},
+		},
+		"NotSemver": {
+			reason:  "We should pick any version in the channel if the current tag isn't a semantic version.",
+			channel: "~1.4",
+			current: "latest",
+			want:    want{Version: "v1.4.2", OK: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v, ok, err := newestVersion(tags, tc.channel, tc.current, tc.rejected)
+			if err != nil {
+				t.Fatalf("\n%s\nnewestVersion(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, want{Version: v, OK: ok}); diff != "" {
+				t.Errorf("\n%s\nnewestVersion(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package definition
 import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
@@ -49,6 +50,9 @@ const (
 
 	suffixStatus     = "/status"
 	suffixFinalizers = "/finalizers"
+
+	pluralNamespacedCompositions         = "namespacedcompositions"
+	pluralNamespacedCompositionRevisions = "namespacedcompositionrevisions"
 )
 
 //nolint:gochecknoglobals // We treat these as constants.
@@ -196,6 +200,30 @@ func RenderClusterRoles(d *v1.CompositeResourceDefinition) []rbacv1.ClusterRole
 		// The browse role only includes composite resources; not claims.
 	}
 
+	// Namespaced XRs may be composed using NamespacedCompositions from their
+	// own namespace. Folks who can edit a namespace's XRs can also author the
+	// NamespacedCompositions they use.
+	if ptr.Deref(d.Spec.Scope, v1.CompositeResourceScopeLegacyCluster) == v1.CompositeResourceScopeNamespaced {
+		edit.Rules = append(edit.Rules,
+			rbacv1.PolicyRule{
+				APIGroups: []string{v1.Group},
+				Resources: []string{pluralNamespacedCompositions},
+				Verbs:     verbsEdit,
+			},
+			rbacv1.PolicyRule{
+				APIGroups: []string{v1.Group},
+				Resources: []string{pluralNamespacedCompositionRevisions},
+				Verbs:     verbsView,
+			},
+		)
+
+		view.Rules = append(view.Rules, rbacv1.PolicyRule{
+			APIGroups: []string{v1.Group},
+			Resources: []string{pluralNamespacedCompositions, pluralNamespacedCompositionRevisions},
+			Verbs:     verbsView,
+		})
+	}
+
 	for _, o := range []metav1.Object{system, edit, view, browse} {
 		meta.AddOwnerReference(o, meta.AsController(meta.TypedReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind)))
 	}
@@ -24,6 +24,7 @@ import (
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 )
@@ -242,6 +243,107 @@ func TestRenderClusterRoles(t *testing.T) {
 				},
 			},
 		},
+		"Namespaced": {
+			reason: "A namespaced XRD should produce ClusterRoles that grant access to NamespacedCompositions",
+			d: &v1.CompositeResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: name, UID: uid},
+				Spec: v1.CompositeResourceDefinitionSpec{
+					Group: group,
+					Names: extv1.CustomResourceDefinitionNames{Plural: pluralXR},
+					Scope: ptr.To(v1.CompositeResourceScopeNamespaced),
+				},
+			},
+			want: []rbacv1.ClusterRole{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            namePrefix + name + nameSuffixSystem,
+						OwnerReferences: []metav1.OwnerReference{owner},
+						Labels: map[string]string{
+							keyAggregateToSystem: valTrue,
+						},
+					},
+					Rules: []rbacv1.PolicyRule{
+						{
+							APIGroups: []string{group},
+							Resources: []string{pluralXR, pluralXR + suffixStatus},
+							Verbs:     verbsEdit,
+						},
+						{
+							APIGroups: []string{group},
+							Resources: []string{pluralXR + suffixFinalizers},
+							Verbs:     verbsUpdate,
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            namePrefix + name + nameSuffixEdit,
+						OwnerReferences: []metav1.OwnerReference{owner},
+						Labels: map[string]string{
+							keyAggregateToAdmin:   valTrue,
+							keyAggregateToNSAdmin: valTrue,
+							keyAggregateToEdit:    valTrue,
+							keyAggregateToNSEdit:  valTrue,
+						},
+					},
+					Rules: []rbacv1.PolicyRule{
+						{
+							APIGroups: []string{group},
+							Resources: []string{pluralXR, pluralXR + suffixStatus},
+							Verbs:     verbsEdit,
+						},
+						{
+							APIGroups: []string{v1.Group},
+							Resources: []string{pluralNamespacedCompositions},
+							Verbs:     verbsEdit,
+						},
+						{
+							APIGroups: []string{v1.Group},
+							Resources: []string{pluralNamespacedCompositionRevisions},
+							Verbs:     verbsView,
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            namePrefix + name + nameSuffixView,
+						OwnerReferences: []metav1.OwnerReference{owner},
+						Labels: map[string]string{
+							keyAggregateToView:   valTrue,
+							keyAggregateToNSView: valTrue,
+						},
+					},
+					Rules: []rbacv1.PolicyRule{
+						{
+							APIGroups: []string{group},
+							Resources: []string{pluralXR, pluralXR + suffixStatus},
+							Verbs:     verbsView,
+						},
+						{
+							APIGroups: []string{v1.Group},
+							Resources: []string{pluralNamespacedCompositions, pluralNamespacedCompositionRevisions},
+							Verbs:     verbsView,
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            namePrefix + name + nameSuffixBrowse,
+						OwnerReferences: []metav1.OwnerReference{owner},
+						Labels: map[string]string{
+							keyAggregateToBrowse: valTrue,
+						},
+					},
+					Rules: []rbacv1.PolicyRule{
+						{
+							APIGroups: []string{group},
+							Resources: []string{pluralXR, pluralXR + suffixStatus},
+							Verbs:     verbsBrowse,
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
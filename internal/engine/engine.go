@@ -325,10 +325,11 @@ type WatchType string
 
 // Common watch types.
 const (
-	WatchTypeClaim               WatchType = "Claim"
-	WatchTypeCompositeResource   WatchType = "CompositeResource"
-	WatchTypeComposedResource    WatchType = "ComposedResource"
-	WatchTypeCompositionRevision WatchType = "CompositionRevision"
+	WatchTypeClaim                         WatchType = "Claim"
+	WatchTypeCompositeResource             WatchType = "CompositeResource"
+	WatchTypeComposedResource              WatchType = "ComposedResource"
+	WatchTypeCompositionRevision           WatchType = "CompositionRevision"
+	WatchTypeNamespacedCompositionRevision WatchType = "NamespacedCompositionRevision"
 )
 
 // Watch an object.
@@ -38,6 +38,43 @@ const (
 	// EnableAlphaFunctionResponseCache enables alpha support for caching
 	// composition function responses.
 	EnableAlphaFunctionResponseCache feature.Flag = "EnableAlphaFunctionResponseCache"
+
+	// EnableAlphaNamespacedCompositions enables alpha support for
+	// NamespacedCompositions, which namespaced composite resources in the
+	// same namespace may select.
+	EnableAlphaNamespacedCompositions feature.Flag = "EnableAlphaNamespacedCompositions"
+
+	// EnableAlphaSafeCRDUpgrades enables alpha support for refusing to
+	// update a package's CRDs when doing so could strand existing custom
+	// resources.
+	EnableAlphaSafeCRDUpgrades feature.Flag = "EnableAlphaSafeCRDUpgrades"
+
+	// EnableAlphaCRDStorageVersionMigration enables alpha support for
+	// migrating custom resources to a CRD's storage version before a package
+	// removes a version they may be stored at. It builds on
+	// EnableAlphaSafeCRDUpgrades; enabling it enables both.
+	EnableAlphaCRDStorageVersionMigration feature.Flag = "EnableAlphaCRDStorageVersionMigration"
+
+	// EnableAlphaAutomaticPackageUpdates enables alpha support for
+	// automatically updating packages to the newest version in the channel
+	// specified by their update policy.
+	EnableAlphaAutomaticPackageUpdates feature.Flag = "EnableAlphaAutomaticPackageUpdates"
+
+	// EnableAlphaRegistryCredentialProviders enables alpha support for
+	// authenticating to package registries using the credential providers
+	// configured by the ImageConfig API, such as token exchange or a Docker
+	// credential helper.
+	EnableAlphaRegistryCredentialProviders feature.Flag = "EnableAlphaRegistryCredentialProviders"
+
+	// EnableAlphaRegistryMirrors enables alpha support for falling back to
+	// the registry mirrors configured by the ImageConfig API when a package's
+	// registry is unavailable.
+	EnableAlphaRegistryMirrors feature.Flag = "EnableAlphaRegistryMirrors"
+
+	// EnableAlphaPackageRollbacks enables alpha support for re-activating a
+	// package's last healthy revision when a new revision doesn't become
+	// healthy within the timeout specified by the package's rollback policy.
+	EnableAlphaPackageRollbacks feature.Flag = "EnableAlphaPackageRollbacks"
 )
 
 // Beta Feature Flags.
@@ -59,6 +59,14 @@ func (c *CoreCRDsMigrator) Run(ctx context.Context, kube client.Client) error {
 	if !sets.NewString(crd.Status.StoredVersions...).Has(c.oldVersion) {
 		return nil
 	}
+	return MigrateStorageVersion(ctx, kube, &crd)
+}
+
+// MigrateStorageVersion rewrites every custom resource of the supplied CRD so
+// that they're all stored at its storage version, then removes every other
+// version from its stored versions. A CRD must not drop a version that
+// resources may still be stored at.
+func MigrateStorageVersion(ctx context.Context, kube client.Client, crd *extv1.CustomResourceDefinition) error {
 	// we need to patch all resources to the new storage version
 	var storageVersion string
 	for _, v := range crd.Spec.Versions {
@@ -96,16 +104,16 @@ func (c *CoreCRDsMigrator) Run(ctx context.Context, kube client.Client) error {
 
 	origCrd := crd.DeepCopy()
 	crd.Status.StoredVersions = []string{storageVersion}
-	if err := kube.Status().Patch(ctx, &crd, client.MergeFrom(origCrd)); err != nil {
-		return errors.Wrapf(err, "couldn't update %s crd", c.crdName)
+	if err := kube.Status().Patch(ctx, crd, client.MergeFrom(origCrd)); err != nil {
+		return errors.Wrapf(err, "couldn't update %s crd", crd.GetName())
 	}
 
 	// One more check just to be sure we actually updated the crd
-	if err := kube.Get(ctx, client.ObjectKey{Name: c.crdName}, &crd); err != nil {
-		return errors.Wrapf(err, "cannot get %s crd to check", c.crdName)
+	if err := kube.Get(ctx, client.ObjectKey{Name: crd.GetName()}, crd); err != nil {
+		return errors.Wrapf(err, "cannot get %s crd to check", crd.GetName())
 	}
 	if len(crd.Status.StoredVersions) != 1 || crd.Status.StoredVersions[0] != storageVersion {
-		return errors.Errorf("was expecting CRD %q to only have %s, got instead: %v", c.crdName, storageVersion, crd.Status.StoredVersions)
+		return errors.Errorf("was expecting CRD %q to only have %s, got instead: %v", crd.GetName(), storageVersion, crd.Status.StoredVersions)
 	}
 
 	return nil
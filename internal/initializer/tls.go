@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -64,6 +65,7 @@ type TLSCertificateGenerator struct {
 	caSecretName        string
 	tlsServerSecretName *string
 	tlsServerDNSNames   []string
+	tlsServerReissue    bool
 	tlsClientSecretName *string
 	tlsClientDNSNames   []string
 	owner               []metav1.OwnerReference
@@ -96,6 +98,15 @@ func TLSCertificateGeneratorWithServerSecretName(s string, dnsNames []string) TL
 	}
 }
 
+// TLSCertificateGeneratorWithServerCertificateReissue returns an
+// TLSCertificateGeneratorOption that reissues an existing server certificate
+// if its DNS names don't match the server DNS names.
+func TLSCertificateGeneratorWithServerCertificateReissue() TLSCertificateGeneratorOption {
+	return func(g *TLSCertificateGenerator) {
+		g.tlsServerReissue = true
+	}
+}
+
 // TLSCertificateGeneratorWithClientSecretName returns an TLSCertificateGeneratorOption that sets client secret name.
 func TLSCertificateGeneratorWithClientSecretName(s string, subjects []string) TLSCertificateGeneratorOption {
 	return func(g *TLSCertificateGenerator) {
@@ -244,11 +255,13 @@ func (e *TLSCertificateGenerator) ensureServerCertificate(ctx context.Context, k
 	if err == nil {
 		create = false
 		if len(sec.Data[corev1.TLSCertKey]) != 0 || len(sec.Data[corev1.TLSPrivateKeyKey]) != 0 || len(sec.Data[SecretKeyCACert]) != 0 {
-			e.log.Info("TLS secret contains server certificate.", "secret", nn.Name)
-			return nil
+			if !e.tlsServerReissue || hasDNSNames(sec.Data[corev1.TLSCertKey], e.tlsServerDNSNames) {
+				e.log.Info("TLS secret contains server certificate.", "secret", nn.Name)
+				return nil
+			}
 		}
 	}
-	e.log.Info("Server certificates are empty or not complete, generating a new pair...", "secret", nn.Name)
+	e.log.Info("Server certificates are empty, not complete, or for different DNS names, generating a new pair...", "secret", nn.Name)
 	dnsNames := e.tlsServerDNSNames
 	if len(dnsNames) == 0 {
 		return errors.New("server DNS names are empty, you must provide at least one DNS name")
@@ -354,6 +367,20 @@ func parseCertificateSigner(key, cert []byte) (*CertificateSigner, error) {
 	}, nil
 }
 
+// hasDNSNames returns true if the supplied PEM encoded certificate is valid for
+// exactly the supplied DNS names.
+func hasDNSNames(cert []byte, dnsNames []string) bool {
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return false
+	}
+	c, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+	return sets.New(c.DNSNames...).Equal(sets.New(dnsNames...))
+}
+
 // DNSNamesForService returns a list of DNS names for a given service name and namespace.
 func DNSNamesForService(service, namespace string) []string {
 	return []string{
@@ -19,7 +19,9 @@ package initializer
 import (
 	"context"
 	"crypto/x509"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
@@ -91,6 +93,48 @@ var (
 )
 
 func TestTLSCertificateGeneratorRun(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	signer, err := parseCertificateSigner([]byte(caKey), []byte(caCert))
+	if err != nil {
+		t.Fatalf("parseCertificateSigner(...): %s", err)
+	}
+	serverKey, serverCert, err := NewCertGenerator().Generate(&x509.Certificate{
+		SerialNumber: big.NewInt(2022),
+		DNSNames:     []string{subject},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().AddDate(10, 0, 0),
+	}, signer)
+	if err != nil {
+		t.Fatalf("Generate(...): %s", err)
+	}
+	serverSecretGetFn := func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		if key.Name == caCertSecretName && key.Namespace == secretNS {
+			s := &corev1.Secret{
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       []byte(caCert),
+					corev1.TLSPrivateKeyKey: []byte(caKey),
+				},
+			}
+			s.DeepCopyInto(obj.(*corev1.Secret))
+			return nil
+		}
+
+		if key.Name != tlsServerSecretName || key.Namespace != secretNS {
+			return errors.New("unexpected secret name or namespace")
+		}
+
+		s := &corev1.Secret{
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       serverCert,
+				corev1.TLSPrivateKeyKey: serverKey,
+				SecretKeyCACert:         []byte(caCert),
+			},
+		}
+		s.DeepCopyInto(obj.(*corev1.Secret))
+		return nil
+	}
+
 	type args struct {
 		kube        client.Client
 		certificate CertificateGenerator
@@ -617,6 +661,52 @@ func TestTLSCertificateGeneratorRun(t *testing.T) {
 				},
 			},
 		},
+		"OnlyServerCertificateReissueDNSNamesUnchanged": {
+			reason: "It should not reissue a server certificate that is valid for the server DNS names.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet:    serverSecretGetFn,
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+				opts: []TLSCertificateGeneratorOption{
+					TLSCertificateGeneratorWithServerSecretName(tlsServerSecretName, []string{subject}),
+					TLSCertificateGeneratorWithServerCertificateReissue(),
+				},
+			},
+			want: want{err: nil},
+		},
+		"OnlyServerCertificateReissueDNSNamesChanged": {
+			reason: "It should reissue a server certificate that isn't valid for the server DNS names.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet:    serverSecretGetFn,
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+				certificate: &MockCertificateGenerator{
+					MockGenerate: func(_ *x509.Certificate, _ *CertificateSigner) ([]byte, []byte, error) {
+						return []byte(caKey), []byte(caCert), nil
+					},
+				},
+				opts: []TLSCertificateGeneratorOption{
+					TLSCertificateGeneratorWithServerSecretName(tlsServerSecretName, []string{"example.org"}),
+					TLSCertificateGeneratorWithServerCertificateReissue(),
+				},
+			},
+			want: want{err: errors.Wrap(errors.Wrapf(errBoom, errFmtCannotCreateOrUpdate, tlsServerSecretName), errGenerateServerCert)},
+		},
+		"OnlyServerCertificateDNSNamesChanged": {
+			reason: "It should not reissue a server certificate that isn't valid for the server DNS names unless asked to.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet:    serverSecretGetFn,
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+				opts: []TLSCertificateGeneratorOption{
+					TLSCertificateGeneratorWithServerSecretName(tlsServerSecretName, []string{"example.org"}),
+				},
+			},
+			want: want{err: nil},
+		},
 		// OnlyClientCertificate test cases
 		"OnlyClientCertificateCannotGetCASecret": {
 			reason: "It should return error if the CA secret cannot be retrieved.",
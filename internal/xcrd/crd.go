@@ -50,9 +50,34 @@ const (
 	errCustomResourceValidationNil = "custom resource validation cannot be nil"
 )
 
+// CompositeResourceOptions configure how a composite resource's
+// CustomResourceDefinition is derived.
+type CompositeResourceOptions struct {
+	// NamespacedCompositions allows namespaced composite resources to
+	// reference a NamespacedComposition.
+	NamespacedCompositions bool
+}
+
+// A CompositeResourceOption configures how a composite resource's
+// CustomResourceDefinition is derived.
+type CompositeResourceOption func(o *CompositeResourceOptions)
+
+// WithNamespacedCompositions allows namespaced composite resources to
+// reference a NamespacedComposition in their own namespace.
+func WithNamespacedCompositions() CompositeResourceOption {
+	return func(o *CompositeResourceOptions) {
+		o.NamespacedCompositions = true
+	}
+}
+
 // ForCompositeResource derives the CustomResourceDefinition for a composite
 // resource from the supplied CompositeResourceDefinition.
-func ForCompositeResource(xrd *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+func ForCompositeResource(xrd *v1.CompositeResourceDefinition, opts ...CompositeResourceOption) (*extv1.CustomResourceDefinition, error) {
+	o := &CompositeResourceOptions{}
+	for _, fn := range opts {
+		fn(o)
+	}
+
 	crd := &extv1.CustomResourceDefinition{
 		Spec: extv1.CustomResourceDefinitionSpec{
 			Group:      xrd.Spec.Group,
@@ -91,6 +116,9 @@ func ForCompositeResource(xrd *v1.CompositeResourceDefinition) (*extv1.CustomRes
 		}
 		crdv.AdditionalPrinterColumns = append(crdv.AdditionalPrinterColumns, CompositeResourcePrinterColumns(scope)...)
 		props := CompositeResourceSpecProps(scope, xrd.Spec.DefaultCompositionUpdatePolicy)
+		if o.NamespacedCompositions && scope == v1.CompositeResourceScopeNamespaced {
+			props["crossplane"].Properties["compositionRef"] = NamespacedCompositionRefProps()
+		}
 		for k, v := range props {
 			crdv.Schema.OpenAPIV3Schema.Properties["spec"].Properties[k] = v
 		}
@@ -2033,6 +2033,77 @@ func TestForCompositeResource(t *testing.T) {
 	}
 }
 
+func TestForCompositeResourceNamespacedCompositions(t *testing.T) {
+	type args struct {
+		scope v1.CompositeResourceScope
+		o     []CompositeResourceOption
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   extv1.JSONSchemaProps
+	}{
+		"Disabled": {
+			reason: "A namespaced XR should only reference a Composition by name unless NamespacedCompositions are enabled.",
+			args: args{
+				scope: v1.CompositeResourceScopeNamespaced,
+			},
+			want: CompositeResourceSpecProps(v1.CompositeResourceScopeNamespaced, nil)["crossplane"].Properties["compositionRef"],
+		},
+		"EnabledCluster": {
+			reason: "A cluster scoped XR should only reference a Composition by name even when NamespacedCompositions are enabled.",
+			args: args{
+				scope: v1.CompositeResourceScopeCluster,
+				o:     []CompositeResourceOption{WithNamespacedCompositions()},
+			},
+			want: CompositeResourceSpecProps(v1.CompositeResourceScopeCluster, nil)["crossplane"].Properties["compositionRef"],
+		},
+		"EnabledNamespaced": {
+			reason: "A namespaced XR should be able to reference a NamespacedComposition when NamespacedCompositions are enabled.",
+			args: args{
+				scope: v1.CompositeResourceScopeNamespaced,
+				o:     []CompositeResourceOption{WithNamespacedCompositions()},
+			},
+			want: NamespacedCompositionRefProps(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			xrd := &v1.CompositeResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: v1.CompositeResourceDefinitionSpec{
+					Scope: ptr.To(tc.args.scope),
+					Group: group,
+					Names: extv1.CustomResourceDefinitionNames{
+						Plural:   plural,
+						Singular: singular,
+						Kind:     kind,
+						ListKind: listKind,
+					},
+					Versions: []v1.CompositeResourceDefinitionVersion{{
+						Name:          version,
+						Referenceable: true,
+						Served:        true,
+						Schema: &v1.CompositeResourceValidation{
+							OpenAPIV3Schema: runtime.RawExtension{Raw: []byte(schema)},
+						},
+					}},
+				},
+			}
+			crd, err := ForCompositeResource(xrd, tc.args.o...)
+			if err != nil {
+				t.Fatalf("ForCompositeResource(...): %s", err)
+			}
+
+			got := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties["crossplane"].Properties["compositionRef"]
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nForCompositeResource(...): -want compositionRef, +got compositionRef:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestValidateClaimNames(t *testing.T) {
 	cases := map[string]struct {
 		d    *v1.CompositeResourceDefinition
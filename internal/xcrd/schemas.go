@@ -290,6 +290,27 @@ func CompositeResourceClaimSpecProps(defaultPol *xpv1.CompositeDeletePolicy) map
 	}
 }
 
+// NamespacedCompositionRefProps is a partial OpenAPIV3Schema for the
+// compositionRef of a namespaced composite resource that may reference a
+// NamespacedComposition in its own namespace instead of a (cluster scoped)
+// Composition.
+func NamespacedCompositionRefProps() extv1.JSONSchemaProps {
+	return extv1.JSONSchemaProps{
+		Type:     "object",
+		Required: []string{"name"},
+		Properties: map[string]extv1.JSONSchemaProps{
+			"kind": {
+				Type: "string",
+				Enum: []extv1.JSON{
+					{Raw: []byte(`"Composition"`)},
+					{Raw: []byte(`"NamespacedComposition"`)},
+				},
+			},
+			"name": {Type: "string"},
+		},
+	}
+}
+
 // CompositeResourceStatusProps is a partial OpenAPIV3Schema for the status
 // fields that Crossplane expects to be present for all composite resources.
 func CompositeResourceStatusProps(s v1.CompositeResourceScope) map[string]extv1.JSONSchemaProps {
@@ -30,9 +30,15 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
 	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
+	"github.com/crossplane/crossplane/internal/xfn"
 	"github.com/crossplane/crossplane/internal/xfn/cached/proto/v1alpha1"
 )
 
+const (
+	errNoRevisionResolver = "cannot resolve the function revision of a request pinned to a specific revision"
+	errResolveRevision    = "cannot resolve function revision"
+)
+
 // A CacheMissReason indicates what caused a cache miss.
 type CacheMissReason string
 
@@ -80,6 +86,23 @@ type FunctionRunner interface {
 	RunFunction(ctx context.Context, name string, req *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error)
 }
 
+// A RevisionResolver resolves the FunctionRevision a request to a composition
+// function will be sent to.
+type RevisionResolver interface {
+	// ResolveRevision returns the name of the FunctionRevision a request
+	// to the named composition function will be sent to.
+	ResolveRevision(ctx context.Context, name string) (string, error)
+}
+
+// A RevisionResolverFn is a function that resolves a FunctionRevision.
+type RevisionResolverFn func(ctx context.Context, name string) (string, error)
+
+// ResolveRevision returns the name of the FunctionRevision a request to the
+// named composition function will be sent to.
+func (fn RevisionResolverFn) ResolveRevision(ctx context.Context, name string) (string, error) {
+	return fn(ctx, name)
+}
+
 // A FileBackedRunner wraps another function runner. It caches responses
 // returned by that runner to the filesystem. It only caches responses that
 // specify a TTL. Requests are served from cache if there's a cached response
@@ -90,6 +113,7 @@ type FileBackedRunner struct {
 	maxTTL  time.Duration
 	log     logging.Logger
 	metrics Metrics
+	rev     RevisionResolver
 }
 
 // A FileBackedRunnerOption configures a FileBackedRunner.
@@ -118,6 +142,15 @@ func WithMaxTTL(ttl time.Duration) FileBackedRunnerOption {
 	}
 }
 
+// WithRevisionResolver specifies how the FileBackedRunner should resolve the
+// FunctionRevision a request pinned to a specific revision will be sent to.
+// Pinned requests bypass the cache if no resolver is specified.
+func WithRevisionResolver(rr RevisionResolver) FileBackedRunnerOption {
+	return func(r *FileBackedRunner) {
+		r.rev = rr
+	}
+}
+
 // WithFilesystem specifies which filesystem implementation the FileBackedRunner
 // should use. The runner will ignore its path argument and cache files at the
 // root of this filesystem. Wrap your desired filesystem with afero.BasePathFS
@@ -159,7 +192,13 @@ func (r *FileBackedRunner) RunFunction(ctx context.Context, name string, req *fn
 		return r.wrapped.RunFunction(ctx, name, req)
 	}
 
-	key := filepath.Join(name, req.GetMeta().GetTag())
+	key, err := r.cacheKey(ctx, name, req)
+	if err != nil {
+		log.Info("RunFunctionResponse cache miss", "reason", ReasonError, "err", err)
+		r.metrics.Miss(name)
+		r.metrics.Error(name)
+		return r.wrapped.RunFunction(ctx, name, req)
+	}
 	log = log.WithValues("cache-key", key)
 
 	b, err := r.fs.ReadFile(key)
@@ -207,7 +246,12 @@ func (r *FileBackedRunner) CacheFunction(ctx context.Context, name string, req *
 		return r.wrapped.RunFunction(ctx, name, req)
 	}
 
-	key := filepath.Join(name, req.GetMeta().GetTag())
+	key, err := r.cacheKey(ctx, name, req)
+	if err != nil {
+		r.log.Info("RunFunctionResponse cache write error", "name", name, "err", err)
+		r.metrics.Error(name)
+		return r.wrapped.RunFunction(ctx, name, req)
+	}
 	log := r.log.WithValues("name", name, "cache-key", key)
 
 	rsp, err := r.wrapped.RunFunction(ctx, name, req)
@@ -279,6 +323,25 @@ func (r *FileBackedRunner) CacheFunction(ctx context.Context, name string, req *
 	return rsp, nil
 }
 
+// cacheKey returns the path at which to cache the response to the supplied
+// request. Different revisions of a Function may respond differently to the
+// same request, so requests pinned to a specific revision are cached under
+// the name of the revision they resolve to. Selectors that resolve to the
+// same revision share cached responses.
+func (r *FileBackedRunner) cacheKey(ctx context.Context, name string, req *fnv1.RunFunctionRequest) (string, error) {
+	if _, ok := xfn.RevisionSelectorFrom(ctx); !ok {
+		return filepath.Join(name, req.GetMeta().GetTag()), nil
+	}
+	if r.rev == nil {
+		return "", errors.New(errNoRevisionResolver)
+	}
+	rev, err := r.rev.ResolveRevision(ctx, name)
+	if err != nil {
+		return "", errors.Wrap(err, errResolveRevision)
+	}
+	return filepath.Join(name, req.GetMeta().GetTag()+"-"+rev), nil
+}
+
 // GarbageCollectFiles runs every interval until the supplied context is
 // cancelled. It garbage collects cached responses with expired deadlines.
 func (r *FileBackedRunner) GarbageCollectFiles(ctx context.Context, interval time.Duration) {
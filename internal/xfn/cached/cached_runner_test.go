@@ -33,6 +33,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
 	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
+	"github.com/crossplane/crossplane/internal/xfn"
 	"github.com/crossplane/crossplane/internal/xfn/cached/proto/v1alpha1"
 )
 
@@ -141,6 +142,7 @@ func TestRunFunction(t *testing.T) {
 				},
 			},
 			args: args{
+				ctx:  context.Background(),
 				name: "coolfn",
 				req: &fnv1.RunFunctionRequest{
 					Meta: &fnv1.RequestMeta{Tag: "hello"},
@@ -167,6 +169,7 @@ func TestRunFunction(t *testing.T) {
 				},
 			},
 			args: args{
+				ctx:  context.Background(),
 				name: "coolfn",
 				req: &fnv1.RunFunctionRequest{
 					Meta: &fnv1.RequestMeta{Tag: "hello"},
@@ -196,6 +199,7 @@ func TestRunFunction(t *testing.T) {
 				},
 			},
 			args: args{
+				ctx:  context.Background(),
 				name: "coolfn",
 				req: &fnv1.RunFunctionRequest{
 					Meta: &fnv1.RequestMeta{Tag: "hello"},
@@ -230,6 +234,7 @@ func TestRunFunction(t *testing.T) {
 				},
 			},
 			args: args{
+				ctx:  context.Background(),
 				name: "coolfn",
 				req: &fnv1.RunFunctionRequest{
 					Meta: &fnv1.RequestMeta{Tag: "hello"},
@@ -277,6 +282,7 @@ func TestRunFunction(t *testing.T) {
 				},
 			},
 			args: args{
+				ctx:  context.Background(),
 				name: "coolfn",
 				req: &fnv1.RunFunctionRequest{
 					Meta: &fnv1.RequestMeta{Tag: "hello"},
@@ -315,6 +321,7 @@ func TestRunFunction(t *testing.T) {
 				},
 			},
 			args: args{
+				ctx:  context.Background(),
 				name: "coolfn",
 				req: &fnv1.RunFunctionRequest{
 					Meta: &fnv1.RequestMeta{Tag: "hello"},
@@ -329,6 +336,133 @@ func TestRunFunction(t *testing.T) {
 				},
 			},
 		},
+		"PinnedRevisionCachedSeparately": {
+			reason: "A response cached for a Function's active revision shouldn't be returned for a request pinned to another revision.",
+			params: params{
+				wrap: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
+					rsp := &fnv1.RunFunctionResponse{
+						Meta: &fnv1.ResponseMeta{
+							Tag: "pinned",
+							Ttl: durationpb.New(10 * time.Minute),
+						},
+					}
+					return rsp, nil
+				}),
+				o: []FileBackedRunnerOption{
+					WithLogger(&TestLogger{t: t}),
+					WithRevisionResolver(RevisionResolverFn(func(_ context.Context, _ string) (string, error) {
+						return "coolfn-a1b2c3", nil
+					})),
+					WithFilesystem(MockFs(map[string][]byte{
+						"coolfn/hello": func() []byte {
+							msg, _ := proto.Marshal(&v1alpha1.CachedRunFunctionResponse{
+								Deadline: timestamppb.New(time.Now().Add(1 * time.Minute)),
+								Response: &fnv1.RunFunctionResponse{
+									Meta: &fnv1.ResponseMeta{
+										Tag: "hello",
+										Ttl: durationpb.New(10 * time.Minute),
+									},
+								},
+							})
+
+							return msg
+						}(),
+					})),
+				},
+			},
+			args: args{
+				ctx:  xfn.WithRevisionSelector(context.Background(), xfn.RevisionSelector{Version: "v1.x"}),
+				name: "coolfn",
+				req: &fnv1.RunFunctionRequest{
+					Meta: &fnv1.RequestMeta{Tag: "hello"},
+				},
+			},
+			want: want{
+				rsp: &fnv1.RunFunctionResponse{
+					Meta: &fnv1.ResponseMeta{
+						Tag: "pinned",
+						Ttl: durationpb.New(10 * time.Minute),
+					},
+				},
+			},
+		},
+		"PinnedRevisionCacheHit": {
+			reason: "A request pinned to a specific revision should be served from cache when there's a response cached for the revision it resolves to.",
+			params: params{
+				wrap: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
+					return nil, errors.New("this shouldn't be called")
+				}),
+				o: []FileBackedRunnerOption{
+					WithLogger(&TestLogger{t: t}),
+					WithRevisionResolver(RevisionResolverFn(func(_ context.Context, _ string) (string, error) {
+						return "coolfn-a1b2c3", nil
+					})),
+					WithFilesystem(MockFs(map[string][]byte{
+						"coolfn/hello-coolfn-a1b2c3": func() []byte {
+							msg, _ := proto.Marshal(&v1alpha1.CachedRunFunctionResponse{
+								Deadline: timestamppb.New(time.Now().Add(1 * time.Minute)),
+								Response: &fnv1.RunFunctionResponse{
+									Meta: &fnv1.ResponseMeta{
+										Tag: "pinned",
+										Ttl: durationpb.New(10 * time.Minute),
+									},
+								},
+							})
+
+							return msg
+						}(),
+					})),
+				},
+			},
+			args: args{
+				ctx:  xfn.WithRevisionSelector(context.Background(), xfn.RevisionSelector{Revision: "coolfn-a1b2c3"}),
+				name: "coolfn",
+				req: &fnv1.RunFunctionRequest{
+					Meta: &fnv1.RequestMeta{Tag: "hello"},
+				},
+			},
+			want: want{
+				rsp: &fnv1.RunFunctionResponse{
+					Meta: &fnv1.ResponseMeta{
+						Tag: "pinned",
+						Ttl: durationpb.New(10 * time.Minute),
+					},
+				},
+			},
+		},
+		"ResolveRevisionError": {
+			reason: "A request pinned to a specific revision should bypass the cache if we can't resolve the revision it'll be sent to.",
+			params: params{
+				wrap: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
+					rsp := &fnv1.RunFunctionResponse{
+						Meta: &fnv1.ResponseMeta{
+							Tag: "uncached",
+						},
+					}
+					return rsp, nil
+				}),
+				o: []FileBackedRunnerOption{
+					WithLogger(&TestLogger{t: t}),
+					WithRevisionResolver(RevisionResolverFn(func(_ context.Context, _ string) (string, error) {
+						return "", errors.New("boom")
+					})),
+				},
+			},
+			args: args{
+				ctx:  xfn.WithRevisionSelector(context.Background(), xfn.RevisionSelector{Version: "v1.x"}),
+				name: "coolfn",
+				req: &fnv1.RunFunctionRequest{
+					Meta: &fnv1.RequestMeta{Tag: "hello"},
+				},
+			},
+			want: want{
+				rsp: &fnv1.RunFunctionResponse{
+					Meta: &fnv1.ResponseMeta{
+						Tag: "uncached",
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
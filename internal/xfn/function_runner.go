@@ -19,6 +19,9 @@ package xfn
 import (
 	"context"
 	"crypto/tls"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -29,6 +32,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
@@ -48,6 +52,8 @@ const (
 	errFmtRunFunction   = "cannot run Function %q"
 	errFmtEmptyEndpoint = "cannot determine gRPC target: active FunctionRevision %q has an empty status.endpoint"
 	errFmtDialFunction  = "cannot gRPC dial target %q from status.endpoint of active FunctionRevision %q"
+
+	errFmtRevisionNotReady = "pinned FunctionRevision %q is not ready to serve requests"
 )
 
 // This configures a gRPC client to use round robin load balancing. This means
@@ -73,6 +79,10 @@ const svcConfig = `
 	]
 }`
 
+// staleConnDrainPeriod is how long we wait before closing a gRPC client
+// connection whose target is stale, giving in-flight RPCs time to complete.
+const staleConnDrainPeriod = 30 * time.Second
+
 // A FunctionRunner runs a composition function.
 type FunctionRunner interface {
 	// RunFunction runs the named composition function.
@@ -147,6 +157,9 @@ func NewPackagedFunctionRunner(c client.Reader, o ...PackagedFunctionRunnerOptio
 
 // RunFunction sends the supplied RunFunctionRequest to the named Function. The
 // function is expected to be an installed Function.pkg.crossplane.io package.
+// The request is sent to the Function's active FunctionRevision, unless the
+// supplied context pins the Function to another revision. See
+// WithRevisionSelector.
 func (r *PackagedFunctionRunner) RunFunction(ctx context.Context, name string, req *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
 	conn, err := r.getClientConn(ctx, name)
 	if err != nil {
@@ -183,30 +196,34 @@ func (r *PackagedFunctionRunner) RunFunction(ctx context.Context, name string, r
 func (r *PackagedFunctionRunner) getClientConn(ctx context.Context, name string) (*grpc.ClientConn, error) {
 	log := r.log.WithValues("function", name)
 
-	l := &pkgv1.FunctionRevisionList{}
-	if err := r.client.List(ctx, l, client.MatchingLabels{pkgv1.LabelParentPackage: name}); err != nil {
-		return nil, errors.Wrapf(err, errListFunctionRevisions)
+	target, active, err := r.revisions(ctx, name)
+	if err != nil {
+		return nil, err
 	}
 
-	var active *pkgv1.FunctionRevision
-	for i := range l.Items {
-		if l.Items[i].GetDesiredState() == pkgv1.PackageRevisionActive {
-			active = &l.Items[i]
-			break
-		}
-	}
-	if active == nil {
-		return nil, errors.New(errNoActiveRevisions)
+	if target.Status.Endpoint == "" {
+		return nil, errors.Errorf(errFmtEmptyEndpoint, target.GetName())
 	}
 
-	if active.Status.Endpoint == "" {
-		return nil, errors.Errorf(errFmtEmptyEndpoint, active.GetName())
+	// Connections to the active revision (or a revision we route to while
+	// it starts) use the Function's Service. A pinned revision that isn't
+	// active is served by its own Service, so it needs its own connection.
+	key := name
+	var dopts []grpc.DialOption
+	if target.Status.Endpoint != active.Status.Endpoint {
+		key = name + "/" + target.GetName()
+
+		// The Function's TLS server certificate is only valid for the
+		// Function's Service, so we present its name as our authority.
+		if authority, ok := strings.CutPrefix(active.Status.Endpoint, "dns:///"); ok {
+			dopts = append(dopts, grpc.WithAuthority(authority))
+		}
 	}
 
 	// If we have a connection for the up-to-date endpoint, return it.
 	r.connsMx.RLock()
-	conn, ok := r.conns[name]
-	if ok && conn.Target() == active.Status.Endpoint {
+	conn, ok := r.conns[key]
+	if ok && conn.Target() == target.Status.Endpoint {
 		defer r.connsMx.RUnlock()
 		return conn, nil
 	}
@@ -218,40 +235,131 @@ func (r *PackagedFunctionRunner) getClientConn(ctx context.Context, name string)
 
 	// Another Goroutine might have updated the connections between when we
 	// released the read lock and took the write lock, so check again.
-	conn, ok = r.conns[name]
+	conn, ok = r.conns[key]
 	if ok {
 		// We now have a connection for the up-to-date endpoint.
-		if conn.Target() == active.Status.Endpoint {
+		if conn.Target() == target.Status.Endpoint {
 			return conn, nil
 		}
 
 		// This connection is to an old endpoint. We need to close it and create
-		// a new connection. Close only returns an error is if the connection is
-		// already closed or in the process of closing.
-		log.Debug("Closing gRPC client connection with stale target", "old-target", conn.Target(), "new-target", active.Status.Endpoint)
-		_ = conn.Close()
-		delete(r.conns, name)
+		// a new connection. RPCs may still be in-flight on the old connection,
+		// so we give them time to complete before closing it. Close only
+		// returns an error is if the connection is already closed or in the
+		// process of closing.
+		log.Debug("Closing gRPC client connection with stale target", "old-target", conn.Target(), "new-target", target.Status.Endpoint, "drain-period", staleConnDrainPeriod)
+		time.AfterFunc(staleConnDrainPeriod, func() { _ = conn.Close() })
+		delete(r.conns, key)
 	}
 
 	is := make([]grpc.UnaryClientInterceptor, len(r.interceptors))
 	for i := range r.interceptors {
-		is[i] = r.interceptors[i].CreateInterceptor(name, active.Spec.Package)
+		is[i] = r.interceptors[i].CreateInterceptor(name, target.Spec.Package)
 	}
 
-	conn, err := grpc.NewClient(active.Status.Endpoint,
+	dopts = append(dopts,
 		grpc.WithTransportCredentials(r.creds),
 		grpc.WithDefaultServiceConfig(svcConfig),
 		grpc.WithChainUnaryInterceptor(is...))
+
+	conn, err = grpc.NewClient(target.Status.Endpoint, dopts...)
 	if err != nil {
-		return nil, errors.Wrapf(err, errFmtDialFunction, active.Status.Endpoint, active.GetName())
+		return nil, errors.Wrapf(err, errFmtDialFunction, target.Status.Endpoint, target.GetName())
 	}
 
-	r.conns[name] = conn
+	r.conns[key] = conn
 
-	log.Debug("Created new gRPC client connection", "target", active.Status.Endpoint)
+	log.Debug("Created new gRPC client connection", "target", target.Status.Endpoint, "revision", target.GetName())
 	return conn, nil
 }
 
+// ResolveRevision returns the name of the FunctionRevision a request to the
+// named Function would be sent to, given the supplied context.
+func (r *PackagedFunctionRunner) ResolveRevision(ctx context.Context, name string) (string, error) {
+	target, _, err := r.revisions(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return target.GetName(), nil
+}
+
+// revisions returns the FunctionRevision requests to the named Function
+// should be sent to, and the Function's active FunctionRevision.
+func (r *PackagedFunctionRunner) revisions(ctx context.Context, name string) (target, active *pkgv1.FunctionRevision, err error) {
+	l := &pkgv1.FunctionRevisionList{}
+	if err := r.client.List(ctx, l, client.MatchingLabels{pkgv1.LabelParentPackage: name}); err != nil {
+		return nil, nil, errors.Wrapf(err, errListFunctionRevisions)
+	}
+
+	for i := range l.Items {
+		if l.Items[i].GetDesiredState() == pkgv1.PackageRevisionActive {
+			active = &l.Items[i]
+			break
+		}
+	}
+	if active == nil {
+		return nil, nil, errors.New(errNoActiveRevisions)
+	}
+
+	target, err = r.targetRevision(ctx, l.Items, active)
+	if err != nil {
+		return nil, nil, err
+	}
+	return target, active, nil
+}
+
+// targetRevision returns the FunctionRevision RunFunctionRequests should be
+// sent to. This is the revision selected by the context's RevisionSelector, if
+// any, or otherwise the active revision.
+func (r *PackagedFunctionRunner) targetRevision(ctx context.Context, revs []pkgv1.FunctionRevision, active *pkgv1.FunctionRevision) (*pkgv1.FunctionRevision, error) {
+	if s, ok := RevisionSelectorFrom(ctx); ok {
+		target, err := SelectRevision(revs, s)
+		if err != nil {
+			return nil, err
+		}
+
+		// A pinned revision that isn't active can't serve requests until
+		// it has its own endpoint.
+		if target.GetName() != active.GetName() && (!runtimeReady(target) || target.Status.Endpoint == active.Status.Endpoint) {
+			return nil, errors.Errorf(errFmtRevisionNotReady, target.GetName())
+		}
+		return target, nil
+	}
+
+	// When a Function is upgraded, keep routing to the previously active
+	// revision until the newly active revision's runtime is ready. This
+	// avoids failing RunFunction calls while the new revision starts.
+	if !runtimeReady(active) {
+		if prev := previousReadyRevision(revs, active); prev != nil {
+			r.log.Debug("Active FunctionRevision is not ready, routing to previously active FunctionRevision", "function", active.GetLabels()[pkgv1.LabelParentPackage], "active", active.GetName(), "previous", prev.GetName())
+			return prev, nil
+		}
+	}
+	return active, nil
+}
+
+// runtimeReady returns true if the supplied FunctionRevision's runtime is ready
+// to serve RunFunctionRequests.
+func runtimeReady(rev *pkgv1.FunctionRevision) bool {
+	return rev.Status.Endpoint != "" && rev.GetCondition(pkgv1.TypeRuntimeHealthy).Status == corev1.ConditionTrue
+}
+
+// previousReadyRevision returns the most recent FunctionRevision older than the
+// supplied active revision whose runtime is ready, or nil if there is none.
+func previousReadyRevision(revs []pkgv1.FunctionRevision, active *pkgv1.FunctionRevision) *pkgv1.FunctionRevision {
+	var prev *pkgv1.FunctionRevision
+	for i := range revs {
+		rev := &revs[i]
+		if rev.GetRevision() >= active.GetRevision() || !runtimeReady(rev) {
+			continue
+		}
+		if prev == nil || rev.GetRevision() > prev.GetRevision() {
+			prev = rev
+		}
+	}
+	return prev
+}
+
 // GarbageCollectConnections runs every interval until the supplied context is
 // cancelled. It garbage collects gRPC client connections to Functions that are
 // no longer installed.
@@ -273,8 +381,9 @@ func (r *PackagedFunctionRunner) GarbageCollectConnections(ctx context.Context,
 }
 
 // GarbageCollectConnectionsNow immediately garbage collects any gRPC client
-// connections to Functions that are no longer installed. It returns the number
-// of connections garbage collected.
+// connections to Functions that are no longer installed, and to pinned
+// FunctionRevisions that were deleted or activated. It returns the number of
+// connections garbage collected.
 func (r *PackagedFunctionRunner) GarbageCollectConnectionsNow(ctx context.Context) (int, error) {
 	// We try to take the write lock for as little time as possible,
 	// because while we have it RunFunction will block. In the happy
@@ -302,19 +411,42 @@ func (r *PackagedFunctionRunner) GarbageCollectConnectionsNow(ctx context.Contex
 		functionExists[f.GetName()] = true
 	}
 
+	// Connections to pinned revisions are keyed function/revision. They're
+	// only used while the revision exists and isn't active. The active
+	// revision is served by the Function's connection.
+	pinnedServable := map[string]bool{}
+	if slices.ContainsFunc(slices.Collect(maps.Keys(r.conns)), func(key string) bool { return strings.Contains(key, "/") }) {
+		rl := &pkgv1.FunctionRevisionList{}
+		if err := r.client.List(ctx, rl); err != nil {
+			return 0, errors.Wrap(err, errListFunctionRevisions)
+		}
+		for _, rev := range rl.Items {
+			if rev.GetDesiredState() != pkgv1.PackageRevisionActive {
+				pinnedServable[rev.GetLabels()[pkgv1.LabelParentPackage]+"/"+rev.GetName()] = true
+			}
+		}
+	}
+
 	// Garbage collect connections.
 	closed := 0
-	for name := range r.conns {
-		if functionExists[name] {
+	for key, conn := range r.conns {
+		name, rev, pinned := strings.Cut(key, "/")
+		switch {
+		case !functionExists[name]:
+			// Close only returns an error is if the connection is
+			// already closed or in the process of closing.
+			_ = conn.Close()
+			r.log.Debug("Closed gRPC client connection to Function that is no longer installed", "function", name)
+		case pinned && !pinnedServable[key]:
+			// RPCs may still be in-flight on the connection, so we
+			// give them time to complete before closing it.
+			time.AfterFunc(staleConnDrainPeriod, func() { _ = conn.Close() })
+			r.log.Debug("Closing gRPC client connection to FunctionRevision that is no longer pinned", "function", name, "revision", rev, "drain-period", staleConnDrainPeriod)
+		default:
 			continue
 		}
-
-		// Close only returns an error is if the connection is already
-		// closed or in the process of closing.
-		_ = r.conns[name].Close()
-		delete(r.conns, name)
+		delete(r.conns, key)
 		closed++
-		r.log.Debug("Closed gRPC client connection to Function that is no longer installed", "function", name)
 	}
 
 	return closed, nil
@@ -29,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
@@ -326,6 +327,130 @@ func TestGetClientConn(t *testing.T) {
 		}
 	})
 
+	// A Function was upgraded, but its newly active FunctionRevision isn't
+	// ready yet. We should keep using the previously active revision.
+	previous := target
+	upgraded := "dns:///localhost:1"
+	c.MockList = test.NewMockListFn(nil, func(obj client.ObjectList) error {
+		l, ok := obj.(*pkgv1.FunctionRevisionList)
+		if !ok {
+			return nil
+		}
+		l.Items = []pkgv1.FunctionRevision{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-fn-revision-a"},
+				Spec: pkgv1.FunctionRevisionSpec{
+					PackageRevisionSpec: pkgv1.PackageRevisionSpec{
+						DesiredState: pkgv1.PackageRevisionInactive,
+						Revision:     1,
+					},
+				},
+				Status: pkgv1.FunctionRevisionStatus{
+					PackageRevisionStatus: pkgv1.PackageRevisionStatus{
+						ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{pkgv1.RuntimeHealthy()}},
+					},
+					Endpoint: previous,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-fn-revision-b"},
+				Spec: pkgv1.FunctionRevisionSpec{
+					PackageRevisionSpec: pkgv1.PackageRevisionSpec{
+						DesiredState: pkgv1.PackageRevisionActive,
+						Revision:     2,
+					},
+				},
+				Status: pkgv1.FunctionRevisionStatus{
+					PackageRevisionStatus: pkgv1.PackageRevisionStatus{
+						ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{pkgv1.RuntimeUnhealthy()}},
+					},
+					Endpoint: upgraded,
+				},
+			},
+		}
+		return nil
+	})
+
+	t.Run("RouteToPreviousRevisionUntilActiveIsReady", func(t *testing.T) {
+		conn, err := r.getClientConn(context.Background(), "cool-fn")
+
+		if diff := cmp.Diff(previous, conn.Target()); diff != "" {
+			t.Errorf("\nr.getClientConn(...): -want, +got:\n%s", diff)
+		}
+		if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+			t.Errorf("\nr.getClientConn(...): -want error, +got error:\n%s", diff)
+		}
+	})
+
+	// A Composition pins the Function to its inactive revision, which is
+	// served by its own endpoint. The active revision is ready.
+	pinned := previous
+	revs := func(pinnedEndpoint string) func(obj client.ObjectList) error {
+		return func(obj client.ObjectList) error {
+			l, ok := obj.(*pkgv1.FunctionRevisionList)
+			if !ok {
+				return nil
+			}
+			l.Items = []pkgv1.FunctionRevision{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-fn-revision-a"},
+					Spec: pkgv1.FunctionRevisionSpec{
+						PackageRevisionSpec: pkgv1.PackageRevisionSpec{
+							DesiredState: pkgv1.PackageRevisionInactive,
+							Revision:     1,
+						},
+					},
+					Status: pkgv1.FunctionRevisionStatus{
+						PackageRevisionStatus: pkgv1.PackageRevisionStatus{
+							ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{pkgv1.RuntimeHealthy()}},
+						},
+						Endpoint: pinnedEndpoint,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-fn-revision-b"},
+					Spec: pkgv1.FunctionRevisionSpec{
+						PackageRevisionSpec: pkgv1.PackageRevisionSpec{
+							DesiredState: pkgv1.PackageRevisionActive,
+							Revision:     2,
+						},
+					},
+					Status: pkgv1.FunctionRevisionStatus{
+						PackageRevisionStatus: pkgv1.PackageRevisionStatus{
+							ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{pkgv1.RuntimeHealthy()}},
+						},
+						Endpoint: upgraded,
+					},
+				},
+			}
+			return nil
+		}
+	}
+	ctx := WithRevisionSelector(context.Background(), RevisionSelector{Revision: "cool-fn-revision-a"})
+
+	c.MockList = test.NewMockListFn(nil, revs(pinned))
+	t.Run("RouteToPinnedRevision", func(t *testing.T) {
+		conn, err := r.getClientConn(ctx, "cool-fn")
+
+		if diff := cmp.Diff(pinned, conn.Target()); diff != "" {
+			t.Errorf("\nr.getClientConn(...): -want, +got:\n%s", diff)
+		}
+		if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+			t.Errorf("\nr.getClientConn(...): -want error, +got error:\n%s", diff)
+		}
+	})
+
+	// The pinned revision is still served by the Function's endpoint, which
+	// now routes to the active revision.
+	c.MockList = test.NewMockListFn(nil, revs(upgraded))
+	t.Run("PinnedRevisionNotReady", func(t *testing.T) {
+		_, err := r.getClientConn(ctx, "cool-fn")
+
+		if diff := cmp.Diff(errors.Errorf(errFmtRevisionNotReady, "cool-fn-revision-a"), err, test.EquateErrors()); diff != "" {
+			t.Errorf("\nr.getClientConn(...): -want error, +got error:\n%s", diff)
+		}
+	})
+
 	// Close any gRPC clients.
 	if _, err := r.GarbageCollectConnectionsNow(context.Background()); err != nil {
 		t.Logf("Error closing client connections: %s", err)
@@ -381,6 +506,61 @@ func TestGarbageCollectConnectionsNow(t *testing.T) {
 		}
 	})
 
+	pinned, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("gRPC dial failed: %s", err)
+	}
+
+	r.connsMx.Lock()
+	r.conns["cool-fn/cool-fn-revision-b"] = pinned
+	r.connsMx.Unlock()
+
+	listRevision := func(state pkgv1.PackageRevisionDesiredState) test.MockListFn {
+		return test.NewMockListFn(nil, func(obj client.ObjectList) error {
+			switch l := obj.(type) {
+			case *pkgv1.FunctionList:
+				l.Items = []pkgv1.Function{{ObjectMeta: metav1.ObjectMeta{Name: "cool-fn"}}}
+			case *pkgv1.FunctionRevisionList:
+				l.Items = []pkgv1.FunctionRevision{{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cool-fn-revision-b",
+						Labels: map[string]string{pkgv1.LabelParentPackage: "cool-fn"},
+					},
+					Spec: pkgv1.FunctionRevisionSpec{
+						PackageRevisionSpec: pkgv1.PackageRevisionSpec{DesiredState: state},
+					},
+				}}
+			}
+			return nil
+		})
+	}
+
+	t.Run("PinnedRevisionInactiveDoNotGarbageCollect", func(t *testing.T) {
+		c.MockList = listRevision(pkgv1.PackageRevisionInactive)
+
+		i, err := r.GarbageCollectConnectionsNow(ctx)
+
+		if diff := cmp.Diff(0, i); diff != "" {
+			t.Errorf("\nr.GarbageCollectConnectionsNow(...): -want, +got:\n%s", diff)
+		}
+		if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+			t.Errorf("\nr.GarbageCollectConnectionsNow(...): -want error, +got error:\n%s", diff)
+		}
+	})
+
+	t.Run("PinnedRevisionActivatedGarbageCollect", func(t *testing.T) {
+		c.MockList = listRevision(pkgv1.PackageRevisionActive)
+
+		i, err := r.GarbageCollectConnectionsNow(ctx)
+
+		if diff := cmp.Diff(1, i); diff != "" {
+			t.Errorf("\nr.GarbageCollectConnectionsNow(...): -want, +got:\n%s", diff)
+		}
+		if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+			t.Errorf("\nr.GarbageCollectConnectionsNow(...): -want error, +got error:\n%s", diff)
+		}
+	})
+
 	t.Run("FunctionDoesNotExistsGarbageCollect", func(t *testing.T) {
 		// No Functions exist
 		c.MockList = test.NewMockListFn(nil)
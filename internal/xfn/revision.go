@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+
+	"github.com/Masterminds/semver"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+// Error strings.
+const (
+	errFmtNoRevision             = "cannot find FunctionRevision %q"
+	errFmtParseVersionConstraint = "cannot parse version constraint %q"
+	errFmtNoRevisionForVersion   = "cannot find a FunctionRevision with a package version that satisfies %q"
+)
+
+// A RevisionSelector pins a Function to one of its FunctionRevisions, rather
+// than its active FunctionRevision.
+type RevisionSelector struct {
+	// Revision is the name of a FunctionRevision.
+	Revision string
+
+	// Version is a semantic version constraint. It selects the
+	// FunctionRevision with the highest package version that satisfies the
+	// constraint.
+	Version string
+}
+
+// RevisionSelectorFor returns the RevisionSelector for the supplied
+// FunctionReference. It returns false if the reference doesn't pin a specific
+// revision or version of the Function.
+func RevisionSelectorFor(ref apiextensionsv1.FunctionReference) (RevisionSelector, bool) {
+	if ref.Revision == nil && ref.Version == nil {
+		return RevisionSelector{}, false
+	}
+	return RevisionSelector{Revision: ptr.Deref(ref.Revision, ""), Version: ptr.Deref(ref.Version, "")}, true
+}
+
+type revisionSelectorKey struct{}
+
+// WithRevisionSelector returns a copy of the supplied context. Functions run
+// using the returned context are pinned to the FunctionRevision selected by the
+// supplied RevisionSelector.
+func WithRevisionSelector(ctx context.Context, s RevisionSelector) context.Context {
+	return context.WithValue(ctx, revisionSelectorKey{}, s)
+}
+
+// RevisionSelectorFrom returns the RevisionSelector of the supplied context, if
+// any.
+func RevisionSelectorFrom(ctx context.Context) (RevisionSelector, bool) {
+	s, ok := ctx.Value(revisionSelectorKey{}).(RevisionSelector)
+	return s, ok
+}
+
+// SelectRevision returns the FunctionRevision selected by the supplied
+// RevisionSelector. A selector that specifies a version selects the
+// FunctionRevision with the highest package version that satisfies it. If two
+// FunctionRevisions have the same package version the newest is selected.
+func SelectRevision(revs []pkgv1.FunctionRevision, s RevisionSelector) (*pkgv1.FunctionRevision, error) {
+	if s.Revision != "" {
+		for i := range revs {
+			if revs[i].GetName() == s.Revision {
+				return &revs[i], nil
+			}
+		}
+		return nil, errors.Errorf(errFmtNoRevision, s.Revision)
+	}
+
+	c, err := semver.NewConstraint(s.Version)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtParseVersionConstraint, s.Version)
+	}
+
+	var selected *pkgv1.FunctionRevision
+	var selectedVersion *semver.Version
+	for i := range revs {
+		v, ok := PackageVersion(&revs[i])
+		if !ok || !c.Check(v) {
+			continue
+		}
+		if selected == nil || v.GreaterThan(selectedVersion) || (v.Equal(selectedVersion) && revs[i].GetRevision() > selected.GetRevision()) {
+			selected, selectedVersion = &revs[i], v
+		}
+	}
+	if selected == nil {
+		return nil, errors.Errorf(errFmtNoRevisionForVersion, s.Version)
+	}
+	return selected, nil
+}
+
+// PackageVersion returns the semantic version of the supplied FunctionRevision's
+// package. It returns false if the package isn't referenced by a semantic
+// version tag.
+func PackageVersion(rev *pkgv1.FunctionRevision) (*semver.Version, bool) {
+	ref, err := name.ParseReference(rev.GetSource(), name.WithDefaultRegistry(""))
+	if err != nil {
+		return nil, false
+	}
+	tag, ok := ref.(name.Tag)
+	if !ok {
+		return nil, false
+	}
+	v, err := semver.NewVersion(tag.TagStr())
+	if err != nil {
+		return nil, false
+	}
+	return v, true
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfn
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestSelectRevision(t *testing.T) {
+	rev := func(name, pkg string, revision int64) pkgv1.FunctionRevision {
+		return pkgv1.FunctionRevision{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: pkgv1.FunctionRevisionSpec{
+				PackageRevisionSpec: pkgv1.PackageRevisionSpec{
+					Package:  pkg,
+					Revision: revision,
+				},
+			},
+		}
+	}
+
+	revs := []pkgv1.FunctionRevision{
+		rev("fn-a", "xpkg.crossplane.io/crossplane-contrib/function-foo:v1.0.0", 1),
+		rev("fn-b", "xpkg.crossplane.io/crossplane-contrib/function-foo:v1.2.0", 2),
+		rev("fn-c", "xpkg.crossplane.io/crossplane-contrib/function-foo@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d974f8ef5d4f6f2", 3),
+		rev("fn-d", "xpkg.crossplane.io/crossplane-contrib/function-foo:v2.0.0", 4),
+		rev("fn-e", "xpkg.crossplane.io/crossplane-contrib/function-foo:v1.2.0", 5),
+	}
+
+	type args struct {
+		revs []pkgv1.FunctionRevision
+		s    RevisionSelector
+	}
+	type want struct {
+		name string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ByRevision": {
+			reason: "We should select the named revision.",
+			args: args{
+				revs: revs,
+				s:    RevisionSelector{Revision: "fn-c"},
+			},
+			want: want{
+				name: "fn-c",
+			},
+		},
+		"RevisionNotFound": {
+			reason: "We should return an error if the named revision doesn't exist.",
+			args: args{
+				revs: revs,
+				s:    RevisionSelector{Revision: "fn-z"},
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoRevision, "fn-z"),
+			},
+		},
+		"ByVersion": {
+			reason: "We should select the newest revision with the highest version that satisfies the constraint.",
+			args: args{
+				revs: revs,
+				s:    RevisionSelector{Version: "^1.0.0"},
+			},
+			want: want{
+				name: "fn-e",
+			},
+		},
+		"NoVersionSatisfiesConstraint": {
+			reason: "We should return an error if no revision's version satisfies the constraint.",
+			args: args{
+				revs: revs,
+				s:    RevisionSelector{Version: ">=v3.0.0"},
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoRevisionForVersion, ">=v3.0.0"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := SelectRevision(tc.args.revs, tc.args.s)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSelectRevision(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			name := ""
+			if got != nil {
+				name = got.GetName()
+			}
+			if diff := cmp.Diff(tc.want.name, name); diff != "" {
+				t.Errorf("\n%s\nSelectRevision(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
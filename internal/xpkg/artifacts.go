@@ -0,0 +1,254 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Artifact types of the OCI artifacts that describe a package. Each artifact
+// refers to the package it describes using the OCI referrers API, so consumers
+// can fetch the metadata they need without pulling the package image.
+//
+// Artifacts aren't covered by the package's digest or signature. Anyone who can
+// push to the package's repository can add one, so the package manager never
+// reads them.
+const (
+	// ArtifactTypePackage is the type of an artifact containing the package's
+	// package.yaml stream.
+	ArtifactTypePackage = "application/vnd.crossplane.xpkg.package.v1+yaml"
+
+	// ArtifactTypeSchemas is the type of an artifact containing a YAML stream
+	// of the package's CustomResourceDefinitions and
+	// CompositeResourceDefinitions.
+	ArtifactTypeSchemas = "application/vnd.crossplane.xpkg.schemas.v1+yaml"
+
+	// ArtifactTypeExamples is the type of an artifact containing a YAML
+	// stream of the package's examples.
+	ArtifactTypeExamples = "application/vnd.crossplane.xpkg.examples.v1+yaml"
+
+	// ArtifactTypeDocs is the type of an artifact containing the package's
+	// Markdown documentation.
+	ArtifactTypeDocs = "application/vnd.crossplane.xpkg.docs.v1+markdown"
+
+	// ArtifactTypeSBOM is the type of an artifact containing an SPDX JSON
+	// software bill of materials for the package.
+	ArtifactTypeSBOM = "application/spdx+json"
+)
+
+// MaxArtifactSize is the maximum size in bytes of the content of an artifact
+// that we read.
+const MaxArtifactSize = 100 << 20 // 100 MB
+
+const (
+	errFmtFindLayer      = "cannot find layer annotated %q"
+	errFmtReadLayer      = "cannot read layer annotated %q"
+	errFmtFindLayerFile  = "cannot find %s in layer annotated %q"
+	errReadArtifact      = "cannot read artifact content"
+	errArtifactNoContent = "artifact has no content layer"
+	errFmtArtifactSize   = "artifact content exceeds the maximum size of %d bytes"
+	errParseStream       = "cannot parse YAML stream"
+)
+
+// An ArtifactNotFoundError indicates that a package has no artifact of a
+// particular type.
+type ArtifactNotFoundError struct {
+	artifactType string
+}
+
+// NewArtifactNotFoundError returns an error indicating that a package has no
+// artifact of the supplied type.
+func NewArtifactNotFoundError(artifactType string) error {
+	return &ArtifactNotFoundError{artifactType: artifactType}
+}
+
+// Error implements the error interface.
+func (e *ArtifactNotFoundError) Error() string {
+	return fmt.Sprintf("package has no %s artifact", e.artifactType)
+}
+
+// IsArtifactNotFound returns true if the supplied error indicates that a
+// package has no artifact of a particular type.
+func IsArtifactNotFound(err error) bool {
+	var e *ArtifactNotFoundError
+	return errors.As(err, &e)
+}
+
+// NewArtifact returns an OCI artifact of the supplied type. The artifact
+// contains the supplied content, refers to the supplied subject, and is
+// annotated with the supplied creation time.
+func NewArtifact(artifactType string, content []byte, subject v1.Descriptor, created time.Time) (v1.Image, error) {
+	img, err := mutate.AppendLayers(empty.Image, static.NewLayer(content, types.MediaType(artifactType)))
+	if err != nil {
+		return nil, errors.Wrap(err, errBuildImage)
+	}
+	img = mutate.MediaType(img, types.OCIManifestSchema1)
+	img = mutate.ConfigMediaType(img, types.MediaType(artifactType))
+	a := map[string]string{ocispec.AnnotationCreated: created.UTC().Format(time.RFC3339)}
+	img = mutate.Annotations(img, a).(v1.Image)         //nolint:forcetypeassert // Annotations always returns an image when passed one.
+	return mutate.Subject(img, subject).(v1.Image), nil //nolint:forcetypeassert // Subject always returns an image when passed one.
+}
+
+// ArtifactContent returns the content of the supplied artifact. It returns an
+// error if the content is larger than MaxArtifactSize.
+func ArtifactContent(img v1.Image) ([]byte, error) {
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, errors.Wrap(err, errReadArtifact)
+	}
+	if len(layers) == 0 {
+		return nil, errors.New(errArtifactNoContent)
+	}
+	// Artifact content isn't a tarball, so we read the blob as is.
+	rc, err := layers[0].Compressed()
+	if err != nil {
+		return nil, errors.Wrap(err, errReadArtifact)
+	}
+	defer rc.Close() //nolint:errcheck // Only reading.
+	b, err := io.ReadAll(io.LimitReader(rc, MaxArtifactSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, errReadArtifact)
+	}
+	if len(b) > MaxArtifactSize {
+		return nil, errors.Errorf(errFmtArtifactSize, MaxArtifactSize)
+	}
+	return b, nil
+}
+
+// FindArtifact returns the descriptor of the newest artifact of the supplied
+// type in the supplied referrers index. It returns an ArtifactNotFoundError
+// if the index contains no artifact of the supplied type.
+func FindArtifact(idx *v1.IndexManifest, artifactType string) (v1.Descriptor, error) {
+	// The OCI distribution spec doesn't define the order of referrers, so we
+	// find the newest artifact using its creation annotation. We break ties,
+	// including between artifacts without the annotation, using the digest so
+	// that we always return the same artifact for the same index.
+	var found *v1.Descriptor
+	for i := range idx.Manifests {
+		d := &idx.Manifests[i]
+		if d.ArtifactType != artifactType {
+			continue
+		}
+		if found == nil || newer(d, found) {
+			found = d
+		}
+	}
+	if found == nil {
+		return v1.Descriptor{}, NewArtifactNotFoundError(artifactType)
+	}
+	return *found, nil
+}
+
+// newer returns true if artifact a was created after artifact b.
+func newer(a, b *v1.Descriptor) bool {
+	ca, cb := created(a), created(b)
+	if !ca.Equal(cb) {
+		return ca.After(cb)
+	}
+	return a.Digest.String() > b.Digest.String()
+}
+
+// created returns the creation time of the supplied artifact, or the zero
+// time if it isn't annotated with a valid creation time.
+func created(d *v1.Descriptor) time.Time {
+	t, _ := time.Parse(time.RFC3339, d.Annotations[ocispec.AnnotationCreated])
+	return t
+}
+
+// AnnotatedLayerFile returns the content of the named file in the layer of the
+// supplied package image with the supplied xpkg annotation. It returns an error
+// if no layer has the annotation.
+func AnnotatedLayerFile(img v1.Image, annotation, file string) ([]byte, error) {
+	m, err := img.Manifest()
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtReadLayer, annotation)
+	}
+	for _, l := range m.Layers {
+		if l.Annotations[AnnotationKey] != annotation {
+			continue
+		}
+		layer, err := img.LayerByDigest(l.Digest)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtReadLayer, annotation)
+		}
+		rc, err := layer.Uncompressed()
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtReadLayer, annotation)
+		}
+		defer rc.Close() //nolint:errcheck // Only reading.
+		t := tar.NewReader(rc)
+		for {
+			h, err := t.Next()
+			if errors.Is(err, io.EOF) {
+				return nil, errors.Errorf(errFmtFindLayerFile, file, annotation)
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtReadLayer, annotation)
+			}
+			if filepath.Base(h.Name) == file {
+				b, err := io.ReadAll(t)
+				return b, errors.Wrapf(err, errFmtReadLayer, annotation)
+			}
+		}
+	}
+	return nil, errors.Errorf(errFmtFindLayer, annotation)
+}
+
+// Schemas returns a YAML stream of the CustomResourceDefinitions and
+// CompositeResourceDefinitions in the supplied package YAML stream.
+func Schemas(stream []byte) ([]byte, error) {
+	out := &bytes.Buffer{}
+	yr := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(stream)))
+	for {
+		doc, err := yr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errParseStream)
+		}
+		tm := struct {
+			Kind string `json:"kind"`
+		}{}
+		if err := k8syaml.Unmarshal(doc, &tm); err != nil {
+			return nil, errors.Wrap(err, errParseStream)
+		}
+		if tm.Kind != "CustomResourceDefinition" && tm.Kind != "CompositeResourceDefinition" {
+			continue
+		}
+		out.WriteString("---\n")
+		out.Write(bytes.TrimPrefix(doc, []byte("---\n")))
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestSchemas(t *testing.T) {
+	type want struct {
+		schemas string
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		stream string
+		want   want
+	}{
+		"FiltersSchemas": {
+			reason: "We should return only the CustomResourceDefinitions and CompositeResourceDefinitions in the stream.",
+			stream: `apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: cool
+---
+apiVersion: apiextensions.crossplane.io/v1
+kind: CompositeResourceDefinition
+metadata:
+  name: xcools.example.org
+---
+apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: cool
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: cools.example.org
+`,
+			want: want{
+				schemas: `---
+apiVersion: apiextensions.crossplane.io/v1
+kind: CompositeResourceDefinition
+metadata:
+  name: xcools.example.org
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: cools.example.org
+`,
+			},
+		},
+		"NoSchemas": {
+			reason: "We should return an empty stream if there are no schemas.",
+			stream: `apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: cool
+`,
+			want: want{
+				schemas: "",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Schemas([]byte(tc.stream))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSchemas(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.schemas, string(got)); diff != "" {
+				t.Errorf("\n%s\nSchemas(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFindArtifact(t *testing.T) {
+	older := v1.Descriptor{
+		ArtifactType: ArtifactTypeSchemas,
+		Digest:       v1.Hash{Algorithm: "sha256", Hex: "b"},
+		Annotations:  map[string]string{ocispec.AnnotationCreated: "2025-01-01T00:00:00Z"},
+	}
+	newer := v1.Descriptor{
+		ArtifactType: ArtifactTypeSchemas,
+		Digest:       v1.Hash{Algorithm: "sha256", Hex: "a"},
+		Annotations:  map[string]string{ocispec.AnnotationCreated: "2025-02-01T00:00:00Z"},
+	}
+	unannotatedA := v1.Descriptor{ArtifactType: ArtifactTypeSchemas, Digest: v1.Hash{Algorithm: "sha256", Hex: "a"}}
+	unannotatedB := v1.Descriptor{ArtifactType: ArtifactTypeSchemas, Digest: v1.Hash{Algorithm: "sha256", Hex: "b"}}
+	sbom := v1.Descriptor{ArtifactType: ArtifactTypeSBOM, Digest: v1.Hash{Algorithm: "sha256", Hex: "c"}}
+
+	type want struct {
+		desc v1.Descriptor
+		err  error
+	}
+	cases := map[string]struct {
+		reason       string
+		idx          *v1.IndexManifest
+		artifactType string
+		want         want
+	}{
+		"Found": {
+			reason:       "We should return the newest artifact of the requested type, regardless of the order of the index.",
+			idx:          &v1.IndexManifest{Manifests: []v1.Descriptor{newer, sbom, older}},
+			artifactType: ArtifactTypeSchemas,
+			want: want{
+				desc: newer,
+			},
+		},
+		"Unannotated": {
+			reason:       "We should break ties between artifacts without a creation time using their digest.",
+			idx:          &v1.IndexManifest{Manifests: []v1.Descriptor{unannotatedB, unannotatedA}},
+			artifactType: ArtifactTypeSchemas,
+			want: want{
+				desc: unannotatedB,
+			},
+		},
+		"NotFound": {
+			reason:       "We should return an ArtifactNotFoundError if there's no artifact of the requested type.",
+			idx:          &v1.IndexManifest{Manifests: []v1.Descriptor{sbom}},
+			artifactType: ArtifactTypeSchemas,
+			want: want{
+				err: NewArtifactNotFoundError(ArtifactTypeSchemas),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := FindArtifact(tc.idx, tc.artifactType)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFindArtifact(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.desc, got); diff != "" {
+				t.Errorf("\n%s\nFindArtifact(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewArtifact(t *testing.T) {
+	subject := v1.Descriptor{
+		MediaType: types.OCIImageIndex,
+		Digest:    v1.Hash{Algorithm: "sha256", Hex: "0123"},
+		Size:      42,
+	}
+	content := []byte("# Cool package\n")
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	img, err := NewArtifact(ArtifactTypeDocs, content, subject, created)
+	if err != nil {
+		t.Fatalf("NewArtifact(...): %v", err)
+	}
+
+	m, err := img.Manifest()
+	if err != nil {
+		t.Fatalf("Manifest(): %v", err)
+	}
+	if diff := cmp.Diff(types.MediaType(ArtifactTypeDocs), m.Config.MediaType); diff != "" {
+		t.Errorf("NewArtifact(...): -want config media type, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(&subject, m.Subject); diff != "" {
+		t.Errorf("NewArtifact(...): -want subject, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("2025-01-01T00:00:00Z", m.Annotations[ocispec.AnnotationCreated]); diff != "" {
+		t.Errorf("NewArtifact(...): -want created annotation, +got:\n%s", diff)
+	}
+
+	got, err := ArtifactContent(img)
+	if err != nil {
+		t.Fatalf("ArtifactContent(...): %v", err)
+	}
+	if diff := cmp.Diff(content, got); diff != "" {
+		t.Errorf("ArtifactContent(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestArtifactContentTooLarge(t *testing.T) {
+	img, err := NewArtifact(ArtifactTypeDocs, bytes.Repeat([]byte("a"), MaxArtifactSize+1), v1.Descriptor{}, time.Now())
+	if err != nil {
+		t.Fatalf("NewArtifact(...): %v", err)
+	}
+	_, err = ArtifactContent(img)
+	if diff := cmp.Diff(errors.Errorf(errFmtArtifactSize, MaxArtifactSize), err, test.EquateErrors()); diff != "" {
+		t.Errorf("ArtifactContent(...): -want error, +got error:\n%s", diff)
+	}
+}
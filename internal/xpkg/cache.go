@@ -18,9 +18,16 @@ package xpkg
 
 import (
 	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/spf13/afero"
 
@@ -28,64 +35,192 @@ import (
 )
 
 const (
-	errGetNopCache = "cannot get content from a NopCache"
+	errGetNopCache              = "cannot get content from a NopCache"
+	errFmtNoCacheRecord         = "cached package %s has no record of the image it was extracted from"
+	errFmtImageDigestMismatch   = "cached package %s was extracted from image %s, not %s"
+	errFmtContentDigestMismatch = "cached package %s has digest %s, but %s was recorded when it was cached"
 )
 
-const cacheContentExt = ".gz"
+const (
+	cacheContentExt = ".gz"
+	cacheRecordExt  = ".digest"
+	cacheTmpPrefix  = ".tmp-"
+)
 
 // A PackageCache caches package content.
+//
+// Content is cached along with the digest of the image it was extracted from.
+// Has and Get only return content extracted from the supplied image digest.
+// An empty digest matches any content, including content that wasn't stored
+// by the cache, for example content a user pre-loaded for a package with
+// packagePullPolicy Never.
 type PackageCache interface {
-	Has(id string) bool
-	Get(id string) (io.ReadCloser, error)
-	Store(id string, content io.ReadCloser) error
+	Has(id, digest string) bool
+	Get(id, digest string) (io.ReadCloser, error)
+	Store(id, digest string, content io.ReadCloser) error
 	Delete(id string) error
 }
 
+// A cacheRecord records what content the cache stored.
+type cacheRecord struct {
+	// Image is the digest of the image the content was extracted from.
+	Image string `json:"image"`
+
+	// Content is the digest of the cached (compressed) content.
+	Content string `json:"content"`
+}
+
 // FsPackageCache stores and retrieves package content in a filesystem-backed
 // cache in a thread-safe manner.
+//
+// The cache writes content atomically, records the digest of the image it was
+// extracted from, and uses the modification time of cached content to track
+// when it was last used. Several Crossplane replicas may safely mount the same
+// cache directory, for example from a ReadWriteMany PersistentVolume. There's
+// no other shared cache backend.
 type FsPackageCache struct {
-	dir string
-	fs  afero.Fs
-	mu  sync.RWMutex
+	dir      string
+	fs       afero.Fs
+	mu       sync.RWMutex
+	maxBytes int64
+	metrics  CacheMetrics
+	now      func() time.Time
+}
+
+// A FsPackageCacheOption configures a FsPackageCache.
+type FsPackageCacheOption func(c *FsPackageCache)
+
+// WithMaxBytes configures the cache to evict the least recently used package
+// content when it exceeds the supplied number of bytes. The cache is unbounded
+// if max is not positive. Only content the cache stored counts toward the
+// budget. Pre-loaded content is never evicted.
+func WithMaxBytes(maxBytes int64) FsPackageCacheOption {
+	return func(c *FsPackageCache) {
+		c.maxBytes = maxBytes
+	}
+}
+
+// WithCacheMetrics configures the metrics the cache records.
+func WithCacheMetrics(m CacheMetrics) FsPackageCacheOption {
+	return func(c *FsPackageCache) {
+		c.metrics = m
+	}
 }
 
 // NewFsPackageCache creates a new FsPackageCache.
-func NewFsPackageCache(dir string, fs afero.Fs) *FsPackageCache {
-	return &FsPackageCache{
-		dir: dir,
-		fs:  fs,
+func NewFsPackageCache(dir string, fs afero.Fs, o ...FsPackageCacheOption) *FsPackageCache {
+	c := &FsPackageCache{
+		dir:     dir,
+		fs:      fs,
+		metrics: &NopCacheMetrics{},
+		now:     time.Now,
 	}
+	for _, fn := range o {
+		fn(c)
+	}
+	return c
 }
 
-// Has indicates whether an item with the given id is in the cache.
-func (c *FsPackageCache) Has(id string) bool {
-	if fi, err := c.fs.Stat(BuildPath(c.dir, id, cacheContentExt)); err == nil && !fi.IsDir() {
-		return true
+// Has indicates whether content extracted from an image with the given digest
+// is cached with the given id. Content that has no record of the image it was
+// extracted from is only considered cached if the digest is empty.
+func (c *FsPackageCache) Has(id, digest string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if fi, err := c.fs.Stat(BuildPath(c.dir, id, cacheContentExt)); err != nil || fi.IsDir() {
+		c.metrics.Miss()
+		return false
 	}
-	return false
+	if digest != "" {
+		if r, err := c.record(id); err != nil || r.Image != digest {
+			c.metrics.Miss()
+			return false
+		}
+	}
+	c.metrics.Hit()
+	return true
 }
 
-// Get retrieves package contents from the cache.
-func (c *FsPackageCache) Get(id string) (io.ReadCloser, error) {
+// Get retrieves package contents from the cache. If the digest isn't empty
+// Get returns an error unless the contents were extracted from an image with
+// the given digest. Get also verifies the contents weren't corrupted since
+// they were stored.
+func (c *FsPackageCache) Get(id, digest string) (io.ReadCloser, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	f, err := c.fs.Open(BuildPath(c.dir, id, cacheContentExt))
+	path := BuildPath(c.dir, id, cacheContentExt)
+	f, err := c.fs.Open(path)
 	if err != nil {
 		return nil, err
 	}
+	if err := c.verify(id, digest, f); err != nil {
+		_ = f.Close()
+		c.metrics.IntegrityError()
+		return nil, err
+	}
+
+	// Record that the content was used, for least recently used eviction.
+	// This is best effort; the worst case is that we evict content sooner
+	// than we otherwise would.
+	now := c.now()
+	_ = c.fs.Chtimes(path, now, now)
+
 	return GzipReadCloser(f)
 }
 
-// Store saves the package contents to the cache.
-func (c *FsPackageCache) Store(id string, content io.ReadCloser) error {
+// verify that the supplied content was extracted from an image with the
+// supplied digest, and that it's the content that was stored. Content without
+// a record (i.e. pre-loaded content) can only be read with an empty digest,
+// and isn't verified.
+func (c *FsPackageCache) verify(id, digest string, f afero.File) error {
+	r, err := c.record(id)
+	if err != nil {
+		if digest != "" {
+			return errors.Errorf(errFmtNoCacheRecord, id)
+		}
+		return nil
+	}
+	if digest != "" && r.Image != digest {
+		return errors.Errorf(errFmtImageDigestMismatch, id, r.Image, digest)
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if got := "sha256:" + hex.EncodeToString(h.Sum(nil)); got != r.Content {
+		return errors.Errorf(errFmtContentDigestMismatch, id, got, r.Content)
+	}
+	return nil
+}
+
+// record returns the record of the content cached with the supplied id.
+func (c *FsPackageCache) record(id string) (*cacheRecord, error) {
+	b, err := afero.ReadFile(c.fs, BuildPath(c.dir, id, cacheRecordExt))
+	if err != nil {
+		return nil, err
+	}
+	r := &cacheRecord{}
+	return r, json.Unmarshal(b, r)
+}
+
+// Store saves the package contents extracted from the image with the given
+// digest to the cache. Store writes the contents to a temporary file, then
+// renames it, so that concurrent readers never see partially written
+// contents.
+func (c *FsPackageCache) Store(id, digest string, content io.ReadCloser) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	cf, err := c.fs.Create(BuildPath(c.dir, id, cacheContentExt))
+	cf, err := afero.TempFile(c.fs, c.dir, cacheTmpPrefix+"*")
 	if err != nil {
 		return err
 	}
-	defer cf.Close() //nolint:errcheck // Error is checked in the happy path.
-	w, err := gzip.NewWriterLevel(cf, gzip.BestSpeed)
+	defer c.fs.Remove(cf.Name()) //nolint:errcheck // Only fails if the file was already renamed.
+	defer cf.Close()             //nolint:errcheck // Error is checked in the happy path.
+	h := sha256.New()
+	w, err := gzip.NewWriterLevel(io.MultiWriter(cf, h), gzip.BestSpeed)
 	if err != nil {
 		return err
 	}
@@ -98,18 +233,98 @@ func (c *FsPackageCache) Store(id string, content io.ReadCloser) error {
 	if err := w.Close(); err != nil {
 		return err
 	}
-	return cf.Close()
+	if err := cf.Close(); err != nil {
+		return err
+	}
+
+	// Remove any stale record before we replace the content, so a concurrent
+	// reader never verifies new content against an old record.
+	if err := c.fs.Remove(BuildPath(c.dir, id, cacheRecordExt)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	path := BuildPath(c.dir, id, cacheContentExt)
+	if err := c.fs.Rename(cf.Name(), path); err != nil {
+		return err
+	}
+	b, err := json.Marshal(&cacheRecord{Image: digest, Content: "sha256:" + hex.EncodeToString(h.Sum(nil))})
+	if err != nil {
+		return err
+	}
+	if err := afero.WriteFile(c.fs, BuildPath(c.dir, id, cacheRecordExt), b, 0o644); err != nil {
+		return err
+	}
+
+	return c.evict(path)
+}
+
+// evict the least recently used content until the cache fits within its
+// budget. It never evicts the supplied path, which was just stored.
+//
+// Only content with a record, i.e. content the cache stored, is considered.
+// Content without a record was pre-loaded, for example for a package with
+// packagePullPolicy Never, which uses its source as its cache id. The cache
+// can't fetch such content again, so it never evicts it.
+func (c *FsPackageCache) evict(keep string) error {
+	if c.maxBytes <= 0 {
+		return nil
+	}
+
+	// We list the cache directory rather than tracking its contents in
+	// memory, so that we account for content stored by other replicas.
+	fis, err := afero.ReadDir(c.fs, c.dir)
+	if err != nil {
+		return err
+	}
+
+	total := int64(0)
+	entries := make([]os.FileInfo, 0, len(fis))
+	for _, fi := range fis {
+		if fi.IsDir() || filepath.Ext(fi.Name()) != cacheContentExt || strings.HasPrefix(fi.Name(), cacheTmpPrefix) {
+			continue
+		}
+		if _, err := c.fs.Stat(BuildPath(c.dir, fi.Name(), cacheRecordExt)); err != nil {
+			continue
+		}
+		total += fi.Size()
+		entries = append(entries, fi)
+	}
+	c.metrics.Size(total)
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ModTime().Before(entries[j].ModTime()) })
+	for _, fi := range entries {
+		if total <= c.maxBytes {
+			break
+		}
+		if filepath.Join(c.dir, fi.Name()) == keep {
+			continue
+		}
+		if err := c.remove(strings.TrimSuffix(fi.Name(), cacheContentExt)); err != nil {
+			return err
+		}
+		total -= fi.Size()
+		c.metrics.Evict(fi.Size())
+	}
+	c.metrics.Size(total)
+
+	return nil
 }
 
 // Delete removes package contents from the cache.
 func (c *FsPackageCache) Delete(id string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	err := c.fs.Remove(BuildPath(c.dir, id, cacheContentExt))
-	if os.IsNotExist(err) {
-		return nil
+	return c.remove(id)
+}
+
+// remove package contents and their record from the cache. The caller must
+// hold the lock.
+func (c *FsPackageCache) remove(id string) error {
+	for _, ext := range []string{cacheContentExt, cacheRecordExt} {
+		if err := c.fs.Remove(BuildPath(c.dir, id, ext)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
 	}
-	return err
+	return nil
 }
 
 // NopCache is a cache implementation that does not store anything and always
@@ -122,17 +337,17 @@ func NewNopCache() *NopCache {
 }
 
 // Has indicates whether content is in the NopCache.
-func (c *NopCache) Has(string) bool {
+func (c *NopCache) Has(string, string) bool {
 	return false
 }
 
 // Get retrieves content from the NopCache.
-func (c *NopCache) Get(string) (io.ReadCloser, error) {
+func (c *NopCache) Get(string, string) (io.ReadCloser, error) {
 	return nil, errors.New(errGetNopCache)
 }
 
 // Store saves content to the NopCache.
-func (c *NopCache) Store(string, io.ReadCloser) error {
+func (c *NopCache) Store(string, string, io.ReadCloser) error {
 	return nil
 }
 
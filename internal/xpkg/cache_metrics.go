@@ -0,0 +1,163 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	_ CacheMetrics = &NopCacheMetrics{}
+	_ CacheMetrics = &PrometheusCacheMetrics{}
+)
+
+// CacheMetrics records package cache metrics.
+type CacheMetrics interface {
+	// Hit records a cache hit.
+	Hit()
+
+	// Miss records a cache miss.
+	Miss()
+
+	// Evict records the eviction of the supplied number of bytes.
+	Evict(bytes int64)
+
+	// IntegrityError records cached content that didn't match its digest.
+	IntegrityError()
+
+	// Size records the total size of the cache in bytes.
+	Size(bytes int64)
+}
+
+// NopCacheMetrics does nothing.
+type NopCacheMetrics struct{}
+
+// Hit does nothing.
+func (m *NopCacheMetrics) Hit() {}
+
+// Miss does nothing.
+func (m *NopCacheMetrics) Miss() {}
+
+// Evict does nothing.
+func (m *NopCacheMetrics) Evict(_ int64) {}
+
+// IntegrityError does nothing.
+func (m *NopCacheMetrics) IntegrityError() {}
+
+// Size does nothing.
+func (m *NopCacheMetrics) Size(_ int64) {}
+
+// PrometheusCacheMetrics for the package cache.
+type PrometheusCacheMetrics struct {
+	hits            prometheus.Counter
+	misses          prometheus.Counter
+	evictions       prometheus.Counter
+	bytesEvicted    prometheus.Counter
+	integrityErrors prometheus.Counter
+	bytes           prometheus.Gauge
+}
+
+// NewPrometheusCacheMetrics exposes package cache metrics via Prometheus.
+func NewPrometheusCacheMetrics() *PrometheusCacheMetrics {
+	return &PrometheusCacheMetrics{
+		hits: prometheus.NewCounter(prometheus.CounterOpts{
+			Subsystem: "package",
+			Name:      "cache_hits_total",
+			Help:      "Total number of package cache hits.",
+		}),
+
+		misses: prometheus.NewCounter(prometheus.CounterOpts{
+			Subsystem: "package",
+			Name:      "cache_misses_total",
+			Help:      "Total number of package cache misses.",
+		}),
+
+		evictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Subsystem: "package",
+			Name:      "cache_evictions_total",
+			Help:      "Total number of packages evicted from the package cache.",
+		}),
+
+		bytesEvicted: prometheus.NewCounter(prometheus.CounterOpts{
+			Subsystem: "package",
+			Name:      "cache_bytes_evicted_total",
+			Help:      "Total number of bytes evicted from the package cache.",
+		}),
+
+		integrityErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Subsystem: "package",
+			Name:      "cache_integrity_errors_total",
+			Help:      "Total number of cached packages that didn't match their recorded digest.",
+		}),
+
+		bytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Subsystem: "package",
+			Name:      "cache_bytes",
+			Help:      "Size of the package cache in bytes, as of the last time a package was stored.",
+		}),
+	}
+}
+
+// Hit records a cache hit.
+func (m *PrometheusCacheMetrics) Hit() {
+	m.hits.Inc()
+}
+
+// Miss records a cache miss.
+func (m *PrometheusCacheMetrics) Miss() {
+	m.misses.Inc()
+}
+
+// Evict records the eviction of the supplied number of bytes.
+func (m *PrometheusCacheMetrics) Evict(bytes int64) {
+	m.evictions.Inc()
+	m.bytesEvicted.Add(float64(bytes))
+}
+
+// IntegrityError records cached content that didn't match its digest.
+func (m *PrometheusCacheMetrics) IntegrityError() {
+	m.integrityErrors.Inc()
+}
+
+// Size records the total size of the cache in bytes.
+func (m *PrometheusCacheMetrics) Size(bytes int64) {
+	m.bytes.Set(float64(bytes))
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector to the provided channel and returns once
+// the last descriptor has been sent.
+func (m *PrometheusCacheMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.hits.Describe(ch)
+	m.misses.Describe(ch)
+	m.evictions.Describe(ch)
+	m.bytesEvicted.Describe(ch)
+	m.integrityErrors.Describe(ch)
+	m.bytes.Describe(ch)
+}
+
+// Collect is called by the Prometheus registry when collecting
+// metrics. The implementation sends each collected metric via the
+// provided channel and returns once the last metric has been sent.
+func (m *PrometheusCacheMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.hits.Collect(ch)
+	m.misses.Collect(ch)
+	m.evictions.Collect(ch)
+	m.bytesEvicted.Collect(ch)
+	m.integrityErrors.Collect(ch)
+	m.bytes.Collect(ch)
+}
@@ -19,14 +19,18 @@ package xpkg
 import (
 	"bytes"
 	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"os"
 	"syscall"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/afero"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 )
 
@@ -37,10 +41,12 @@ func TestHas(t *testing.T) {
 	cf, _ := fs.Create("/cache/exists.gz")
 	_ = fs.Mkdir("/cache/some-dir.gz", os.ModeDir)
 	defer cf.Close()
+	_ = NewFsPackageCache("/cache", fs).Store("recorded", "sha256:image", io.NopCloser(new(bytes.Buffer)))
 
 	type args struct {
-		cache PackageCache
-		id    string
+		cache  PackageCache
+		id     string
+		digest string
 	}
 	cases := map[string]struct {
 		reason string
@@ -63,6 +69,33 @@ func TestHas(t *testing.T) {
 			},
 			want: false,
 		},
+		"NoRecord": {
+			reason: "Should return false if the package has no record of the image it was extracted from.",
+			args: args{
+				cache:  NewFsPackageCache("/cache", fs),
+				id:     "exists",
+				digest: "sha256:image",
+			},
+			want: false,
+		},
+		"ImageDigestMatches": {
+			reason: "Should return true if the package was extracted from an image with the supplied digest.",
+			args: args{
+				cache:  NewFsPackageCache("/cache", fs),
+				id:     "recorded",
+				digest: "sha256:image",
+			},
+			want: true,
+		},
+		"ImageDigestDiffers": {
+			reason: "Should return false if the package was extracted from an image with a different digest.",
+			args: args{
+				cache:  NewFsPackageCache("/cache", fs),
+				id:     "recorded",
+				digest: "sha256:other",
+			},
+			want: false,
+		},
 		"ErrIsDir": {
 			reason: "Should return error if path is a directory.",
 			args: args{
@@ -75,7 +108,7 @@ func TestHas(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			h := tc.args.cache.Has(tc.args.id)
+			h := tc.args.cache.Has(tc.args.id, tc.args.digest)
 
 			if diff := cmp.Diff(tc.want, h); diff != "" {
 				t.Errorf("\n%s\nHas(...): -want, +got:\n%s", tc.reason, diff)
@@ -129,7 +162,7 @@ func TestGet(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			_, err := tc.args.cache.Get(tc.args.id)
+			_, err := tc.args.cache.Get(tc.args.id, "")
 
 			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nGet(...): -want err, +got err:\n%s", tc.reason, diff)
@@ -169,7 +202,7 @@ func TestStore(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			err := tc.args.cache.Store(tc.args.id, io.NopCloser(new(bytes.Buffer)))
+			err := tc.args.cache.Store(tc.args.id, "sha256:image", io.NopCloser(new(bytes.Buffer)))
 
 			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nStore(...): -want err, +got err:\n%s", tc.reason, diff)
@@ -178,6 +211,169 @@ func TestStore(t *testing.T) {
 	}
 }
 
+func TestGetVerifiesDigest(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	c := NewFsPackageCache("/cache", fs)
+	_ = c.Store("verified", "sha256:image", io.NopCloser(bytes.NewBufferString("some content")))
+	_ = c.Store("corrupted", "sha256:image", io.NopCloser(bytes.NewBufferString("some content")))
+	r, _ := c.record("corrupted")
+	corrupt := []byte("corrupted content")
+	_ = afero.WriteFile(fs, "/cache/corrupted.gz", corrupt, 0o644)
+	sum := sha256.Sum256(corrupt)
+
+	preloaded := &bytes.Buffer{}
+	w := gzip.NewWriter(preloaded)
+	_, _ = w.Write([]byte("preloaded content"))
+	_ = w.Close()
+	_ = afero.WriteFile(fs, "/cache/preloaded.gz", preloaded.Bytes(), 0o644)
+
+	type args struct {
+		id     string
+		digest string
+	}
+	type want struct {
+		content string
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Verified": {
+			reason: "Should return content that was extracted from the supplied image and matches its recorded digest.",
+			args: args{
+				id:     "verified",
+				digest: "sha256:image",
+			},
+			want: want{
+				content: "some content",
+			},
+		},
+		"ImageDigestMismatch": {
+			reason: "Should return an error if content was extracted from a different image.",
+			args: args{
+				id:     "verified",
+				digest: "sha256:other",
+			},
+			want: want{
+				err: errors.Errorf(errFmtImageDigestMismatch, "verified", "sha256:image", "sha256:other"),
+			},
+		},
+		"ContentDigestMismatch": {
+			reason: "Should return an error if content doesn't match its recorded digest.",
+			args: args{
+				id:     "corrupted",
+				digest: "sha256:image",
+			},
+			want: want{
+				err: errors.Errorf(errFmtContentDigestMismatch, "corrupted", "sha256:"+hex.EncodeToString(sum[:]), r.Content),
+			},
+		},
+		"NoRecord": {
+			reason: "Should return an error if content has no record of the image it was extracted from.",
+			args: args{
+				id:     "preloaded",
+				digest: "sha256:image",
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoCacheRecord, "preloaded"),
+			},
+		},
+		"Preloaded": {
+			reason: "Should return content without a record if no image digest is supplied.",
+			args: args{
+				id: "preloaded",
+			},
+			want: want{
+				content: "preloaded content",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rc, err := c.Get(tc.args.id, tc.args.digest)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			b, _ := io.ReadAll(rc)
+			if diff := cmp.Diff(tc.want.content, string(b)); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want content, +got content:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStoreEvicts(t *testing.T) {
+	now := time.Now()
+
+	type args struct {
+		maxBytes int64
+		id       string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"Unbounded": {
+			reason: "Should not evict anything if the cache is unbounded.",
+			args: args{
+				id: "new",
+			},
+			want: []string{"new", "newer", "old", "preloaded"},
+		},
+		"EvictLeastRecentlyUsed": {
+			reason: "Should evict the least recently used content until the cache fits its budget, but never pre-loaded content.",
+			args: args{
+				maxBytes: 60,
+				id:       "new",
+			},
+			want: []string{"new", "newer", "preloaded"},
+		},
+		"KeepStored": {
+			reason: "Should never evict the content that was just stored.",
+			args: args{
+				maxBytes: 1,
+				id:       "new",
+			},
+			want: []string{"new", "preloaded"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			for id, age := range map[string]time.Duration{"old": 2 * time.Hour, "newer": time.Hour, "preloaded": 3 * time.Hour} {
+				_ = afero.WriteFile(fs, "/cache/"+id+".gz", bytes.Repeat([]byte("a"), 30), 0o644)
+				_ = fs.Chtimes("/cache/"+id+".gz", now.Add(-age), now.Add(-age))
+				if id != "preloaded" {
+					_ = afero.WriteFile(fs, "/cache/"+id+".digest", []byte(`{"image":"sha256:image"}`), 0o644)
+				}
+			}
+
+			c := NewFsPackageCache("/cache", fs, WithMaxBytes(tc.args.maxBytes))
+			if err := c.Store(tc.args.id, "sha256:image", io.NopCloser(new(bytes.Buffer))); err != nil {
+				t.Fatalf("Store(...): %s", err)
+			}
+
+			var got []string
+			for _, id := range []string{"new", "newer", "old", "preloaded"} {
+				if c.Has(id, "") {
+					got = append(got, id)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nStore(...): -want cached, +got cached:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestDelete(t *testing.T) {
 	fs := afero.NewMemMapFs()
 	_, _ = fs.Create("/cache/exists.xpkg")
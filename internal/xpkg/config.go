@@ -14,6 +14,8 @@ import (
 const (
 	errListImageConfigs = "cannot list ImageConfigs"
 	errFindBestMatch    = "cannot find best matching ImageConfig"
+
+	errFmtVerificationConfigMissing = "%s verification config is missing"
 )
 
 // ConfigStore is a store for image configuration.
@@ -26,6 +28,12 @@ type ConfigStore interface {
 	// RewritePath returns the name of the selected image config and the
 	// rewritten path of the given image based on that config.
 	RewritePath(ctx context.Context, image string) (imageConfig, newPath string, err error)
+	// RegistryAuthenticationFor returns the name of the selected image config
+	// and the credential provider configuration for a given image.
+	RegistryAuthenticationFor(ctx context.Context, image string) (imageConfig string, auth *v1beta1.RegistryAuthentication, err error)
+	// MirrorsFor returns the name of the selected image config and the
+	// mirrors of the given image based on that config, in order.
+	MirrorsFor(ctx context.Context, image string) (imageConfig string, mirrors []Mirror, err error)
 }
 
 // isValidConfig is a function that determines if an ImageConfig is valid while
@@ -89,9 +97,12 @@ func (s *ImageConfigStore) ImageVerificationConfigFor(ctx context.Context, image
 		return "", nil, nil
 	}
 
-	if config.Spec.Verification.Cosign == nil {
-		// Only cosign verification is supported for now.
-		return config.Name, nil, errors.New("cosign verification config is missing")
+	// Only the config block of the selected provider is used, so it must be
+	// set.
+	v := config.Spec.Verification
+	if (v.Provider == v1beta1.ImageVerificationProviderCosign && v.Cosign == nil) ||
+		(v.Provider == v1beta1.ImageVerificationProviderNotation && v.Notation == nil) {
+		return config.Name, nil, errors.Errorf(errFmtVerificationConfigMissing, v.Provider)
 	}
 
 	return config.Name, config.Spec.Verification, nil
@@ -118,19 +129,49 @@ func (s *ImageConfigStore) RewritePath(ctx context.Context, image string) (image
 		return config.Name, "", errors.New("rewrite prefix is missing")
 	}
 
-	// Find the longest prefix match in the selected image config; this is what
-	// we'll replace.
-	matchPrefix := ""
-	for _, m := range config.Spec.MatchImages {
-		if !strings.HasPrefix(image, m.Prefix) {
-			continue
-		}
-		if len(m.Prefix) > len(matchPrefix) {
-			matchPrefix = m.Prefix
+	return config.Name, rewritePrefix + strings.TrimPrefix(image, longestMatch(config, image)), nil
+}
+
+// RegistryAuthenticationFor returns the name of the selected image config and
+// the credential provider configuration for a given image. Only ImageConfigs
+// that configure a credential provider, i.e. token exchange or a credential
+// helper, are considered.
+func (s *ImageConfigStore) RegistryAuthenticationFor(ctx context.Context, image string) (imageConfig string, auth *v1beta1.RegistryAuthentication, err error) {
+	config, err := s.bestMatch(ctx, image, func(c *v1beta1.ImageConfig) bool {
+		if c.Spec.Registry == nil || c.Spec.Registry.Authentication == nil {
+			return false
 		}
+		a := c.Spec.Registry.Authentication
+		return a.TokenExchange != nil || a.CredentialHelper != nil
+	})
+	if err != nil {
+		return "", nil, errors.Wrap(err, errFindBestMatch)
+	}
+
+	if config == nil {
+		// No ImageConfig with a credential provider found for this image, this
+		// is not an error.
+		return "", nil, nil
 	}
 
-	return config.Name, rewritePrefix + strings.TrimPrefix(image, matchPrefix), nil
+	return config.Name, config.Spec.Registry.Authentication, nil
+}
+
+// MirrorsFor returns the name of the selected image config and the mirrors of
+// the given image based on that config, in order.
+func (s *ImageConfigStore) MirrorsFor(ctx context.Context, image string) (imageConfig string, mirrors []Mirror, err error) {
+	config, err := s.bestMatch(ctx, image, hasMirrors)
+	if err != nil {
+		return "", nil, errors.Wrap(err, errFindBestMatch)
+	}
+
+	if config == nil {
+		// No ImageConfig with mirrors found for this image, this is not an
+		// error.
+		return "", nil, nil
+	}
+
+	return config.Name, mirrorsOf(config, image), nil
 }
 
 // bestMatch finds the best matching ImageConfig for an image based on the
@@ -142,10 +183,16 @@ func (s *ImageConfigStore) bestMatch(ctx context.Context, image string, valid is
 		return nil, errors.Wrap(err, errListImageConfigs)
 	}
 
+	return bestMatchIn(l.Items, image, valid), nil
+}
+
+// bestMatchIn finds the best matching ImageConfig for an image among the
+// supplied ImageConfigs based on the longest prefix match.
+func bestMatchIn(ics []v1beta1.ImageConfig, image string, valid isValidConfig) *v1beta1.ImageConfig {
 	var config *v1beta1.ImageConfig
 	var longest int
 
-	for _, c := range l.Items {
+	for _, c := range ics {
 		if !valid(&c) {
 			continue
 		}
@@ -158,5 +205,20 @@ func (s *ImageConfigStore) bestMatch(ctx context.Context, image string, valid is
 		}
 	}
 
-	return config, nil
+	return config
+}
+
+// longestMatch returns the longest prefix of the supplied ImageConfig that
+// matches the supplied image.
+func longestMatch(c *v1beta1.ImageConfig, image string) string {
+	matchPrefix := ""
+	for _, m := range c.Spec.MatchImages {
+		if !strings.HasPrefix(image, m.Prefix) {
+			continue
+		}
+		if len(m.Prefix) > len(matchPrefix) {
+			matchPrefix = m.Prefix
+		}
+	}
+	return matchPrefix
 }
@@ -436,3 +436,203 @@ func TestImageConfigStoreBestMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestImageConfigStoreRegistryAuthenticationFor(t *testing.T) {
+	helper := &v1beta1.RegistryAuthentication{
+		CredentialHelper: &v1beta1.RegistryCredentialHelper{Name: "ecr-login"},
+	}
+	type args struct {
+		client client.Client
+		image  string
+	}
+	type want struct {
+		imageConfig string
+		auth        *v1beta1.RegistryAuthentication
+		err         error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"ErrListConfig": {
+			args: args{
+				image: "registry1.com/acme-co/configuration-foo",
+				client: &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, errListImageConfigs), errFindBestMatch),
+			},
+		},
+		"IgnorePullSecretOnly": {
+			args: args{
+				image: "registry1.com/acme-co/configuration-foo",
+				client: &test.MockClient{
+					MockList: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+						*list.(*v1beta1.ImageConfigList) = v1beta1.ImageConfigList{
+							Items: []v1beta1.ImageConfig{
+								{
+									ObjectMeta: metav1.ObjectMeta{Name: "secret"},
+									Spec: v1beta1.ImageConfigSpec{
+										MatchImages: []v1beta1.ImageMatch{{Prefix: "registry1.com/acme-co"}},
+										Registry: &v1beta1.RegistryConfig{
+											Authentication: &v1beta1.RegistryAuthentication{
+												PullSecretRef: corev1.LocalObjectReference{Name: "test"},
+											},
+										},
+									},
+								},
+								{
+									ObjectMeta: metav1.ObjectMeta{Name: "helper"},
+									Spec: v1beta1.ImageConfigSpec{
+										MatchImages: []v1beta1.ImageMatch{{Prefix: "registry1.com"}},
+										Registry: &v1beta1.RegistryConfig{
+											Authentication: helper,
+										},
+									},
+								},
+							},
+						}
+						return nil
+					},
+				},
+			},
+			want: want{
+				imageConfig: "helper",
+				auth:        helper,
+			},
+		},
+		"NoMatch": {
+			args: args{
+				image: "registry2.com/acme-co/configuration-foo",
+				client: &test.MockClient{
+					MockList: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+						*list.(*v1beta1.ImageConfigList) = v1beta1.ImageConfigList{
+							Items: []v1beta1.ImageConfig{
+								{
+									ObjectMeta: metav1.ObjectMeta{Name: "helper"},
+									Spec: v1beta1.ImageConfigSpec{
+										MatchImages: []v1beta1.ImageMatch{{Prefix: "registry1.com"}},
+										Registry: &v1beta1.RegistryConfig{
+											Authentication: helper,
+										},
+									},
+								},
+							},
+						}
+						return nil
+					},
+				},
+			},
+			want: want{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &ImageConfigStore{
+				client: tc.args.client,
+			}
+			ic, auth, err := s.RegistryAuthenticationFor(context.Background(), tc.args.image)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("RegistryAuthenticationFor() error -want +got: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.imageConfig, ic); diff != "" {
+				t.Errorf("RegistryAuthenticationFor() imageConfig -want +got: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.auth, auth); diff != "" {
+				t.Errorf("RegistryAuthenticationFor() auth -want +got: %s", diff)
+			}
+		})
+	}
+}
+
+func TestImageConfigStoreImageVerificationConfigFor(t *testing.T) {
+	notation := &v1beta1.ImageVerification{
+		Provider: v1beta1.ImageVerificationProviderNotation,
+		Notation: &v1beta1.NotationVerificationConfig{
+			TrustPolicy: v1beta1.NotationSource{ConfigMapRef: &v1beta1.LocalConfigMapKeySelector{Name: "policy", Key: "trustpolicy.json"}},
+		},
+	}
+	type args struct {
+		client client.Client
+		image  string
+	}
+	type want struct {
+		imageConfig string
+		iv          *v1beta1.ImageVerification
+		err         error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"NotationOnly": {
+			args: args{
+				image: "registry1.com/acme-co/configuration-foo",
+				client: &test.MockClient{
+					MockList: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+						*list.(*v1beta1.ImageConfigList) = v1beta1.ImageConfigList{
+							Items: []v1beta1.ImageConfig{
+								{
+									ObjectMeta: metav1.ObjectMeta{Name: "notation"},
+									Spec: v1beta1.ImageConfigSpec{
+										MatchImages:  []v1beta1.ImageMatch{{Prefix: "registry1.com/acme-co"}},
+										Verification: notation,
+									},
+								},
+							},
+						}
+						return nil
+					},
+				},
+			},
+			want: want{
+				imageConfig: "notation",
+				iv:          notation,
+			},
+		},
+		"ProviderConfigMismatch": {
+			args: args{
+				image: "registry1.com/acme-co/configuration-foo",
+				client: &test.MockClient{
+					MockList: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+						*list.(*v1beta1.ImageConfigList) = v1beta1.ImageConfigList{
+							Items: []v1beta1.ImageConfig{
+								{
+									ObjectMeta: metav1.ObjectMeta{Name: "empty"},
+									Spec: v1beta1.ImageConfigSpec{
+										MatchImages:  []v1beta1.ImageMatch{{Prefix: "registry1.com/acme-co"}},
+										Verification: &v1beta1.ImageVerification{Provider: v1beta1.ImageVerificationProviderCosign, Notation: notation.Notation},
+									},
+								},
+							},
+						}
+						return nil
+					},
+				},
+			},
+			want: want{
+				imageConfig: "empty",
+				err:         errors.Errorf(errFmtVerificationConfigMissing, v1beta1.ImageVerificationProviderCosign),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &ImageConfigStore{
+				client: tc.args.client,
+			}
+			ic, iv, err := s.ImageVerificationConfigFor(context.Background(), tc.args.image)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ImageVerificationConfigFor() error -want +got: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.imageConfig, ic); diff != "" {
+				t.Errorf("ImageVerificationConfigFor() imageConfig -want +got: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.iv, iv); diff != "" {
+				t.Errorf("ImageVerificationConfigFor() verification -want +got: %s", diff)
+			}
+		})
+	}
+}
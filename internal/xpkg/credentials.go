@@ -0,0 +1,309 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/authn/k8schain"
+	"github.com/google/go-containerregistry/pkg/name"
+	authv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	errGetRegistryAuthentication = "cannot get registry authentication configuration"
+	errFmtExchangeToken          = "cannot exchange service account token for ImageConfig %q"
+	errCreateServiceAccountToken = "cannot create service account token"
+	errBuildTokenRequest         = "cannot build token exchange request"
+	errSendTokenRequest          = "cannot send token exchange request"
+	errFmtTokenExchangeStatus    = "token exchange endpoint returned status %d: %s"
+	errDecodeTokenResponse       = "cannot decode token exchange response"
+	errEmptyAccessToken          = "token exchange response did not include an access token"
+	errFmtHelperNotInstalled     = "ImageConfig %q uses credential helper %q, which isn't installed in the Crossplane image; install docker-credential-%s in a custom Crossplane image to use it"
+	errFmtRunCredentialHelper    = "cannot run credential helper %q"
+	errFmtDecodeCredentialHelper = "cannot decode output of credential helper %q"
+)
+
+// OAuth 2.0 token exchange (RFC 8693) parameters.
+const (
+	grantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	tokenTypeJWT           = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// tokenExpiryLeeway is how long before its expiry we stop using a cached
+// access token, so it doesn't expire while a fetch is in progress.
+const tokenExpiryLeeway = 1 * time.Minute
+
+// credentialHelperPrefix is the prefix of Docker credential helper binaries.
+const credentialHelperPrefix = "docker-credential-"
+
+// WithCredentialProviders is a FetcherOpt that configures a K8sFetcher to
+// authenticate to registries using the credential providers configured by
+// ImageConfigs in the supplied store. Credentials from providers take
+// precedence over pull secrets.
+//
+// The fetcher only runs the credential helpers that are installed when it's
+// created. It rejects ImageConfigs that use any other credential helper.
+func WithCredentialProviders(s ConfigStore) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		k.config = s
+		k.helpers = make(map[string]bool)
+		for _, h := range InstalledCredentialHelpers() {
+			k.helpers[h] = true
+		}
+		return nil
+	}
+}
+
+// InstalledCredentialHelpers returns the names of the Docker credential
+// helpers installed on the PATH. The Crossplane image doesn't include any
+// credential helpers, so they must be added to a custom image.
+func InstalledCredentialHelpers() []string {
+	helpers := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			h, ok := strings.CutPrefix(e.Name(), credentialHelperPrefix)
+			if !ok || h == "" || seen[h] || e.IsDir() {
+				continue
+			}
+			if _, err := exec.LookPath(filepath.Join(dir, e.Name())); err != nil {
+				continue
+			}
+			seen[h] = true
+			helpers = append(helpers, h)
+		}
+	}
+	return helpers
+}
+
+// Keychain returns the keychain used to authenticate requests for the supplied
+// image reference. It resolves credentials using the credential provider
+// configured for the image, if any, then the supplied pull secrets and the
+// fetcher's service account.
+func (i *K8sFetcher) Keychain(ctx context.Context, ref name.Reference, secrets ...string) (authn.Keychain, error) {
+	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
+		Namespace:          i.namespace,
+		ServiceAccountName: i.serviceAccount,
+		ImagePullSecrets:   secrets,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if i.config == nil {
+		return auth, nil
+	}
+
+	ic, ra, err := i.config.RegistryAuthenticationFor(ctx, ref.String())
+	if err != nil {
+		return nil, errors.Wrap(err, errGetRegistryAuthentication)
+	}
+
+	kc, err := i.providerKeychain(ctx, ic, ra)
+	if err != nil {
+		return nil, err
+	}
+	if kc != nil {
+		return authn.NewMultiKeychain(kc, auth), nil
+	}
+	return auth, nil
+}
+
+// providerKeychain returns a keychain that resolves credentials using the
+// credential provider configured by the supplied registry authentication, or
+// nil if it doesn't configure a credential provider.
+func (i *K8sFetcher) providerKeychain(ctx context.Context, imageConfig string, ra *v1beta1.RegistryAuthentication) (authn.Keychain, error) {
+	switch {
+	case ra == nil:
+		return nil, nil
+	case ra.TokenExchange != nil:
+		return &tokenExchangeKeychain{ctx: ctx, fetcher: i, imageConfig: imageConfig, cfg: ra.TokenExchange}, nil
+	case ra.CredentialHelper != nil:
+		h := ra.CredentialHelper.Name
+		if !i.helpers[h] {
+			return nil, errors.Errorf(errFmtHelperNotInstalled, imageConfig, h, h)
+		}
+		return authn.NewKeychainFromHelper(&credentialHelper{ctx: ctx, name: h}), nil
+	}
+	return nil, nil
+}
+
+// A tokenExchangeKeychain resolves registry credentials by exchanging a token
+// for the fetcher's service account for a registry access token.
+type tokenExchangeKeychain struct {
+	ctx         context.Context //nolint:containedctx // authn.Keychain doesn't take a context.
+	fetcher     *K8sFetcher
+	imageConfig string
+	cfg         *v1beta1.RegistryTokenExchange
+}
+
+// Resolve returns an authenticator for the supplied registry.
+func (k *tokenExchangeKeychain) Resolve(_ authn.Resource) (authn.Authenticator, error) {
+	token, err := k.fetcher.exchangeToken(k.ctx, k.cfg)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtExchangeToken, k.imageConfig)
+	}
+	if k.cfg.Username != nil {
+		return authn.FromConfig(authn.AuthConfig{Username: *k.cfg.Username, Password: token}), nil
+	}
+	return authn.FromConfig(authn.AuthConfig{RegistryToken: token}), nil
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// exchangeToken returns an access token for the supplied token exchange
+// configuration, using a cached token if it hasn't yet expired.
+func (i *K8sFetcher) exchangeToken(ctx context.Context, cfg *v1beta1.RegistryTokenExchange) (string, error) {
+	key := cfg.URL + "\n" + cfg.Audience
+	if t, ok := i.tokens.Get(key); ok {
+		return t, nil
+	}
+
+	tr, err := i.client.CoreV1().ServiceAccounts(i.namespace).CreateToken(ctx, i.serviceAccount, &authv1.TokenRequest{
+		Spec: authv1.TokenRequestSpec{
+			Audiences:         []string{cfg.Audience},
+			ExpirationSeconds: cfg.ExpirationSeconds,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", errors.Wrap(err, errCreateServiceAccountToken)
+	}
+
+	form := url.Values{
+		"grant_type":         {grantTypeTokenExchange},
+		"subject_token":      {tr.Status.Token},
+		"subject_token_type": {tokenTypeJWT},
+		"audience":           {cfg.Audience},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errors.Wrap(err, errBuildTokenRequest)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", i.userAgent)
+
+	rsp, err := (&http.Client{Transport: i.transport}).Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, errSendTokenRequest)
+	}
+	defer rsp.Body.Close() //nolint:errcheck // Only error is on close.
+
+	if rsp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(rsp.Body, 1024))
+		return "", errors.Errorf(errFmtTokenExchangeStatus, rsp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	t := &tokenResponse{}
+	if err := json.NewDecoder(rsp.Body).Decode(t); err != nil {
+		return "", errors.Wrap(err, errDecodeTokenResponse)
+	}
+	if t.AccessToken == "" {
+		return "", errors.New(errEmptyAccessToken)
+	}
+
+	// Tokens that don't advertise an expiry aren't cached.
+	if t.ExpiresIn > 0 {
+		i.tokens.Set(key, t.AccessToken, time.Now().Add(time.Duration(t.ExpiresIn)*time.Second))
+	}
+
+	return t.AccessToken, nil
+}
+
+type cachedToken struct {
+	token   string
+	expires time.Time
+}
+
+// A tokenCache caches exchanged access tokens until shortly before they
+// expire.
+type tokenCache struct {
+	mu     sync.RWMutex
+	tokens map[string]cachedToken
+}
+
+func newTokenCache() *tokenCache {
+	return &tokenCache{tokens: make(map[string]cachedToken)}
+}
+
+// Get returns the cached token for the supplied key, if it hasn't expired.
+func (c *tokenCache) Get(key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	t, ok := c.tokens[key]
+	if !ok || time.Now().Add(tokenExpiryLeeway).After(t.expires) {
+		return "", false
+	}
+	return t.token, true
+}
+
+// Set caches the supplied token until it expires.
+func (c *tokenCache) Set(key, token string, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens[key] = cachedToken{token: token, expires: expires}
+}
+
+// A credentialHelper gets registry credentials by running a Docker credential
+// helper binary. See https://github.com/docker/docker-credential-helpers.
+type credentialHelper struct {
+	ctx  context.Context //nolint:containedctx // authn.Helper doesn't take a context.
+	name string
+}
+
+type credentialHelperResponse struct {
+	Username string `json:"Username"`
+	Secret   string `json:"Secret"`
+}
+
+// Get returns the username and secret for the supplied registry server URL.
+func (h *credentialHelper) Get(serverURL string) (string, string, error) {
+	cmd := exec.CommandContext(h.ctx, credentialHelperPrefix+h.name, "get") //nolint:gosec // The helper name is validated by the ImageConfig schema.
+	cmd.Stdin = strings.NewReader(serverURL)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", errors.Wrapf(err, errFmtRunCredentialHelper, h.name)
+	}
+
+	r := &credentialHelperResponse{}
+	if err := json.Unmarshal(out, r); err != nil {
+		return "", "", errors.Wrapf(err, errFmtDecodeCredentialHelper, h.name)
+	}
+	return r.Username, r.Secret, nil
+}
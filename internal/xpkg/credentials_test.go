@@ -0,0 +1,184 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+// mockConfigStore is a ConfigStore that only returns registry authentication.
+// We can't use the fake package here because it imports this one.
+type mockConfigStore struct {
+	ConfigStore
+
+	imageConfig string
+	auth        *v1beta1.RegistryAuthentication
+}
+
+func (s *mockConfigStore) RegistryAuthenticationFor(_ context.Context, _ string) (string, *v1beta1.RegistryAuthentication, error) {
+	return s.imageConfig, s.auth, nil
+}
+
+func TestKeychain(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("grant_type") != grantTypeTokenExchange || r.PostForm.Get("subject_token") != "sa-token" || r.PostForm.Get("audience") != "registry" {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"access_token":"registry-token","expires_in":3600}`)
+	}))
+	defer srv.Close()
+
+	// Create a fake credential helper on the PATH.
+	dir := t.TempDir()
+	script := "#!/bin/sh\ncat >/dev/null\necho '{\"Username\":\"helper-user\",\"Secret\":\"helper-secret\"}'\n"
+	if err := os.WriteFile(filepath.Join(dir, credentialHelperPrefix+"test"), []byte(script), 0o700); err != nil { //nolint:gosec // The helper must be executable.
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	type want struct {
+		auth *authn.AuthConfig
+		err  bool
+	}
+	cases := map[string]struct {
+		reason string
+		auth   *v1beta1.RegistryAuthentication
+		want   want
+	}{
+		"NoProvider": {
+			reason: "We should fall back to anonymous access if no credential provider matches.",
+			want: want{
+				auth: &authn.AuthConfig{},
+			},
+		},
+		"TokenExchangeBearer": {
+			reason: "We should use the exchanged access token as a registry bearer token.",
+			auth: &v1beta1.RegistryAuthentication{
+				TokenExchange: &v1beta1.RegistryTokenExchange{URL: srv.URL, Audience: "registry"},
+			},
+			want: want{
+				auth: &authn.AuthConfig{RegistryToken: "registry-token"},
+			},
+		},
+		"TokenExchangeUsername": {
+			reason: "We should use the exchanged access token as a password if a username is supplied.",
+			auth: &v1beta1.RegistryAuthentication{
+				TokenExchange: &v1beta1.RegistryTokenExchange{URL: srv.URL, Audience: "registry", Username: ptr.To("oauth2")},
+			},
+			want: want{
+				auth: &authn.AuthConfig{Username: "oauth2", Password: "registry-token"},
+			},
+		},
+		"TokenExchangeError": {
+			reason: "We should return an error if the token exchange fails.",
+			auth: &v1beta1.RegistryAuthentication{
+				TokenExchange: &v1beta1.RegistryTokenExchange{URL: srv.URL, Audience: "wrong"},
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"CredentialHelper": {
+			reason: "We should use the credentials returned by the credential helper.",
+			auth: &v1beta1.RegistryAuthentication{
+				CredentialHelper: &v1beta1.RegistryCredentialHelper{Name: "test"},
+			},
+			want: want{
+				auth: &authn.AuthConfig{Username: "helper-user", Password: "helper-secret"},
+			},
+		},
+		"CredentialHelperNotInstalled": {
+			reason: "We should return an error if the credential helper isn't installed.",
+			auth: &v1beta1.RegistryAuthentication{
+				CredentialHelper: &v1beta1.RegistryCredentialHelper{Name: "missing"},
+			},
+			want: want{
+				err: true,
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			client := fake.NewSimpleClientset(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "crossplane", Namespace: "crossplane-system"}})
+			client.PrependReactor("create", "serviceaccounts", func(a ktesting.Action) (bool, runtime.Object, error) {
+				if a.GetSubresource() != "token" {
+					return false, nil, nil
+				}
+				return true, &authv1.TokenRequest{Status: authv1.TokenRequestStatus{Token: "sa-token"}}, nil
+			})
+			f, err := NewK8sFetcher(client,
+				WithNamespace("crossplane-system"),
+				WithServiceAccount("crossplane"),
+				WithCredentialProviders(&mockConfigStore{imageConfig: "test", auth: tc.auth}),
+			)
+			if err != nil {
+				t.Fatalf("NewK8sFetcher(...): %v", err)
+			}
+
+			ref := name.MustParseReference("registry.example.org/crossplane/provider-foo:v1.0.0")
+			kc, err := f.Keychain(context.Background(), ref)
+			if err != nil {
+				if !tc.want.err {
+					t.Fatalf("\n%s\nKeychain(...): %v", tc.reason, err)
+				}
+				return
+			}
+			a, err := kc.Resolve(ref.Context())
+			if tc.want.err {
+				if err == nil {
+					t.Errorf("\n%s\nResolve(...): want error, got nil", tc.reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nResolve(...): %v", tc.reason, err)
+			}
+			got, err := a.Authorization()
+			if err != nil {
+				t.Fatalf("Authorization(): %v", err)
+			}
+			if diff := cmp.Diff(tc.want.auth, got); diff != "" {
+				t.Errorf("\n%s\nResolve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
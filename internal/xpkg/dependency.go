@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const errInvalidDependency = "encountered an invalid dependency: package dependencies must specify either a valid type, or an explicit apiVersion, kind, and package"
+
+// LockDependencies converts the dependencies declared in the supplied package
+// metadata to Lock dependencies.
+func LockDependencies(meta pkgmetav1.Pkg) ([]v1beta1.Dependency, error) {
+	deps := make([]v1beta1.Dependency, len(meta.GetDependencies()))
+	for i, dep := range meta.GetDependencies() {
+		pdep := v1beta1.Dependency{}
+		switch {
+		// If the GVK and package are specified explicitly they take precedence.
+		case dep.APIVersion != nil && dep.Kind != nil && dep.Package != nil:
+			pdep.APIVersion = dep.APIVersion
+			pdep.Kind = dep.Kind
+			pdep.Package = *dep.Package
+		case dep.Configuration != nil:
+			pdep.Package = *dep.Configuration
+			pdep.Type = ptr.To(v1beta1.ConfigurationPackageType)
+		case dep.Provider != nil:
+			pdep.Package = *dep.Provider
+			pdep.Type = ptr.To(v1beta1.ProviderPackageType)
+		case dep.Function != nil:
+			pdep.Package = *dep.Function
+			pdep.Type = ptr.To(v1beta1.FunctionPackageType)
+		default:
+			return nil, errors.New(errInvalidDependency)
+		}
+		pdep.Constraints = dep.Version
+		deps[i] = pdep
+	}
+	return deps, nil
+}
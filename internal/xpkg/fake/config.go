@@ -14,6 +14,8 @@ type MockConfigStore struct {
 	MockPullSecretFor              func(ctx context.Context, image string) (imageConfig string, pullSecret string, err error)
 	MockImageVerificationConfigFor func(ctx context.Context, image string) (imageConfig string, verificationConfig *v1beta1.ImageVerification, err error)
 	MockRewritePath                func(ctx context.Context, image string) (imageConfig, newPath string, err error)
+	MockRegistryAuthenticationFor  func(ctx context.Context, image string) (imageConfig string, auth *v1beta1.RegistryAuthentication, err error)
+	MockMirrorsFor                 func(ctx context.Context, image string) (imageConfig string, mirrors []xpkg.Mirror, err error)
 }
 
 // PullSecretFor calls the underlying MockPullSecretFor.
@@ -31,6 +33,16 @@ func (s *MockConfigStore) RewritePath(ctx context.Context, image string) (imageC
 	return s.MockRewritePath(ctx, image)
 }
 
+// RegistryAuthenticationFor calls the underlying MockRegistryAuthenticationFor.
+func (s *MockConfigStore) RegistryAuthenticationFor(ctx context.Context, image string) (imageConfig string, auth *v1beta1.RegistryAuthentication, err error) {
+	return s.MockRegistryAuthenticationFor(ctx, image)
+}
+
+// MirrorsFor calls the underlying MockMirrorsFor.
+func (s *MockConfigStore) MirrorsFor(ctx context.Context, image string) (imageConfig string, mirrors []xpkg.Mirror, err error) {
+	return s.MockMirrorsFor(ctx, image)
+}
+
 // NewMockConfigStorePullSecretForFn creates a new MockPullSecretFor function for MockConfigStore.
 func NewMockConfigStorePullSecretForFn(imageConfig, pullSecret string, err error) func(context.Context, string) (string, string, error) {
 	return func(context.Context, string) (string, string, error) {
@@ -52,3 +64,19 @@ func NewMockRewritePathFn(imageConfig, newPath string, err error) func(context.C
 		return imageConfig, newPath, err
 	}
 }
+
+// NewMockRegistryAuthenticationForFn creates a new
+// MockRegistryAuthenticationFor function for MockConfigStore.
+func NewMockRegistryAuthenticationForFn(imageConfig string, auth *v1beta1.RegistryAuthentication, err error) func(context.Context, string) (string, *v1beta1.RegistryAuthentication, error) {
+	return func(_ context.Context, _ string) (string, *v1beta1.RegistryAuthentication, error) {
+		return imageConfig, auth, err
+	}
+}
+
+// NewMockMirrorsForFn creates a new MockMirrorsFor function for
+// MockConfigStore.
+func NewMockMirrorsForFn(imageConfig string, mirrors []xpkg.Mirror, err error) func(context.Context, string) (string, []xpkg.Mirror, error) {
+	return func(_ context.Context, _ string) (string, []xpkg.Mirror, error) {
+		return imageConfig, mirrors, err
+	}
+}
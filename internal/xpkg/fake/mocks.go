@@ -33,7 +33,7 @@ var _ xpkg.PackageCache = &MockCache{}
 type MockCache struct {
 	MockHas    func() bool
 	MockGet    func() (io.ReadCloser, error)
-	MockStore  func(s string, rc io.ReadCloser) error
+	MockStore  func(s, d string, rc io.ReadCloser) error
 	MockDelete func() error
 }
 
@@ -48,8 +48,8 @@ func NewMockCacheGetFn(rc io.ReadCloser, err error) func() (io.ReadCloser, error
 }
 
 // NewMockCacheStoreFn creates a new MockStore function for MockCache.
-func NewMockCacheStoreFn(err error) func(s string, rc io.ReadCloser) error {
-	return func(_ string, _ io.ReadCloser) error { return err }
+func NewMockCacheStoreFn(err error) func(s, d string, rc io.ReadCloser) error {
+	return func(_, _ string, _ io.ReadCloser) error { return err }
 }
 
 // NewMockCacheDeleteFn creates a new MockDelete function for MockCache.
@@ -58,18 +58,18 @@ func NewMockCacheDeleteFn(err error) func() error {
 }
 
 // Has calls the underlying MockHas.
-func (c *MockCache) Has(string) bool {
+func (c *MockCache) Has(string, string) bool {
 	return c.MockHas()
 }
 
 // Get calls the underlying MockGet.
-func (c *MockCache) Get(string) (io.ReadCloser, error) {
+func (c *MockCache) Get(string, string) (io.ReadCloser, error) {
 	return c.MockGet()
 }
 
 // Store calls the underlying MockStore.
-func (c *MockCache) Store(s string, rc io.ReadCloser) error {
-	return c.MockStore(s, rc)
+func (c *MockCache) Store(s, d string, rc io.ReadCloser) error {
+	return c.MockStore(s, d, rc)
 }
 
 // Delete calls the underlying MockDelete.
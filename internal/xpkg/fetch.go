@@ -23,7 +23,6 @@ import (
 	"io"
 	"net/http"
 
-	"github.com/google/go-containerregistry/pkg/authn/k8schain"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/empty"
@@ -52,11 +51,16 @@ type Fetcher interface {
 
 // K8sFetcher uses kubernetes credentials to fetch package images.
 type K8sFetcher struct {
-	client         kubernetes.Interface
-	namespace      string
-	serviceAccount string
-	transport      http.RoundTripper
-	userAgent      string
+	client           kubernetes.Interface
+	namespace        string
+	serviceAccount   string
+	transport        http.RoundTripper
+	userAgent        string
+	config           ConfigStore
+	helpers          map[string]bool
+	mirrors          ConfigStore
+	mirrorTransports *transportCache
+	tokens           *tokenCache
 }
 
 // FetcherOpt can be used to add optional parameters to NewK8sFetcher.
@@ -113,8 +117,10 @@ func NewK8sFetcher(client kubernetes.Interface, opts ...FetcherOpt) (*K8sFetcher
 		return nil, errors.Errorf("default transport was not a %T", &http.Transport{})
 	}
 	k := &K8sFetcher{
-		client:    client,
-		transport: dt.Clone(),
+		client:           client,
+		transport:        dt.Clone(),
+		mirrorTransports: newTransportCache(),
+		tokens:           newTokenCache(),
 	}
 
 	for _, o := range opts {
@@ -128,69 +134,42 @@ func NewK8sFetcher(client kubernetes.Interface, opts ...FetcherOpt) (*K8sFetcher
 
 // Fetch fetches a package image.
 func (i *K8sFetcher) Fetch(ctx context.Context, ref name.Reference, secrets ...string) (v1.Image, error) {
-	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
-		Namespace:          i.namespace,
-		ServiceAccountName: i.serviceAccount,
-		ImagePullSecrets:   secrets,
+	var img v1.Image
+	err := i.fetch(ctx, ref, secrets, func(ref name.Reference, o ...remote.Option) error {
+		var err error
+		img, err = remote.Image(ref, o...)
+		return err
 	})
-	if err != nil {
-		return nil, err
-	}
-	return remote.Image(ref,
-		remote.WithAuthFromKeychain(auth),
-		remote.WithTransport(i.transport),
-		remote.WithContext(ctx),
-		remote.WithUserAgent(i.userAgent),
-	)
+	return img, err
 }
 
 // Head fetches a package descriptor.
 func (i *K8sFetcher) Head(ctx context.Context, ref name.Reference, secrets ...string) (*v1.Descriptor, error) {
-	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
-		Namespace:          i.namespace,
-		ServiceAccountName: i.serviceAccount,
-		ImagePullSecrets:   secrets,
-	})
-	if err != nil {
-		return nil, err
-	}
-	d, err := remote.Head(ref,
-		remote.WithAuthFromKeychain(auth),
-		remote.WithTransport(i.transport),
-		remote.WithContext(ctx),
-		remote.WithUserAgent(i.userAgent),
-	)
-	if err != nil || d == nil {
-		rd, gErr := remote.Get(ref,
-			remote.WithAuthFromKeychain(auth),
-			remote.WithTransport(i.transport),
-			remote.WithContext(ctx),
-			remote.WithUserAgent(i.userAgent),
-		)
-		if gErr != nil {
-			return nil, errors.Wrapf(gErr, "failed to fetch package descriptor with a GET request after a previous HEAD request failure: %v", err)
+	var d *v1.Descriptor
+	err := i.fetch(ctx, ref, secrets, func(ref name.Reference, o ...remote.Option) error {
+		var err error
+		d, err = remote.Head(ref, o...)
+		if err != nil || d == nil {
+			rd, gErr := remote.Get(ref, o...)
+			if gErr != nil {
+				return errors.Wrapf(gErr, "failed to fetch package descriptor with a GET request after a previous HEAD request failure: %v", err)
+			}
+			d = &rd.Descriptor
 		}
-		return &rd.Descriptor, nil
-	}
-	return d, nil
+		return nil
+	})
+	return d, err
 }
 
 // Tags fetches a package's tags.
 func (i *K8sFetcher) Tags(ctx context.Context, ref name.Reference, secrets ...string) ([]string, error) {
-	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
-		Namespace:          i.namespace,
-		ServiceAccountName: i.serviceAccount,
-		ImagePullSecrets:   secrets,
+	var tags []string
+	err := i.fetch(ctx, ref, secrets, func(ref name.Reference, o ...remote.Option) error {
+		var err error
+		tags, err = remote.List(ref.Context(), o...)
+		return err
 	})
-	if err != nil {
-		return nil, err
-	}
-	return remote.List(ref.Context(),
-		remote.WithAuthFromKeychain(auth),
-		remote.WithTransport(i.transport),
-		remote.WithContext(ctx),
-		remote.WithUserAgent(i.userAgent),
-	)
+	return tags, err
 }
 
 // NopFetcher always returns an empty image and never returns error.
@@ -0,0 +1,308 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/authn/k8schain"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	errGetMirrors            = "cannot get registry mirrors"
+	errFmtFetchFromMirror    = "cannot fetch from mirror %q"
+	errFmtParseMirrorRef     = "cannot parse mirror image reference %q"
+	errFmtGetCABundle        = "cannot get CA bundle from ConfigMap %q"
+	errFmtNoCABundleKey      = "key %q not found in ConfigMap %q"
+	errFmtParseCABundle      = "cannot parse CA bundle from ConfigMap %q"
+	errTransportNotHTTP      = "Fetcher transport is not an HTTP transport"
+	errFmtMirrorsUnavailable = "cannot fetch from registry or any of its %d mirrors"
+)
+
+// A Mirror is an alternative location from which an image can be fetched.
+type Mirror struct {
+	// Image is the image's path in the mirror.
+	Image string
+
+	// Authentication is the authentication information for the mirror, if
+	// any.
+	Authentication *v1beta1.RegistryAuthentication
+
+	// TLS is the TLS configuration for the mirror, if any.
+	TLS *v1beta1.MirrorTLS
+}
+
+// PullSecret returns the name of the mirror's pull secret, if any.
+func (m Mirror) PullSecret() string {
+	if m.Authentication == nil {
+		return ""
+	}
+	return m.Authentication.PullSecretRef.Name
+}
+
+// FindMirrors returns the name of the ImageConfig among the supplied
+// ImageConfigs that best matches the supplied image and configures mirrors,
+// and the mirrors of the image based on that config, in order.
+func FindMirrors(ics []v1beta1.ImageConfig, image string) (imageConfig string, mirrors []Mirror) {
+	config := bestMatchIn(ics, image, hasMirrors)
+	if config == nil {
+		return "", nil
+	}
+	return config.Name, mirrorsOf(config, image)
+}
+
+func hasMirrors(c *v1beta1.ImageConfig) bool {
+	return len(c.Spec.Mirrors) > 0
+}
+
+// mirrorsOf returns the mirrors of the supplied image configured by the
+// supplied ImageConfig. Each mirror replaces the longest prefix of the
+// ImageConfig that matches the image.
+func mirrorsOf(c *v1beta1.ImageConfig, image string) []Mirror {
+	path := strings.TrimPrefix(image, longestMatch(c, image))
+	mirrors := make([]Mirror, 0, len(c.Spec.Mirrors))
+	for _, m := range c.Spec.Mirrors {
+		mirrors = append(mirrors, Mirror{
+			Image:          m.Prefix + path,
+			Authentication: m.Authentication,
+			TLS:            m.TLS,
+		})
+	}
+	return mirrors
+}
+
+// IsUnavailable returns true if the supplied error indicates a registry was
+// unreachable, failed to serve a request, or rate limited a request. Fetches
+// that fail with such an error may succeed from a mirror.
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var terr *transport.Error
+	if errors.As(err, &terr) {
+		return terr.StatusCode == http.StatusTooManyRequests || terr.StatusCode >= http.StatusInternalServerError
+	}
+	var nerr net.Error
+	return errors.As(err, &nerr)
+}
+
+// WithMirrors is a FetcherOpt that configures a K8sFetcher to fall back to
+// the mirrors configured by ImageConfigs in the supplied store when an image's
+// registry is unavailable.
+func WithMirrors(s ConfigStore) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		k.mirrors = s
+		return nil
+	}
+}
+
+// A fetchFn fetches the supplied reference using the supplied options.
+type fetchFn func(ref name.Reference, o ...remote.Option) error
+
+// fetch calls the supplied function to fetch the supplied reference. If the
+// reference's registry is unavailable it calls the function again for each
+// of the reference's mirrors, in order, until one succeeds.
+func (i *K8sFetcher) fetch(ctx context.Context, ref name.Reference, secrets []string, fn fetchFn) error {
+	auth, err := i.Keychain(ctx, ref, secrets...)
+	if err != nil {
+		return err
+	}
+	err = fn(ref, i.options(ctx, auth, i.transport)...)
+	if i.mirrors == nil || !IsUnavailable(err) {
+		return err
+	}
+
+	ic, mirrors, mErr := i.mirrors.MirrorsFor(ctx, ref.String())
+	if mErr != nil {
+		return errors.Join(err, errors.Wrap(mErr, errGetMirrors))
+	}
+	if len(mirrors) == 0 {
+		return err
+	}
+
+	errs := []error{err}
+	for _, m := range mirrors {
+		mErr := i.fetchMirror(ctx, ic, m, fn)
+		if mErr == nil {
+			return nil
+		}
+		errs = append(errs, errors.Wrapf(mErr, errFmtFetchFromMirror, m.Image))
+	}
+	return errors.Wrapf(errors.Join(errs...), errFmtMirrorsUnavailable, len(mirrors))
+}
+
+// fetchMirror calls the supplied function to fetch the supplied mirror, using
+// the mirror's authentication and TLS configuration.
+func (i *K8sFetcher) fetchMirror(ctx context.Context, imageConfig string, m Mirror, fn fetchFn) error {
+	ref, err := name.ParseReference(m.Image)
+	if err != nil {
+		return errors.Wrapf(err, errFmtParseMirrorRef, m.Image)
+	}
+
+	o, err := i.MirrorOptions(ctx, imageConfig, m)
+	if err != nil {
+		return err
+	}
+
+	return fn(ref, o...)
+}
+
+// MirrorOptions returns the options used to access the supplied mirror, which
+// was configured by the supplied ImageConfig. They include the mirror's pull
+// secret, any credential providers it's configured to use, and its CA bundle.
+func (i *K8sFetcher) MirrorOptions(ctx context.Context, imageConfig string, m Mirror) ([]remote.Option, error) {
+	var secrets []string
+	if s := m.PullSecret(); s != "" {
+		secrets = append(secrets, s)
+	}
+	var auth authn.Keychain
+	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
+		Namespace:          i.namespace,
+		ServiceAccountName: i.serviceAccount,
+		ImagePullSecrets:   secrets,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Mirrors only use credential providers if they're enabled.
+	if i.config != nil {
+		kc, err := i.providerKeychain(ctx, imageConfig, m.Authentication)
+		if err != nil {
+			return nil, err
+		}
+		if kc != nil {
+			auth = authn.NewMultiKeychain(kc, auth)
+		}
+	}
+
+	t, err := i.mirrorTransport(ctx, m.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.options(ctx, auth, t), nil
+}
+
+// A transportCache caches the transports used to fetch from mirrors with a CA
+// bundle, so that fetches reuse connections rather than building a new
+// transport each time. Transports are keyed by the ConfigMap key that
+// supplies their CA bundle, and replaced when the CA bundle changes.
+type transportCache struct {
+	mu         sync.Mutex
+	transports map[string]cachedTransport
+}
+
+type cachedTransport struct {
+	pem       string
+	transport *http.Transport
+}
+
+func newTransportCache() *transportCache {
+	return &transportCache{transports: make(map[string]cachedTransport)}
+}
+
+// Get returns the transport cached for the supplied key, if it was built from
+// the supplied CA bundle. Otherwise it calls the supplied function to build a
+// new transport and caches it.
+func (c *transportCache) Get(key, pem string, build func() (*http.Transport, error)) (*http.Transport, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ct, ok := c.transports[key]
+	if ok && ct.pem == pem {
+		return ct.transport, nil
+	}
+
+	t, err := build()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		ct.transport.CloseIdleConnections()
+	}
+	c.transports[key] = cachedTransport{pem: pem, transport: t}
+	return t, nil
+}
+
+// mirrorTransport returns the transport used to fetch from a mirror with the
+// supplied TLS configuration.
+func (i *K8sFetcher) mirrorTransport(ctx context.Context, cfg *v1beta1.MirrorTLS) (http.RoundTripper, error) {
+	if cfg == nil || cfg.CABundleConfigMapRef == nil {
+		return i.transport, nil
+	}
+
+	ref := cfg.CABundleConfigMapRef
+	cm, err := i.client.CoreV1().ConfigMaps(i.namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetCABundle, ref.Name)
+	}
+	pem, ok := cm.Data[ref.Key]
+	if !ok {
+		return nil, errors.Errorf(errFmtNoCABundleKey, ref.Key, ref.Name)
+	}
+
+	return i.mirrorTransports.Get(ref.Name+"/"+ref.Key, pem, func() (*http.Transport, error) {
+		t, ok := i.transport.(*http.Transport)
+		if !ok {
+			return nil, errors.New(errTransportNotHTTP)
+		}
+		t = t.Clone()
+
+		// Trust the mirror's CAs in addition to those we trust for all
+		// registries.
+		var pool *x509.CertPool
+		if t.TLSClientConfig != nil && t.TLSClientConfig.RootCAs != nil {
+			pool = t.TLSClientConfig.RootCAs.Clone()
+		} else if pool, err = x509.SystemCertPool(); err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(pem)) {
+			return nil, errors.Errorf(errFmtParseCABundle, ref.Name)
+		}
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		t.TLSClientConfig.RootCAs = pool
+
+		return t, nil
+	})
+}
+
+// options returns the remote options used to fetch from a registry.
+func (i *K8sFetcher) options(ctx context.Context, auth authn.Keychain, t http.RoundTripper) []remote.Option {
+	return []remote.Option{
+		remote.WithAuthFromKeychain(auth),
+		remote.WithTransport(t),
+		remote.WithContext(ctx),
+		remote.WithUserAgent(i.userAgent),
+	}
+}
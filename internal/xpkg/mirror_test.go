@@ -0,0 +1,315 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+func TestFindMirrors(t *testing.T) {
+	auth := &v1beta1.RegistryAuthentication{PullSecretRef: corev1.LocalObjectReference{Name: "mirror-secret"}}
+	ics := []v1beta1.ImageConfig{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "no-mirrors"},
+			Spec: v1beta1.ImageConfigSpec{
+				MatchImages:  []v1beta1.ImageMatch{{Prefix: "xpkg.crossplane.io/crossplane-contrib/provider-nop"}},
+				RewriteImage: &v1beta1.ImageRewrite{Prefix: "registry.example.org/provider-nop"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "mirrors"},
+			Spec: v1beta1.ImageConfigSpec{
+				MatchImages: []v1beta1.ImageMatch{
+					{Prefix: "xpkg.crossplane.io"},
+					{Prefix: "xpkg.crossplane.io/crossplane-contrib"},
+				},
+				Mirrors: []v1beta1.ImageMirror{
+					{Prefix: "mirror-a.example.org/contrib", Authentication: auth},
+					{Prefix: "mirror-b.example.org/contrib"},
+				},
+			},
+		},
+	}
+
+	type want struct {
+		imageConfig string
+		mirrors     []Mirror
+	}
+	cases := map[string]struct {
+		reason string
+		image  string
+		want   want
+	}{
+		"NoMatch": {
+			reason: "An image that no ImageConfig with mirrors matches should have no mirrors.",
+			image:  "registry.example.org/crossplane-contrib/provider-nop:v0.2.0",
+			want:   want{},
+		},
+		"Match": {
+			reason: "Each mirror should replace the longest matching prefix, and mirrors should be returned in order. ImageConfigs without mirrors should be ignored.",
+			image:  "xpkg.crossplane.io/crossplane-contrib/provider-nop:v0.2.0",
+			want: want{
+				imageConfig: "mirrors",
+				mirrors: []Mirror{
+					{Image: "mirror-a.example.org/contrib/provider-nop:v0.2.0", Authentication: auth},
+					{Image: "mirror-b.example.org/contrib/provider-nop:v0.2.0"},
+				},
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			ic, mirrors := FindMirrors(ics, tc.image)
+			if diff := cmp.Diff(tc.want.imageConfig, ic); diff != "" {
+				t.Errorf("\n%s\nFindMirrors(...): -want imageConfig, +got imageConfig:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mirrors, mirrors); diff != "" {
+				t.Errorf("\n%s\nFindMirrors(...): -want mirrors, +got mirrors:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsUnavailable(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   bool
+	}{
+		"Nil": {
+			reason: "A nil error doesn't indicate the registry is unavailable.",
+			want:   false,
+		},
+		"NotFound": {
+			reason: "A 404 means the registry is available.",
+			err:    &transport.Error{StatusCode: http.StatusNotFound},
+			want:   false,
+		},
+		"RateLimited": {
+			reason: "A 429 means the registry rate limited the request.",
+			err:    errors.Wrap(&transport.Error{StatusCode: http.StatusTooManyRequests}, "cannot fetch"),
+			want:   true,
+		},
+		"ServerError": {
+			reason: "A 503 means the registry failed to serve the request.",
+			err:    &transport.Error{StatusCode: http.StatusServiceUnavailable},
+			want:   true,
+		},
+		"Network": {
+			reason: "A network error means the registry is unreachable.",
+			err:    errors.Wrap(&url.Error{Op: "Get", URL: "https://registry.example.org", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, "cannot fetch"),
+			want:   true,
+		},
+		"Other": {
+			reason: "Other errors don't indicate the registry is unavailable.",
+			err:    errors.New("boom"),
+			want:   false,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			got := IsUnavailable(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsUnavailable(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// mirrorConfigStore is a ConfigStore that only returns mirrors.
+type mirrorConfigStore struct {
+	ConfigStore
+
+	mirrors []Mirror
+}
+
+func (s *mirrorConfigStore) MirrorsFor(_ context.Context, _ string) (string, []Mirror, error) {
+	return "mirrors", s.mirrors, nil
+}
+
+func TestFetchWithMirrors(t *testing.T) {
+	// The primary registry fails all requests with a server error. We use a
+	// status that go-containerregistry doesn't retry to keep the test fast.
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInsufficientStorage)
+	}))
+	defer primary.Close()
+
+	mirror := httptest.NewServer(registry.New())
+	defer mirror.Close()
+
+	img, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mirrorHost := strings.TrimPrefix(mirror.URL, "http://")
+	mref, err := name.ParseReference(mirrorHost + "/contrib/provider-nop:v0.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(mref, img); err != nil {
+		t.Fatal(err)
+	}
+	want, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := name.ParseReference(strings.TrimPrefix(primary.URL, "http://") + "/crossplane-contrib/provider-nop:v0.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		reason  string
+		mirrors []Mirror
+		wantErr bool
+	}{
+		"NoMirrors": {
+			reason:  "We should return the primary registry's error if the image has no mirrors.",
+			wantErr: true,
+		},
+		"FallBack": {
+			reason: "We should fetch the image from the first available mirror.",
+			mirrors: []Mirror{
+				{Image: strings.TrimPrefix(primary.URL, "http://") + "/contrib/provider-nop:v0.2.0"},
+				{Image: mirrorHost + "/contrib/provider-nop:v0.2.0"},
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			f, err := NewK8sFetcher(fake.NewSimpleClientset(), WithMirrors(&mirrorConfigStore{mirrors: tc.mirrors}))
+			if err != nil {
+				t.Fatalf("NewK8sFetcher(...): %v", err)
+			}
+
+			got, err := f.Fetch(context.Background(), ref)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("\n%s\nFetch(...): want error, got nil", tc.reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nFetch(...): %v", tc.reason, err)
+			}
+			d, err := got.Digest()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, d); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMirrorTransport(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "mirror-ca", Namespace: "crossplane-system"},
+		Data:       map[string]string{"ca.crt": newCA(t, "ca-a")},
+	}
+	client := fake.NewSimpleClientset(cm)
+	f, err := NewK8sFetcher(client, WithNamespace("crossplane-system"))
+	if err != nil {
+		t.Fatalf("NewK8sFetcher(...): %v", err)
+	}
+	cfg := &v1beta1.MirrorTLS{CABundleConfigMapRef: &v1beta1.LocalConfigMapKeySelector{Name: "mirror-ca", Key: "ca.crt"}}
+
+	first, err := f.mirrorTransport(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("mirrorTransport(...): %v", err)
+	}
+	second, err := f.mirrorTransport(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("mirrorTransport(...): %v", err)
+	}
+	if first != second {
+		t.Errorf("mirrorTransport(...): want the cached transport when the CA bundle is unchanged, got a new transport")
+	}
+	if first == f.transport {
+		t.Errorf("mirrorTransport(...): want a mirror transport, got the fetcher's default transport")
+	}
+
+	cm.Data["ca.crt"] = newCA(t, "ca-b")
+	if _, err := client.CoreV1().ConfigMaps("crossplane-system").Update(context.Background(), cm, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	third, err := f.mirrorTransport(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("mirrorTransport(...): %v", err)
+	}
+	if third == first {
+		t.Errorf("mirrorTransport(...): want a new transport when the CA bundle changes, got the cached transport")
+	}
+}
+
+// newCA returns a PEM encoded self-signed CA certificate.
+func newCA(t *testing.T, cn string) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}